@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Names of the ACL users managed by the operator
+const (
+	// OperatorUserName is the user the operator authenticates with when the ACL users are managed
+	OperatorUserName = "redis-operator"
+	// ProbeUserName is the user the liveness probe authenticates with
+	ProbeUserName = "pinger"
+	// DefaultUserName is the user authenticated with the secretPath password
+	DefaultUserName = "default"
+)
+
+// ACLEnabled returns true when the ACL users of the redis nodes are managed by the operator.
+func (r *RedisFailover) ACLEnabled() bool {
+	return len(r.Spec.Auth.Users) > 0
+}
+
+// PasswordRotationGracePeriod returns how long the previous password is accepted once the redis
+// nodes were given a new one.
+func (r *RedisFailover) PasswordRotationGracePeriod() time.Duration {
+	if r.Spec.Auth.RotationGracePeriodSeconds > 0 {
+		return time.Duration(r.Spec.Auth.RotationGracePeriodSeconds) * time.Second
+	}
+	return DefaultPasswordRotationGracePeriod
+}
+
+// validateUsers checks the declared ACL users can be applied with ACL SETUSER
+func (r *RedisFailover) validateUsers() error {
+	names := map[string]bool{}
+	for _, user := range r.Spec.Auth.Users {
+		switch {
+		case user.Name == "":
+			return errors.New("auth.users must have a name")
+		case strings.ContainsAny(user.Name, " \t\r\n"):
+			return fmt.Errorf("auth.users name %q is invalid", user.Name)
+		case user.Name == OperatorUserName || user.Name == ProbeUserName || user.Name == DefaultUserName:
+			return fmt.Errorf("auth.users name %s is reserved", user.Name)
+		case names[user.Name]:
+			return fmt.Errorf("auth.users %s is declared more than once", user.Name)
+		case user.SecretPath == "":
+			return fmt.Errorf("auth.users %s must have a secretPath", user.Name)
+		}
+		names[user.Name] = true
+
+		for _, rule := range append(append(append([]string{}, user.Commands...), user.Keys...), user.Channels...) {
+			if rule == "" || strings.ContainsAny(rule, " \t\r\n") {
+				return fmt.Errorf("auth.users %s has an invalid rule %q", user.Name, rule)
+			}
+		}
+		for _, command := range user.Commands {
+			if !strings.HasPrefix(command, "+") && !strings.HasPrefix(command, "-") {
+				return fmt.Errorf("auth.users %s command %s must start with + or -", user.Name, command)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Backup constants
+const (
+	RFBackupKind       = "RedisFailoverBackup"
+	RFBackupName       = "redisfailoverbackup"
+	RFBackupNamePlural = "redisfailoverbackups"
+)
+
+// BackupPhase is the stage a backup is at
+type BackupPhase string
+
+// Backup phases
+const (
+	BackupPhaseRunning   BackupPhase = "Running"
+	BackupPhaseCompleted BackupPhase = "Completed"
+	BackupPhaseFailed    BackupPhase = "Failed"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisFailoverBackup represents an RDB snapshot of a Redis failover streamed to object storage
+// +kubebuilder:printcolumn:name="NAME",type="string",JSONPath=".metadata.name"
+// +kubebuilder:printcolumn:name="REDISFAILOVER",type="string",JSONPath=".spec.redisFailoverName"
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="SIZE",type="integer",JSONPath=".status.size"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:singular=redisfailoverbackup,path=redisfailoverbackups,shortName=rfb,scope=Namespaced
+type RedisFailoverBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              RedisFailoverBackupSpec   `json:"spec"`
+	Status            RedisFailoverBackupStatus `json:"status,omitempty"`
+}
+
+// RedisFailoverBackupSpec represents the Redis failover to back up and where to
+type RedisFailoverBackupSpec struct {
+	// RedisFailoverName is the Redis failover of the namespace the snapshot is taken from.
+	RedisFailoverName string `json:"redisFailoverName"`
+	// Storage is where the snapshot is uploaded. Defaults to spec.backup.storage of the Redis failover.
+	// +optional
+	Storage *BackupStorage `json:"storage,omitempty"`
+	// AllowMaster allows taking the snapshot from the master when no replica is in sync.
+	// +optional
+	AllowMaster bool `json:"allowMaster,omitempty"`
+}
+
+// RedisFailoverBackupStatus represents the result of a backup
+type RedisFailoverBackupStatus struct {
+	Phase   BackupPhase `json:"phase,omitempty"`
+	Message string      `json:"message,omitempty"`
+	// PodName is the Redis pod the snapshot was taken from.
+	PodName string `json:"podName,omitempty"`
+	// Location is the URL of the uploaded snapshot, as s3://<bucket>/<key>.
+	Location string `json:"location,omitempty"`
+	// Size is the size of the snapshot in bytes.
+	Size int64 `json:"size,omitempty"`
+	// Checksum is the SHA-256 of the snapshot, as sha256:<hex>.
+	Checksum       string       `json:"checksum,omitempty"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisFailoverBackupList represents a RedisFailoverBackup list
+type RedisFailoverBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []RedisFailoverBackup `json:"items"`
+}
+
+// BackupSettings defines the backups taken on a schedule
+type BackupSettings struct {
+	// Schedule is the cron expression, in the standard 5 fields format, the backups are taken at.
+	Schedule string        `json:"schedule"`
+	Storage  BackupStorage `json:"storage"`
+	// +optional
+	Retention BackupRetention `json:"retention,omitempty"`
+	// AllowMaster allows taking the snapshot from the master when no replica is in sync.
+	// +optional
+	AllowMaster bool `json:"allowMaster,omitempty"`
+}
+
+// BackupStorage defines where the snapshots are uploaded
+type BackupStorage struct {
+	S3 *S3Storage `json:"s3,omitempty"`
+}
+
+// S3Storage defines a bucket of an S3 compatible object storage
+type S3Storage struct {
+	// Endpoint is the URL of the object storage, as https://s3.eu-west-1.amazonaws.com. The
+	// buckets are addressed with the path style.
+	Endpoint string `json:"endpoint"`
+	// Region defaults to us-east-1.
+	// +optional
+	Region string `json:"region,omitempty"`
+	Bucket string `json:"bucket"`
+	// Prefix is prepended to the keys of the snapshots.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+	// SecretName is the secret holding the accessKeyID and secretAccessKey fields.
+	SecretName string `json:"secretName"`
+}
+
+// BackupRetention defines how many scheduled backups are kept
+type BackupRetention struct {
+	// KeepLast is the number of completed scheduled backups kept, along with the same number of
+	// failed ones. The older ones are deleted with their snapshot. All of them are kept when 0.
+	// +optional
+	KeepLast int32 `json:"keepLast,omitempty"`
+}
+
+// BackupScheduled returns true when backups are taken on a schedule.
+func (r *RedisFailover) BackupScheduled() bool {
+	return r.Spec.Backup != nil && r.Spec.Backup.Schedule != ""
+}
+
+// Validate checks the storage is complete
+func (s *BackupStorage) Validate() error {
+	if s.S3 == nil {
+		return errors.New("backup storage must include s3")
+	}
+	if s.S3.Endpoint == "" || s.S3.Bucket == "" || s.S3.SecretName == "" {
+		return errors.New("backup s3 storage must include an endpoint, a bucket and a secretName")
+	}
+	return nil
+}
+
+func (r *RedisFailover) validateBackup() error {
+	if r.Spec.Backup == nil {
+		return nil
+	}
+	if _, err := ParseSchedule(r.Spec.Backup.Schedule); err != nil {
+		return fmt.Errorf("backup schedule: %w", err)
+	}
+	return r.Spec.Backup.Storage.Validate()
+}
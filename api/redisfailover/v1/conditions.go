@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types reported on the RedisFailover status.
+const (
+	// ConditionMasterAvailable reports whether exactly one master is serving.
+	ConditionMasterAvailable = "MasterAvailable"
+	// ConditionReplicasInSync reports whether every replica is running and replicating from the master.
+	ConditionReplicasInSync = "ReplicasInSync"
+	// ConditionSentinelsInQuorum reports whether the sentinels are running and agree on the master.
+	ConditionSentinelsInQuorum = "SentinelsInQuorum"
+	// ConditionResourcesReconciled reports whether the Kubernetes resources owned by the RedisFailover were applied.
+	ConditionResourcesReconciled = "ResourcesReconciled"
+	// ConditionUpgrading reports whether pods are being rolled to a new StatefulSet revision.
+	ConditionUpgrading = "Upgrading"
+	// ConditionSwitchover reports the outcome of the last manual switchover.
+	ConditionSwitchover = "Switchover"
+	// ConditionACLUsersInSync reports whether the ACL users of every redis node match the declared ones.
+	ConditionACLUsersInSync = "ACLUsersInSync"
+	// ConditionPasswordInSync reports whether the redis nodes only accept the password of the auth secret.
+	ConditionPasswordInSync = "PasswordInSync"
+	// ConditionRestored reports the outcome of the restore of spec.restore.
+	ConditionRestored = "Restored"
+	// ConditionScaleDown reports the progress and the outcome of the last scale down of the redis nodes.
+	ConditionScaleDown = "ScaleDown"
+	// ConditionStorageExpanded reports the progress and the outcome of the expansion of the
+	// persistent volume claims of the redis nodes.
+	ConditionStorageExpanded = "StorageExpanded"
+	// ConditionConfigInSync reports whether the managed configuration of every redis node matches
+	// the declared one.
+	ConditionConfigInSync = "ConfigInSync"
+	// ConditionCustomConfigAccepted reports whether every parameter of the redis customConfig is
+	// known to the redis version, the unknown ones being skipped.
+	ConditionCustomConfigAccepted = "CustomConfigAccepted"
+)
+
+// Condition reasons reported on the RedisFailover status.
+const (
+	ReasonMasterElected       = "MasterElected"
+	ReasonNoMaster            = "NoMaster"
+	ReasonMultipleMasters     = "MultipleMasters"
+	ReasonStaleMasterFenced   = "StaleMasterFenced"
+	ReasonMasterUnhealthy     = "MasterUnhealthy"
+	ReasonFailoverFailed      = "FailoverFailed"
+	ReasonCheckFailed         = "CheckFailed"
+	ReasonReplicasNotRunning  = "ReplicasNotRunning"
+	ReasonReplicasSynced      = "ReplicasSynced"
+	ReasonReplicasNotSynced   = "ReplicasNotSynced"
+	ReasonSentinelsNotRunning = "SentinelsNotRunning"
+	ReasonSentinelsSynced     = "SentinelsSynced"
+	ReasonSentinelsNotSynced  = "SentinelsNotSynced"
+	ReasonReconciled          = "Reconciled"
+	ReasonReconcileFailed     = "ReconcileFailed"
+	ReasonRollingUpdate       = "RollingUpdate"
+	ReasonRevisionUpToDate    = "RevisionUpToDate"
+	ReasonRollingUpdateFailed = "RollingUpdateFailed"
+	ReasonSwitchoverSucceeded = "SwitchoverSucceeded"
+	ReasonSwitchoverFailed    = "SwitchoverFailed"
+	ReasonACLUsersSynced      = "ACLUsersSynced"
+	ReasonACLUsersDrifted     = "ACLUsersDrifted"
+	ReasonACLSyncFailed       = "ACLSyncFailed"
+	ReasonPasswordApplied     = "PasswordApplied"
+	ReasonPasswordRotating    = "PasswordRotating"
+	ReasonRotationFailed      = "RotationFailed"
+	ReasonRestoreInProgress   = "RestoreInProgress"
+	ReasonRestoreCompleted    = "RestoreCompleted"
+	ReasonRestoreRefused      = "RestoreRefused"
+	ReasonScalingDown         = "ScalingDown"
+	ReasonScaleDownCompleted  = "ScaleDownCompleted"
+	ReasonScaleDownFailed     = "ScaleDownFailed"
+	ReasonStorageExpanding    = "StorageExpanding"
+	ReasonStorageExpanded     = "StorageExpanded"
+	ReasonExpansionNotAllowed = "ExpansionNotAllowed"
+	ReasonConfigSynced        = "ConfigSynced"
+	ReasonConfigDrifted       = "ConfigDrifted"
+	ReasonCustomConfigKnown   = "CustomConfigKnown"
+	ReasonUnknownParameters   = "UnknownParameters"
+)
+
+// SetCondition adds or updates the condition of the given type on the RedisFailover status.
+// The last transition time is only changed when the condition status changes.
+func (r *RedisFailover) SetCondition(conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&r.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: r.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// RemoveCondition removes the condition of the given type from the RedisFailover status.
+func (r *RedisFailover) RemoveCondition(conditionType string) {
+	meta.RemoveStatusCondition(&r.Status.Conditions, conditionType)
+}
+
+// GetCondition returns the condition of the given type, or nil if it is not set.
+func (r *RedisFailover) GetCondition(conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(r.Status.Conditions, conditionType)
+}
+
+// IsConditionTrue returns true when the condition of the given type is set and has status True.
+func (r *RedisFailover) IsConditionTrue(conditionType string) bool {
+	return meta.IsStatusConditionTrue(r.Status.Conditions, conditionType)
+}
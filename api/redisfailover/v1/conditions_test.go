@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetCondition(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRedisFailover("test", nil)
+	rf.Generation = 3
+
+	rf.SetCondition(ConditionMasterAvailable, metav1.ConditionFalse, ReasonNoMaster, "no master detected")
+	condition := rf.GetCondition(ConditionMasterAvailable)
+	if assert.NotNil(condition) {
+		assert.Equal(metav1.ConditionFalse, condition.Status)
+		assert.Equal(ReasonNoMaster, condition.Reason)
+		assert.Equal(int64(3), condition.ObservedGeneration)
+		assert.False(condition.LastTransitionTime.IsZero())
+	}
+	assert.False(rf.IsConditionTrue(ConditionMasterAvailable))
+
+	// Updating the message without changing the status keeps the transition time.
+	transition := metav1.NewTime(condition.LastTransitionTime.Add(-time.Minute))
+	rf.Status.Conditions[0].LastTransitionTime = transition
+	rf.SetCondition(ConditionMasterAvailable, metav1.ConditionFalse, ReasonNoMaster, "still no master")
+	condition = rf.GetCondition(ConditionMasterAvailable)
+	assert.Equal(transition, condition.LastTransitionTime)
+	assert.Equal("still no master", condition.Message)
+
+	rf.SetCondition(ConditionMasterAvailable, metav1.ConditionTrue, ReasonMasterElected, "")
+	assert.True(rf.IsConditionTrue(ConditionMasterAvailable))
+	assert.NotEqual(transition, rf.GetCondition(ConditionMasterAvailable).LastTransitionTime)
+
+	rf.RemoveCondition(ConditionMasterAvailable)
+	assert.Nil(rf.GetCondition(ConditionMasterAvailable))
+	assert.Empty(rf.Status.Conditions)
+}
+
+func TestValidateKeepsConditions(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRedisFailover("test", nil)
+	rf.Status.State = NotHealthyState
+	rf.Status.ObservedGeneration = 2
+	rf.SetCondition(ConditionReplicasInSync, metav1.ConditionTrue, ReasonReplicasSynced, "")
+
+	assert.NoError(rf.Validate())
+	assert.Equal(HealthyState, rf.Status.State)
+	assert.Equal(int64(2), rf.Status.ObservedGeneration)
+	assert.True(rf.IsConditionTrue(ConditionReplicasInSync))
+}
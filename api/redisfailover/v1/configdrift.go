@@ -0,0 +1,34 @@
+package v1
+
+import "fmt"
+
+// DriftPolicy tells what is done when the managed redis configuration is changed on a redis node
+// outside of the operator, with CONFIG SET
+// +kubebuilder:validation:Enum=enforce;report;ignore
+type DriftPolicy string
+
+// Drift policies
+const (
+	// DriftPolicyEnforce reports the drifted parameters and applies them again
+	DriftPolicyEnforce DriftPolicy = "enforce"
+	// DriftPolicyReport reports the drifted parameters and leaves them as they are
+	DriftPolicyReport DriftPolicy = "report"
+	// DriftPolicyIgnore doesn't check the redis nodes for drift
+	DriftPolicyIgnore DriftPolicy = "ignore"
+)
+
+// RedisDriftPolicy returns the drift policy of the redis configuration, enforce by default
+func (r *RedisFailover) RedisDriftPolicy() DriftPolicy {
+	if r.Spec.Redis.DriftPolicy == "" {
+		return DriftPolicyEnforce
+	}
+	return r.Spec.Redis.DriftPolicy
+}
+
+func (r *RedisFailover) validateDriftPolicy() error {
+	switch r.Spec.Redis.DriftPolicy {
+	case "", DriftPolicyEnforce, DriftPolicyReport, DriftPolicyIgnore:
+		return nil
+	}
+	return fmt.Errorf("driftPolicy must be one of %s, %s and %s", DriftPolicyEnforce, DriftPolicyReport, DriftPolicyIgnore)
+}
@@ -13,6 +13,9 @@ const (
 	defaultExporterImage         = "quay.io/oliver006/redis_exporter:v1.80.0-alpine"
 	defaultImage                 = "redis:7.2.12-alpine"
 	defaultRedisPort             = 6379
+	defaultSentinelMasterName    = "mymaster"
+	defaultSentinelPort          = 26379
+	defaultRestorePath           = "dump.rdb"
 	HealthyState                 = "Healthy"
 	NotHealthyState              = "NotHealthy"
 )
@@ -24,6 +27,8 @@ var (
 	DefaultSentinelEnabled = false
 	// DefaultFailoverTimeout is the default timeout for operator-managed failover
 	DefaultFailoverTimeout = metav1.Duration{Duration: 10 * time.Second}
+	// DefaultPasswordRotationGracePeriod is the default time the previous password is accepted after a rotation
+	DefaultPasswordRotationGracePeriod = 5 * time.Minute
 )
 
 var (
@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/saremox/redis-operator/api/redisfailover"
+)
+
+// FinalSnapshotFinalizer holds the deletion of a Redis failover until its final snapshot is taken
+const FinalSnapshotFinalizer = redisfailover.GroupName + "/final-snapshot"
+
+// FinalSnapshotSettings defines where the snapshot taken when the Redis failover is deleted is
+// kept. Exactly one of S3 and RetainVolumeClaim must be set.
+type FinalSnapshotSettings struct {
+	// S3 is the bucket the snapshot is uploaded to.
+	// +optional
+	S3 *S3Storage `json:"s3,omitempty"`
+	// RetainVolumeClaim keeps the persistent volume claim of the redis node the snapshot is taken
+	// on, the other ones are deleted along with the Redis failover.
+	// +optional
+	RetainVolumeClaim bool `json:"retainVolumeClaim,omitempty"`
+}
+
+// FinalSnapshotEnabled returns true when a snapshot is taken before the Redis failover is deleted.
+func (r *RedisFailover) FinalSnapshotEnabled() bool {
+	return r.Spec.Redis.Storage.FinalSnapshot != nil
+}
+
+func (r *RedisFailover) validateFinalSnapshot() error {
+	finalSnapshot := r.Spec.Redis.Storage.FinalSnapshot
+	if finalSnapshot == nil {
+		return nil
+	}
+
+	if r.Spec.Redis.Storage.KeepAfterDeletion {
+		return errors.New("storage finalSnapshot can't be used along with keepAfterDeletion")
+	}
+	if !r.DeleteClaimsWhenDeleted() {
+		return errors.New("storage finalSnapshot can't be used along with pvcRetentionPolicy whenDeleted Retain")
+	}
+	if (finalSnapshot.S3 != nil) == finalSnapshot.RetainVolumeClaim {
+		return errors.New("storage finalSnapshot must include exactly one of s3 and retainVolumeClaim")
+	}
+	if finalSnapshot.S3 != nil {
+		storage := BackupStorage{S3: finalSnapshot.S3}
+		if err := storage.Validate(); err != nil {
+			return fmt.Errorf("storage finalSnapshot: %w", err)
+		}
+	}
+	if finalSnapshot.RetainVolumeClaim && r.Spec.Redis.Storage.PersistentVolumeClaim == nil {
+		return errors.New("storage finalSnapshot retainVolumeClaim requires a persistentVolumeClaim storage")
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodAddress returns the address the redis pod is reached and replicated with: its stable name
+// under the headless service of the statefulset when spec.announceHostnames is set, its IP
+// otherwise or when the pod has no name under the service.
+func (r *RedisFailover) PodAddress(pod *corev1.Pod) string {
+	if !r.Spec.AnnounceHostnames || pod.Spec.Hostname == "" || pod.Spec.Subdomain == "" {
+		return r.PodIP(pod)
+	}
+	return fmt.Sprintf("%s.%s.%s.svc", pod.Spec.Hostname, pod.Spec.Subdomain, pod.Namespace)
+}
+
+// SameAddress returns true when both addresses are the same redis node. The hostnames reported
+// by redis may be fully qualified with the cluster domain, or end with a dot.
+func SameAddress(a, b string) bool {
+	a = strings.TrimSuffix(strings.ToLower(a), ".")
+	b = strings.TrimSuffix(strings.ToLower(b), ".")
+	if a == b {
+		return true
+	}
+	if IPFamilyOf(a) != "" || IPFamilyOf(b) != "" {
+		return false
+	}
+	return strings.HasPrefix(a, b+".") && strings.HasSuffix(b, ".svc") ||
+		strings.HasPrefix(b, a+".") && strings.HasSuffix(a, ".svc")
+}
+
+func (r *RedisFailover) validateAnnounceHostnames() error {
+	if r.Spec.AnnounceHostnames && len(r.Spec.Redis.Command) > 0 {
+		return errors.New("announceHostnames can't be used along with a custom redis command")
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"fmt"
+	"net"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodIP returns the IP the pod is addressed with: the one of spec.ipFamily on dual-stack
+// clusters, the primary IP of the pod when it is not set or the pod has no IP of that family.
+func (r *RedisFailover) PodIP(pod *corev1.Pod) string {
+	if r.Spec.IPFamily == "" {
+		return pod.Status.PodIP
+	}
+	for _, podIP := range pod.Status.PodIPs {
+		if IPFamilyOf(podIP.IP) == r.Spec.IPFamily {
+			return podIP.IP
+		}
+	}
+	return pod.Status.PodIP
+}
+
+// IPFamilyOf returns the IP family of the address, or an empty one when it is not an IP
+func IPFamilyOf(address string) corev1.IPFamily {
+	ip := net.ParseIP(address)
+	switch {
+	case ip == nil:
+		return ""
+	case ip.To4() != nil:
+		return corev1.IPv4Protocol
+	default:
+		return corev1.IPv6Protocol
+	}
+}
+
+func (r *RedisFailover) validateIPFamily() error {
+	switch r.Spec.IPFamily {
+	case "", corev1.IPv4Protocol, corev1.IPv6Protocol:
+		return nil
+	}
+	return fmt.Errorf("ipFamily must be either %s or %s", corev1.IPv4Protocol, corev1.IPv6Protocol)
+}
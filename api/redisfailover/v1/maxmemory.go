@@ -0,0 +1,93 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// defaultMaxMemoryPercent is the share of the memory limit given to the dataset by default. The
+// rest is left to the replication buffers and the copy-on-write of the fork saving snapshots.
+const defaultMaxMemoryPercent = 75
+
+// MaxMemoryPolicy is how redis evicts keys once maxmemory is reached
+// +kubebuilder:validation:Enum=noeviction;allkeys-lru;allkeys-lfu;allkeys-random;volatile-lru;volatile-lfu;volatile-random;volatile-ttl
+type MaxMemoryPolicy string
+
+// maxMemoryPolicies are the eviction policies supported by redis
+var maxMemoryPolicies = []MaxMemoryPolicy{"noeviction", "allkeys-lru", "allkeys-lfu", "allkeys-random", "volatile-lru", "volatile-lfu", "volatile-random", "volatile-ttl"}
+
+// maxMemoryParameters are the redis configuration parameters set from spec.redis.maxMemoryPolicy
+// and spec.redis.maxMemoryPercent
+var maxMemoryParameters = []string{"maxmemory", "maxmemory-policy"}
+
+// MaxMemory returns the maxmemory derived from the memory limit of the redis container, in
+// bytes, and whether it is set at all. It is set when either spec.redis.maxMemoryPolicy or
+// spec.redis.maxMemoryPercent is, and the memory is limited.
+func (r *RedisFailover) MaxMemory() (int64, bool) {
+	if r.Spec.Redis.MaxMemoryPolicy == "" && r.Spec.Redis.MaxMemoryPercent == nil {
+		return 0, false
+	}
+	limit, ok := r.Spec.Redis.Resources.Limits[corev1.ResourceMemory]
+	if !ok || limit.IsZero() {
+		return 0, false
+	}
+	percent := int64(defaultMaxMemoryPercent)
+	if r.Spec.Redis.MaxMemoryPercent != nil {
+		percent = int64(*r.Spec.Redis.MaxMemoryPercent)
+	}
+	return limit.Value() * percent / 100, true
+}
+
+// MaxMemoryConfig returns the redis configuration, as CONFIG SET parameters and values, of
+// spec.redis.maxMemoryPolicy and spec.redis.maxMemoryPercent.
+func (r *RedisFailover) MaxMemoryConfig() []string {
+	config := []string{}
+	if maxMemory, ok := r.MaxMemory(); ok {
+		config = append(config, fmt.Sprintf("maxmemory %d", maxMemory))
+	}
+	if r.Spec.Redis.MaxMemoryPolicy != "" {
+		config = append(config, fmt.Sprintf("maxmemory-policy %s", r.Spec.Redis.MaxMemoryPolicy))
+	}
+	return config
+}
+
+func (r *RedisFailover) validateMaxMemory() error {
+	if r.Spec.Redis.MaxMemoryPolicy == "" && r.Spec.Redis.MaxMemoryPercent == nil {
+		return nil
+	}
+
+	if policy := r.Spec.Redis.MaxMemoryPolicy; policy != "" {
+		supported := false
+		for _, maxMemoryPolicy := range maxMemoryPolicies {
+			supported = supported || policy == maxMemoryPolicy
+		}
+		if !supported {
+			return fmt.Errorf("maxMemoryPolicy %s is not supported", policy)
+		}
+	}
+	if percent := r.Spec.Redis.MaxMemoryPercent; percent != nil {
+		if *percent <= 0 || *percent > 100 {
+			return errors.New("maxMemoryPercent must be between 1 and 100")
+		}
+		if _, ok := r.MaxMemory(); !ok {
+			return errors.New("maxMemoryPercent requires a memory limit on the redis resources")
+		}
+	}
+
+	for _, config := range r.Spec.Redis.CustomConfig {
+		fields := strings.Fields(config)
+		if len(fields) == 0 {
+			continue
+		}
+		parameter := strings.ToLower(fields[0])
+		for _, maxMemoryParameter := range maxMemoryParameters {
+			if parameter == maxMemoryParameter {
+				return fmt.Errorf("customConfig can't set %s along with maxMemoryPolicy or maxMemoryPercent", parameter)
+			}
+		}
+	}
+	return nil
+}
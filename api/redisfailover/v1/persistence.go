@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// AppendFsync is how often the append only file is synced to disk
+type AppendFsync string
+
+// Append only file sync policies
+const (
+	AppendFsyncAlways   AppendFsync = "always"
+	AppendFsyncEverySec AppendFsync = "everysec"
+	AppendFsyncNo       AppendFsync = "no"
+)
+
+// persistenceParameters are the redis configuration parameters set from spec.redis.persistence
+var persistenceParameters = []string{"save", "appendonly", "appendfsync", "auto-aof-rewrite-percentage", "auto-aof-rewrite-min-size"}
+
+// PersistenceSettings defines how the redis nodes persist their dataset on disk. When it is not
+// set, an RDB snapshot is saved every 15 minutes if a key changed, or every 5 minutes if 10 did.
+type PersistenceSettings struct {
+	// Disabled turns every kind of persistence off, rdb and aof can't be set along with it.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+	// RDB defines when a snapshot of the dataset is saved.
+	// +optional
+	RDB *RDBSettings `json:"rdb,omitempty"`
+	// AOF defines the append only file logging every write.
+	// +optional
+	AOF *AOFSettings `json:"aof,omitempty"`
+}
+
+// RDBSettings defines when a snapshot of the dataset is saved
+type RDBSettings struct {
+	// SavePoints save a snapshot when any of them is reached. No snapshot is saved when empty.
+	// +optional
+	SavePoints []RDBSavePoint `json:"savePoints,omitempty"`
+}
+
+// RDBSavePoint saves a snapshot after the given number of seconds if at least the given number
+// of keys changed
+type RDBSavePoint struct {
+	Seconds int32 `json:"seconds"`
+	Changes int32 `json:"changes"`
+}
+
+// AOFSettings defines the append only file logging every write
+type AOFSettings struct {
+	// Enabled logs every write to the append only file, which is replayed on restart.
+	Enabled bool `json:"enabled,omitempty"`
+	// Fsync is how often the append only file is synced to disk. Defaults to everysec.
+	// +kubebuilder:validation:Enum=always;everysec;no
+	// +optional
+	Fsync AppendFsync `json:"fsync,omitempty"`
+	// RewritePercentage rewrites the append only file once it grew by this percentage since the
+	// last rewrite. 0 disables the automatic rewrites.
+	// +optional
+	RewritePercentage *int32 `json:"rewritePercentage,omitempty"`
+	// RewriteMinSize is the size the append only file must reach before it is rewritten.
+	// +optional
+	RewriteMinSize *resource.Quantity `json:"rewriteMinSize,omitempty"`
+}
+
+// PersistenceEnabled returns true when the redis nodes persist their dataset on disk
+func (r *RedisFailover) PersistenceEnabled() bool {
+	persistence := r.Spec.Redis.Persistence
+	if persistence == nil {
+		return true
+	}
+	if persistence.Disabled {
+		return false
+	}
+	return (persistence.RDB != nil && len(persistence.RDB.SavePoints) != 0) || (persistence.AOF != nil && persistence.AOF.Enabled)
+}
+
+// PersistenceConfig returns the redis configuration, as CONFIG SET parameters and values, of
+// spec.redis.persistence. It is empty when it is not set.
+func (r *RedisFailover) PersistenceConfig() []string {
+	persistence := r.Spec.Redis.Persistence
+	if persistence == nil {
+		return nil
+	}
+	if persistence.Disabled {
+		return []string{`save ""`, "appendonly no"}
+	}
+
+	config := []string{`save ""`}
+	if persistence.RDB != nil && len(persistence.RDB.SavePoints) != 0 {
+		savePoints := []string{}
+		for _, savePoint := range persistence.RDB.SavePoints {
+			savePoints = append(savePoints, fmt.Sprintf("%d %d", savePoint.Seconds, savePoint.Changes))
+		}
+		config[0] = "save " + strings.Join(savePoints, " ")
+	}
+
+	aof := persistence.AOF
+	if aof == nil {
+		aof = &AOFSettings{}
+	}
+	if aof.Enabled {
+		config = append(config, "appendonly yes")
+	} else {
+		config = append(config, "appendonly no")
+	}
+	fsync := aof.Fsync
+	if fsync == "" {
+		fsync = AppendFsyncEverySec
+	}
+	config = append(config, fmt.Sprintf("appendfsync %s", fsync))
+	if aof.RewritePercentage != nil {
+		config = append(config, fmt.Sprintf("auto-aof-rewrite-percentage %d", *aof.RewritePercentage))
+	}
+	if aof.RewriteMinSize != nil {
+		config = append(config, fmt.Sprintf("auto-aof-rewrite-min-size %d", aof.RewriteMinSize.Value()))
+	}
+	return config
+}
+
+// RedisConfig returns the redis configuration applied at runtime: the one of
+// spec.redis.persistence, the maxmemory one, then the runtime entries of spec.redis.customConfig.
+func (r *RedisFailover) RedisConfig() []string {
+	config := append(r.PersistenceConfig(), r.MaxMemoryConfig()...)
+	return append(config, r.RuntimeCustomConfig()...)
+}
+
+func (r *RedisFailover) validatePersistence() error {
+	persistence := r.Spec.Redis.Persistence
+	if persistence == nil {
+		return nil
+	}
+
+	if persistence.Disabled && (persistence.RDB != nil || persistence.AOF != nil) {
+		return errors.New("persistence disabled can't be used along with rdb or aof")
+	}
+	if persistence.RDB != nil {
+		for _, savePoint := range persistence.RDB.SavePoints {
+			if savePoint.Seconds <= 0 || savePoint.Changes <= 0 {
+				return errors.New("persistence rdb savePoints must have positive seconds and changes")
+			}
+		}
+	}
+	if aof := persistence.AOF; aof != nil {
+		switch aof.Fsync {
+		case "", AppendFsyncAlways, AppendFsyncEverySec, AppendFsyncNo:
+		default:
+			return fmt.Errorf("persistence aof fsync must be one of %s, %s and %s", AppendFsyncAlways, AppendFsyncEverySec, AppendFsyncNo)
+		}
+		if aof.RewritePercentage != nil && *aof.RewritePercentage < 0 {
+			return errors.New("persistence aof rewritePercentage can't be negative")
+		}
+		if aof.RewriteMinSize != nil && aof.RewriteMinSize.Sign() < 0 {
+			return errors.New("persistence aof rewriteMinSize can't be negative")
+		}
+	}
+
+	storage := r.Spec.Redis.Storage
+	if r.PersistenceEnabled() && storage.PersistentVolumeClaim == nil {
+		return errors.New("persistence requires a persistentVolumeClaim storage, the emptyDir one is lost along with its pod")
+	}
+	if !r.PersistenceEnabled() && storage.KeepAfterDeletion {
+		return errors.New("storage keepAfterDeletion can't be used along with persistence disabled")
+	}
+
+	for _, config := range r.Spec.Redis.CustomConfig {
+		fields := strings.Fields(config)
+		if len(fields) == 0 {
+			continue
+		}
+		parameter := strings.ToLower(fields[0])
+		for _, persistenceParameter := range persistenceParameters {
+			if parameter == persistenceParameter {
+				return fmt.Errorf("customConfig can't set %s along with persistence", parameter)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package v1
+
+import "errors"
+
+// PVCRetentionPolicyType tells whether the persistent volume claims of the redis nodes are kept
+type PVCRetentionPolicyType string
+
+// PVC retention policy types
+const (
+	RetainPVCRetentionPolicyType PVCRetentionPolicyType = "Retain"
+	DeletePVCRetentionPolicyType PVCRetentionPolicyType = "Delete"
+)
+
+// PVCRetentionPolicy defines what happens to the persistent volume claims of the redis nodes
+// once they are no longer used.
+type PVCRetentionPolicy struct {
+	// WhenScaled applies to the claims of the redis nodes removed by a scale down. They are only
+	// deleted once their pods are gone. Defaults to Retain.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +optional
+	WhenScaled PVCRetentionPolicyType `json:"whenScaled,omitempty"`
+	// WhenDeleted applies to the claims of every redis node when the Redis failover is deleted.
+	// Defaults to Delete, unless keepAfterDeletion is set.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +optional
+	WhenDeleted PVCRetentionPolicyType `json:"whenDeleted,omitempty"`
+}
+
+// DeleteClaimsWhenScaled returns true when the persistent volume claims of the redis nodes
+// removed by a scale down are deleted.
+func (r *RedisFailover) DeleteClaimsWhenScaled() bool {
+	policy := r.Spec.Redis.Storage.PVCRetentionPolicy
+	return policy != nil && policy.WhenScaled == DeletePVCRetentionPolicyType
+}
+
+// DeleteClaimsWhenDeleted returns true when the persistent volume claims of the redis nodes are
+// deleted along with the Redis failover.
+func (r *RedisFailover) DeleteClaimsWhenDeleted() bool {
+	storage := r.Spec.Redis.Storage
+	if storage.PVCRetentionPolicy != nil && storage.PVCRetentionPolicy.WhenDeleted != "" {
+		return storage.PVCRetentionPolicy.WhenDeleted == DeletePVCRetentionPolicyType
+	}
+	return !storage.KeepAfterDeletion
+}
+
+func (r *RedisFailover) validatePVCRetentionPolicy() error {
+	storage := r.Spec.Redis.Storage
+	policy := storage.PVCRetentionPolicy
+	if policy == nil {
+		return nil
+	}
+
+	for _, policyType := range []PVCRetentionPolicyType{policy.WhenScaled, policy.WhenDeleted} {
+		if policyType != "" && policyType != RetainPVCRetentionPolicyType && policyType != DeletePVCRetentionPolicyType {
+			return errors.New("storage pvcRetentionPolicy must be either Retain or Delete")
+		}
+	}
+	if storage.KeepAfterDeletion && policy.WhenDeleted == DeletePVCRetentionPolicyType {
+		return errors.New("storage pvcRetentionPolicy whenDeleted Delete can't be used along with keepAfterDeletion")
+	}
+	return nil
+}
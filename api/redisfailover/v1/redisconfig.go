@@ -0,0 +1,178 @@
+package v1
+
+import (
+	"strconv"
+	"strings"
+)
+
+// redisConfigParameter tells how a change of a redis configuration parameter is applied
+type redisConfigParameter int
+
+const (
+	// runtimeParameter is applied on the running redis nodes with CONFIG SET
+	runtimeParameter redisConfigParameter = iota + 1
+	// restartParameter is only read from redis.conf at startup, the redis nodes are restarted to apply it
+	restartParameter
+)
+
+// redisRuntimeParameters are the parameters applied with CONFIG SET by every supported redis version
+var redisRuntimeParameters = []string{
+	"bind", "port", "protected-mode", "tcp-keepalive", "timeout", "loglevel", "ignore-warnings",
+	"tls-port", "tls-cert-file", "tls-key-file", "tls-client-cert-file", "tls-client-key-file", "tls-dh-params-file",
+	"tls-ca-cert-file", "tls-ca-cert-dir", "tls-auth-clients", "tls-replication", "tls-cluster", "tls-protocols",
+	"tls-ciphers", "tls-ciphersuites", "tls-prefer-server-ciphers", "tls-session-caching", "tls-session-cache-size",
+	"tls-session-cache-timeout",
+	"save", "stop-writes-on-bgsave-error", "rdbcompression", "rdbchecksum", "sanitize-dump-payload",
+	"rdb-del-sync-files",
+	"replica-serve-stale-data", "slave-serve-stale-data", "replica-read-only", "slave-read-only", "repl-diskless-sync",
+	"repl-diskless-sync-delay", "repl-diskless-load", "repl-ping-replica-period", "repl-ping-slave-period", "repl-timeout",
+	"repl-disable-tcp-nodelay", "repl-backlog-size", "repl-backlog-ttl", "replica-priority", "slave-priority",
+	"replica-announced", "min-replicas-to-write", "min-slaves-to-write", "min-replicas-max-lag", "min-slaves-max-lag",
+	"replica-announce-ip", "slave-announce-ip", "replica-announce-port", "slave-announce-port", "masterauth", "masteruser",
+	"tracking-table-max-keys", "acllog-max-len", "requirepass", "acl-pubsub-default", "maxclients",
+	"maxmemory", "maxmemory-policy", "maxmemory-samples", "maxmemory-eviction-tenacity", "replica-ignore-maxmemory",
+	"slave-ignore-maxmemory", "active-expire-effort",
+	"lazyfree-lazy-eviction", "lazyfree-lazy-expire", "lazyfree-lazy-server-del", "replica-lazy-flush", "slave-lazy-flush",
+	"lazyfree-lazy-user-del", "lazyfree-lazy-user-flush", "oom-score-adj", "oom-score-adj-values",
+	"appendonly", "appendfsync", "no-appendfsync-on-rewrite", "auto-aof-rewrite-percentage", "auto-aof-rewrite-min-size",
+	"aof-load-truncated", "aof-use-rdb-preamble", "lua-time-limit",
+	"cluster-node-timeout", "cluster-replica-validity-factor", "cluster-slave-validity-factor", "cluster-migration-barrier",
+	"cluster-allow-replica-migration", "cluster-require-full-coverage", "cluster-replica-no-failover",
+	"cluster-slave-no-failover", "cluster-allow-reads-when-down", "cluster-announce-ip", "cluster-announce-port",
+	"cluster-announce-tls-port", "cluster-announce-bus-port",
+	"slowlog-log-slower-than", "slowlog-max-len", "latency-monitor-threshold", "notify-keyspace-events",
+	"hash-max-ziplist-entries", "hash-max-ziplist-value", "list-max-ziplist-size", "list-compress-depth",
+	"set-max-intset-entries", "zset-max-ziplist-entries", "zset-max-ziplist-value", "hll-sparse-max-bytes",
+	"stream-node-max-bytes", "stream-node-max-entries", "activerehashing", "client-output-buffer-limit",
+	"client-query-buffer-limit", "proto-max-bulk-len", "hz", "dynamic-hz", "aof-rewrite-incremental-fsync",
+	"rdb-save-incremental-fsync", "lfu-log-factor", "lfu-decay-time", "jemalloc-bg-thread",
+	"activedefrag", "active-defrag-ignore-bytes", "active-defrag-threshold-lower", "active-defrag-threshold-upper",
+	"active-defrag-cycle-min", "active-defrag-cycle-max", "active-defrag-max-scan-fields",
+}
+
+// redisRestartParameters are the parameters only read at startup by every supported redis version
+var redisRestartParameters = []string{
+	"include", "loadmodule", "daemonize", "supervised", "pidfile", "logfile", "syslog-enabled", "syslog-ident",
+	"syslog-facility", "always-show-logo", "set-proc-title", "databases", "unixsocket", "unixsocketperm", "tcp-backlog",
+	"io-threads", "io-threads-do-reads", "aclfile", "appendfilename", "cluster-enabled", "cluster-config-file",
+	"disable-thp", "server_cpulist", "bio_cpulist", "aof_rewrite_cpulist", "bgsave_cpulist",
+}
+
+// redisConfigCatalogues are the configuration parameters known to each major redis version
+var redisConfigCatalogues = map[int]map[string]redisConfigParameter{
+	6: newRedisConfigCatalogue(
+		append([]string{"gopher-enabled", "dir", "dbfilename"}, redisRuntimeParameters...),
+		append([]string{"proc-title-template"}, redisRestartParameters...),
+	),
+	7: newRedisConfigCatalogue(
+		append([]string{
+			"bind-source-addr", "tls-key-file-pass", "tls-client-key-file-pass", "crash-log-enabled",
+			"crash-memcheck-enabled", "repl-diskless-sync-max-replicas", "propagation-error-behavior",
+			"replica-ignore-disk-write-errors", "aof-timestamp-enabled", "shutdown-timeout", "shutdown-on-sigint",
+			"shutdown-on-sigterm", "busy-reply-threshold", "cluster-allow-pubsubshard-when-down",
+			"cluster-link-sendbuf-limit", "cluster-announce-hostname", "cluster-announce-human-nodename",
+			"cluster-preferred-endpoint-type", "latency-tracking", "latency-tracking-info-percentiles",
+			"hash-max-listpack-entries", "hash-max-listpack-value", "list-max-listpack-size", "set-max-listpack-entries",
+			"set-max-listpack-value", "zset-max-listpack-entries", "zset-max-listpack-value", "maxmemory-clients",
+			"proc-title-template", "locale-collate",
+		}, redisRuntimeParameters...),
+		// dir and dbfilename are protected configs, refused by CONFIG SET unless enable-protected-configs is set
+		append([]string{
+			"appenddirname", "cluster-port", "enable-protected-configs", "enable-debug-command", "enable-module-command",
+			"dir", "dbfilename",
+		}, redisRestartParameters...),
+	),
+}
+
+func newRedisConfigCatalogue(runtime, restart []string) map[string]redisConfigParameter {
+	catalogue := map[string]redisConfigParameter{}
+	for _, parameter := range runtime {
+		catalogue[parameter] = runtimeParameter
+	}
+	for _, parameter := range restart {
+		catalogue[parameter] = restartParameter
+	}
+	return catalogue
+}
+
+// RedisMajorVersion returns the major version of the redis nodes, read from the tag of
+// spec.redis.image or else from the version reported in the status. It is 0 when unknown.
+func (r *RedisFailover) RedisMajorVersion() int {
+	image := r.Spec.Redis.Image
+	if image == "" {
+		image = defaultImage
+	}
+	image = strings.SplitN(image, "@", 2)[0]
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		if major := parseMajorVersion(image[i+1:]); major != 0 {
+			return major
+		}
+	}
+	return parseMajorVersion(r.Status.RedisVersion)
+}
+
+// parseMajorVersion returns the major version a version or an image tag starts with, or 0
+func parseMajorVersion(version string) int {
+	version = strings.TrimPrefix(version, "v")
+	end := 0
+	for end < len(version) && version[end] >= '0' && version[end] <= '9' {
+		end++
+	}
+	major, err := strconv.Atoi(version[:end])
+	if err != nil {
+		return 0
+	}
+	return major
+}
+
+// redisConfigParameterOf returns how a change of the parameter set by the configuration entry is
+// applied, or 0 when the parameter is unknown to the redis version. Every parameter is applied
+// with CONFIG SET when the redis version has no catalogue.
+func (r *RedisFailover) redisConfigParameterOf(config string) (string, redisConfigParameter) {
+	fields := strings.Fields(config)
+	if len(fields) == 0 {
+		return "", runtimeParameter
+	}
+	parameter := strings.ToLower(fields[0])
+	catalogue, ok := redisConfigCatalogues[r.RedisMajorVersion()]
+	if !ok {
+		return parameter, runtimeParameter
+	}
+	return parameter, catalogue[parameter]
+}
+
+// RuntimeCustomConfig returns the entries of spec.redis.customConfig applied on the running redis
+// nodes with CONFIG SET
+func (r *RedisFailover) RuntimeCustomConfig() []string {
+	config := []string{}
+	for _, entry := range r.Spec.Redis.CustomConfig {
+		if _, parameter := r.redisConfigParameterOf(entry); parameter == runtimeParameter {
+			config = append(config, entry)
+		}
+	}
+	return config
+}
+
+// RestartCustomConfig returns the entries of spec.redis.customConfig only read from redis.conf at
+// startup. The redis nodes are rolled when they change.
+func (r *RedisFailover) RestartCustomConfig() []string {
+	config := []string{}
+	for _, entry := range r.Spec.Redis.CustomConfig {
+		if _, parameter := r.redisConfigParameterOf(entry); parameter == restartParameter {
+			config = append(config, entry)
+		}
+	}
+	return config
+}
+
+// UnknownCustomConfig returns the parameters of spec.redis.customConfig unknown to the redis
+// version. Their entries are skipped, as redis would refuse them at startup or with CONFIG SET.
+func (r *RedisFailover) UnknownCustomConfig() []string {
+	parameters := []string{}
+	for _, entry := range r.Spec.Redis.CustomConfig {
+		if name, parameter := r.redisConfigParameterOf(entry); parameter == 0 {
+			parameters = append(parameters, name)
+		}
+	}
+	return parameters
+}
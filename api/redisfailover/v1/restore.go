@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// RestoreSettings defines the RDB snapshot the Redis failover is seeded with. Exactly one of
+// BackupName, S3 and PersistentVolumeClaim must be set.
+type RestoreSettings struct {
+	// BackupName is a completed RedisFailoverBackup of the namespace.
+	// +optional
+	BackupName string `json:"backupName,omitempty"`
+	// +optional
+	S3 *S3RestoreSource `json:"s3,omitempty"`
+	// +optional
+	PersistentVolumeClaim *PersistentVolumeClaimRestoreSource `json:"persistentVolumeClaim,omitempty"`
+	// Overwrite allows restoring into a Redis failover that already exists, its data is replaced.
+	// Otherwise the restore is refused.
+	// +optional
+	Overwrite bool `json:"overwrite,omitempty"`
+	// Image is the image of the init container seeding the data volume, it needs sh, wget and
+	// sha256sum. Defaults to the redis image.
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// S3RestoreSource defines an RDB snapshot in a bucket of an S3 compatible object storage
+type S3RestoreSource struct {
+	S3Storage `json:",inline"`
+	// Key is the key of the snapshot, under the prefix.
+	Key string `json:"key"`
+	// Checksum is the SHA-256 of the snapshot, as sha256:<hex>. The snapshot is not checked
+	// when empty.
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// PersistentVolumeClaimRestoreSource defines an RDB snapshot in a persistent volume claim
+type PersistentVolumeClaimRestoreSource struct {
+	ClaimName string `json:"claimName"`
+	// Path is the path of the snapshot in the volume. Defaults to dump.rdb.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// Restoring returns true while the Redis failover is seeded with the snapshot of spec.restore.
+func (r *RedisFailover) Restoring() bool {
+	if r.Spec.Restore == nil {
+		return false
+	}
+	condition := r.GetCondition(ConditionRestored)
+	return condition != nil && condition.Reason == ReasonRestoreInProgress
+}
+
+// Source describes the snapshot restored
+func (s *RestoreSettings) Source() string {
+	switch {
+	case s.BackupName != "":
+		return fmt.Sprintf("backup %s", s.BackupName)
+	case s.S3 != nil:
+		return fmt.Sprintf("s3://%s/%s", s.S3.Bucket, s.S3.ObjectKey())
+	case s.PersistentVolumeClaim != nil:
+		return fmt.Sprintf("persistentvolumeclaim %s:%s", s.PersistentVolumeClaim.ClaimName, s.PersistentVolumeClaim.Path)
+	}
+	return ""
+}
+
+// ObjectKey returns the key of the snapshot in the bucket
+func (s *S3RestoreSource) ObjectKey() string {
+	return strings.TrimPrefix(path.Join(s.Prefix, s.Key), "/")
+}
+
+func (r *RedisFailover) validateRestore() error {
+	restore := r.Spec.Restore
+	if restore == nil {
+		return nil
+	}
+
+	sources := 0
+	if restore.BackupName != "" {
+		sources++
+	}
+	if restore.S3 != nil {
+		sources++
+		storage := BackupStorage{S3: &restore.S3.S3Storage}
+		if err := storage.Validate(); err != nil {
+			return fmt.Errorf("restore: %w", err)
+		}
+		if restore.S3.Key == "" {
+			return errors.New("restore s3 source must include a key")
+		}
+		if restore.S3.Checksum != "" && !strings.HasPrefix(restore.S3.Checksum, "sha256:") {
+			return errors.New("restore s3 source checksum must be formatted as sha256:<hex>")
+		}
+	}
+	if restore.PersistentVolumeClaim != nil {
+		sources++
+		if restore.PersistentVolumeClaim.ClaimName == "" {
+			return errors.New("restore persistentVolumeClaim source must include a claimName")
+		}
+		if restore.PersistentVolumeClaim.Path == "" {
+			restore.PersistentVolumeClaim.Path = defaultRestorePath
+		}
+	}
+	if sources != 1 {
+		return errors.New("restore must include exactly one of backupName, s3 and persistentVolumeClaim")
+	}
+
+	if r.Bootstrapping() {
+		return errors.New("restore can't be used along with bootstrapNode")
+	}
+
+	if restore.Image == "" {
+		restore.Image = r.Spec.Redis.Image
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScheduleErrors(t *testing.T) {
+	tests := []struct {
+		expr          string
+		expectedError string
+	}{
+		{expr: "* * * *", expectedError: `expected 5 fields in "* * * *", found 4`},
+		{expr: "60 * * * *", expectedError: `invalid value "60" in minute field, expected 0-59`},
+		{expr: "* 24 * * *", expectedError: `invalid value "24" in hour field, expected 0-23`},
+		{expr: "* * 0 * *", expectedError: `invalid value "0" in day of month field, expected 1-31`},
+		{expr: "* * * foo *", expectedError: `invalid value "foo" in month field, expected 1-12`},
+		{expr: "* * * * 8", expectedError: `invalid value "8" in day of week field, expected 0-7`},
+		{expr: "*/0 * * * *", expectedError: `invalid step "0" in minute field`},
+		{expr: "30-10 * * * *", expectedError: `invalid range "30-10" in minute field`},
+		{expr: "@every 5m", expectedError: `expected 5 fields in "@every 5m", found 2`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			_, err := ParseSchedule(test.expr)
+			assert.EqualError(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// Wednesday
+	from := time.Date(2024, 1, 31, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr     string
+		from     time.Time
+		expected time.Time
+	}{
+		{expr: "* * * * *", from: from, expected: time.Date(2024, 1, 31, 10, 18, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", from: from, expected: time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC)},
+		{expr: "5/20 * * * *", from: from, expected: time.Date(2024, 1, 31, 10, 25, 0, 0, time.UTC)},
+		{expr: "0 3 * * *", from: from, expected: time.Date(2024, 2, 1, 3, 0, 0, 0, time.UTC)},
+		{expr: "@daily", from: from, expected: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "@hourly", from: from, expected: time.Date(2024, 1, 31, 11, 0, 0, 0, time.UTC)},
+		{expr: "@weekly", from: from, expected: time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{expr: "@monthly", from: from, expected: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "@yearly", from: from, expected: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 * * 7", from: from, expected: time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{expr: "30 2 * * mon-fri", from: from, expected: time.Date(2024, 2, 1, 2, 30, 0, 0, time.UTC)},
+		{expr: "0 12 * feb,mar sat", from: from, expected: time.Date(2024, 2, 3, 12, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 2 *", from: from, expected: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 31 * *", from: from, expected: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)},
+		// a day matching either the day of month or the day of week matches
+		{expr: "0 0 15 * fri", from: from, expected: time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 30 2 *", from: from, expected: time.Time{}},
+		// evaluated in UTC
+		{expr: "0 11 * * *", from: from.In(time.FixedZone("UTC+2", 2*60*60)), expected: time.Date(2024, 1, 31, 11, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			schedule, err := ParseSchedule(test.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, schedule.Next(test.from))
+		})
+	}
+}
+
+func TestScheduleLast(t *testing.T) {
+	assert := assert.New(t)
+
+	schedule, err := ParseSchedule("0 */6 * * *")
+	assert.NoError(err)
+
+	after := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	assert.Equal(time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC), schedule.Last(after, time.Date(2024, 1, 31, 15, 0, 0, 0, time.UTC)))
+	assert.Equal(time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC), schedule.Last(after, time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)))
+	assert.True(schedule.Last(after, time.Date(2024, 1, 31, 5, 59, 0, 0, time.UTC)).IsZero())
+}
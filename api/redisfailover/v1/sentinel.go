@@ -0,0 +1,34 @@
+package v1
+
+import (
+	"errors"
+	"regexp"
+)
+
+var sentinelMasterNameRE = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// SentinelMasterName returns the name the sentinels monitor the master under
+func (r *RedisFailover) SentinelMasterName() string {
+	if r.Spec.Sentinel.MasterName == "" {
+		return defaultSentinelMasterName
+	}
+	return r.Spec.Sentinel.MasterName
+}
+
+// SentinelPort returns the port the sentinels listen on
+func (r *RedisFailover) SentinelPort() int32 {
+	if r.Spec.Sentinel.Port <= 0 {
+		return defaultSentinelPort
+	}
+	return r.Spec.Sentinel.Port
+}
+
+func (r *RedisFailover) validateSentinel() error {
+	if r.Spec.Sentinel.MasterName != "" && !sentinelMasterNameRE.MatchString(r.Spec.Sentinel.MasterName) {
+		return errors.New("sentinel masterName can only contain letters, digits, dots, dashes and underscores")
+	}
+	if r.Spec.Sentinel.Port < 0 || r.Spec.Sentinel.Port > 65535 {
+		return errors.New("sentinel port must be between 1 and 65535")
+	}
+	return nil
+}
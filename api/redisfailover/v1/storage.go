@@ -0,0 +1,76 @@
+package v1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ClaimResizeState is the stage the expansion of a persistent volume claim is at
+type ClaimResizeState string
+
+// Claim resize states
+const (
+	// ClaimResizing is reported while the volume is expanded by the storage provider
+	ClaimResizing ClaimResizeState = "Resizing"
+	// ClaimFileSystemResizePending is reported once the volume is expanded, until its file system
+	// is. Offline expansions require the pod using the claim to be restarted.
+	ClaimFileSystemResizePending ClaimResizeState = "FileSystemResizePending"
+	// ClaimResized is reported once the claim has the requested capacity
+	ClaimResized ClaimResizeState = "Resized"
+)
+
+// StorageStatus reports the expansion of the persistent volume claims of the redis nodes
+type StorageStatus struct {
+	// Size is the storage request applied to every persistent volume claim. It can't be shrunk.
+	Size string `json:"size,omitempty"`
+	// Claims reports the expansion of every persistent volume claim.
+	// +optional
+	Claims []ClaimStatus `json:"claims,omitempty"`
+}
+
+// ClaimStatus reports the expansion of a persistent volume claim
+type ClaimStatus struct {
+	Name string `json:"name"`
+	// Capacity is the actual capacity of the volume bound to the claim.
+	Capacity string           `json:"capacity,omitempty"`
+	State    ClaimResizeState `json:"state,omitempty"`
+}
+
+// StorageRequest returns the storage requested for the persistent volume claims of the redis
+// nodes, or nil when they don't use one.
+func (r *RedisFailover) StorageRequest() *resource.Quantity {
+	if r.Spec.Redis.Storage.PersistentVolumeClaim == nil {
+		return nil
+	}
+	request, ok := r.Spec.Redis.Storage.PersistentVolumeClaim.Spec.Resources.Requests[corev1.ResourceStorage]
+	if !ok {
+		return nil
+	}
+	return &request
+}
+
+func (r *RedisFailover) validateStorageSize() error {
+	if r.Status.Storage == nil || r.Status.Storage.Size == "" {
+		return nil
+	}
+	size, err := resource.ParseQuantity(r.Status.Storage.Size)
+	if err != nil {
+		return nil
+	}
+	return r.ValidateStorageSize(size)
+}
+
+// ValidateStorageSize returns an error when the storage requested is smaller than the size the
+// persistent volume claims of the redis nodes already have, as they can't be shrunk.
+func (r *RedisFailover) ValidateStorageSize(size resource.Quantity) error {
+	request := r.StorageRequest()
+	if request == nil {
+		return nil
+	}
+	if request.Cmp(size) < 0 {
+		return fmt.Errorf("storage persistentVolumeClaim can't be shrunk from %s to %s", size.String(), request.String())
+	}
+	return nil
+}
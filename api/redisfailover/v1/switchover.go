@@ -0,0 +1,13 @@
+package v1
+
+// SwitchoverAnnotation requests a manual switchover of the master. Its value is the name of
+// the replica pod to promote; when empty, the most up to date replica is promoted.
+// The operator removes the annotation once the switchover has been attempted.
+const SwitchoverAnnotation = "redisfailovers.databases.spotahome.com/switchover"
+
+// SwitchoverRequested returns the pod requested to become master through the switchover
+// annotation, and whether a switchover has been requested at all.
+func (r *RedisFailover) SwitchoverRequested() (string, bool) {
+	target, ok := r.Annotations[SwitchoverAnnotation]
+	return target, ok
+}
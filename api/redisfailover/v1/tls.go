@@ -0,0 +1,11 @@
+package v1
+
+// TLSEnabled returns true when the redis and sentinel traffic is encrypted with TLS.
+func (r *RedisFailover) TLSEnabled() bool {
+	return r.Spec.TLS != nil
+}
+
+// TLSManaged returns true when the TLS certificates are generated and renewed by the operator.
+func (r *RedisFailover) TLSManaged() bool {
+	return r.TLSEnabled() && r.Spec.TLS.Managed
+}
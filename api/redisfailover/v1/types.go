@@ -12,8 +12,11 @@ import (
 // +kubebuilder:printcolumn:name="NAME",type="string",JSONPath=".metadata.name"
 // +kubebuilder:printcolumn:name="REDIS",type="integer",JSONPath=".spec.redis.replicas"
 // +kubebuilder:printcolumn:name="SENTINELS",type="integer",JSONPath=".spec.sentinel.replicas"
+// +kubebuilder:printcolumn:name="MASTER",type="string",JSONPath=".status.master.podName"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.state"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:singular=redisfailover,path=redisfailovers,shortName=rf,scope=Namespaced
+// +kubebuilder:subresource:scale:specpath=.spec.redis.replicas,statuspath=.status.currentReplicas,selectorpath=.status.selector
 type RedisFailover struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -28,6 +31,19 @@ type RedisFailoverSpec struct {
 	Auth           AuthSettings       `json:"auth,omitempty"`
 	LabelWhitelist []string           `json:"labelWhitelist,omitempty"`
 	BootstrapNode  *BootstrapSettings `json:"bootstrapNode,omitempty"`
+	TLS            *TLSSettings       `json:"tls,omitempty"`
+	Backup         *BackupSettings    `json:"backup,omitempty"`
+	Restore        *RestoreSettings   `json:"restore,omitempty"`
+	// IPFamily is the IP family the redis and sentinel nodes are addressed with on dual-stack
+	// clusters, and the primary one of the services. Defaults to the family of the pod IPs.
+	// +kubebuilder:validation:Enum=IPv4;IPv6
+	// +optional
+	IPFamily corev1.IPFamily `json:"ipFamily,omitempty"`
+	// AnnounceHostnames replicates the redis nodes and has the sentinels monitor them by their
+	// stable names under the headless service of the statefulset instead of their pod IPs.
+	// Requires redis 6.2 or later.
+	// +optional
+	AnnounceHostnames bool `json:"announceHostnames,omitempty"`
 }
 
 // RedisCommandRename defines the specification of a "rename-command" configuration option
@@ -49,6 +65,10 @@ type RedisSettings struct {
 	ShutdownConfigMap             string                            `json:"shutdownConfigMap,omitempty"`
 	StartupConfigMap              string                            `json:"startupConfigMap,omitempty"`
 	Storage                       RedisStorage                      `json:"storage,omitempty"`
+	Persistence                   *PersistenceSettings              `json:"persistence,omitempty"`
+	MaxMemoryPolicy               MaxMemoryPolicy                   `json:"maxMemoryPolicy,omitempty"`
+	MaxMemoryPercent              *int32                            `json:"maxMemoryPercent,omitempty"`
+	DriftPolicy                   DriftPolicy                       `json:"driftPolicy,omitempty"`
 	InitContainers                []corev1.Container                `json:"initContainers,omitempty"`
 	Exporter                      Exporter                          `json:"exporter,omitempty"`
 	ExtraContainers               []corev1.Container                `json:"extraContainers,omitempty"`
@@ -112,11 +132,50 @@ type SentinelSettings struct {
 	CustomReadinessProbe       *corev1.Probe                     `json:"customReadinessProbe,omitempty"`
 	CustomStartupProbe         *corev1.Probe                     `json:"customStartupProbe,omitempty"`
 	DisablePodDisruptionBudget bool                              `json:"disablePodDisruptionBudget,omitempty"`
+	// MasterName is the name the sentinels monitor the master under, the one the clients
+	// query it with. Defaults to mymaster.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9._-]+$`
+	// +optional
+	MasterName string `json:"masterName,omitempty"`
+	// Port is the port the sentinels listen on. Defaults to 26379.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
 }
 
 // AuthSettings contains settings about auth
 type AuthSettings struct {
 	SecretPath string `json:"secretPath,omitempty"`
+	// RotationGracePeriodSeconds is how long the previous password keeps being accepted once the
+	// redis nodes were given the new one, so the clients can switch to it. Defaults to 300.
+	RotationGracePeriodSeconds int32 `json:"rotationGracePeriodSeconds,omitempty"`
+	// Users are the ACL users applied to every redis node. The users that are not declared are
+	// removed, except the default user and the ones used by the operator and the probes.
+	Users []RedisUser `json:"users,omitempty"`
+}
+
+// RedisUser declares an ACL user
+type RedisUser struct {
+	Name string `json:"name"`
+	// SecretPath is the name of the Secret holding the password of the user in its password field
+	SecretPath string `json:"secretPath"`
+	// Commands are the commands and command categories the user is allowed or denied, e.g. +@read or -flushall
+	Commands []string `json:"commands,omitempty"`
+	// Keys are the key patterns the user can access, e.g. cache:*
+	Keys []string `json:"keys,omitempty"`
+	// Channels are the Pub/Sub channel patterns the user can access
+	Channels []string `json:"channels,omitempty"`
+}
+
+// TLSSettings contains settings about the encryption of the redis and sentinel traffic
+type TLSSettings struct {
+	// SecretName is the name of the Secret holding the certificate (tls.crt), its private
+	// key (tls.key) and the CA certificate (ca.crt), as issued by cert-manager.
+	SecretName string `json:"secretName,omitempty"`
+	// Managed makes the operator generate a CA and the certificate in Secrets it owns and
+	// renew them before they expire. SecretName defaults to rftls-<name> when managed.
+	Managed bool `json:"managed,omitempty"`
 }
 
 // BootstrapSettings contains settings about a potential bootstrap node
@@ -147,6 +206,14 @@ type RedisStorage struct {
 	KeepAfterDeletion     bool                           `json:"keepAfterDeletion,omitempty"`
 	EmptyDir              *corev1.EmptyDirVolumeSource   `json:"emptyDir,omitempty"`
 	PersistentVolumeClaim *EmbeddedPersistentVolumeClaim `json:"persistentVolumeClaim,omitempty"`
+	// PVCRetentionPolicy defines whether the persistent volume claims are kept when the redis
+	// nodes are scaled down and when the Redis failover is deleted.
+	// +optional
+	PVCRetentionPolicy *PVCRetentionPolicy `json:"pvcRetentionPolicy,omitempty"`
+	// FinalSnapshot takes a snapshot of the dataset when the Redis failover is deleted, before its
+	// persistent volume claims are.
+	// +optional
+	FinalSnapshot *FinalSnapshotSettings `json:"finalSnapshot,omitempty"`
 }
 
 // EmbeddedPersistentVolumeClaim is an embedded version of k8s.io/api/core/v1.PersistentVolumeClaim.
@@ -206,8 +273,57 @@ type RedisFailoverList struct {
 	Items []RedisFailover `json:"items"`
 }
 
+// RedisFailoverStatus represents the observed state of a Redis failover
 type RedisFailoverStatus struct {
 	State       string `json:"state,omitempty"`
 	LastChanged string `json:"lastChanged,omitempty"`
 	Message     string `json:"message,omitempty"`
+	// ObservedGeneration is the most recent generation reconciled by the operator.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions describe the current state of the failover: MasterAvailable, ReplicasInSync,
+	// SentinelsInQuorum, ResourcesReconciled and Upgrading.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Master is the Redis pod currently serving as master.
+	Master *RedisMasterStatus `json:"master,omitempty"`
+	// Replicas holds the replication state of every Redis replica.
+	Replicas []RedisReplicaStatus `json:"replicas,omitempty"`
+	// RedisVersion is the version reported by the running Redis servers.
+	RedisVersion string `json:"redisVersion,omitempty"`
+	// FailoverEpoch is incremented on every promotion done by the operator. The master pod is
+	// labelled with the epoch it was promoted at, so a stale master can be fenced.
+	FailoverEpoch int64 `json:"failoverEpoch,omitempty"`
+	// LastBackupScheduleTime is the last time a backup was scheduled at.
+	LastBackupScheduleTime *metav1.Time `json:"lastBackupScheduleTime,omitempty"`
+	// CurrentReplicas is the number of redis pods of the statefulset, reported to the scale subresource.
+	CurrentReplicas int32 `json:"currentReplicas,omitempty"`
+	// Selector is the label selector of the redis pods, reported to the scale subresource.
+	Selector string `json:"selector,omitempty"`
+	// Storage reports the expansion of the persistent volume claims of the redis nodes.
+	// +optional
+	Storage *StorageStatus `json:"storage,omitempty"`
+	// ACLUsersChecksum is the checksum of the ACL rules of the users, the hashes of their
+	// passwords included, applied to every redis node at the last sync. The changes made by a
+	// sync are only reported as drift while it is unchanged.
+	ACLUsersChecksum string `json:"aclUsersChecksum,omitempty"`
+}
+
+// RedisMasterStatus identifies the Redis master
+type RedisMasterStatus struct {
+	PodName string `json:"podName,omitempty"`
+	IP      string `json:"ip,omitempty"`
+}
+
+// RedisReplicaStatus represents the replication state of a Redis replica
+type RedisReplicaStatus struct {
+	PodName string `json:"podName,omitempty"`
+	IP      string `json:"ip,omitempty"`
+	// MasterLinkStatus is the master_link_status reported by the replica, "up" or "down".
+	MasterLinkStatus string `json:"masterLinkStatus,omitempty"`
+	// Lag is the number of bytes the replica offset is behind the master offset.
+	Lag int64 `json:"lag"`
+	// SyncInProgress is true while the replica is performing a full synchronization.
+	SyncInProgress bool `json:"syncInProgress"`
 }
@@ -29,10 +29,62 @@ func (r *RedisFailover) Validate() error {
 		r.Spec.Redis.CustomConfig = deduplicateStr(append(defaultRedisCustomConfig, r.Spec.Redis.CustomConfig...))
 	}
 
+	if err := r.validateUsers(); err != nil {
+		return err
+	}
+
+	if err := r.validateBackup(); err != nil {
+		return err
+	}
+
+	if r.TLSEnabled() && !r.TLSManaged() && r.Spec.TLS.SecretName == "" {
+		return errors.New("tls.secretName must be provided when TLS is enabled")
+	}
+
 	if r.Spec.Redis.Image == "" {
 		r.Spec.Redis.Image = defaultImage
 	}
 
+	if err := r.validateRestore(); err != nil {
+		return err
+	}
+
+	if err := r.validatePersistence(); err != nil {
+		return err
+	}
+
+	if err := r.validateMaxMemory(); err != nil {
+		return err
+	}
+
+	if err := r.validateDriftPolicy(); err != nil {
+		return err
+	}
+
+	if err := r.validateStorageSize(); err != nil {
+		return err
+	}
+
+	if err := r.validatePVCRetentionPolicy(); err != nil {
+		return err
+	}
+
+	if err := r.validateFinalSnapshot(); err != nil {
+		return err
+	}
+
+	if err := r.validateIPFamily(); err != nil {
+		return err
+	}
+
+	if err := r.validateAnnounceHostnames(); err != nil {
+		return err
+	}
+
+	if err := r.validateSentinel(); err != nil {
+		return err
+	}
+
 	if r.Spec.Sentinel.Image == "" {
 		r.Spec.Sentinel.Image = defaultImage
 	}
@@ -61,9 +113,11 @@ func (r *RedisFailover) Validate() error {
 		r.Spec.Sentinel.CustomConfig = defaultSentinelCustomConfig
 	}
 
-	r.Status = RedisFailoverStatus{
-		State: HealthyState,
-	}
+	// Only the outcome of the reconcile is reset, the rest of the status is what was observed
+	// before and is written back as is by the reconciles failing before the redis nodes are
+	// checked again
+	r.Status.State = HealthyState
+	r.Status.Message = ""
 
 	return nil
 }
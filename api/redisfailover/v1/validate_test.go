@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -14,8 +16,11 @@ func TestValidate(t *testing.T) {
 		rfBootstrapNode        *BootstrapSettings
 		rfRedisCustomConfig    []string
 		rfSentinelCustomConfig []string
+		rfTLS                  *TLSSettings
+		rfUsers                []RedisUser
 		expectedError          string
 		expectedBootstrapNode  *BootstrapSettings
+		expectedTLS            *TLSSettings
 	}{
 		{
 			name:   "populates default values",
@@ -65,6 +70,49 @@ func TestValidate(t *testing.T) {
 			rfBootstrapNode:       &BootstrapSettings{Host: "127.0.0.1"},
 			expectedBootstrapNode: &BootstrapSettings{Host: "127.0.0.1", Port: "6379"},
 		},
+		{
+			name:          "errors on TLS without secret",
+			rfName:        "test",
+			rfTLS:         &TLSSettings{},
+			expectedError: "tls.secretName must be provided when TLS is enabled",
+		},
+		{
+			name:        "Accepts managed TLS without secret",
+			rfName:      "test",
+			rfTLS:       &TLSSettings{Managed: true},
+			expectedTLS: &TLSSettings{Managed: true},
+		},
+		{
+			name:   "Accepts ACL users",
+			rfName: "test",
+			rfUsers: []RedisUser{
+				{Name: "app", SecretPath: "app-password", Commands: []string{"+@read", "-flushall"}, Keys: []string{"cache:*"}},
+			},
+		},
+		{
+			name:          "errors on reserved ACL user",
+			rfName:        "test",
+			rfUsers:       []RedisUser{{Name: "redis-operator", SecretPath: "password"}},
+			expectedError: "auth.users name redis-operator is reserved",
+		},
+		{
+			name:          "errors on ACL user without secret",
+			rfName:        "test",
+			rfUsers:       []RedisUser{{Name: "app"}},
+			expectedError: "auth.users app must have a secretPath",
+		},
+		{
+			name:          "errors on ACL user with invalid command",
+			rfName:        "test",
+			rfUsers:       []RedisUser{{Name: "app", SecretPath: "app-password", Commands: []string{"@read"}}},
+			expectedError: "auth.users app command @read must start with + or -",
+		},
+		{
+			name:          "errors on ACL user with whitespace in a rule",
+			rfName:        "test",
+			rfUsers:       []RedisUser{{Name: "app", SecretPath: "app-password", Keys: []string{"cache:* ~other"}}},
+			expectedError: "auth.users app has an invalid rule \"cache:* ~other\"",
+		},
 	}
 
 	for _, test := range tests {
@@ -73,6 +121,8 @@ func TestValidate(t *testing.T) {
 			rf := generateRedisFailover(test.rfName, test.rfBootstrapNode)
 			rf.Spec.Redis.CustomConfig = test.rfRedisCustomConfig
 			rf.Spec.Sentinel.CustomConfig = test.rfSentinelCustomConfig
+			rf.Spec.TLS = test.rfTLS
+			rf.Spec.Auth.Users = test.rfUsers
 
 			err := rf.Validate()
 
@@ -119,6 +169,8 @@ func TestValidate(t *testing.T) {
 							},
 						},
 						BootstrapNode: test.expectedBootstrapNode,
+						TLS:           test.expectedTLS,
+						Auth:          AuthSettings{Users: test.rfUsers},
 					},
 					Status: RedisFailoverStatus{
 						State:       HealthyState,
@@ -135,3 +187,839 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateKeepsObservedStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRedisFailover("test", nil)
+	observed := RedisFailoverStatus{
+		State:              NotHealthyState,
+		Message:            "no master found",
+		LastChanged:        "2024-01-31T03:00:00Z",
+		ObservedGeneration: 3,
+		Master:             &RedisMasterStatus{PodName: "rfr-test-0", IP: "10.0.0.1"},
+		Replicas:           []RedisReplicaStatus{{PodName: "rfr-test-1", IP: "10.0.0.2", MasterLinkStatus: "up"}},
+		RedisVersion:       "7.2.4",
+		FailoverEpoch:      2,
+		CurrentReplicas:    3,
+		Selector:           "app.kubernetes.io/component=redis",
+		Conditions:         []metav1.Condition{{Type: "Ready", Status: metav1.ConditionFalse, Reason: "NoMaster"}},
+	}
+	rf.Status = observed
+
+	assert.NoError(rf.Validate())
+	observed.State = HealthyState
+	observed.Message = ""
+	assert.Equal(observed, rf.Status)
+}
+
+func TestValidateBackup(t *testing.T) {
+	storage := BackupStorage{S3: &S3Storage{Endpoint: "http://minio:9000", Bucket: "backups", SecretName: "s3-credentials"}}
+
+	tests := []struct {
+		name          string
+		backup        *BackupSettings
+		expectedError string
+	}{
+		{
+			name: "no backups",
+		},
+		{
+			name:   "valid backup settings",
+			backup: &BackupSettings{Schedule: "0 3 * * *", Storage: storage},
+		},
+		{
+			name:          "invalid schedule",
+			backup:        &BackupSettings{Schedule: "0 3 * *", Storage: storage},
+			expectedError: `backup schedule: expected 5 fields in "0 3 * *", found 4`,
+		},
+		{
+			name:          "missing s3 storage",
+			backup:        &BackupSettings{Schedule: "0 3 * * *"},
+			expectedError: "backup storage must include s3",
+		},
+		{
+			name:          "incomplete s3 storage",
+			backup:        &BackupSettings{Schedule: "0 3 * * *", Storage: BackupStorage{S3: &S3Storage{Endpoint: "http://minio:9000"}}},
+			expectedError: "backup s3 storage must include an endpoint, a bucket and a secretName",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.Backup = test.backup
+
+			err := rf.Validate()
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateRestore(t *testing.T) {
+	s3Storage := S3Storage{Endpoint: "http://minio:9000", Bucket: "backups", SecretName: "s3-credentials"}
+
+	tests := []struct {
+		name          string
+		restore       *RestoreSettings
+		bootstrapNode *BootstrapSettings
+		expectedError string
+	}{
+		{
+			name:    "restore from a backup",
+			restore: &RestoreSettings{BackupName: "test-202401310300"},
+		},
+		{
+			name:    "restore from s3",
+			restore: &RestoreSettings{S3: &S3RestoreSource{S3Storage: s3Storage, Key: "test/dump.rdb"}},
+		},
+		{
+			name:    "restore from a persistent volume claim",
+			restore: &RestoreSettings{PersistentVolumeClaim: &PersistentVolumeClaimRestoreSource{ClaimName: "snapshots"}},
+		},
+		{
+			name:          "no source",
+			restore:       &RestoreSettings{Overwrite: true},
+			expectedError: "restore must include exactly one of backupName, s3 and persistentVolumeClaim",
+		},
+		{
+			name:          "several sources",
+			restore:       &RestoreSettings{BackupName: "test-202401310300", PersistentVolumeClaim: &PersistentVolumeClaimRestoreSource{ClaimName: "snapshots"}},
+			expectedError: "restore must include exactly one of backupName, s3 and persistentVolumeClaim",
+		},
+		{
+			name:          "s3 source without key",
+			restore:       &RestoreSettings{S3: &S3RestoreSource{S3Storage: s3Storage}},
+			expectedError: "restore s3 source must include a key",
+		},
+		{
+			name:          "s3 source with an md5 checksum",
+			restore:       &RestoreSettings{S3: &S3RestoreSource{S3Storage: s3Storage, Key: "test/dump.rdb", Checksum: "md5:d41d8cd98f00b204e9800998ecf8427e"}},
+			expectedError: "restore s3 source checksum must be formatted as sha256:<hex>",
+		},
+		{
+			name:          "incomplete s3 source",
+			restore:       &RestoreSettings{S3: &S3RestoreSource{Key: "test/dump.rdb"}},
+			expectedError: "restore: backup s3 storage must include an endpoint, a bucket and a secretName",
+		},
+		{
+			name:          "persistent volume claim source without claim",
+			restore:       &RestoreSettings{PersistentVolumeClaim: &PersistentVolumeClaimRestoreSource{Path: "dump.rdb"}},
+			expectedError: "restore persistentVolumeClaim source must include a claimName",
+		},
+		{
+			name:          "restore while bootstrapping",
+			restore:       &RestoreSettings{BackupName: "test-202401310300"},
+			bootstrapNode: &BootstrapSettings{Host: "127.0.0.1"},
+			expectedError: "restore can't be used along with bootstrapNode",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := generateRedisFailover("test", test.bootstrapNode)
+			rf.Spec.Restore = test.restore
+
+			err := rf.Validate()
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, defaultImage, rf.Spec.Restore.Image)
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateRestoreDefaults(t *testing.T) {
+	rf := generateRedisFailover("test", nil)
+	rf.Spec.Redis.Image = "redis:7.2.12"
+	rf.Spec.Restore = &RestoreSettings{PersistentVolumeClaim: &PersistentVolumeClaimRestoreSource{ClaimName: "snapshots"}}
+
+	assert.NoError(t, rf.Validate())
+	assert.Equal(t, "dump.rdb", rf.Spec.Restore.PersistentVolumeClaim.Path)
+	assert.Equal(t, "redis:7.2.12", rf.Spec.Restore.Image)
+}
+
+func TestValidateFinalSnapshot(t *testing.T) {
+	s3Storage := &S3Storage{Endpoint: "http://minio:9000", Bucket: "backups", SecretName: "s3-credentials"}
+
+	tests := []struct {
+		name              string
+		finalSnapshot     *FinalSnapshotSettings
+		keepAfterDeletion bool
+		retentionPolicy   *PVCRetentionPolicy
+		emptyDir          bool
+		expectedError     string
+	}{
+		{
+			name: "no final snapshot",
+		},
+		{
+			name:          "final snapshot to s3",
+			finalSnapshot: &FinalSnapshotSettings{S3: s3Storage},
+		},
+		{
+			name:          "final snapshot in the retained volume claim",
+			finalSnapshot: &FinalSnapshotSettings{RetainVolumeClaim: true},
+		},
+		{
+			name:              "final snapshot along with keepAfterDeletion",
+			finalSnapshot:     &FinalSnapshotSettings{S3: s3Storage},
+			keepAfterDeletion: true,
+			expectedError:     "storage finalSnapshot can't be used along with keepAfterDeletion",
+		},
+		{
+			name:            "final snapshot along with retained volume claims",
+			finalSnapshot:   &FinalSnapshotSettings{S3: s3Storage},
+			retentionPolicy: &PVCRetentionPolicy{WhenDeleted: RetainPVCRetentionPolicyType},
+			expectedError:   "storage finalSnapshot can't be used along with pvcRetentionPolicy whenDeleted Retain",
+		},
+		{
+			name:          "no target",
+			finalSnapshot: &FinalSnapshotSettings{},
+			expectedError: "storage finalSnapshot must include exactly one of s3 and retainVolumeClaim",
+		},
+		{
+			name:          "several targets",
+			finalSnapshot: &FinalSnapshotSettings{S3: s3Storage, RetainVolumeClaim: true},
+			expectedError: "storage finalSnapshot must include exactly one of s3 and retainVolumeClaim",
+		},
+		{
+			name:          "incomplete s3 storage",
+			finalSnapshot: &FinalSnapshotSettings{S3: &S3Storage{Endpoint: "http://minio:9000"}},
+			expectedError: "storage finalSnapshot: backup s3 storage must include an endpoint, a bucket and a secretName",
+		},
+		{
+			name:          "retained volume claim without persistent storage",
+			finalSnapshot: &FinalSnapshotSettings{RetainVolumeClaim: true},
+			emptyDir:      true,
+			expectedError: "storage finalSnapshot retainVolumeClaim requires a persistentVolumeClaim storage",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := generateRedisFailover("test", nil)
+			if !test.emptyDir {
+				rf.Spec.Redis.Storage.PersistentVolumeClaim = &EmbeddedPersistentVolumeClaim{}
+			}
+			rf.Spec.Redis.Storage.KeepAfterDeletion = test.keepAfterDeletion
+			rf.Spec.Redis.Storage.PVCRetentionPolicy = test.retentionPolicy
+			rf.Spec.Redis.Storage.FinalSnapshot = test.finalSnapshot
+
+			err := rf.Validate()
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, test.finalSnapshot != nil, rf.FinalSnapshotEnabled())
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidatePVCRetentionPolicy(t *testing.T) {
+	tests := []struct {
+		name                string
+		retentionPolicy     *PVCRetentionPolicy
+		keepAfterDeletion   bool
+		expectedWhenScaled  bool
+		expectedWhenDeleted bool
+		expectedError       string
+	}{
+		{
+			name:                "no policy",
+			expectedWhenDeleted: true,
+		},
+		{
+			name:              "no policy, keep after deletion",
+			keepAfterDeletion: true,
+		},
+		{
+			name:                "delete when scaled",
+			retentionPolicy:     &PVCRetentionPolicy{WhenScaled: DeletePVCRetentionPolicyType},
+			expectedWhenScaled:  true,
+			expectedWhenDeleted: true,
+		},
+		{
+			name:            "retain when deleted",
+			retentionPolicy: &PVCRetentionPolicy{WhenScaled: RetainPVCRetentionPolicyType, WhenDeleted: RetainPVCRetentionPolicyType},
+		},
+		{
+			name:            "unknown policy",
+			retentionPolicy: &PVCRetentionPolicy{WhenScaled: "Keep"},
+			expectedError:   "storage pvcRetentionPolicy must be either Retain or Delete",
+		},
+		{
+			name:              "delete when deleted along with keepAfterDeletion",
+			retentionPolicy:   &PVCRetentionPolicy{WhenDeleted: DeletePVCRetentionPolicyType},
+			keepAfterDeletion: true,
+			expectedError:     "storage pvcRetentionPolicy whenDeleted Delete can't be used along with keepAfterDeletion",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.Redis.Storage.PersistentVolumeClaim = &EmbeddedPersistentVolumeClaim{}
+			rf.Spec.Redis.Storage.PVCRetentionPolicy = test.retentionPolicy
+			rf.Spec.Redis.Storage.KeepAfterDeletion = test.keepAfterDeletion
+
+			err := rf.Validate()
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedWhenScaled, rf.DeleteClaimsWhenScaled())
+				assert.Equal(t, test.expectedWhenDeleted, rf.DeleteClaimsWhenDeleted())
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateStorageSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       string
+		size          string
+		expectedError string
+	}{
+		{
+			name:    "no size applied yet",
+			request: "1Gi",
+		},
+		{
+			name:    "same size",
+			request: "1Gi",
+			size:    "1Gi",
+		},
+		{
+			name:    "expansion",
+			request: "2Gi",
+			size:    "1Gi",
+		},
+		{
+			name:          "shrink",
+			request:       "512Mi",
+			size:          "1Gi",
+			expectedError: "storage persistentVolumeClaim can't be shrunk from 1Gi to 512Mi",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.Redis.Storage.PersistentVolumeClaim = &EmbeddedPersistentVolumeClaim{
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(test.request)},
+					},
+				},
+			}
+			if test.size != "" {
+				rf.Status.Storage = &StorageStatus{Size: test.size}
+			}
+
+			err := rf.Validate()
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+				// The storage status is kept
+				assert.Equal(t, test.size != "", rf.Status.Storage != nil)
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidatePersistence(t *testing.T) {
+	rdb := &RDBSettings{SavePoints: []RDBSavePoint{{Seconds: 900, Changes: 1}, {Seconds: 60, Changes: 1000}}}
+	minSize := resource.MustParse("64Mi")
+
+	tests := []struct {
+		name              string
+		persistence       *PersistenceSettings
+		emptyDir          bool
+		keepAfterDeletion bool
+		customConfig      []string
+		expectedConfig    []string
+		expectedError     string
+	}{
+		{
+			name: "no persistence settings",
+		},
+		{
+			name:           "rdb save points",
+			persistence:    &PersistenceSettings{RDB: rdb},
+			expectedConfig: []string{"save 900 1 60 1000", "appendonly no", "appendfsync everysec"},
+		},
+		{
+			name:           "aof only",
+			persistence:    &PersistenceSettings{AOF: &AOFSettings{Enabled: true, Fsync: AppendFsyncNo, RewriteMinSize: &minSize}},
+			expectedConfig: []string{`save ""`, "appendonly yes", "appendfsync no", "auto-aof-rewrite-min-size 67108864"},
+		},
+		{
+			name:           "no persistence",
+			persistence:    &PersistenceSettings{Disabled: true},
+			emptyDir:       true,
+			expectedConfig: []string{`save ""`, "appendonly no"},
+		},
+		{
+			name:          "disabled along with rdb",
+			persistence:   &PersistenceSettings{Disabled: true, RDB: rdb},
+			expectedError: "persistence disabled can't be used along with rdb or aof",
+		},
+		{
+			name:          "invalid save point",
+			persistence:   &PersistenceSettings{RDB: &RDBSettings{SavePoints: []RDBSavePoint{{Seconds: 900}}}},
+			expectedError: "persistence rdb savePoints must have positive seconds and changes",
+		},
+		{
+			name:          "invalid fsync",
+			persistence:   &PersistenceSettings{AOF: &AOFSettings{Enabled: true, Fsync: "sometimes"}},
+			expectedError: "persistence aof fsync must be one of always, everysec and no",
+		},
+		{
+			name:          "persistence with emptyDir",
+			persistence:   &PersistenceSettings{RDB: rdb},
+			emptyDir:      true,
+			expectedError: "persistence requires a persistentVolumeClaim storage, the emptyDir one is lost along with its pod",
+		},
+		{
+			name:              "no persistence with keepAfterDeletion",
+			persistence:       &PersistenceSettings{Disabled: true},
+			keepAfterDeletion: true,
+			expectedError:     "storage keepAfterDeletion can't be used along with persistence disabled",
+		},
+		{
+			name:          "persistence along with customConfig",
+			persistence:   &PersistenceSettings{RDB: rdb},
+			customConfig:  []string{"appendonly yes"},
+			expectedError: "customConfig can't set appendonly along with persistence",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := generateRedisFailover("test", nil)
+			if !test.emptyDir {
+				rf.Spec.Redis.Storage.PersistentVolumeClaim = &EmbeddedPersistentVolumeClaim{}
+			}
+			rf.Spec.Redis.Storage.KeepAfterDeletion = test.keepAfterDeletion
+			rf.Spec.Redis.Persistence = test.persistence
+			rf.Spec.Redis.CustomConfig = test.customConfig
+
+			err := rf.Validate()
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedConfig, rf.PersistenceConfig())
+				// The persistence configuration is applied before the custom one
+				assert.Equal(t, append(test.expectedConfig, rf.Spec.Redis.CustomConfig...), rf.RedisConfig())
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateMaxMemory(t *testing.T) {
+	percent := func(percent int32) *int32 {
+		return &percent
+	}
+
+	tests := []struct {
+		name           string
+		memoryLimit    string
+		policy         MaxMemoryPolicy
+		percent        *int32
+		customConfig   []string
+		expectedConfig []string
+		expectedError  string
+	}{
+		{
+			name:           "no maxmemory settings",
+			memoryLimit:    "1Gi",
+			expectedConfig: []string{},
+		},
+		{
+			name:           "policy with the default percent",
+			memoryLimit:    "1Gi",
+			policy:         "allkeys-lru",
+			expectedConfig: []string{"maxmemory 805306368", "maxmemory-policy allkeys-lru"},
+		},
+		{
+			name:           "percent",
+			memoryLimit:    "1Gi",
+			percent:        percent(50),
+			expectedConfig: []string{"maxmemory 536870912"},
+		},
+		{
+			name:           "policy without memory limit",
+			policy:         "noeviction",
+			expectedConfig: []string{"maxmemory-policy noeviction"},
+		},
+		{
+			name:          "unknown policy",
+			memoryLimit:   "1Gi",
+			policy:        "allkeys-oldest",
+			expectedError: "maxMemoryPolicy allkeys-oldest is not supported",
+		},
+		{
+			name:          "percent out of range",
+			memoryLimit:   "1Gi",
+			percent:       percent(120),
+			expectedError: "maxMemoryPercent must be between 1 and 100",
+		},
+		{
+			name:          "percent without memory limit",
+			percent:       percent(50),
+			expectedError: "maxMemoryPercent requires a memory limit on the redis resources",
+		},
+		{
+			name:          "maxmemory along with customConfig",
+			memoryLimit:   "1Gi",
+			policy:        "allkeys-lfu",
+			customConfig:  []string{"maxmemory 100mb"},
+			expectedError: "customConfig can't set maxmemory along with maxMemoryPolicy or maxMemoryPercent",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := generateRedisFailover("test", nil)
+			if test.memoryLimit != "" {
+				rf.Spec.Redis.Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(test.memoryLimit)}
+			}
+			rf.Spec.Redis.MaxMemoryPolicy = test.policy
+			rf.Spec.Redis.MaxMemoryPercent = test.percent
+			rf.Spec.Redis.CustomConfig = test.customConfig
+
+			err := rf.Validate()
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedConfig, rf.MaxMemoryConfig())
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateCustomConfig(t *testing.T) {
+	tests := []struct {
+		name                  string
+		image                 string
+		redisVersion          string
+		customConfig          []string
+		expectedMajorVersion  int
+		expectedRuntimeConfig []string
+		expectedRestartConfig []string
+		expectedUnknownConfig []string
+	}{
+		{
+			name:                  "runtime and restart parameters of the default image",
+			customConfig:          []string{"maxclients 100", "Databases 32", "list-max-listpack-size -2"},
+			expectedMajorVersion:  7,
+			expectedRuntimeConfig: []string{"replica-priority 100", "maxclients 100", "list-max-listpack-size -2"},
+			expectedRestartConfig: []string{"Databases 32"},
+			expectedUnknownConfig: []string{},
+		},
+		{
+			name:                  "protected parameters of redis 7",
+			customConfig:          []string{`dir "/data/redis"`, "dbfilename dump.rdb"},
+			expectedMajorVersion:  7,
+			expectedRuntimeConfig: []string{"replica-priority 100"},
+			expectedRestartConfig: []string{`dir "/data/redis"`, "dbfilename dump.rdb"},
+			expectedUnknownConfig: []string{},
+		},
+		{
+			name:                  "runtime parameters of redis 6",
+			image:                 "redis:6.2.14",
+			customConfig:          []string{`dir "/data/redis"`, "dbfilename dump.rdb"},
+			expectedMajorVersion:  6,
+			expectedRuntimeConfig: []string{"replica-priority 100", `dir "/data/redis"`, "dbfilename dump.rdb"},
+			expectedRestartConfig: []string{},
+			expectedUnknownConfig: []string{},
+		},
+		{
+			name:                  "parameter unknown to the image version",
+			image:                 "registry.example.com:5000/redis:6.2.14-alpine",
+			customConfig:          []string{"list-max-listpack-size -2"},
+			expectedMajorVersion:  6,
+			expectedRuntimeConfig: []string{"replica-priority 100"},
+			expectedRestartConfig: []string{},
+			expectedUnknownConfig: []string{"list-max-listpack-size"},
+		},
+		{
+			name:                  "typo in a parameter",
+			customConfig:          []string{"maxclients 100", "maxmemory-polcy allkeys-lru"},
+			expectedMajorVersion:  7,
+			expectedRuntimeConfig: []string{"replica-priority 100", "maxclients 100"},
+			expectedRestartConfig: []string{},
+			expectedUnknownConfig: []string{"maxmemory-polcy"},
+		},
+		{
+			name:                  "version reported by the redis nodes",
+			image:                 "redis@sha256:0123456789abcdef",
+			redisVersion:          "6.2.14",
+			customConfig:          []string{"gopher-enabled no"},
+			expectedMajorVersion:  6,
+			expectedRuntimeConfig: []string{"replica-priority 100", "gopher-enabled no"},
+			expectedRestartConfig: []string{},
+			expectedUnknownConfig: []string{},
+		},
+		{
+			name:                  "version without catalogue",
+			image:                 "redis:latest",
+			customConfig:          []string{"databases 32", "some-parameter yes"},
+			expectedRuntimeConfig: []string{"replica-priority 100", "databases 32", "some-parameter yes"},
+			expectedRestartConfig: []string{},
+			expectedUnknownConfig: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.Redis.Image = test.image
+			rf.Spec.Redis.CustomConfig = test.customConfig
+			rf.Status.RedisVersion = test.redisVersion
+
+			assert.Equal(t, test.expectedMajorVersion, rf.RedisMajorVersion())
+			assert.NoError(t, rf.Validate())
+			assert.Equal(t, test.expectedRuntimeConfig, rf.RuntimeCustomConfig())
+			assert.Equal(t, test.expectedRestartConfig, rf.RestartCustomConfig())
+			assert.Equal(t, test.expectedUnknownConfig, rf.UnknownCustomConfig())
+		})
+	}
+}
+
+func TestValidateDriftPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         DriftPolicy
+		expectedPolicy DriftPolicy
+		expectedError  string
+	}{
+		{
+			name:           "enforced by default",
+			expectedPolicy: DriftPolicyEnforce,
+		},
+		{
+			name:           "reported",
+			policy:         DriftPolicyReport,
+			expectedPolicy: DriftPolicyReport,
+		},
+		{
+			name:          "unknown policy",
+			policy:        "revert",
+			expectedError: "driftPolicy must be one of enforce, report and ignore",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.Redis.DriftPolicy = test.policy
+
+			err := rf.Validate()
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedPolicy, rf.RedisDriftPolicy())
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateIPFamily(t *testing.T) {
+	tests := []struct {
+		name          string
+		ipFamily      corev1.IPFamily
+		expectedError string
+	}{
+		{
+			name: "cluster default",
+		},
+		{
+			name:     "IPv6",
+			ipFamily: corev1.IPv6Protocol,
+		},
+		{
+			name:          "unknown family",
+			ipFamily:      "IPv5",
+			expectedError: "ipFamily must be either IPv4 or IPv6",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.IPFamily = test.ipFamily
+
+			err := rf.Validate()
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestPodIP(t *testing.T) {
+	dualStack := &corev1.Pod{Status: corev1.PodStatus{
+		PodIP:  "10.0.0.1",
+		PodIPs: []corev1.PodIP{{IP: "10.0.0.1"}, {IP: "fd00::1"}},
+	}}
+	singleStack := &corev1.Pod{Status: corev1.PodStatus{
+		PodIP:  "10.0.0.1",
+		PodIPs: []corev1.PodIP{{IP: "10.0.0.1"}},
+	}}
+
+	tests := []struct {
+		name       string
+		ipFamily   corev1.IPFamily
+		pod        *corev1.Pod
+		expectedIP string
+	}{
+		{
+			name:       "primary IP by default",
+			pod:        dualStack,
+			expectedIP: "10.0.0.1",
+		},
+		{
+			name:       "IP of the family on dual-stack",
+			ipFamily:   corev1.IPv6Protocol,
+			pod:        dualStack,
+			expectedIP: "fd00::1",
+		},
+		{
+			name:       "primary IP without one of the family",
+			ipFamily:   corev1.IPv6Protocol,
+			pod:        singleStack,
+			expectedIP: "10.0.0.1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.IPFamily = test.ipFamily
+
+			assert.Equal(t, test.expectedIP, rf.PodIP(test.pod))
+		})
+	}
+}
+
+func TestIPFamilyOf(t *testing.T) {
+	assert.Equal(t, corev1.IPv4Protocol, IPFamilyOf("10.0.0.1"))
+	assert.Equal(t, corev1.IPv6Protocol, IPFamilyOf("fd00::1"))
+	assert.Equal(t, corev1.IPv4Protocol, IPFamilyOf("::ffff:10.0.0.1"))
+	assert.Equal(t, corev1.IPFamily(""), IPFamilyOf("redis-0"))
+}
+
+func TestValidateAnnounceHostnames(t *testing.T) {
+	rf := generateRedisFailover("test", nil)
+	rf.Spec.AnnounceHostnames = true
+	assert.NoError(t, rf.Validate())
+
+	rf.Spec.Redis.Command = []string{"redis-server", "/redis/redis.conf"}
+	assert.EqualError(t, rf.Validate(), "announceHostnames can't be used along with a custom redis command")
+}
+
+func TestPodAddress(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-0", Namespace: "testns"},
+		Spec:       corev1.PodSpec{Hostname: "rfr-test-0", Subdomain: "rfr-test"},
+		Status:     corev1.PodStatus{PodIP: "10.0.0.1"},
+	}
+
+	rf := generateRedisFailover("test", nil)
+	assert.Equal(t, "10.0.0.1", rf.PodAddress(pod))
+
+	rf.Spec.AnnounceHostnames = true
+	assert.Equal(t, "rfr-test-0.rfr-test.testns.svc", rf.PodAddress(pod))
+
+	// Pods not created by the statefulset controller have no name under the service
+	assert.Equal(t, "10.0.0.1", rf.PodAddress(&corev1.Pod{Status: corev1.PodStatus{PodIP: "10.0.0.1"}}))
+}
+
+func TestSameAddress(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{a: "10.0.0.1", b: "10.0.0.1", expected: true},
+		{a: "10.0.0.1", b: "10.0.0.10"},
+		{a: "FD00::1", b: "fd00::1", expected: true},
+		{a: "rfr-test-0.rfr-test.testns.svc", b: "rfr-test-0.rfr-test.testns.svc", expected: true},
+		{a: "rfr-test-0.rfr-test.testns.svc", b: "rfr-test-0.rfr-test.testns.svc.cluster.local.", expected: true},
+		{a: "rfr-test-0.rfr-test.testns.svc.cluster.local", b: "rfr-test-0.rfr-test.testns.svc", expected: true},
+		{a: "rfr-test-0.rfr-test.testns.svc", b: "rfr-test-1.rfr-test.testns.svc"},
+		{a: "rfr-test-0.rfr-test.testns.svc", b: "10.0.0.1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.a+" "+test.b, func(t *testing.T) {
+			assert.Equal(t, test.expected, SameAddress(test.a, test.b))
+		})
+	}
+}
+
+func TestValidateSentinel(t *testing.T) {
+	tests := []struct {
+		name               string
+		masterName         string
+		port               int32
+		expectedMasterName string
+		expectedPort       int32
+		expectedError      string
+	}{
+		{
+			name:               "defaults",
+			expectedMasterName: "mymaster",
+			expectedPort:       26379,
+		},
+		{
+			name:               "custom master name and port",
+			masterName:         "cache-v2.primary",
+			port:               26380,
+			expectedMasterName: "cache-v2.primary",
+			expectedPort:       26380,
+		},
+		{
+			name:          "master name with spaces",
+			masterName:    "my master",
+			expectedError: "sentinel masterName can only contain letters, digits, dots, dashes and underscores",
+		},
+		{
+			name:          "port out of range",
+			port:          70000,
+			expectedError: "sentinel port must be between 1 and 65535",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.Sentinel.MasterName = test.masterName
+			rf.Spec.Sentinel.Port = test.port
+
+			err := rf.Validate()
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedMasterName, rf.SentinelMasterName())
+				assert.Equal(t, test.expectedPort, rf.SentinelPort())
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
@@ -7,12 +7,46 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AOFSettings) DeepCopyInto(out *AOFSettings) {
+	*out = *in
+	if in.RewritePercentage != nil {
+		in, out := &in.RewritePercentage, &out.RewritePercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RewriteMinSize != nil {
+		in, out := &in.RewriteMinSize, &out.RewriteMinSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AOFSettings.
+func (in *AOFSettings) DeepCopy() *AOFSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(AOFSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthSettings) DeepCopyInto(out *AuthSettings) {
 	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]RedisUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -26,6 +60,61 @@ func (in *AuthSettings) DeepCopy() *AuthSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupRetention) DeepCopyInto(out *BackupRetention) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupRetention.
+func (in *BackupRetention) DeepCopy() *BackupRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupSettings) DeepCopyInto(out *BackupSettings) {
+	*out = *in
+	in.Storage.DeepCopyInto(&out.Storage)
+	out.Retention = in.Retention
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupSettings.
+func (in *BackupSettings) DeepCopy() *BackupSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupStorage) DeepCopyInto(out *BackupStorage) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3Storage)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStorage.
+func (in *BackupStorage) DeepCopy() *BackupStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BootstrapSettings) DeepCopyInto(out *BootstrapSettings) {
 	*out = *in
@@ -42,6 +131,22 @@ func (in *BootstrapSettings) DeepCopy() *BootstrapSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClaimStatus) DeepCopyInto(out *ClaimStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClaimStatus.
+func (in *ClaimStatus) DeepCopy() *ClaimStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClaimStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
 	*out = *in
@@ -130,6 +235,122 @@ func (in *Exporter) DeepCopy() *Exporter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FinalSnapshotSettings) DeepCopyInto(out *FinalSnapshotSettings) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3Storage)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FinalSnapshotSettings.
+func (in *FinalSnapshotSettings) DeepCopy() *FinalSnapshotSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(FinalSnapshotSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCRetentionPolicy) DeepCopyInto(out *PVCRetentionPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCRetentionPolicy.
+func (in *PVCRetentionPolicy) DeepCopy() *PVCRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistenceSettings) DeepCopyInto(out *PersistenceSettings) {
+	*out = *in
+	if in.RDB != nil {
+		in, out := &in.RDB, &out.RDB
+		*out = new(RDBSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AOF != nil {
+		in, out := &in.AOF, &out.AOF
+		*out = new(AOFSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistenceSettings.
+func (in *PersistenceSettings) DeepCopy() *PersistenceSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistenceSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentVolumeClaimRestoreSource) DeepCopyInto(out *PersistentVolumeClaimRestoreSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimRestoreSource.
+func (in *PersistentVolumeClaimRestoreSource) DeepCopy() *PersistentVolumeClaimRestoreSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentVolumeClaimRestoreSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDBSavePoint) DeepCopyInto(out *RDBSavePoint) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDBSavePoint.
+func (in *RDBSavePoint) DeepCopy() *RDBSavePoint {
+	if in == nil {
+		return nil
+	}
+	out := new(RDBSavePoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDBSettings) DeepCopyInto(out *RDBSettings) {
+	*out = *in
+	if in.SavePoints != nil {
+		in, out := &in.SavePoints, &out.SavePoints
+		*out = make([]RDBSavePoint, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDBSettings.
+func (in *RDBSettings) DeepCopy() *RDBSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(RDBSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisCommandRename) DeepCopyInto(out *RedisCommandRename) {
 	*out = *in
@@ -152,7 +373,7 @@ func (in *RedisFailover) DeepCopyInto(out *RedisFailover) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -174,6 +395,112 @@ func (in *RedisFailover) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisFailoverBackup) DeepCopyInto(out *RedisFailoverBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisFailoverBackup.
+func (in *RedisFailoverBackup) DeepCopy() *RedisFailoverBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisFailoverBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RedisFailoverBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisFailoverBackupList) DeepCopyInto(out *RedisFailoverBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RedisFailoverBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisFailoverBackupList.
+func (in *RedisFailoverBackupList) DeepCopy() *RedisFailoverBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisFailoverBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RedisFailoverBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisFailoverBackupSpec) DeepCopyInto(out *RedisFailoverBackupSpec) {
+	*out = *in
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(BackupStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisFailoverBackupSpec.
+func (in *RedisFailoverBackupSpec) DeepCopy() *RedisFailoverBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisFailoverBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisFailoverBackupStatus) DeepCopyInto(out *RedisFailoverBackupStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisFailoverBackupStatus.
+func (in *RedisFailoverBackupStatus) DeepCopy() *RedisFailoverBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisFailoverBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisFailoverList) DeepCopyInto(out *RedisFailoverList) {
 	*out = *in
@@ -212,7 +539,7 @@ func (in *RedisFailoverSpec) DeepCopyInto(out *RedisFailoverSpec) {
 	*out = *in
 	in.Redis.DeepCopyInto(&out.Redis)
 	in.Sentinel.DeepCopyInto(&out.Sentinel)
-	out.Auth = in.Auth
+	in.Auth.DeepCopyInto(&out.Auth)
 	if in.LabelWhitelist != nil {
 		in, out := &in.LabelWhitelist, &out.LabelWhitelist
 		*out = make([]string, len(*in))
@@ -223,6 +550,21 @@ func (in *RedisFailoverSpec) DeepCopyInto(out *RedisFailoverSpec) {
 		*out = new(BootstrapSettings)
 		**out = **in
 	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSSettings)
+		**out = **in
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(BackupSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(RestoreSettings)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -239,6 +581,32 @@ func (in *RedisFailoverSpec) DeepCopy() *RedisFailoverSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisFailoverStatus) DeepCopyInto(out *RedisFailoverStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Master != nil {
+		in, out := &in.Master, &out.Master
+		*out = new(RedisMasterStatus)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]RedisReplicaStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastBackupScheduleTime != nil {
+		in, out := &in.LastBackupScheduleTime, &out.LastBackupScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -252,6 +620,38 @@ func (in *RedisFailoverStatus) DeepCopy() *RedisFailoverStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisMasterStatus) DeepCopyInto(out *RedisMasterStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisMasterStatus.
+func (in *RedisMasterStatus) DeepCopy() *RedisMasterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisMasterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisReplicaStatus) DeepCopyInto(out *RedisReplicaStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisReplicaStatus.
+func (in *RedisReplicaStatus) DeepCopy() *RedisReplicaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisReplicaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisSettings) DeepCopyInto(out *RedisSettings) {
 	*out = *in
@@ -272,6 +672,16 @@ func (in *RedisSettings) DeepCopyInto(out *RedisSettings) {
 		copy(*out, *in)
 	}
 	in.Storage.DeepCopyInto(&out.Storage)
+	if in.Persistence != nil {
+		in, out := &in.Persistence, &out.Persistence
+		*out = new(PersistenceSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxMemoryPercent != nil {
+		in, out := &in.MaxMemoryPercent, &out.MaxMemoryPercent
+		*out = new(int32)
+		**out = **in
+	}
 	if in.InitContainers != nil {
 		in, out := &in.InitContainers, &out.InitContainers
 		*out = make([]corev1.Container, len(*in))
@@ -397,6 +807,16 @@ func (in *RedisStorage) DeepCopyInto(out *RedisStorage) {
 		*out = new(EmbeddedPersistentVolumeClaim)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PVCRetentionPolicy != nil {
+		in, out := &in.PVCRetentionPolicy, &out.PVCRetentionPolicy
+		*out = new(PVCRetentionPolicy)
+		**out = **in
+	}
+	if in.FinalSnapshot != nil {
+		in, out := &in.FinalSnapshot, &out.FinalSnapshot
+		*out = new(FinalSnapshotSettings)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -410,6 +830,96 @@ func (in *RedisStorage) DeepCopy() *RedisStorage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisUser) DeepCopyInto(out *RedisUser) {
+	*out = *in
+	if in.Commands != nil {
+		in, out := &in.Commands, &out.Commands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisUser.
+func (in *RedisUser) DeepCopy() *RedisUser {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreSettings) DeepCopyInto(out *RestoreSettings) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3RestoreSource)
+		**out = **in
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(PersistentVolumeClaimRestoreSource)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreSettings.
+func (in *RestoreSettings) DeepCopy() *RestoreSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3RestoreSource) DeepCopyInto(out *S3RestoreSource) {
+	*out = *in
+	out.S3Storage = in.S3Storage
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3RestoreSource.
+func (in *S3RestoreSource) DeepCopy() *S3RestoreSource {
+	if in == nil {
+		return nil
+	}
+	out := new(S3RestoreSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3Storage) DeepCopyInto(out *S3Storage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Storage.
+func (in *S3Storage) DeepCopy() *S3Storage {
+	if in == nil {
+		return nil
+	}
+	out := new(S3Storage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SentinelConfigCopy) DeepCopyInto(out *SentinelConfigCopy) {
 	*out = *in
@@ -434,6 +944,16 @@ func (in *SentinelConfigCopy) DeepCopy() *SentinelConfigCopy {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SentinelSettings) DeepCopyInto(out *SentinelSettings) {
 	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FailoverTimeout != nil {
+		in, out := &in.FailoverTimeout, &out.FailoverTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
 	if in.CustomConfig != nil {
 		in, out := &in.CustomConfig, &out.CustomConfig
@@ -557,3 +1077,40 @@ func (in *SentinelSettings) DeepCopy() *SentinelSettings {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageStatus) DeepCopyInto(out *StorageStatus) {
+	*out = *in
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = make([]ClaimStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageStatus.
+func (in *StorageStatus) DeepCopy() *StorageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSSettings) DeepCopyInto(out *TLSSettings) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSSettings.
+func (in *TLSSettings) DeepCopy() *TLSSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSSettings)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -16,6 +16,10 @@ func (c *FakeDatabasesV1) RedisFailovers(namespace string) v1.RedisFailoverInter
 	return &FakeRedisFailovers{c, namespace}
 }
 
+func (c *FakeDatabasesV1) RedisFailoverBackups(namespace string) v1.RedisFailoverBackupInterface {
+	return &FakeRedisFailoverBackups{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeDatabasesV1) RESTClient() rest.Interface {
@@ -0,0 +1,125 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeRedisFailoverBackups implements RedisFailoverBackupInterface
+type FakeRedisFailoverBackups struct {
+	Fake *FakeDatabasesV1
+	ns   string
+}
+
+var redisfailoverbackupsResource = v1.SchemeGroupVersion.WithResource("redisfailoverbackups")
+
+var redisfailoverbackupsKind = v1.SchemeGroupVersion.WithKind("RedisFailoverBackup")
+
+// Get takes name of the redisFailoverBackup, and returns the corresponding redisFailoverBackup object, and an error if there is any.
+func (c *FakeRedisFailoverBackups) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.RedisFailoverBackup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(redisfailoverbackupsResource, c.ns, name), &v1.RedisFailoverBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1.RedisFailoverBackup), err
+}
+
+// List takes label and field selectors, and returns the list of RedisFailoverBackups that match those selectors.
+func (c *FakeRedisFailoverBackups) List(ctx context.Context, opts metav1.ListOptions) (result *v1.RedisFailoverBackupList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(redisfailoverbackupsResource, redisfailoverbackupsKind, c.ns, opts), &v1.RedisFailoverBackupList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1.RedisFailoverBackupList{ListMeta: obj.(*v1.RedisFailoverBackupList).ListMeta}
+	for _, item := range obj.(*v1.RedisFailoverBackupList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested redisFailoverBackups.
+func (c *FakeRedisFailoverBackups) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(redisfailoverbackupsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a redisFailoverBackup and creates it.  Returns the server's representation of the redisFailoverBackup, and an error, if there is any.
+func (c *FakeRedisFailoverBackups) Create(ctx context.Context, redisFailoverBackup *v1.RedisFailoverBackup, opts metav1.CreateOptions) (result *v1.RedisFailoverBackup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(redisfailoverbackupsResource, c.ns, redisFailoverBackup), &v1.RedisFailoverBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1.RedisFailoverBackup), err
+}
+
+// Update takes the representation of a redisFailoverBackup and updates it. Returns the server's representation of the redisFailoverBackup, and an error, if there is any.
+func (c *FakeRedisFailoverBackups) Update(ctx context.Context, redisFailoverBackup *v1.RedisFailoverBackup, opts metav1.UpdateOptions) (result *v1.RedisFailoverBackup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(redisfailoverbackupsResource, c.ns, redisFailoverBackup), &v1.RedisFailoverBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1.RedisFailoverBackup), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeRedisFailoverBackups) UpdateStatus(ctx context.Context, redisFailoverBackup *v1.RedisFailoverBackup, opts metav1.UpdateOptions) (*v1.RedisFailoverBackup, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(redisfailoverbackupsResource, "status", c.ns, redisFailoverBackup), &v1.RedisFailoverBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1.RedisFailoverBackup), err
+}
+
+// Delete takes name of the redisFailoverBackup and deletes it. Returns an error if one occurs.
+func (c *FakeRedisFailoverBackups) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(redisfailoverbackupsResource, c.ns, name, opts), &v1.RedisFailoverBackup{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeRedisFailoverBackups) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(redisfailoverbackupsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1.RedisFailoverBackupList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched redisFailoverBackup.
+func (c *FakeRedisFailoverBackups) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.RedisFailoverBackup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(redisfailoverbackupsResource, c.ns, name, pt, data, subresources...), &v1.RedisFailoverBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1.RedisFailoverBackup), err
+}
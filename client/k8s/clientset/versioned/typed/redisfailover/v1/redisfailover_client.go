@@ -13,6 +13,7 @@ import (
 type DatabasesV1Interface interface {
 	RESTClient() rest.Interface
 	RedisFailoversGetter
+	RedisFailoverBackupsGetter
 }
 
 // DatabasesV1Client is used to interact with features provided by the databases.spotahome.com group.
@@ -24,6 +25,10 @@ func (c *DatabasesV1Client) RedisFailovers(namespace string) RedisFailoverInterf
 	return newRedisFailovers(c, namespace)
 }
 
+func (c *DatabasesV1Client) RedisFailoverBackups(namespace string) RedisFailoverBackupInterface {
+	return newRedisFailoverBackups(c, namespace)
+}
+
 // NewForConfig creates a new DatabasesV1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
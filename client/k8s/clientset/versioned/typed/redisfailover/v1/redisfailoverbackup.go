@@ -0,0 +1,179 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	scheme "github.com/saremox/redis-operator/client/k8s/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// RedisFailoverBackupsGetter has a method to return a RedisFailoverBackupInterface.
+// A group's client should implement this interface.
+type RedisFailoverBackupsGetter interface {
+	RedisFailoverBackups(namespace string) RedisFailoverBackupInterface
+}
+
+// RedisFailoverBackupInterface has methods to work with RedisFailoverBackup resources.
+type RedisFailoverBackupInterface interface {
+	Create(ctx context.Context, redisFailoverBackup *v1.RedisFailoverBackup, opts metav1.CreateOptions) (*v1.RedisFailoverBackup, error)
+	Update(ctx context.Context, redisFailoverBackup *v1.RedisFailoverBackup, opts metav1.UpdateOptions) (*v1.RedisFailoverBackup, error)
+	UpdateStatus(ctx context.Context, redisFailoverBackup *v1.RedisFailoverBackup, opts metav1.UpdateOptions) (*v1.RedisFailoverBackup, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.RedisFailoverBackup, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.RedisFailoverBackupList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.RedisFailoverBackup, err error)
+	RedisFailoverBackupExpansion
+}
+
+// redisFailoverBackups implements RedisFailoverBackupInterface
+type redisFailoverBackups struct {
+	client rest.Interface
+	ns     string
+}
+
+// newRedisFailoverBackups returns a RedisFailoverBackups
+func newRedisFailoverBackups(c *DatabasesV1Client, namespace string) *redisFailoverBackups {
+	return &redisFailoverBackups{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the redisFailoverBackup, and returns the corresponding redisFailoverBackup object, and an error if there is any.
+func (c *redisFailoverBackups) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.RedisFailoverBackup, err error) {
+	result = &v1.RedisFailoverBackup{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("redisfailoverbackups").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of RedisFailoverBackups that match those selectors.
+func (c *redisFailoverBackups) List(ctx context.Context, opts metav1.ListOptions) (result *v1.RedisFailoverBackupList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.RedisFailoverBackupList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("redisfailoverbackups").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested redisFailoverBackups.
+func (c *redisFailoverBackups) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("redisfailoverbackups").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a redisFailoverBackup and creates it.  Returns the server's representation of the redisFailoverBackup, and an error, if there is any.
+func (c *redisFailoverBackups) Create(ctx context.Context, redisFailoverBackup *v1.RedisFailoverBackup, opts metav1.CreateOptions) (result *v1.RedisFailoverBackup, err error) {
+	result = &v1.RedisFailoverBackup{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("redisfailoverbackups").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(redisFailoverBackup).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a redisFailoverBackup and updates it. Returns the server's representation of the redisFailoverBackup, and an error, if there is any.
+func (c *redisFailoverBackups) Update(ctx context.Context, redisFailoverBackup *v1.RedisFailoverBackup, opts metav1.UpdateOptions) (result *v1.RedisFailoverBackup, err error) {
+	result = &v1.RedisFailoverBackup{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("redisfailoverbackups").
+		Name(redisFailoverBackup.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(redisFailoverBackup).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *redisFailoverBackups) UpdateStatus(ctx context.Context, redisFailoverBackup *v1.RedisFailoverBackup, opts metav1.UpdateOptions) (result *v1.RedisFailoverBackup, err error) {
+	result = &v1.RedisFailoverBackup{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("redisfailoverbackups").
+		Name(redisFailoverBackup.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(redisFailoverBackup).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the redisFailoverBackup and deletes it. Returns an error if one occurs.
+func (c *redisFailoverBackups) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("redisfailoverbackups").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *redisFailoverBackups) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("redisfailoverbackups").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched redisFailoverBackup.
+func (c *redisFailoverBackups) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.RedisFailoverBackup, err error) {
+	result = &v1.RedisFailoverBackup{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("redisfailoverbackups").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
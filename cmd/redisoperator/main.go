@@ -82,7 +82,7 @@ func (m *Main) Run() error {
 	k8sservice := k8s.New(k8sClient, customClient, aeClientset, m.logger, metricsRecorder)
 
 	// Create the redis clients
-	redisClient := redis.New(metricsRecorder)
+	redisClient := redis.New(metricsRecorder, m.flags.ToRedisClientConfig())
 
 	// Get lease lock resource namespace
 	lockNamespace := getNamespace()
@@ -93,10 +93,19 @@ func (m *Main) Run() error {
 		return err
 	}
 
+	backupController, err := redisfailover.NewBackupController(m.flags.ToRedisOperatorConfig(), k8sservice, k8sClient, lockNamespace, redisClient, metricsRecorder, m.logger)
+	if err != nil {
+		return err
+	}
+
 	go func() {
 		errC <- redisfailoverOperator.Run(context.Background())
 	}()
 
+	go func() {
+		errC <- backupController.Run(context.Background())
+	}()
+
 	// Await signals.
 	sigC := m.createSignalCapturer()
 	var finalErr error
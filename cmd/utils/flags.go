@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"time"
 
 	"github.com/saremox/redis-operator/operator/redisfailover"
+	"github.com/saremox/redis-operator/service/redis"
 	"k8s.io/client-go/util/homedir"
 )
 
@@ -23,6 +25,12 @@ type CMDFlags struct {
 	Concurrency              int
 	SyncInterval             int
 	LogLevel                 string
+	RedisDialTimeout         time.Duration
+	RedisReadTimeout         time.Duration
+	RedisWriteTimeout        time.Duration
+	RedisPoolSize            int
+	RedisPoolIdleTimeout     time.Duration
+	ClusterDomain            string
 }
 
 // Init initializes and parse the flags
@@ -41,6 +49,12 @@ func (c *CMDFlags) Init() {
 	flag.IntVar(&c.Concurrency, "concurrency", 3, "Number of conccurent workers meant to process events")
 	flag.IntVar(&c.SyncInterval, "sync-interval", 30, "Number of seconds between checks")
 	flag.StringVar(&c.LogLevel, "log-level", "info", "set log level")
+	flag.DurationVar(&c.RedisDialTimeout, "redis-dial-timeout", redis.DefaultConfig.DialTimeout, "Timeout to connect to the redis and sentinel nodes")
+	flag.DurationVar(&c.RedisReadTimeout, "redis-read-timeout", redis.DefaultConfig.ReadTimeout, "Timeout to read the replies of the redis and sentinel nodes")
+	flag.DurationVar(&c.RedisWriteTimeout, "redis-write-timeout", redis.DefaultConfig.WriteTimeout, "Timeout to send commands to the redis and sentinel nodes")
+	flag.IntVar(&c.RedisPoolSize, "redis-pool-size", redis.DefaultConfig.PoolSize, "Maximum number of connections open to every redis and sentinel node")
+	flag.DurationVar(&c.RedisPoolIdleTimeout, "redis-pool-idle-timeout", redis.DefaultConfig.PoolIdleTimeout, "Time the connections to a redis or sentinel node are kept unused before being closed")
+	flag.StringVar(&c.ClusterDomain, "cluster-domain", "cluster.local", "DNS domain of the cluster, used in the names the certificates generated by the operator are issued for")
 	// Parse flags
 	flag.Parse()
 
@@ -57,5 +71,17 @@ func (c *CMDFlags) ToRedisOperatorConfig() redisfailover.Config {
 		Concurrency:              c.Concurrency,
 		SyncInterval:             c.SyncInterval,
 		SupportedNamespacesRegex: c.SupportedNamespacesRegex,
+		ClusterDomain:            c.ClusterDomain,
+	}
+}
+
+// ToRedisClientConfig convert the flags to the redis client config
+func (c *CMDFlags) ToRedisClientConfig() redis.Config {
+	return redis.Config{
+		DialTimeout:     c.RedisDialTimeout,
+		ReadTimeout:     c.RedisReadTimeout,
+		WriteTimeout:    c.RedisWriteTimeout,
+		PoolSize:        c.RedisPoolSize,
+		PoolIdleTimeout: c.RedisPoolIdleTimeout,
 	}
 }
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"time"
+
 	koopercontroller "github.com/spotahome/kooper/v2/controller"
 )
 
@@ -29,3 +31,11 @@ func (d dummy) RecordK8sOperation(namespace string, kind string, object string,
 }
 func (d dummy) RecordRedisOperation(kind string, IP string, operation string, status string, err string) {
 }
+func (d dummy) SetRedisConfigDrift(namespace string, resource string, instance string, parameters int) {
+}
+func (d dummy) PruneRedisConfigDrift(namespace string, resource string, instances []string) {
+}
+func (d dummy) SetRedisConnectionPools(pools int, connections int, idleConnections int) {
+}
+func (d dummy) RecordRedisCommandDuration(kind string, command string, duration time.Duration) {
+}
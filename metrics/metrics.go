@@ -57,6 +57,7 @@ const (
 	KIND_REDIS                  = "REDIS"
 	KIND_SENTINEL               = "SENTINEL"
 	APPLY_REDIS_CONFIG          = "APPLY_REDIS_CONFIG"
+	GET_REDIS_CONFIG            = "GET_REDIS_CONFIG"
 	APPLY_EXTERNAL_MASTER       = "APPLY_EXT_MASTER_ALL"
 	APPLY_SENTINEL_CONFIG       = "APPLY_SENTINEL_CONFIG"
 	MONITOR_REDIS_WITH_PORT     = "SET_SENTINEL_TO_MONITOR_REDIS_WITH_GIVEN_PORT"
@@ -71,6 +72,17 @@ const (
 	CHECK_SENTINEL_QUORUM       = "SENTINEL_CKQUORUM"
 	SLAVE_IS_READY              = "CHECK_IF_SLAVE_IS_READY"
 	GET_REPLICATION_INFO        = "GET_REPLICATION_INFO"
+	GET_REDIS_VERSION           = "GET_REDIS_VERSION"
+	PAUSE_WRITES                = "PAUSE_CLIENT_WRITES"
+	UNPAUSE_CLIENTS             = "UNPAUSE_CLIENTS"
+	SENTINEL_FAILOVER           = "SENTINEL_FORCE_FAILOVER"
+	GET_SENTINEL_REPLICAS       = "SENTINEL_GET_REPLICAS"
+	GET_ACL_USERS               = "GET_ACL_USERS"
+	SET_ACL_USER                = "SET_ACL_USER"
+	DELETE_ACL_USER             = "DELETE_ACL_USER"
+	BACKGROUND_SAVE             = "BACKGROUND_SAVE"
+	GET_PERSISTENCE_INFO        = "GET_PERSISTENCE_INFO"
+	SYNC_RDB                    = "SYNC_RDB"
 )
 
 var ( // used for grabage collection of metrics
@@ -97,17 +109,33 @@ type Recorder interface {
 
 	RecordK8sOperation(namespace string, kind string, name string, operation string, status string, err string)
 	RecordRedisOperation(kind string, IP string, operation string, status string, err string)
+
+	// Number of managed configuration parameters changed outside of the operator on a redis node
+	SetRedisConfigDrift(namespace string, resource string, instance string, parameters int)
+	PruneRedisConfigDrift(namespace string, resource string, instances []string)
+
+	// Connection pools to the redis and sentinel nodes, and the time the commands sent to them take
+	SetRedisConnectionPools(pools int, connections int, idleConnections int)
+	RecordRedisCommandDuration(kind string, command string, duration time.Duration)
 }
 
+// instanceSet is a set of instances having a metric
+type instanceSet map[string]bool
+
 // PromMetrics implements the instrumenter so the metrics can be managed by Prometheus.
 type recorder struct {
 	// Metrics fields.
-	clusterOK            *prometheus.GaugeVec   // clusterOk is the status of a cluster
-	ensureResource       *prometheus.CounterVec // number of successful "ensure" operators performed by the controller.
-	redisCheck           *prometheus.CounterVec // indicates any error encountered in managed redis instance(s)
-	sentinelCheck        *prometheus.CounterVec // indicates any error encountered in managed sentinel instance(s)
-	k8sServiceOperations *prometheus.CounterVec // number of operations performed on k8s
-	redisOperations      *prometheus.CounterVec // number of operations performed on redis/sentinel instances
+	clusterOK            *prometheus.GaugeVec     // clusterOk is the status of a cluster
+	ensureResource       *prometheus.CounterVec   // number of successful "ensure" operators performed by the controller.
+	redisCheck           *prometheus.CounterVec   // indicates any error encountered in managed redis instance(s)
+	sentinelCheck        *prometheus.CounterVec   // indicates any error encountered in managed sentinel instance(s)
+	k8sServiceOperations *prometheus.CounterVec   // number of operations performed on k8s
+	redisOperations      *prometheus.CounterVec   // number of operations performed on redis/sentinel instances
+	redisConfigDrift     *prometheus.GaugeVec     // number of managed configuration parameters drifted on redis instances
+	redisConnectionPools prometheus.Gauge         // number of connection pools open to redis/sentinel instances
+	redisPoolConnections *prometheus.GaugeVec     // number of connections of the pools, by state
+	redisCommandDuration *prometheus.HistogramVec // time the commands sent to redis/sentinel instances take
+	driftInstances       map[string]instanceSet   // instances with a config drift metric, by resource
 	koopercontroller.MetricsRecorder
 }
 
@@ -158,6 +186,35 @@ func NewRecorder(namespace string, reg prometheus.Registerer) Recorder {
 			Help:      "number of operations performed on k8s",
 		}, []string{"namespace", "kind", "name", "operation", "status", "err"})
 
+	redisConfigDrift := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: promControllerSubsystem,
+		Name:      "redis_config_drifted_parameters",
+		Help:      "number of managed configuration parameters changed outside of the operator on a redis instance",
+	}, []string{"namespace", "resource", "instance"})
+
+	redisConnectionPools := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: promControllerSubsystem,
+		Name:      "redis_connection_pools",
+		Help:      "number of connection pools open to redis/sentinel instances",
+	})
+
+	redisPoolConnections := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: promControllerSubsystem,
+		Name:      "redis_pool_connections",
+		Help:      "number of connections of the pools open to redis/sentinel instances",
+	}, []string{"state"})
+
+	redisCommandDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: promControllerSubsystem,
+		Name:      "redis_command_duration_seconds",
+		Help:      "time the commands sent to redis/sentinel instances take",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"kind", "command"})
+
 	// Create the instance.
 	r := recorder{
 		clusterOK:            clusterOK,
@@ -166,6 +223,11 @@ func NewRecorder(namespace string, reg prometheus.Registerer) Recorder {
 		sentinelCheck:        sentinelCheck,
 		k8sServiceOperations: k8sServiceOperations,
 		redisOperations:      redisOperations,
+		redisConfigDrift:     redisConfigDrift,
+		redisConnectionPools: redisConnectionPools,
+		redisPoolConnections: redisPoolConnections,
+		redisCommandDuration: redisCommandDuration,
+		driftInstances:       map[string]instanceSet{},
 		MetricsRecorder: kooperprometheus.New(kooperprometheus.Config{
 			Registerer: reg,
 		}),
@@ -179,6 +241,10 @@ func NewRecorder(namespace string, reg prometheus.Registerer) Recorder {
 		r.sentinelCheck,
 		r.k8sServiceOperations,
 		r.redisOperations,
+		r.redisConfigDrift,
+		r.redisConnectionPools,
+		r.redisPoolConnections,
+		r.redisCommandDuration,
 	)
 	recorders = append(recorders, r)
 	return r
@@ -224,6 +290,46 @@ func (r recorder) RecordRedisOperation(kind /*redis/sentinel? */ string, IP stri
 	updateInstanceMetricLastUpdatedTracker(IP)
 }
 
+func (r recorder) SetRedisConfigDrift(namespace string, resource string, instance string, parameters int) {
+	r.redisConfigDrift.WithLabelValues(namespace, resource, instance).Set(float64(parameters))
+	updateResourceMetricLastUpdatedTracker(namespace, "redisfailover", resource)
+	key := fmt.Sprintf("%v/%v", namespace, resource)
+	mutex.Lock()
+	if r.driftInstances[key] == nil {
+		r.driftInstances[key] = instanceSet{}
+	}
+	r.driftInstances[key][instance] = true
+	mutex.Unlock()
+}
+
+// PruneRedisConfigDrift deletes the drift metrics of the instances of the resource not given, so
+// the ones of the deleted pods don't stay
+func (r recorder) PruneRedisConfigDrift(namespace string, resource string, instances []string) {
+	keep := map[string]bool{}
+	for _, instance := range instances {
+		keep[instance] = true
+	}
+	key := fmt.Sprintf("%v/%v", namespace, resource)
+	mutex.Lock()
+	defer mutex.Unlock()
+	for instance := range r.driftInstances[key] {
+		if !keep[instance] {
+			r.redisConfigDrift.DeleteLabelValues(namespace, resource, instance)
+			delete(r.driftInstances[key], instance)
+		}
+	}
+}
+
+func (r recorder) SetRedisConnectionPools(pools int, connections int, idleConnections int) {
+	r.redisConnectionPools.Set(float64(pools))
+	r.redisPoolConnections.WithLabelValues("total").Set(float64(connections))
+	r.redisPoolConnections.WithLabelValues("idle").Set(float64(idleConnections))
+}
+
+func (r recorder) RecordRedisCommandDuration(kind string, command string, duration time.Duration) {
+	r.redisCommandDuration.WithLabelValues(kind, command).Observe(duration.Seconds())
+}
+
 func updateResourceMetricLastUpdatedTracker(namespace string, kind string, name string) {
 	mutex.Lock()
 	resourceMetricLastUpdated[fmt.Sprintf("%v/%v/%v", namespace, kind, name)] = time.Now()
@@ -251,6 +357,7 @@ func removeStaleMetrics() {
 			for _, label := range customResourceBasedLabels {
 				metricsDeletedCount += recorder.redisCheck.DeletePartialMatch(label)
 				metricsDeletedCount += recorder.sentinelCheck.DeletePartialMatch(label)
+				metricsDeletedCount += recorder.redisConfigDrift.DeletePartialMatch(label)
 				labelWithName := label
 				labelWithName["name"] = labelWithName["resource"]
 				delete(labelWithName, "resource")
@@ -296,6 +403,9 @@ func getLabelsOfStaleMetrics() (kubernetesResourceBasedLabels []prometheus.Label
 			// its not longer required - since it is known to be stale. remove it from the tracker.
 			mutex.Lock()
 			delete(resourceMetricLastUpdated, key)
+			for _, recorder := range recorders {
+				delete(recorder.driftInstances, fmt.Sprintf("%v/%v", namespace, resource))
+			}
 			mutex.Unlock()
 		}
 	}
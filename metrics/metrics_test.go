@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -17,10 +18,11 @@ import (
 func TestPrometheusMetrics(t *testing.T) {
 
 	tests := []struct {
-		name       string
-		addMetrics func(rec metrics.Recorder)
-		expMetrics []string
-		expCode    int
+		name          string
+		addMetrics    func(rec metrics.Recorder)
+		expMetrics    []string
+		notExpMetrics []string
+		expCode       int
 	}{
 		{
 			name: "Setting OK should give an OK",
@@ -109,6 +111,60 @@ func TestPrometheusMetrics(t *testing.T) {
 			},
 			expCode: http.StatusOK,
 		},
+		{
+			name: "Config drift should give the drifted parameters of each instance",
+			addMetrics: func(rec metrics.Recorder) {
+				rec.SetRedisConfigDrift("testns", "test", "rfr-test-0", 2)
+				rec.SetRedisConfigDrift("testns", "test", "rfr-test-1", 0)
+			},
+			expMetrics: []string{
+				`my_metrics_controller_redis_config_drifted_parameters{instance="rfr-test-0",namespace="testns",resource="test"} 2`,
+				`my_metrics_controller_redis_config_drifted_parameters{instance="rfr-test-1",namespace="testns",resource="test"} 0`,
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "Config drift of the instances gone should be deleted",
+			addMetrics: func(rec metrics.Recorder) {
+				rec.SetRedisConfigDrift("testns", "test", "rfr-test-0", 2)
+				rec.SetRedisConfigDrift("testns", "test", "rfr-test-1", 1)
+				rec.SetRedisConfigDrift("testns", "test2", "rfr-test2-1", 1)
+				rec.PruneRedisConfigDrift("testns", "test", []string{"rfr-test-0"})
+			},
+			expMetrics: []string{
+				`my_metrics_controller_redis_config_drifted_parameters{instance="rfr-test-0",namespace="testns",resource="test"} 2`,
+				`my_metrics_controller_redis_config_drifted_parameters{instance="rfr-test2-1",namespace="testns",resource="test2"} 1`,
+			},
+			notExpMetrics: []string{
+				`instance="rfr-test-1"`,
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "Connection pools should give the pools and their connections",
+			addMetrics: func(rec metrics.Recorder) {
+				rec.SetRedisConnectionPools(3, 4, 1)
+			},
+			expMetrics: []string{
+				`my_metrics_controller_redis_connection_pools 3`,
+				`my_metrics_controller_redis_pool_connections{state="total"} 4`,
+				`my_metrics_controller_redis_pool_connections{state="idle"} 1`,
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "Command durations should be observed by kind and command",
+			addMetrics: func(rec metrics.Recorder) {
+				rec.RecordRedisCommandDuration(metrics.KIND_REDIS, "info", 20*time.Millisecond)
+				rec.RecordRedisCommandDuration(metrics.KIND_REDIS, "info", 2*time.Second)
+			},
+			expMetrics: []string{
+				`my_metrics_controller_redis_command_duration_seconds_bucket{command="info",kind="REDIS",le="0.025"} 1`,
+				`my_metrics_controller_redis_command_duration_seconds_bucket{command="info",kind="REDIS",le="2.5"} 2`,
+				`my_metrics_controller_redis_command_duration_seconds_count{command="info",kind="REDIS"} 2`,
+			},
+			expCode: http.StatusOK,
+		},
 	}
 
 	for _, test := range tests {
@@ -134,6 +190,9 @@ func TestPrometheusMetrics(t *testing.T) {
 				for _, expMetric := range test.expMetrics {
 					assert.Contains(string(body), expMetric)
 				}
+				for _, notExpMetric := range test.notExpMetrics {
+					assert.NotContains(string(body), notExpMetric)
+				}
 			}
 		})
 	}
@@ -74,6 +74,74 @@ func (_m *RedisFailover) WatchRedisFailovers(ctx context.Context, namespace stri
 func (_m *RedisFailover) UpdateRedisFailoverStatus(ctx context.Context, namespace string, redisFailover *redisfailoverv1.RedisFailover, opts v1.PatchOptions) {
 }
 
+// AddRedisFailoverFinalizer provides a mock function with given fields: ctx, redisFailover, finalizer
+func (_m *RedisFailover) AddRedisFailoverFinalizer(ctx context.Context, redisFailover *redisfailoverv1.RedisFailover, finalizer string) error {
+	ret := _m.Called(ctx, redisFailover, finalizer)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *redisfailoverv1.RedisFailover, string) error); ok {
+		r0 = rf(ctx, redisFailover, finalizer)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetRedisFailover provides a mock function with given fields: ctx, namespace, name
+func (_m *RedisFailover) GetRedisFailover(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailover, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 *redisfailoverv1.RedisFailover
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*redisfailoverv1.RedisFailover, error)); ok {
+		return rf(ctx, namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *redisfailoverv1.RedisFailover); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailover)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveRedisFailoverAnnotation provides a mock function with given fields: ctx, namespace, name, key
+func (_m *RedisFailover) RemoveRedisFailoverAnnotation(ctx context.Context, namespace string, name string, key string) error {
+	ret := _m.Called(ctx, namespace, name, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, namespace, name, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveRedisFailoverFinalizer provides a mock function with given fields: ctx, redisFailover, finalizer
+func (_m *RedisFailover) RemoveRedisFailoverFinalizer(ctx context.Context, redisFailover *redisfailoverv1.RedisFailover, finalizer string) error {
+	ret := _m.Called(ctx, redisFailover, finalizer)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *redisfailoverv1.RedisFailover, string) error); ok {
+		r0 = rf(ctx, redisFailover, finalizer)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewRedisFailover interface {
 	mock.TestingT
 	Cleanup(func())
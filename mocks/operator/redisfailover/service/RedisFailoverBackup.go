@@ -0,0 +1,86 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	service "github.com/saremox/redis-operator/operator/redisfailover/service"
+	mock "github.com/stretchr/testify/mock"
+
+	v1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+)
+
+// RedisFailoverBackup is an autogenerated mock type for the RedisFailoverBackup type
+type RedisFailoverBackup struct {
+	mock.Mock
+}
+
+// BackupRedis provides a mock function with given fields: ctx, ip, rFailover, storage, name
+func (_m *RedisFailoverBackup) BackupRedis(ctx context.Context, ip string, rFailover *v1.RedisFailover, storage *v1.BackupStorage, name string) (*service.BackupResult, error) {
+	ret := _m.Called(ctx, ip, rFailover, storage, name)
+
+	var r0 *service.BackupResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover, *v1.BackupStorage, string) (*service.BackupResult, error)); ok {
+		return rf(ctx, ip, rFailover, storage, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover, *v1.BackupStorage, string) *service.BackupResult); ok {
+		r0 = rf(ctx, ip, rFailover, storage, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.BackupResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.RedisFailover, *v1.BackupStorage, string) error); ok {
+		r1 = rf(ctx, ip, rFailover, storage, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteBackup provides a mock function with given fields: ctx, namespace, storage, location
+func (_m *RedisFailoverBackup) DeleteBackup(ctx context.Context, namespace string, storage *v1.BackupStorage, location string) error {
+	ret := _m.Called(ctx, namespace, storage, location)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.BackupStorage, string) error); ok {
+		r0 = rf(ctx, namespace, storage, location)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveRedis provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverBackup) SaveRedis(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewRedisFailoverBackup interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRedisFailoverBackup creates a new instance of RedisFailoverBackup. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRedisFailoverBackup(t mockConstructorTestingTNewRedisFailoverBackup) *RedisFailoverBackup {
+	mock := &RedisFailoverBackup{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,12 +3,14 @@
 package mocks
 
 import (
+	context "context"
+
+	service "github.com/saremox/redis-operator/operator/redisfailover/service"
 	mock "github.com/stretchr/testify/mock"
 
 	time "time"
 
 	v1 "github.com/saremox/redis-operator/api/redisfailover/v1"
-	service "github.com/saremox/redis-operator/operator/redisfailover/service"
 )
 
 // RedisFailoverCheck is an autogenerated mock type for the RedisFailoverCheck type
@@ -16,13 +18,13 @@ type RedisFailoverCheck struct {
 	mock.Mock
 }
 
-// CheckAllSlavesFromMaster provides a mock function with given fields: master, rFailover
-func (_m *RedisFailoverCheck) CheckAllSlavesFromMaster(master string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(master, rFailover)
+// CheckAllSlavesFromMaster provides a mock function with given fields: ctx, master, rFailover
+func (_m *RedisFailoverCheck) CheckAllSlavesFromMaster(ctx context.Context, master string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, master, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(master, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, master, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -30,23 +32,23 @@ func (_m *RedisFailoverCheck) CheckAllSlavesFromMaster(master string, rFailover
 	return r0
 }
 
-// CheckIfMasterLocalhost provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) CheckIfMasterLocalhost(rFailover *v1.RedisFailover) (bool, error) {
-	ret := _m.Called(rFailover)
+// CheckIfMasterLocalhost provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) CheckIfMasterLocalhost(ctx context.Context, rFailover *v1.RedisFailover) (bool, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (bool, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (bool, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) bool); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) bool); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -68,23 +70,23 @@ func (_m *RedisFailoverCheck) CheckRedisNumber(rFailover *v1.RedisFailover) erro
 	return r0
 }
 
-// CheckRedisSlavesReady provides a mock function with given fields: slaveIP, rFailover
-func (_m *RedisFailoverCheck) CheckRedisSlavesReady(slaveIP string, rFailover *v1.RedisFailover) (bool, error) {
-	ret := _m.Called(slaveIP, rFailover)
+// CheckRedisSlavesReady provides a mock function with given fields: ctx, slaveIP, rFailover
+func (_m *RedisFailoverCheck) CheckRedisSlavesReady(ctx context.Context, slaveIP string, rFailover *v1.RedisFailover) (bool, error) {
+	ret := _m.Called(ctx, slaveIP, rFailover)
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) (bool, error)); ok {
-		return rf(slaveIP, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) (bool, error)); ok {
+		return rf(ctx, slaveIP, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) bool); ok {
-		r0 = rf(slaveIP, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) bool); ok {
+		r0 = rf(ctx, slaveIP, rFailover)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, *v1.RedisFailover) error); ok {
-		r1 = rf(slaveIP, rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, slaveIP, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -92,20 +94,20 @@ func (_m *RedisFailoverCheck) CheckRedisSlavesReady(slaveIP string, rFailover *v
 	return r0, r1
 }
 
-// CheckSentinelMonitor provides a mock function with given fields: sentinel, monitor
-func (_m *RedisFailoverCheck) CheckSentinelMonitor(sentinel string, monitor ...string) error {
+// CheckSentinelMonitor provides a mock function with given fields: ctx, sentinel, rFailover, monitor
+func (_m *RedisFailoverCheck) CheckSentinelMonitor(ctx context.Context, sentinel string, rFailover *v1.RedisFailover, monitor ...string) error {
 	_va := make([]interface{}, len(monitor))
 	for _i := range monitor {
 		_va[_i] = monitor[_i]
 	}
 	var _ca []interface{}
-	_ca = append(_ca, sentinel)
+	_ca = append(_ca, ctx, sentinel, rFailover)
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, ...string) error); ok {
-		r0 = rf(sentinel, monitor...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover, ...string) error); ok {
+		r0 = rf(ctx, sentinel, rFailover, monitor...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -127,13 +129,13 @@ func (_m *RedisFailoverCheck) CheckSentinelNumber(rFailover *v1.RedisFailover) e
 	return r0
 }
 
-// CheckSentinelNumberInMemory provides a mock function with given fields: sentinel, rFailover
-func (_m *RedisFailoverCheck) CheckSentinelNumberInMemory(sentinel string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(sentinel, rFailover)
+// CheckSentinelNumberInMemory provides a mock function with given fields: ctx, sentinel, rFailover
+func (_m *RedisFailoverCheck) CheckSentinelNumberInMemory(ctx context.Context, sentinel string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, sentinel, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(sentinel, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, sentinel, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -141,23 +143,23 @@ func (_m *RedisFailoverCheck) CheckSentinelNumberInMemory(sentinel string, rFail
 	return r0
 }
 
-// CheckSentinelQuorum provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) CheckSentinelQuorum(rFailover *v1.RedisFailover) (int, error) {
-	ret := _m.Called(rFailover)
+// CheckSentinelQuorum provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) CheckSentinelQuorum(ctx context.Context, rFailover *v1.RedisFailover) (int, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (int, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (int, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) int); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) int); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -165,13 +167,13 @@ func (_m *RedisFailoverCheck) CheckSentinelQuorum(rFailover *v1.RedisFailover) (
 	return r0, r1
 }
 
-// CheckSentinelSlavesNumberInMemory provides a mock function with given fields: sentinel, rFailover
-func (_m *RedisFailoverCheck) CheckSentinelSlavesNumberInMemory(sentinel string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(sentinel, rFailover)
+// CheckSentinelSlavesNumberInMemory provides a mock function with given fields: ctx, sentinel, rFailover
+func (_m *RedisFailoverCheck) CheckSentinelSlavesNumberInMemory(ctx context.Context, sentinel string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, sentinel, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(sentinel, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, sentinel, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -179,23 +181,23 @@ func (_m *RedisFailoverCheck) CheckSentinelSlavesNumberInMemory(sentinel string,
 	return r0
 }
 
-// GetMasterIP provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetMasterIP(rFailover *v1.RedisFailover) (string, error) {
-	ret := _m.Called(rFailover)
+// GetMasterIP provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetMasterIP(ctx context.Context, rFailover *v1.RedisFailover) (string, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (string, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (string, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) string); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) string); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -227,23 +229,23 @@ func (_m *RedisFailoverCheck) GetMaxRedisPodTime(rFailover *v1.RedisFailover) (t
 	return r0, r1
 }
 
-// GetNumberMasters provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetNumberMasters(rFailover *v1.RedisFailover) (int, error) {
-	ret := _m.Called(rFailover)
+// GetNumberMasters provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetNumberMasters(ctx context.Context, rFailover *v1.RedisFailover) (int, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (int, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (int, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) int); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) int); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -301,23 +303,23 @@ func (_m *RedisFailoverCheck) GetRedisesIPs(rFailover *v1.RedisFailover) ([]stri
 	return r0, r1
 }
 
-// GetRedisesMasterPod provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetRedisesMasterPod(rFailover *v1.RedisFailover) (string, error) {
-	ret := _m.Called(rFailover)
+// GetRedisesMasterPod provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetRedisesMasterPod(ctx context.Context, rFailover *v1.RedisFailover) (string, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (string, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (string, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) string); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) string); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -325,25 +327,25 @@ func (_m *RedisFailoverCheck) GetRedisesMasterPod(rFailover *v1.RedisFailover) (
 	return r0, r1
 }
 
-// GetRedisesSlavesPods provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetRedisesSlavesPods(rFailover *v1.RedisFailover) ([]string, error) {
-	ret := _m.Called(rFailover)
+// GetRedisesSlavesPods provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetRedisesSlavesPods(ctx context.Context, rFailover *v1.RedisFailover) ([]string, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 []string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) ([]string, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) ([]string, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) []string); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) []string); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -443,30 +445,30 @@ func (_m *RedisFailoverCheck) IsSentinelRunning(rFailover *v1.RedisFailover) boo
 	return r0
 }
 
-// CheckMasterHealth provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) CheckMasterHealth(rFailover *v1.RedisFailover) (bool, string, error) {
-	ret := _m.Called(rFailover)
+// CheckMasterHealth provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) CheckMasterHealth(ctx context.Context, rFailover *v1.RedisFailover) (bool, string, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 bool
 	var r1 string
 	var r2 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (bool, string, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (bool, string, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) bool); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) bool); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) string); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) string); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
-	if rf, ok := ret.Get(2).(func(*v1.RedisFailover) error); ok {
-		r2 = rf(rFailover)
+	if rf, ok := ret.Get(2).(func(context.Context, *v1.RedisFailover) error); ok {
+		r2 = rf(ctx, rFailover)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -474,25 +476,25 @@ func (_m *RedisFailoverCheck) CheckMasterHealth(rFailover *v1.RedisFailover) (bo
 	return r0, r1, r2
 }
 
-// GetBestReplicaForPromotion provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetBestReplicaForPromotion(rFailover *v1.RedisFailover) (*service.ReplicaInfo, error) {
-	ret := _m.Called(rFailover)
+// GetBestReplicaForPromotion provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetBestReplicaForPromotion(ctx context.Context, rFailover *v1.RedisFailover) (*service.ReplicaInfo, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 *service.ReplicaInfo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (*service.ReplicaInfo, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) (*service.ReplicaInfo, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) *service.ReplicaInfo); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) *service.ReplicaInfo); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*service.ReplicaInfo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -500,23 +502,123 @@ func (_m *RedisFailoverCheck) GetBestReplicaForPromotion(rFailover *v1.RedisFail
 	return r0, r1
 }
 
-// GetReplicaReplicationOffsets provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetReplicaReplicationOffsets(rFailover *v1.RedisFailover) ([]service.ReplicaInfo, error) {
-	ret := _m.Called(rFailover)
+// GetReplicaReplicationOffsets provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetReplicaReplicationOffsets(ctx context.Context, rFailover *v1.RedisFailover) ([]service.ReplicaInfo, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 []service.ReplicaInfo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) ([]service.ReplicaInfo, error)); ok {
-		return rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) ([]service.ReplicaInfo, error)); ok {
+		return rf(ctx, rFailover)
 	}
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) []service.ReplicaInfo); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) []service.ReplicaInfo); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]service.ReplicaInfo)
 		}
 	}
 
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRedisesReplication provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetRedisesReplication(ctx context.Context, rFailover *v1.RedisFailover) ([]service.RedisNodeReplication, error) {
+	ret := _m.Called(ctx, rFailover)
+
+	var r0 []service.RedisNodeReplication
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) ([]service.RedisNodeReplication, error)); ok {
+		return rf(ctx, rFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) []service.RedisNodeReplication); ok {
+		r0 = rf(ctx, rFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.RedisNodeReplication)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRedisConfigDrift provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverCheck) GetRedisConfigDrift(ctx context.Context, ip string, rFailover *v1.RedisFailover) ([]string, error) {
+	ret := _m.Called(ctx, ip, rFailover)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) ([]string, error)); ok {
+		return rf(ctx, ip, rFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) []string); ok {
+		r0 = rf(ctx, ip, rFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, ip, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSentinelSlavesNumberInMemory provides a mock function with given fields: ctx, sentinel, rFailover
+func (_m *RedisFailoverCheck) GetSentinelSlavesNumberInMemory(ctx context.Context, sentinel string, rFailover *v1.RedisFailover) (int32, error) {
+	ret := _m.Called(ctx, sentinel, rFailover)
+
+	var r0 int32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) (int32, error)); ok {
+		return rf(ctx, sentinel, rFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) int32); ok {
+		r0 = rf(ctx, sentinel, rFailover)
+	} else {
+		r0 = ret.Get(0).(int32)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, sentinel, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetACLUsersChecksum provides a mock function with given fields: rFailover
+func (_m *RedisFailoverCheck) GetACLUsersChecksum(rFailover *v1.RedisFailover) (string, error) {
+	ret := _m.Called(rFailover)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (string, error)); ok {
+		return rf(rFailover)
+	}
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) string); ok {
+		r0 = rf(rFailover)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
 	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
 		r1 = rf(rFailover)
 	} else {
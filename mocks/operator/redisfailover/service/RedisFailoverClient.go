@@ -3,9 +3,13 @@
 package mocks
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	service "github.com/saremox/redis-operator/operator/redisfailover/service"
+
 	v1 "github.com/saremox/redis-operator/api/redisfailover/v1"
 )
 
@@ -182,6 +186,88 @@ func (_m *RedisFailoverClient) EnsureNotPresentSentinelResources(rFailover *v1.R
 	return r0
 }
 
+// EnsureTLSSecrets provides a mock function with given fields: rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureTLSSecrets(rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(rFailover, labels, ownerRefs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(rFailover, labels, ownerRefs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnsureRedisAuthSecret provides a mock function with given fields: rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureRedisAuthSecret(rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(rFailover, labels, ownerRefs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(rFailover, labels, ownerRefs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetRedisPasswordState provides a mock function with given fields: rFailover
+func (_m *RedisFailoverClient) GetRedisPasswordState(rFailover *v1.RedisFailover) (*service.RedisPasswordState, error) {
+	ret := _m.Called(rFailover)
+
+	var r0 *service.RedisPasswordState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) (*service.RedisPasswordState, error)); ok {
+		return rf(rFailover)
+	}
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) *service.RedisPasswordState); ok {
+		r0 = rf(rFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.RedisPasswordState)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
+		r1 = rf(rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetRedisPasswordState provides a mock function with given fields: rFailover, state
+func (_m *RedisFailoverClient) SetRedisPasswordState(rFailover *v1.RedisFailover, state *service.RedisPasswordState) error {
+	ret := _m.Called(rFailover, state)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*v1.RedisFailover, *service.RedisPasswordState) error); ok {
+		r0 = rf(rFailover, state)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnsureRedisRestoreSecret provides a mock function with given fields: ctx, rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureRedisRestoreSecret(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewRedisFailoverClient interface {
 	mock.TestingT
 	Cleanup(func())
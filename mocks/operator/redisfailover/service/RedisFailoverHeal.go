@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 
 	v1 "github.com/saremox/redis-operator/api/redisfailover/v1"
@@ -27,13 +29,13 @@ func (_m *RedisFailoverHeal) DeletePod(podName string, rFailover *v1.RedisFailov
 	return r0
 }
 
-// MakeMaster provides a mock function with given fields: ip, rFailover
-func (_m *RedisFailoverHeal) MakeMaster(ip string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(ip, rFailover)
+// MakeMaster provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) MakeMaster(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(ip, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -41,13 +43,13 @@ func (_m *RedisFailoverHeal) MakeMaster(ip string, rFailover *v1.RedisFailover)
 	return r0
 }
 
-// NewSentinelMonitor provides a mock function with given fields: ip, monitor, rFailover
-func (_m *RedisFailoverHeal) NewSentinelMonitor(ip string, monitor string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(ip, monitor, rFailover)
+// NewSentinelMonitor provides a mock function with given fields: ctx, ip, monitor, rFailover
+func (_m *RedisFailoverHeal) NewSentinelMonitor(ctx context.Context, ip string, monitor string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, monitor, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, *v1.RedisFailover) error); ok {
-		r0 = rf(ip, monitor, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, monitor, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -55,13 +57,13 @@ func (_m *RedisFailoverHeal) NewSentinelMonitor(ip string, monitor string, rFail
 	return r0
 }
 
-// NewSentinelMonitorWithPort provides a mock function with given fields: ip, monitor, port, rFailover
-func (_m *RedisFailoverHeal) NewSentinelMonitorWithPort(ip string, monitor string, port string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(ip, monitor, port, rFailover)
+// NewSentinelMonitorWithPort provides a mock function with given fields: ctx, ip, monitor, port, rFailover
+func (_m *RedisFailoverHeal) NewSentinelMonitorWithPort(ctx context.Context, ip string, monitor string, port string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, monitor, port, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, *v1.RedisFailover) error); ok {
-		r0 = rf(ip, monitor, port, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, monitor, port, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -69,13 +71,13 @@ func (_m *RedisFailoverHeal) NewSentinelMonitorWithPort(ip string, monitor strin
 	return r0
 }
 
-// RestoreSentinel provides a mock function with given fields: ip
-func (_m *RedisFailoverHeal) RestoreSentinel(ip string) error {
-	ret := _m.Called(ip)
+// RestoreSentinel provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) RestoreSentinel(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -83,13 +85,13 @@ func (_m *RedisFailoverHeal) RestoreSentinel(ip string) error {
 	return r0
 }
 
-// SetExternalMasterOnAll provides a mock function with given fields: masterIP, masterPort, rFailover
-func (_m *RedisFailoverHeal) SetExternalMasterOnAll(masterIP string, masterPort string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(masterIP, masterPort, rFailover)
+// SetExternalMasterOnAll provides a mock function with given fields: ctx, masterIP, masterPort, rFailover
+func (_m *RedisFailoverHeal) SetExternalMasterOnAll(ctx context.Context, masterIP string, masterPort string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, masterIP, masterPort, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, *v1.RedisFailover) error); ok {
-		r0 = rf(masterIP, masterPort, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, masterIP, masterPort, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -97,13 +99,13 @@ func (_m *RedisFailoverHeal) SetExternalMasterOnAll(masterIP string, masterPort
 	return r0
 }
 
-// SetMasterOnAll provides a mock function with given fields: masterIP, rFailover
-func (_m *RedisFailoverHeal) SetMasterOnAll(masterIP string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(masterIP, rFailover)
+// SetMasterOnAll provides a mock function with given fields: ctx, masterIP, rFailover
+func (_m *RedisFailoverHeal) SetMasterOnAll(ctx context.Context, masterIP string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, masterIP, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(masterIP, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, masterIP, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -111,13 +113,13 @@ func (_m *RedisFailoverHeal) SetMasterOnAll(masterIP string, rFailover *v1.Redis
 	return r0
 }
 
-// SetOldestAsMaster provides a mock function with given fields: rFailover
-func (_m *RedisFailoverHeal) SetOldestAsMaster(rFailover *v1.RedisFailover) error {
-	ret := _m.Called(rFailover)
+// SetOldestAsMaster provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverHeal) SetOldestAsMaster(ctx context.Context, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) error); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -125,13 +127,13 @@ func (_m *RedisFailoverHeal) SetOldestAsMaster(rFailover *v1.RedisFailover) erro
 	return r0
 }
 
-// SetRedisCustomConfig provides a mock function with given fields: ip, rFailover
-func (_m *RedisFailoverHeal) SetRedisCustomConfig(ip string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(ip, rFailover)
+// SetRedisCustomConfig provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) SetRedisCustomConfig(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(ip, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -139,13 +141,13 @@ func (_m *RedisFailoverHeal) SetRedisCustomConfig(ip string, rFailover *v1.Redis
 	return r0
 }
 
-// SetSentinelCustomConfig provides a mock function with given fields: ip, rFailover
-func (_m *RedisFailoverHeal) SetSentinelCustomConfig(ip string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(ip, rFailover)
+// SetSentinelCustomConfig provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) SetSentinelCustomConfig(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(ip, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -153,13 +155,123 @@ func (_m *RedisFailoverHeal) SetSentinelCustomConfig(ip string, rFailover *v1.Re
 	return r0
 }
 
-// PromoteBestReplica provides a mock function with given fields: newMasterIP, rFailover
-func (_m *RedisFailoverHeal) PromoteBestReplica(newMasterIP string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(newMasterIP, rFailover)
+// PromoteBestReplica provides a mock function with given fields: ctx, newMasterIP, rFailover
+func (_m *RedisFailoverHeal) PromoteBestReplica(ctx context.Context, newMasterIP string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, newMasterIP, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(newMasterIP, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, newMasterIP, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SentinelSwitchover provides a mock function with given fields: ctx, sentinelIP, masterIP, newMasterIP, rFailover
+func (_m *RedisFailoverHeal) SentinelSwitchover(ctx context.Context, sentinelIP string, masterIP string, newMasterIP string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, sentinelIP, masterIP, newMasterIP, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, sentinelIP, masterIP, newMasterIP, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Switchover provides a mock function with given fields: ctx, masterIP, newMasterIP, rFailover
+func (_m *RedisFailoverHeal) Switchover(ctx context.Context, masterIP string, newMasterIP string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, masterIP, newMasterIP, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, masterIP, newMasterIP, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DemoteStaleMasters provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverHeal) DemoteStaleMasters(ctx context.Context, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SyncACLUsers provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) SyncACLUsers(ctx context.Context, ip string, rFailover *v1.RedisFailover) ([]string, error) {
+	ret := _m.Called(ctx, ip, rFailover)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) ([]string, error)); ok {
+		return rf(ctx, ip, rFailover)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) []string); ok {
+		r0 = rf(ctx, ip, rFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, ip, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddRedisPassword provides a mock function with given fields: ctx, ip, password, rFailover
+func (_m *RedisFailoverHeal) AddRedisPassword(ctx context.Context, ip string, password string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, password, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, password, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveRedisPassword provides a mock function with given fields: ctx, ip, password, rFailover
+func (_m *RedisFailoverHeal) RemoveRedisPassword(ctx context.Context, ip string, password string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, password, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, password, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetSentinelAuthPass provides a mock function with given fields: ctx, ip, password, rFailover
+func (_m *RedisFailoverHeal) SetSentinelAuthPass(ctx context.Context, ip string, password string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, password, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, password, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -15,6 +15,8 @@ import (
 
 	rbacv1 "k8s.io/api/rbac/v1"
 
+	storagev1 "k8s.io/api/storage/v1"
+
 	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
 
 	v1 "k8s.io/api/core/v1"
@@ -972,6 +974,408 @@ func (_m *Services) WatchRedisFailovers(ctx context.Context, namespace string, o
 func (_m *Services) UpdateRedisFailoverStatus(ctx context.Context, namespace string, redisFailover *redisfailoverv1.RedisFailover, opts metav1.PatchOptions) {
 }
 
+// RemoveRedisFailoverAnnotation provides a mock function with given fields: ctx, namespace, name, key
+func (_m *Services) RemoveRedisFailoverAnnotation(ctx context.Context, namespace string, name string, key string) error {
+	ret := _m.Called(ctx, namespace, name, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, namespace, name, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateOrUpdateSecret provides a mock function with given fields: namespace, secret
+func (_m *Services) CreateOrUpdateSecret(namespace string, secret *v1.Secret) error {
+	ret := _m.Called(namespace, secret)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *v1.Secret) error); ok {
+		r0 = rf(namespace, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateSecret provides a mock function with given fields: namespace, secret
+func (_m *Services) CreateSecret(namespace string, secret *v1.Secret) error {
+	ret := _m.Called(namespace, secret)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *v1.Secret) error); ok {
+		r0 = rf(namespace, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSecret provides a mock function with given fields: namespace, secret
+func (_m *Services) UpdateSecret(namespace string, secret *v1.Secret) error {
+	ret := _m.Called(namespace, secret)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *v1.Secret) error); ok {
+		r0 = rf(namespace, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WatchSecrets provides a mock function with given fields: ctx, namespace, opts
+func (_m *Services) WatchSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	ret := _m.Called(ctx, namespace, opts)
+
+	var r0 watch.Interface
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) (watch.Interface, error)); ok {
+		return rf(ctx, namespace, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) watch.Interface); ok {
+		r0 = rf(ctx, namespace, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(watch.Interface)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.ListOptions) error); ok {
+		r1 = rf(ctx, namespace, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateRedisFailoverBackup provides a mock function with given fields: ctx, namespace, backup
+func (_m *Services) CreateRedisFailoverBackup(ctx context.Context, namespace string, backup *redisfailoverv1.RedisFailoverBackup) error {
+	ret := _m.Called(ctx, namespace, backup)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *redisfailoverv1.RedisFailoverBackup) error); ok {
+		r0 = rf(ctx, namespace, backup)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteRedisFailoverBackup provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) DeleteRedisFailoverBackup(ctx context.Context, namespace string, name string) error {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetRedisFailover provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetRedisFailover(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailover, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 *redisfailoverv1.RedisFailover
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*redisfailoverv1.RedisFailover, error)); ok {
+		return rf(ctx, namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *redisfailoverv1.RedisFailover); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailover)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRedisFailoverBackups provides a mock function with given fields: ctx, namespace, opts
+func (_m *Services) ListRedisFailoverBackups(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverBackupList, error) {
+	ret := _m.Called(ctx, namespace, opts)
+
+	var r0 *redisfailoverv1.RedisFailoverBackupList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) (*redisfailoverv1.RedisFailoverBackupList, error)); ok {
+		return rf(ctx, namespace, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) *redisfailoverv1.RedisFailoverBackupList); ok {
+		r0 = rf(ctx, namespace, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailoverBackupList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.ListOptions) error); ok {
+		r1 = rf(ctx, namespace, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateRedisFailoverBackup provides a mock function with given fields: ctx, namespace, backup
+func (_m *Services) UpdateRedisFailoverBackup(ctx context.Context, namespace string, backup *redisfailoverv1.RedisFailoverBackup) (*redisfailoverv1.RedisFailoverBackup, error) {
+	ret := _m.Called(ctx, namespace, backup)
+
+	var r0 *redisfailoverv1.RedisFailoverBackup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *redisfailoverv1.RedisFailoverBackup) (*redisfailoverv1.RedisFailoverBackup, error)); ok {
+		return rf(ctx, namespace, backup)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *redisfailoverv1.RedisFailoverBackup) *redisfailoverv1.RedisFailoverBackup); ok {
+		r0 = rf(ctx, namespace, backup)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailoverBackup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *redisfailoverv1.RedisFailoverBackup) error); ok {
+		r1 = rf(ctx, namespace, backup)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WatchRedisFailoverBackups provides a mock function with given fields: ctx, namespace, opts
+func (_m *Services) WatchRedisFailoverBackups(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	ret := _m.Called(ctx, namespace, opts)
+
+	var r0 watch.Interface
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) (watch.Interface, error)); ok {
+		return rf(ctx, namespace, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) watch.Interface); ok {
+		r0 = rf(ctx, namespace, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(watch.Interface)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.ListOptions) error); ok {
+		r1 = rf(ctx, namespace, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRedisFailoverBackup provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetRedisFailoverBackup(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailoverBackup, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 *redisfailoverv1.RedisFailoverBackup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*redisfailoverv1.RedisFailoverBackup, error)); ok {
+		return rf(ctx, namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *redisfailoverv1.RedisFailoverBackup); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailoverBackup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddRedisFailoverFinalizer provides a mock function with given fields: ctx, redisFailover, finalizer
+func (_m *Services) AddRedisFailoverFinalizer(ctx context.Context, redisFailover *redisfailoverv1.RedisFailover, finalizer string) error {
+	ret := _m.Called(ctx, redisFailover, finalizer)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *redisfailoverv1.RedisFailover, string) error); ok {
+		r0 = rf(ctx, redisFailover, finalizer)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateEvent provides a mock function with given fields: namespace, event
+func (_m *Services) CreateEvent(namespace string, event *v1.Event) error {
+	ret := _m.Called(namespace, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *v1.Event) error); ok {
+		r0 = rf(namespace, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPersistentVolumeClaim provides a mock function with given fields: namespace, name
+func (_m *Services) GetPersistentVolumeClaim(namespace string, name string) (*v1.PersistentVolumeClaim, error) {
+	ret := _m.Called(namespace, name)
+
+	var r0 *v1.PersistentVolumeClaim
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*v1.PersistentVolumeClaim, error)); ok {
+		return rf(namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *v1.PersistentVolumeClaim); ok {
+		r0 = rf(namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.PersistentVolumeClaim)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveRedisFailoverFinalizer provides a mock function with given fields: ctx, redisFailover, finalizer
+func (_m *Services) RemoveRedisFailoverFinalizer(ctx context.Context, redisFailover *redisfailoverv1.RedisFailover, finalizer string) error {
+	ret := _m.Called(ctx, redisFailover, finalizer)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *redisfailoverv1.RedisFailover, string) error); ok {
+		r0 = rf(ctx, redisFailover, finalizer)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePersistentVolumeClaim provides a mock function with given fields: namespace, pvc
+func (_m *Services) UpdatePersistentVolumeClaim(namespace string, pvc *v1.PersistentVolumeClaim) error {
+	ret := _m.Called(namespace, pvc)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *v1.PersistentVolumeClaim) error); ok {
+		r0 = rf(namespace, pvc)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePersistentVolumeClaim provides a mock function with given fields: namespace, name
+func (_m *Services) DeletePersistentVolumeClaim(namespace string, name string) error {
+	ret := _m.Called(namespace, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(namespace, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListPersistentVolumeClaims provides a mock function with given fields: namespace, opts
+func (_m *Services) ListPersistentVolumeClaims(namespace string, opts metav1.ListOptions) (*v1.PersistentVolumeClaimList, error) {
+	ret := _m.Called(namespace, opts)
+
+	var r0 *v1.PersistentVolumeClaimList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, metav1.ListOptions) (*v1.PersistentVolumeClaimList, error)); ok {
+		return rf(namespace, opts)
+	}
+	if rf, ok := ret.Get(0).(func(string, metav1.ListOptions) *v1.PersistentVolumeClaimList); ok {
+		r0 = rf(namespace, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.PersistentVolumeClaimList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, metav1.ListOptions) error); ok {
+		r1 = rf(namespace, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStorageClass provides a mock function with given fields: name
+func (_m *Services) GetStorageClass(name string) (*storagev1.StorageClass, error) {
+	ret := _m.Called(name)
+
+	var r0 *storagev1.StorageClass
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*storagev1.StorageClass, error)); ok {
+		return rf(name)
+	}
+	if rf, ok := ret.Get(0).(func(string) *storagev1.StorageClass); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storagev1.StorageClass)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemovePodLabels provides a mock function with given fields: namespace, podName, keys
+func (_m *Services) RemovePodLabels(namespace string, podName string, keys []string) error {
+	ret := _m.Called(namespace, podName, keys)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, []string) error); ok {
+		r0 = rf(namespace, podName, keys)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewServices interface {
 	mock.TestingT
 	Cleanup(func())
@@ -3,9 +3,14 @@
 package mocks
 
 import (
+	context "context"
+	io "io"
+
 	mock "github.com/stretchr/testify/mock"
 
 	redis "github.com/saremox/redis-operator/service/redis"
+
+	time "time"
 )
 
 // Client is an autogenerated mock type for the Client type
@@ -13,23 +18,23 @@ type Client struct {
 	mock.Mock
 }
 
-// GetNumberSentinelSlavesInMemory provides a mock function with given fields: ip
-func (_m *Client) GetNumberSentinelSlavesInMemory(ip string) (int32, error) {
-	ret := _m.Called(ip)
+// GetNumberSentinelSlavesInMemory provides a mock function with given fields: ctx, ip
+func (_m *Client) GetNumberSentinelSlavesInMemory(ctx context.Context, ip string) (int32, error) {
+	ret := _m.Called(ctx, ip)
 
 	var r0 int32
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (int32, error)); ok {
-		return rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int32, error)); ok {
+		return rf(ctx, ip)
 	}
-	if rf, ok := ret.Get(0).(func(string) int32); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string) int32); ok {
+		r0 = rf(ctx, ip)
 	} else {
 		r0 = ret.Get(0).(int32)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(ip)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ip)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -37,23 +42,23 @@ func (_m *Client) GetNumberSentinelSlavesInMemory(ip string) (int32, error) {
 	return r0, r1
 }
 
-// GetNumberSentinelsInMemory provides a mock function with given fields: ip
-func (_m *Client) GetNumberSentinelsInMemory(ip string) (int32, error) {
-	ret := _m.Called(ip)
+// GetNumberSentinelsInMemory provides a mock function with given fields: ctx, ip
+func (_m *Client) GetNumberSentinelsInMemory(ctx context.Context, ip string) (int32, error) {
+	ret := _m.Called(ctx, ip)
 
 	var r0 int32
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (int32, error)); ok {
-		return rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int32, error)); ok {
+		return rf(ctx, ip)
 	}
-	if rf, ok := ret.Get(0).(func(string) int32); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string) int32); ok {
+		r0 = rf(ctx, ip)
 	} else {
 		r0 = ret.Get(0).(int32)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(ip)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ip)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -61,30 +66,30 @@ func (_m *Client) GetNumberSentinelsInMemory(ip string) (int32, error) {
 	return r0, r1
 }
 
-// GetSentinelMonitor provides a mock function with given fields: ip
-func (_m *Client) GetSentinelMonitor(ip string) (string, string, error) {
-	ret := _m.Called(ip)
+// GetSentinelMonitor provides a mock function with given fields: ctx, ip
+func (_m *Client) GetSentinelMonitor(ctx context.Context, ip string) (string, string, error) {
+	ret := _m.Called(ctx, ip)
 
 	var r0 string
 	var r1 string
 	var r2 error
-	if rf, ok := ret.Get(0).(func(string) (string, string, error)); ok {
-		return rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, string, error)); ok {
+		return rf(ctx, ip)
 	}
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, ip)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) string); ok {
-		r1 = rf(ip)
+	if rf, ok := ret.Get(1).(func(context.Context, string) string); ok {
+		r1 = rf(ctx, ip)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
-	if rf, ok := ret.Get(2).(func(string) error); ok {
-		r2 = rf(ip)
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, ip)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -92,23 +97,23 @@ func (_m *Client) GetSentinelMonitor(ip string) (string, string, error) {
 	return r0, r1, r2
 }
 
-// GetSlaveOf provides a mock function with given fields: ip, port, password
-func (_m *Client) GetSlaveOf(ip string, port string, password string) (string, error) {
-	ret := _m.Called(ip, port, password)
+// GetSlaveOf provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) GetSlaveOf(ctx context.Context, ip string, port string, password string) (string, error) {
+	ret := _m.Called(ctx, ip, port, password)
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, string) (string, error)); ok {
-		return rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (string, error)); ok {
+		return rf(ctx, ip, port, password)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, string) string); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = rf(ctx, ip, port, password)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(ip, port, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -116,23 +121,23 @@ func (_m *Client) GetSlaveOf(ip string, port string, password string) (string, e
 	return r0, r1
 }
 
-// IsMaster provides a mock function with given fields: ip, port, password
-func (_m *Client) IsMaster(ip string, port string, password string) (bool, error) {
-	ret := _m.Called(ip, port, password)
+// IsMaster provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) IsMaster(ctx context.Context, ip string, port string, password string) (bool, error) {
+	ret := _m.Called(ctx, ip, port, password)
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, string) (bool, error)); ok {
-		return rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (bool, error)); ok {
+		return rf(ctx, ip, port, password)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, string) bool); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) bool); ok {
+		r0 = rf(ctx, ip, port, password)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(ip, port, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -140,13 +145,13 @@ func (_m *Client) IsMaster(ip string, port string, password string) (bool, error
 	return r0, r1
 }
 
-// MakeMaster provides a mock function with given fields: ip, port, password
-func (_m *Client) MakeMaster(ip string, port string, password string) error {
-	ret := _m.Called(ip, port, password)
+// MakeMaster provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) MakeMaster(ctx context.Context, ip string, port string, password string) error {
+	ret := _m.Called(ctx, ip, port, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, ip, port, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -154,13 +159,13 @@ func (_m *Client) MakeMaster(ip string, port string, password string) error {
 	return r0
 }
 
-// MakeSlaveOf provides a mock function with given fields: ip, masterIP, password
-func (_m *Client) MakeSlaveOf(ip string, masterIP string, password string) error {
-	ret := _m.Called(ip, masterIP, password)
+// MakeSlaveOf provides a mock function with given fields: ctx, ip, masterIP, password
+func (_m *Client) MakeSlaveOf(ctx context.Context, ip string, masterIP string, password string) error {
+	ret := _m.Called(ctx, ip, masterIP, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(ip, masterIP, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, ip, masterIP, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -168,13 +173,13 @@ func (_m *Client) MakeSlaveOf(ip string, masterIP string, password string) error
 	return r0
 }
 
-// MakeSlaveOfWithPort provides a mock function with given fields: ip, masterIP, masterPort, password
-func (_m *Client) MakeSlaveOfWithPort(ip string, masterIP string, masterPort string, password string) error {
-	ret := _m.Called(ip, masterIP, masterPort, password)
+// MakeSlaveOfWithPort provides a mock function with given fields: ctx, ip, masterIP, masterPort, password
+func (_m *Client) MakeSlaveOfWithPort(ctx context.Context, ip string, masterIP string, masterPort string, password string) error {
+	ret := _m.Called(ctx, ip, masterIP, masterPort, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
-		r0 = rf(ip, masterIP, masterPort, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
+		r0 = rf(ctx, ip, masterIP, masterPort, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -182,13 +187,13 @@ func (_m *Client) MakeSlaveOfWithPort(ip string, masterIP string, masterPort str
 	return r0
 }
 
-// MonitorRedis provides a mock function with given fields: ip, monitor, quorum, password
-func (_m *Client) MonitorRedis(ip string, monitor string, quorum string, password string) error {
-	ret := _m.Called(ip, monitor, quorum, password)
+// MonitorRedis provides a mock function with given fields: ctx, ip, monitor, quorum, password
+func (_m *Client) MonitorRedis(ctx context.Context, ip string, monitor string, quorum string, password string) error {
+	ret := _m.Called(ctx, ip, monitor, quorum, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
-		r0 = rf(ip, monitor, quorum, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
+		r0 = rf(ctx, ip, monitor, quorum, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -196,13 +201,13 @@ func (_m *Client) MonitorRedis(ip string, monitor string, quorum string, passwor
 	return r0
 }
 
-// MonitorRedisWithPort provides a mock function with given fields: ip, monitor, port, quorum, password
-func (_m *Client) MonitorRedisWithPort(ip string, monitor string, port string, quorum string, password string) error {
-	ret := _m.Called(ip, monitor, port, quorum, password)
+// MonitorRedisWithPort provides a mock function with given fields: ctx, ip, monitor, port, quorum, password
+func (_m *Client) MonitorRedisWithPort(ctx context.Context, ip string, monitor string, port string, quorum string, password string) error {
+	ret := _m.Called(ctx, ip, monitor, port, quorum, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, string, string) error); ok {
-		r0 = rf(ip, monitor, port, quorum, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string) error); ok {
+		r0 = rf(ctx, ip, monitor, port, quorum, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -210,13 +215,13 @@ func (_m *Client) MonitorRedisWithPort(ip string, monitor string, port string, q
 	return r0
 }
 
-// ResetSentinel provides a mock function with given fields: ip
-func (_m *Client) ResetSentinel(ip string) error {
-	ret := _m.Called(ip)
+// ResetSentinel provides a mock function with given fields: ctx, ip
+func (_m *Client) ResetSentinel(ctx context.Context, ip string) error {
+	ret := _m.Called(ctx, ip)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ip)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -224,13 +229,13 @@ func (_m *Client) ResetSentinel(ip string) error {
 	return r0
 }
 
-// SentinelCheckQuorum provides a mock function with given fields: ip
-func (_m *Client) SentinelCheckQuorum(ip string) error {
-	ret := _m.Called(ip)
+// SentinelCheckQuorum provides a mock function with given fields: ctx, ip
+func (_m *Client) SentinelCheckQuorum(ctx context.Context, ip string) error {
+	ret := _m.Called(ctx, ip)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ip)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -238,13 +243,13 @@ func (_m *Client) SentinelCheckQuorum(ip string) error {
 	return r0
 }
 
-// SetCustomRedisConfig provides a mock function with given fields: ip, port, configs, password
-func (_m *Client) SetCustomRedisConfig(ip string, port string, configs []string, password string) error {
-	ret := _m.Called(ip, port, configs, password)
+// SetCustomRedisConfig provides a mock function with given fields: ctx, ip, port, configs, password
+func (_m *Client) SetCustomRedisConfig(ctx context.Context, ip string, port string, configs []string, password string) error {
+	ret := _m.Called(ctx, ip, port, configs, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, []string, string) error); ok {
-		r0 = rf(ip, port, configs, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, string) error); ok {
+		r0 = rf(ctx, ip, port, configs, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -252,13 +257,13 @@ func (_m *Client) SetCustomRedisConfig(ip string, port string, configs []string,
 	return r0
 }
 
-// SetCustomSentinelConfig provides a mock function with given fields: ip, configs
-func (_m *Client) SetCustomSentinelConfig(ip string, configs []string) error {
-	ret := _m.Called(ip, configs)
+// SetCustomSentinelConfig provides a mock function with given fields: ctx, ip, configs
+func (_m *Client) SetCustomSentinelConfig(ctx context.Context, ip string, configs []string) error {
+	ret := _m.Called(ctx, ip, configs)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, []string) error); ok {
-		r0 = rf(ip, configs)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) error); ok {
+		r0 = rf(ctx, ip, configs)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -266,23 +271,23 @@ func (_m *Client) SetCustomSentinelConfig(ip string, configs []string) error {
 	return r0
 }
 
-// SlaveIsReady provides a mock function with given fields: ip, port, password
-func (_m *Client) SlaveIsReady(ip string, port string, password string) (bool, error) {
-	ret := _m.Called(ip, port, password)
+// SlaveIsReady provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) SlaveIsReady(ctx context.Context, ip string, port string, password string) (bool, error) {
+	ret := _m.Called(ctx, ip, port, password)
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, string) (bool, error)); ok {
-		return rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (bool, error)); ok {
+		return rf(ctx, ip, port, password)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, string) bool); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) bool); ok {
+		r0 = rf(ctx, ip, port, password)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(ip, port, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -290,25 +295,300 @@ func (_m *Client) SlaveIsReady(ip string, port string, password string) (bool, e
 	return r0, r1
 }
 
-// GetReplicationInfo provides a mock function with given fields: ip, port, password
-func (_m *Client) GetReplicationInfo(ip string, port string, password string) (*redis.ReplicationInfo, error) {
-	ret := _m.Called(ip, port, password)
+// GetReplicationInfo provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) GetReplicationInfo(ctx context.Context, ip string, port string, password string) (*redis.ReplicationInfo, error) {
+	ret := _m.Called(ctx, ip, port, password)
 
 	var r0 *redis.ReplicationInfo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, string) (*redis.ReplicationInfo, error)); ok {
-		return rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*redis.ReplicationInfo, error)); ok {
+		return rf(ctx, ip, port, password)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, string) *redis.ReplicationInfo); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *redis.ReplicationInfo); ok {
+		r0 = rf(ctx, ip, port, password)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*redis.ReplicationInfo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(ip, port, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRedisVersion provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) GetRedisVersion(ctx context.Context, ip string, port string, password string) (string, error) {
+	ret := _m.Called(ctx, ip, port, password)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (string, error)); ok {
+		return rf(ctx, ip, port, password)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = rf(ctx, ip, port, password)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PauseWrites provides a mock function with given fields: ctx, ip, port, password, timeout
+func (_m *Client) PauseWrites(ctx context.Context, ip string, port string, password string, timeout time.Duration) error {
+	ret := _m.Called(ctx, ip, port, password, timeout)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Duration) error); ok {
+		r0 = rf(ctx, ip, port, password, timeout)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SentinelFailover provides a mock function with given fields: ctx, ip
+func (_m *Client) SentinelFailover(ctx context.Context, ip string) error {
+	ret := _m.Called(ctx, ip)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnpauseClients provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) UnpauseClients(ctx context.Context, ip string, port string, password string) error {
+	ret := _m.Called(ctx, ip, port, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, ip, port, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WithOptions provides a mock function with given fields: options
+func (_m *Client) WithOptions(options redis.ConnectionOptions) redis.Client {
+	ret := _m.Called(options)
+
+	var r0 redis.Client
+	if rf, ok := ret.Get(0).(func(redis.ConnectionOptions) redis.Client); ok {
+		r0 = rf(options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(redis.Client)
+		}
+	}
+
+	return r0
+}
+
+// DeleteACLUser provides a mock function with given fields: ctx, ip, port, password, user
+func (_m *Client) DeleteACLUser(ctx context.Context, ip string, port string, password string, user string) error {
+	ret := _m.Called(ctx, ip, port, password, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
+		r0 = rf(ctx, ip, port, password, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetACLUsers provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) GetACLUsers(ctx context.Context, ip string, port string, password string) (map[string]string, error) {
+	ret := _m.Called(ctx, ip, port, password)
+
+	var r0 map[string]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (map[string]string, error)); ok {
+		return rf(ctx, ip, port, password)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) map[string]string); ok {
+		r0 = rf(ctx, ip, port, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetACLUser provides a mock function with given fields: ctx, ip, port, password, user, rules
+func (_m *Client) SetACLUser(ctx context.Context, ip string, port string, password string, user string, rules []string) error {
+	ret := _m.Called(ctx, ip, port, password, user, rules)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, []string) error); ok {
+		r0 = rf(ctx, ip, port, password, user, rules)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BackgroundSave provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) BackgroundSave(ctx context.Context, ip string, port string, password string) error {
+	ret := _m.Called(ctx, ip, port, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, ip, port, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPersistenceInfo provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) GetPersistenceInfo(ctx context.Context, ip string, port string, password string) (*redis.PersistenceInfo, error) {
+	ret := _m.Called(ctx, ip, port, password)
+
+	var r0 *redis.PersistenceInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*redis.PersistenceInfo, error)); ok {
+		return rf(ctx, ip, port, password)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *redis.PersistenceInfo); ok {
+		r0 = rf(ctx, ip, port, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redis.PersistenceInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SyncRDB provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) SyncRDB(ctx context.Context, ip string, port string, password string) (io.ReadCloser, int64, error) {
+	ret := _m.Called(ctx, ip, port, password)
+
+	var r0 io.ReadCloser
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (io.ReadCloser, int64, error)); ok {
+		return rf(ctx, ip, port, password)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) io.ReadCloser); ok {
+		r0 = rf(ctx, ip, port, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) int64); ok {
+		r1 = rf(ctx, ip, port, password)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, string) error); ok {
+		r2 = rf(ctx, ip, port, password)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetRedisConfig provides a mock function with given fields: ctx, ip, port, password, parameters
+func (_m *Client) GetRedisConfig(ctx context.Context, ip string, port string, password string, parameters []string) (map[string]string, error) {
+	ret := _m.Called(ctx, ip, port, password, parameters)
+
+	var r0 map[string]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, []string) (map[string]string, error)); ok {
+		return rf(ctx, ip, port, password, parameters)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, []string) map[string]string); ok {
+		r0 = rf(ctx, ip, port, password, parameters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, []string) error); ok {
+		r1 = rf(ctx, ip, port, password, parameters)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetSentinelGlobalConfig provides a mock function with given fields: ctx, ip, configs
+func (_m *Client) SetSentinelGlobalConfig(ctx context.Context, ip string, configs []string) error {
+	ret := _m.Called(ctx, ip, configs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) error); ok {
+		r0 = rf(ctx, ip, configs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSentinelReplicaPriorities provides a mock function with given fields: ctx, ip
+func (_m *Client) GetSentinelReplicaPriorities(ctx context.Context, ip string) (map[string]string, error) {
+	ret := _m.Called(ctx, ip)
+
+	var r0 map[string]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (map[string]string, error)); ok {
+		return rf(ctx, ip)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) map[string]string); ok {
+		r0 = rf(ctx, ip)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ip)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -316,6 +596,20 @@ func (_m *Client) GetReplicationInfo(ip string, port string, password string) (*
 	return r0, r1
 }
 
+// SetSentinelCommandRenames provides a mock function with given fields: ctx, ip
+func (_m *Client) SetSentinelCommandRenames(ctx context.Context, ip string) error {
+	ret := _m.Called(ctx, ip)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewClient interface {
 	mock.TestingT
 	Cleanup(func())
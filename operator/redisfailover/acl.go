@@ -0,0 +1,63 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+)
+
+// SyncACLUsers applies the declared ACL users to every redis node, the users being stored by
+// each node. The changes made while the rules of the users, their passwords included, did not
+// change since the last sync are reported as drift on the ACLUsersInSync condition, as they
+// reveal users modified outside of the operator.
+func (r *RedisFailoverHandler) SyncACLUsers(ctx context.Context, rf *redisfailoverv1.RedisFailover) {
+	if !rf.ACLEnabled() {
+		rf.RemoveCondition(redisfailoverv1.ConditionACLUsersInSync)
+		rf.Status.ACLUsersChecksum = ""
+		return
+	}
+
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+
+	checksum, err := r.rfChecker.GetACLUsersChecksum(rf)
+	if err != nil {
+		logger.Errorf("Unable to get the ACL rules of the users: %s", err.Error())
+		rf.SetCondition(redisfailoverv1.ConditionACLUsersInSync, metav1.ConditionFalse, redisfailoverv1.ReasonACLSyncFailed, err.Error())
+		return
+	}
+
+	redises, err := r.rfChecker.GetRedisesIPs(rf)
+	if err != nil {
+		logger.Errorf("Unable to get the redis nodes to sync the ACL users: %s", err.Error())
+		rf.SetCondition(redisfailoverv1.ConditionACLUsersInSync, metav1.ConditionFalse, redisfailoverv1.ReasonACLSyncFailed, err.Error())
+		return
+	}
+
+	drift := []string{}
+	for _, rip := range redises {
+		changes, err := r.rfHealer.SyncACLUsers(ctx, rip, rf)
+		if err != nil {
+			logger.Errorf("Unable to sync the ACL users of %s: %s", rip, err.Error())
+			rf.SetCondition(redisfailoverv1.ConditionACLUsersInSync, metav1.ConditionFalse, redisfailoverv1.ReasonACLSyncFailed, fmt.Sprintf("%s: %s", rip, err.Error()))
+			return
+		}
+		for _, change := range changes {
+			drift = append(drift, fmt.Sprintf("%s: %s", rip, change))
+		}
+	}
+
+	// The checksum is only recorded once every redis node is synced, so the changes still
+	// applied to the nodes a failed sync did not reach are not taken for drift
+	rulesChanged := rf.Status.ACLUsersChecksum != checksum
+	rf.Status.ACLUsersChecksum = checksum
+	if len(drift) > 0 && !rulesChanged {
+		logger.Warningf("Corrected ACL users drift: %s", strings.Join(drift, ", "))
+		rf.SetCondition(redisfailoverv1.ConditionACLUsersInSync, metav1.ConditionFalse, redisfailoverv1.ReasonACLUsersDrifted, fmt.Sprintf("corrected drift: %s", strings.Join(drift, ", ")))
+		return
+	}
+	rf.SetCondition(redisfailoverv1.ConditionACLUsersInSync, metav1.ConditionTrue, redisfailoverv1.ReasonACLUsersSynced, "ACL users applied to every redis node")
+}
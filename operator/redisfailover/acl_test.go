@@ -0,0 +1,129 @@
+package redisfailover_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+	mRFService "github.com/saremox/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/saremox/redis-operator/operator/redisfailover"
+)
+
+func TestSyncACLUsers(t *testing.T) {
+	tests := []struct {
+		name           string
+		users          []v1.RedisUser
+		lastChecksum   string
+		changes        []string
+		healErr        error
+		expCondition   metav1.ConditionStatus
+		expReason      string
+		expNoSync      bool
+		expNoCondition bool
+	}{
+		{
+			name:           "ACL users not managed",
+			expNoSync:      true,
+			expNoCondition: true,
+		},
+		{
+			name:         "Users applied after a spec change",
+			users:        []v1.RedisUser{{Name: "app", SecretPath: "app-secret"}},
+			changes:      []string{"created user app"},
+			expCondition: metav1.ConditionTrue,
+			expReason:    v1.ReasonACLUsersSynced,
+		},
+		{
+			name:         "Users applied after a password change",
+			users:        []v1.RedisUser{{Name: "app", SecretPath: "app-secret"}},
+			lastChecksum: "previous-password",
+			changes:      []string{"updated user app"},
+			expCondition: metav1.ConditionTrue,
+			expReason:    v1.ReasonACLUsersSynced,
+		},
+		{
+			name:         "Users in sync",
+			users:        []v1.RedisUser{{Name: "app", SecretPath: "app-secret"}},
+			lastChecksum: "rules",
+			expCondition: metav1.ConditionTrue,
+			expReason:    v1.ReasonACLUsersSynced,
+		},
+		{
+			name:         "Users modified outside of the operator",
+			users:        []v1.RedisUser{{Name: "app", SecretPath: "app-secret"}},
+			lastChecksum: "rules",
+			changes:      []string{"deleted user intruder"},
+			expCondition: metav1.ConditionFalse,
+			expReason:    v1.ReasonACLUsersDrifted,
+		},
+		{
+			name:         "Users sync fails",
+			users:        []v1.RedisUser{{Name: "app", SecretPath: "app-secret"}},
+			lastChecksum: "previous-password",
+			healErr:      errors.New(""),
+			expCondition: metav1.ConditionFalse,
+			expReason:    v1.ReasonACLSyncFailed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			rf.Generation = 2
+			rf.Spec.Auth.Users = test.users
+			rf.Status.ACLUsersChecksum = test.lastChecksum
+			rf.Status.Conditions = []metav1.Condition{{
+				Type:   v1.ConditionACLUsersInSync,
+				Status: metav1.ConditionTrue,
+				Reason: v1.ReasonACLUsersSynced,
+			}}
+
+			mk := &mK8SService.Services{}
+			mrfs := &mRFService.RedisFailoverClient{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+			if !test.expNoSync {
+				mrfc.On("GetACLUsersChecksum", rf).Once().Return("rules", nil)
+				mrfc.On("GetRedisesIPs", rf).Once().Return([]string{"0.0.0.0", "0.0.0.1"}, nil)
+				mrfh.On("SyncACLUsers", mock.Anything, "0.0.0.0", rf).Once().Return(test.changes, test.healErr)
+				if test.healErr == nil {
+					mrfh.On("SyncACLUsers", mock.Anything, "0.0.0.1", rf).Once().Return([]string{}, nil)
+				}
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			handler.SyncACLUsers(context.TODO(), rf)
+
+			condition := rf.GetCondition(v1.ConditionACLUsersInSync)
+			if test.expNoCondition {
+				assert.Nil(condition)
+			} else if assert.NotNil(condition) {
+				assert.Equal(test.expCondition, condition.Status)
+				assert.Equal(test.expReason, condition.Reason)
+				assert.Equal(rf.Generation, condition.ObservedGeneration)
+			}
+			// The rules are recorded once every redis node is synced
+			switch {
+			case test.expNoSync:
+				assert.Empty(rf.Status.ACLUsersChecksum)
+			case test.healErr != nil:
+				assert.Equal(test.lastChecksum, rf.Status.ACLUsersChecksum)
+			default:
+				assert.Equal("rules", rf.Status.ACLUsersChecksum)
+			}
+
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
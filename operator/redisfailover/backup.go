@@ -0,0 +1,272 @@
+package redisfailover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+	"github.com/saremox/redis-operator/service/k8s"
+)
+
+// ScheduleBackup creates the RedisFailoverBackup of the last activation of the backup schedule
+// that has not been taken yet. The schedule starts when the backups are enabled, the activations
+// missed while the operator was down are taken as a single backup.
+func (r *RedisFailoverHandler) ScheduleBackup(ctx context.Context, rf *redisfailoverv1.RedisFailover) {
+	if !rf.BackupScheduled() {
+		rf.Status.LastBackupScheduleTime = nil
+		return
+	}
+
+	now := time.Now()
+	if rf.Status.LastBackupScheduleTime == nil {
+		rf.Status.LastBackupScheduleTime = &metav1.Time{Time: now}
+		return
+	}
+
+	schedule, err := redisfailoverv1.ParseSchedule(rf.Spec.Backup.Schedule)
+	if err != nil {
+		return
+	}
+	activation := schedule.Last(rf.Status.LastBackupScheduleTime.Time, now)
+	if activation.IsZero() {
+		return
+	}
+
+	backup := &redisfailoverv1.RedisFailoverBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-%s", rf.Name, activation.UTC().Format("200601021504")),
+			Namespace:       rf.Namespace,
+			Labels:          r.getLabels(rf),
+			OwnerReferences: r.createOwnerReferences(rf),
+		},
+		Spec: redisfailoverv1.RedisFailoverBackupSpec{
+			RedisFailoverName: rf.Name,
+			Storage:           rf.Spec.Backup.Storage.DeepCopy(),
+			AllowMaster:       rf.Spec.Backup.AllowMaster,
+		},
+	}
+	err = r.k8sservice.CreateRedisFailoverBackup(ctx, rf.Namespace, backup)
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).Errorf("Unable to create the scheduled backup: %s", err.Error())
+		return
+	}
+	rf.Status.LastBackupScheduleTime = &metav1.Time{Time: activation}
+}
+
+// RedisFailoverBackupHandler is the RedisFailoverBackup handler. It takes the snapshots of the
+// Redis failovers and uploads them to object storage.
+type RedisFailoverBackupHandler struct {
+	k8sservice k8s.Services
+	rfChecker  rfservice.RedisFailoverCheck
+	rfBackup   rfservice.RedisFailoverBackup
+	logger     log.Logger
+}
+
+// NewRedisFailoverBackupHandler returns a new RF backup handler
+func NewRedisFailoverBackupHandler(rfChecker rfservice.RedisFailoverCheck, rfBackup rfservice.RedisFailoverBackup, k8sservice k8s.Services, logger log.Logger) *RedisFailoverBackupHandler {
+	return &RedisFailoverBackupHandler{
+		k8sservice: k8sservice,
+		rfChecker:  rfChecker,
+		rfBackup:   rfBackup,
+		logger:     logger,
+	}
+}
+
+// Handle takes the snapshot of a backup that has not been taken yet. A backup interrupted while
+// running is taken again. The outcome is recorded in the status of the backup, a failed backup
+// is not retried.
+func (h *RedisFailoverBackupHandler) Handle(ctx context.Context, obj runtime.Object) error {
+	cached, ok := obj.(*redisfailoverv1.RedisFailoverBackup)
+	if !ok {
+		return fmt.Errorf("can't handle the received object: not a redisfailoverbackup")
+	}
+	if cached.Status.Phase == redisfailoverv1.BackupPhaseCompleted || cached.Status.Phase == redisfailoverv1.BackupPhaseFailed {
+		return nil
+	}
+	// The cache may not have caught up with the outcome of the previous run yet
+	backup, err := h.k8sservice.GetRedisFailoverBackup(ctx, cached.Namespace, cached.Name)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if backup.Status.Phase == redisfailoverv1.BackupPhaseCompleted || backup.Status.Phase == redisfailoverv1.BackupPhaseFailed {
+		return nil
+	}
+
+	logger := h.logger.WithField("redisfailoverbackup", backup.Name).WithField("namespace", backup.Namespace)
+
+	rf, err := h.k8sservice.GetRedisFailover(ctx, backup.Namespace, backup.Spec.RedisFailoverName)
+	if k8serrors.IsNotFound(err) {
+		return h.fail(ctx, backup, fmt.Errorf("redisfailover %q not found", backup.Spec.RedisFailoverName))
+	}
+	if err != nil {
+		return err
+	}
+	if err := rf.Validate(); err != nil {
+		return h.fail(ctx, backup, err)
+	}
+
+	storage := backup.Spec.Storage
+	if storage == nil && rf.Spec.Backup != nil {
+		storage = &rf.Spec.Backup.Storage
+	}
+	if storage == nil {
+		return h.fail(ctx, backup, errors.New("no storage in the backup nor in the backup settings of the redisfailover"))
+	}
+	if err := storage.Validate(); err != nil {
+		return h.fail(ctx, backup, err)
+	}
+
+	node, err := h.getBackupNode(ctx, rf, backup.Spec.AllowMaster)
+	if err != nil {
+		return h.fail(ctx, backup, err)
+	}
+
+	backup.Status = redisfailoverv1.RedisFailoverBackupStatus{
+		Phase:     redisfailoverv1.BackupPhaseRunning,
+		PodName:   node.PodName,
+		StartTime: &metav1.Time{Time: time.Now()},
+	}
+	backup, err = h.k8sservice.UpdateRedisFailoverBackup(ctx, backup.Namespace, backup)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Taking the snapshot of %s", node.PodName)
+
+	result, err := h.rfBackup.BackupRedis(ctx, node.IP, rf, storage, backup.Name)
+	if err != nil {
+		logger.Errorf("Backup failed: %s", err.Error())
+		if err := h.fail(ctx, backup, err); err != nil {
+			return err
+		}
+		h.pruneBackups(ctx, rf)
+		return nil
+	}
+
+	backup.Status.Phase = redisfailoverv1.BackupPhaseCompleted
+	backup.Status.Message = ""
+	backup.Status.Location = result.Location
+	backup.Status.Size = result.Size
+	backup.Status.Checksum = result.Checksum
+	backup.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+	if _, err := h.k8sservice.UpdateRedisFailoverBackup(ctx, backup.Namespace, backup); err != nil {
+		return err
+	}
+	logger.Infof("Backup completed to %s", result.Location)
+
+	h.pruneBackups(ctx, rf)
+	return nil
+}
+
+// fail records the failure of the backup
+func (h *RedisFailoverBackupHandler) fail(ctx context.Context, backup *redisfailoverv1.RedisFailoverBackup, cause error) error {
+	backup.Status.Phase = redisfailoverv1.BackupPhaseFailed
+	backup.Status.Message = cause.Error()
+	backup.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+	_, err := h.k8sservice.UpdateRedisFailoverBackup(ctx, backup.Namespace, backup)
+	return err
+}
+
+// getBackupNode returns the replica in sync with the highest replication offset, or the master
+// when there is none and it is allowed
+func (h *RedisFailoverBackupHandler) getBackupNode(ctx context.Context, rf *redisfailoverv1.RedisFailover, allowMaster bool) (*rfservice.RedisNodeReplication, error) {
+	nodes, err := h.rfChecker.GetRedisesReplication(ctx, rf)
+	if err != nil {
+		return nil, err
+	}
+
+	var replica, master *rfservice.RedisNodeReplication
+	for i := range nodes {
+		node := &nodes[i]
+		if node.Replication.Role == "master" {
+			master = node
+			continue
+		}
+		if node.Replication.MasterLinkStatus != "up" || node.Replication.SyncInProgress {
+			continue
+		}
+		if replica == nil || node.Replication.SlaveReplOffset > replica.Replication.SlaveReplOffset {
+			replica = node
+		}
+	}
+
+	switch {
+	case replica != nil:
+		return replica, nil
+	case master != nil && allowMaster:
+		return master, nil
+	default:
+		return nil, errors.New("no replica in sync to take the snapshot from, set allowMaster to take it from the master")
+	}
+}
+
+// pruneBackups deletes the scheduled backups of the Redis failover beyond the retention, along
+// with their snapshot. The backups failing to be deleted are deleted after the next backup.
+func (h *RedisFailoverBackupHandler) pruneBackups(ctx context.Context, rf *redisfailoverv1.RedisFailover) {
+	if rf.Spec.Backup == nil || rf.Spec.Backup.Retention.KeepLast <= 0 {
+		return
+	}
+	keepLast := int(rf.Spec.Backup.Retention.KeepLast)
+	logger := h.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace)
+
+	backupList, err := h.k8sservice.ListRedisFailoverBackups(ctx, rf.Namespace, metav1.ListOptions{})
+	if err != nil {
+		logger.Errorf("Unable to list the backups to apply the retention: %s", err.Error())
+		return
+	}
+
+	completed := []redisfailoverv1.RedisFailoverBackup{}
+	failed := []redisfailoverv1.RedisFailoverBackup{}
+	for _, backup := range backupList.Items {
+		if owner := metav1.GetControllerOf(&backup); owner == nil || owner.UID != rf.UID {
+			continue
+		}
+		switch backup.Status.Phase {
+		case redisfailoverv1.BackupPhaseCompleted:
+			completed = append(completed, backup)
+		case redisfailoverv1.BackupPhaseFailed:
+			failed = append(failed, backup)
+		}
+	}
+
+	expired := append(expiredBackups(completed, keepLast), expiredBackups(failed, keepLast)...)
+	for _, backup := range expired {
+		if backup.Status.Location != "" && backup.Spec.Storage != nil {
+			if err := h.rfBackup.DeleteBackup(ctx, backup.Namespace, backup.Spec.Storage, backup.Status.Location); err != nil {
+				logger.Errorf("Unable to delete the snapshot of backup %s: %s", backup.Name, err.Error())
+				continue
+			}
+		}
+		if err := h.k8sservice.DeleteRedisFailoverBackup(ctx, backup.Namespace, backup.Name); err != nil && !k8serrors.IsNotFound(err) {
+			logger.Errorf("Unable to delete backup %s: %s", backup.Name, err.Error())
+			continue
+		}
+		logger.Infof("Backup %s deleted by the retention policy", backup.Name)
+	}
+}
+
+// expiredBackups returns the backups older than the newest keepLast ones
+func expiredBackups(backups []redisfailoverv1.RedisFailoverBackup, keepLast int) []redisfailoverv1.RedisFailoverBackup {
+	sort.Slice(backups, func(i, j int) bool {
+		ti, tj := backups[i].CreationTimestamp, backups[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return tj.Before(&ti)
+		}
+		return backups[i].Name > backups[j].Name
+	})
+	if len(backups) <= keepLast {
+		return nil
+	}
+	return backups[keepLast:]
+}
@@ -0,0 +1,319 @@
+package redisfailover_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+	mRFService "github.com/saremox/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/saremox/redis-operator/operator/redisfailover"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+	"github.com/saremox/redis-operator/service/redis"
+)
+
+func generateBackupSettings(schedule string, keepLast int32) *redisfailoverv1.BackupSettings {
+	return &redisfailoverv1.BackupSettings{
+		Schedule: schedule,
+		Storage: redisfailoverv1.BackupStorage{
+			S3: &redisfailoverv1.S3Storage{Endpoint: "http://minio:9000", Bucket: "backups", SecretName: "s3-credentials"},
+		},
+		Retention: redisfailoverv1.BackupRetention{KeepLast: keepLast},
+	}
+}
+
+func TestScheduleBackup(t *testing.T) {
+	now := time.Now().UTC()
+	lastHour := now.Truncate(time.Hour)
+
+	tests := []struct {
+		name        string
+		backup      *redisfailoverv1.BackupSettings
+		lastTime    *time.Time
+		createErr   error
+		expCreate   string
+		expLastTime func(*metav1.Time) bool
+	}{
+		{
+			name:        "No backup schedule",
+			lastTime:    &lastHour,
+			expLastTime: func(t *metav1.Time) bool { return t == nil },
+		},
+		{
+			name:        "Schedule starts when the backups are enabled",
+			backup:      generateBackupSettings("@hourly", 0),
+			expLastTime: func(t *metav1.Time) bool { return t != nil && !t.Time.Before(now) },
+		},
+		{
+			name:        "No activation since the last backup",
+			backup:      generateBackupSettings("@hourly", 0),
+			lastTime:    &lastHour,
+			expLastTime: func(t *metav1.Time) bool { return t.Time.Equal(lastHour) },
+		},
+		{
+			name:        "Backup of the last activation created",
+			backup:      generateBackupSettings("@hourly", 0),
+			lastTime:    func() *time.Time { t := lastHour.Add(-3 * time.Hour); return &t }(),
+			expCreate:   "test-" + lastHour.Format("200601021504"),
+			expLastTime: func(t *metav1.Time) bool { return t.Time.Equal(lastHour) },
+		},
+		{
+			name:        "Backup of the last activation already created",
+			backup:      generateBackupSettings("@hourly", 0),
+			lastTime:    func() *time.Time { t := lastHour.Add(-time.Hour); return &t }(),
+			createErr:   k8serrors.NewAlreadyExists(schema.GroupResource{}, "test"),
+			expCreate:   "test-" + lastHour.Format("200601021504"),
+			expLastTime: func(t *metav1.Time) bool { return t.Time.Equal(lastHour) },
+		},
+		{
+			name:        "Backup creation failed",
+			backup:      generateBackupSettings("@hourly", 0),
+			lastTime:    func() *time.Time { t := lastHour.Add(-time.Hour); return &t }(),
+			createErr:   errors.New(""),
+			expCreate:   "test-" + lastHour.Format("200601021504"),
+			expLastTime: func(t *metav1.Time) bool { return t.Time.Equal(lastHour.Add(-time.Hour)) },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			rf.Spec.Backup = test.backup
+			if test.lastTime != nil {
+				rf.Status.LastBackupScheduleTime = &metav1.Time{Time: *test.lastTime}
+			}
+
+			mk := &mK8SService.Services{}
+			if test.expCreate != "" {
+				mk.On("CreateRedisFailoverBackup", mock.Anything, namespace, mock.MatchedBy(func(backup *redisfailoverv1.RedisFailoverBackup) bool {
+					return backup.Name == test.expCreate &&
+						backup.Spec.RedisFailoverName == rf.Name &&
+						backup.Spec.Storage.S3.Bucket == "backups" &&
+						metav1.IsControlledBy(backup, rf)
+				})).Once().Return(test.createErr)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, &mRFService.RedisFailoverCheck{}, &mRFService.RedisFailoverHeal{}, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			handler.ScheduleBackup(context.TODO(), rf)
+
+			assert.True(test.expLastTime(rf.Status.LastBackupScheduleTime))
+			mk.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBackupHandler(t *testing.T) {
+	replica := func(pod, ip string, offset int64, linkStatus string) rfservice.RedisNodeReplication {
+		return rfservice.RedisNodeReplication{PodName: pod, IP: ip, Replication: &redis.ReplicationInfo{Role: "slave", MasterLinkStatus: linkStatus, SlaveReplOffset: offset}}
+	}
+	master := rfservice.RedisNodeReplication{PodName: "rfr-test-0", IP: "0.0.0.0", Replication: &redis.ReplicationInfo{Role: "master"}}
+
+	tests := []struct {
+		name        string
+		phase       redisfailoverv1.BackupPhase
+		rfNotFound  bool
+		rfBackup    *redisfailoverv1.BackupSettings
+		storage     bool
+		allowMaster bool
+		nodes       []rfservice.RedisNodeReplication
+		backupErr   error
+		expIP       string
+		expPhase    redisfailoverv1.BackupPhase
+		expMessage  string
+	}{
+		{
+			name:  "Completed backup",
+			phase: redisfailoverv1.BackupPhaseCompleted,
+		},
+		{
+			name:     "Backup of the replica in sync with the highest offset",
+			storage:  true,
+			nodes:    []rfservice.RedisNodeReplication{master, replica("rfr-test-1", "0.0.0.1", 100, "up"), replica("rfr-test-2", "0.0.0.2", 200, "up"), replica("rfr-test-3", "0.0.0.3", 300, "down")},
+			expIP:    "0.0.0.2",
+			expPhase: redisfailoverv1.BackupPhaseCompleted,
+		},
+		{
+			name:     "Interrupted backup taken again with the storage of the redisfailover",
+			phase:    redisfailoverv1.BackupPhaseRunning,
+			rfBackup: generateBackupSettings("@daily", 0),
+			nodes:    []rfservice.RedisNodeReplication{master, replica("rfr-test-1", "0.0.0.1", 100, "up")},
+			expIP:    "0.0.0.1",
+			expPhase: redisfailoverv1.BackupPhaseCompleted,
+		},
+		{
+			name:       "No replica in sync",
+			storage:    true,
+			nodes:      []rfservice.RedisNodeReplication{master, replica("rfr-test-1", "0.0.0.1", 100, "down")},
+			expPhase:   redisfailoverv1.BackupPhaseFailed,
+			expMessage: "no replica in sync to take the snapshot from, set allowMaster to take it from the master",
+		},
+		{
+			name:        "Master allowed",
+			storage:     true,
+			allowMaster: true,
+			nodes:       []rfservice.RedisNodeReplication{master},
+			expIP:       "0.0.0.0",
+			expPhase:    redisfailoverv1.BackupPhaseCompleted,
+		},
+		{
+			name:       "No storage",
+			expPhase:   redisfailoverv1.BackupPhaseFailed,
+			expMessage: "no storage in the backup nor in the backup settings of the redisfailover",
+		},
+		{
+			name:       "Redisfailover not found",
+			rfNotFound: true,
+			expPhase:   redisfailoverv1.BackupPhaseFailed,
+			expMessage: `redisfailover "test" not found`,
+		},
+		{
+			name:       "Backup failed",
+			storage:    true,
+			nodes:      []rfservice.RedisNodeReplication{master, replica("rfr-test-1", "0.0.0.1", 100, "up")},
+			backupErr:  errors.New("background save failed, check the logs of the redis node"),
+			expIP:      "0.0.0.1",
+			expPhase:   redisfailoverv1.BackupPhaseFailed,
+			expMessage: "background save failed, check the logs of the redis node",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			rf.Spec.Backup = test.rfBackup
+
+			backup := &redisfailoverv1.RedisFailoverBackup{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-manual", Namespace: namespace},
+				Spec:       redisfailoverv1.RedisFailoverBackupSpec{RedisFailoverName: rf.Name, AllowMaster: test.allowMaster},
+				Status:     redisfailoverv1.RedisFailoverBackupStatus{Phase: test.phase},
+			}
+			if test.storage {
+				backup.Spec.Storage = &generateBackupSettings("", 0).Storage
+			}
+
+			mk := &mK8SService.Services{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfb := &mRFService.RedisFailoverBackup{}
+
+			updates := []redisfailoverv1.RedisFailoverBackupStatus{}
+			if test.expPhase != "" {
+				mk.On("GetRedisFailoverBackup", mock.Anything, namespace, backup.Name).Once().Return(backup.DeepCopy(), nil)
+				if test.rfNotFound {
+					mk.On("GetRedisFailover", mock.Anything, namespace, rf.Name).Once().Return(nil, k8serrors.NewNotFound(schema.GroupResource{}, rf.Name))
+				} else {
+					mk.On("GetRedisFailover", mock.Anything, namespace, rf.Name).Once().Return(rf, nil)
+				}
+				mk.On("UpdateRedisFailoverBackup", mock.Anything, namespace, mock.Anything).Return(func(_ context.Context, _ string, b *redisfailoverv1.RedisFailoverBackup) *redisfailoverv1.RedisFailoverBackup {
+					updates = append(updates, *b.Status.DeepCopy())
+					return b.DeepCopy()
+				}, nil)
+			}
+			if test.nodes != nil {
+				mrfc.On("GetRedisesReplication", mock.Anything, rf).Once().Return(test.nodes, nil)
+			}
+			if test.expIP != "" {
+				result := &rfservice.BackupResult{Location: "s3://backups/test/test-manual.rdb", Size: 1024, Checksum: "sha256:0123"}
+				if test.backupErr != nil {
+					result = nil
+				}
+				mrfb.On("BackupRedis", mock.Anything, test.expIP, rf, mock.Anything, backup.Name).Once().Return(result, test.backupErr)
+			}
+
+			handler := rfOperator.NewRedisFailoverBackupHandler(mrfc, mrfb, mk, log.Dummy)
+			err := handler.Handle(context.Background(), backup)
+			assert.NoError(err)
+
+			if test.expPhase == "" {
+				assert.Empty(updates)
+			} else if assert.NotEmpty(updates) {
+				status := updates[len(updates)-1]
+				assert.Equal(test.expPhase, status.Phase)
+				assert.Equal(test.expMessage, status.Message)
+				assert.NotNil(status.CompletionTime)
+				if test.expPhase == redisfailoverv1.BackupPhaseCompleted {
+					assert.Equal(redisfailoverv1.BackupPhaseRunning, updates[0].Phase)
+					assert.Equal("s3://backups/test/test-manual.rdb", status.Location)
+					assert.Equal(int64(1024), status.Size)
+					assert.Equal("sha256:0123", status.Checksum)
+					assert.NotEmpty(status.PodName)
+					assert.NotNil(status.StartTime)
+				}
+			}
+
+			mk.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfb.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBackupHandlerRetention(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false)
+	rf.UID = types.UID("rf-uid")
+	rf.Spec.Backup = generateBackupSettings("@hourly", 2)
+	ownerRefs := []metav1.OwnerReference{*metav1.NewControllerRef(rf, redisfailoverv1.VersionKind(redisfailoverv1.RFKind))}
+
+	generateBackup := func(name string, age time.Duration, phase redisfailoverv1.BackupPhase, owned bool) redisfailoverv1.RedisFailoverBackup {
+		backup := redisfailoverv1.RedisFailoverBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, CreationTimestamp: metav1.NewTime(time.Now().Add(-age))},
+			Spec:       redisfailoverv1.RedisFailoverBackupSpec{RedisFailoverName: rf.Name, Storage: &rf.Spec.Backup.Storage},
+			Status:     redisfailoverv1.RedisFailoverBackupStatus{Phase: phase},
+		}
+		if phase == redisfailoverv1.BackupPhaseCompleted {
+			backup.Status.Location = "s3://backups/test/" + name + ".rdb"
+		}
+		if owned {
+			backup.OwnerReferences = ownerRefs
+		}
+		return backup
+	}
+	running := generateBackup("test-5", 0, "", true)
+	backups := &redisfailoverv1.RedisFailoverBackupList{Items: []redisfailoverv1.RedisFailoverBackup{
+		generateBackup("test-1", 5*time.Hour, redisfailoverv1.BackupPhaseCompleted, true),
+		generateBackup("test-2", 4*time.Hour, redisfailoverv1.BackupPhaseFailed, true),
+		generateBackup("test-3", 3*time.Hour, redisfailoverv1.BackupPhaseCompleted, true),
+		generateBackup("test-4", 2*time.Hour, redisfailoverv1.BackupPhaseCompleted, true),
+		generateBackup("manual", 10*time.Hour, redisfailoverv1.BackupPhaseCompleted, false),
+		running,
+	}}
+
+	mk := &mK8SService.Services{}
+	mk.On("GetRedisFailoverBackup", mock.Anything, namespace, running.Name).Once().Return(running.DeepCopy(), nil)
+	mk.On("GetRedisFailover", mock.Anything, namespace, rf.Name).Once().Return(rf, nil)
+	mk.On("UpdateRedisFailoverBackup", mock.Anything, namespace, mock.Anything).Twice().Return(func(_ context.Context, _ string, b *redisfailoverv1.RedisFailoverBackup) *redisfailoverv1.RedisFailoverBackup {
+		return b.DeepCopy()
+	}, nil)
+	mk.On("ListRedisFailoverBackups", mock.Anything, namespace, metav1.ListOptions{}).Once().Return(backups, nil)
+	mk.On("DeleteRedisFailoverBackup", mock.Anything, namespace, "test-1").Once().Return(nil)
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfc.On("GetRedisesReplication", mock.Anything, rf).Once().Return([]rfservice.RedisNodeReplication{
+		{PodName: "rfr-test-1", IP: "0.0.0.1", Replication: &redis.ReplicationInfo{Role: "slave", MasterLinkStatus: "up"}},
+	}, nil)
+	mrfb := &mRFService.RedisFailoverBackup{}
+	mrfb.On("BackupRedis", mock.Anything, "0.0.0.1", rf, running.Spec.Storage, running.Name).Once().Return(&rfservice.BackupResult{Location: "s3://backups/test/test-5.rdb"}, nil)
+	mrfb.On("DeleteBackup", mock.Anything, namespace, &rf.Spec.Backup.Storage, "s3://backups/test/test-1.rdb").Once().Return(nil)
+
+	handler := rfOperator.NewRedisFailoverBackupHandler(mrfc, mrfb, mk, log.Dummy)
+	assert.NoError(handler.Handle(context.Background(), &running))
+
+	mk.AssertExpectations(t)
+	mrfc.AssertExpectations(t)
+	mrfb.AssertExpectations(t)
+}
@@ -3,8 +3,10 @@ package redisfailover
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/saremox/redis-operator/service/k8s"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sort"
 	"strconv"
 	"time"
 
@@ -14,7 +16,7 @@ import (
 )
 
 // UpdateRedisesPods if the running version of pods is equal to the statefulset one
-func (r *RedisFailoverHandler) UpdateRedisesPods(rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHandler) UpdateRedisesPods(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
 	redises, err := r.rfChecker.GetRedisesIPs(rf)
 	if err != nil {
 		return err
@@ -22,13 +24,13 @@ func (r *RedisFailoverHandler) UpdateRedisesPods(rf *redisfailoverv1.RedisFailov
 
 	masterIP := ""
 	if !rf.Bootstrapping() {
-		masterIP, _ = r.rfChecker.GetMasterIP(rf)
+		masterIP, _ = r.rfChecker.GetMasterIP(ctx, rf)
 		r.logger.WithField("namespace", rf.Namespace).WithField("name", rf.Name).WithField("masterIP", masterIP).Debug("got master IP")
 	}
 	// No performed updates when nodes are syncing, still not connected, etc.
 	for _, rip := range redises {
 		if rip != masterIP {
-			ready, err := r.rfChecker.CheckRedisSlavesReady(rip, rf)
+			ready, err := r.rfChecker.CheckRedisSlavesReady(ctx, rip, rf)
 			r.logger.WithField("namespace", rf.Namespace).WithField("name", rf.Name).WithField("ready", ready).Debug("got secondary state")
 			if err != nil {
 				return err
@@ -45,7 +47,7 @@ func (r *RedisFailoverHandler) UpdateRedisesPods(rf *redisfailoverv1.RedisFailov
 	}
 	r.logger.WithField("namespace", rf.Namespace).WithField("name", rf.Name).WithField("ssUR", ssUR).Debug("got StatefulSet update revision")
 
-	redisesPods, err := r.rfChecker.GetRedisesSlavesPods(rf)
+	redisesPods, err := r.rfChecker.GetRedisesSlavesPods(ctx, rf)
 	if err != nil {
 		return err
 	}
@@ -58,6 +60,7 @@ func (r *RedisFailoverHandler) UpdateRedisesPods(rf *redisfailoverv1.RedisFailov
 		}
 		if revision != ssUR {
 			//Delete pod and wait next round to check if the new one is synced
+			rf.SetCondition(redisfailoverv1.ConditionUpgrading, metav1.ConditionTrue, redisfailoverv1.ReasonRollingUpdate, "rolling pods to revision "+ssUR)
 			err = r.rfHealer.DeletePod(pod, rf)
 			if err != nil {
 				return err
@@ -69,7 +72,7 @@ func (r *RedisFailoverHandler) UpdateRedisesPods(rf *redisfailoverv1.RedisFailov
 
 	if !rf.Bootstrapping() {
 		// Update stale pod with role master
-		master, err := r.rfChecker.GetRedisesMasterPod(rf)
+		master, err := r.rfChecker.GetRedisesMasterPod(ctx, rf)
 		if err != nil {
 			return err
 		}
@@ -79,6 +82,13 @@ func (r *RedisFailoverHandler) UpdateRedisesPods(rf *redisfailoverv1.RedisFailov
 			return err
 		}
 		if masterRevision != ssUR {
+			rf.SetCondition(redisfailoverv1.ConditionUpgrading, metav1.ConditionTrue, redisfailoverv1.ReasonRollingUpdate, "rolling pods to revision "+ssUR)
+			// Hand the master role over to an up to date replica first, so no writes are lost
+			// while waiting for a failover
+			err = r.switchoverStaleMaster(ctx, rf, masterIP)
+			if err != nil {
+				return err
+			}
 			err = r.rfHealer.DeletePod(master, rf)
 			if err != nil {
 				return err
@@ -88,28 +98,31 @@ func (r *RedisFailoverHandler) UpdateRedisesPods(rf *redisfailoverv1.RedisFailov
 		}
 	}
 
+	rf.SetCondition(redisfailoverv1.ConditionUpgrading, metav1.ConditionFalse, redisfailoverv1.ReasonRevisionUpToDate, "all pods run revision "+ssUR)
 	return nil
 }
 
 // CheckAndHeal runs verifcation checks to ensure the RedisFailover is in an expected and healthy state.
 // If the checks do not match up to expectations, an attempt will be made to "heal" the RedisFailover into a healthy state.
-func (r *RedisFailoverHandler) CheckAndHeal(rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHandler) CheckAndHeal(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
 
 	oldState := rf.Status.State
 
-	rf.Status = redisfailoverv1.RedisFailoverStatus{
-		State: redisfailoverv1.HealthyState,
-	}
+	rf.Status.State = redisfailoverv1.HealthyState
+	rf.Status.Message = ""
 
-	defer updateStatus(r.k8sservice, rf, oldState)
+	defer func() {
+		r.updateReplicationStatus(ctx, rf)
+		updateStatus(r.k8sservice, rf, oldState)
+	}()
 
 	if rf.Bootstrapping() {
-		return r.checkAndHealBootstrapMode(rf)
+		return r.checkAndHealBootstrapMode(ctx, rf)
 	}
 
 	// Route to operator-managed mode when Sentinel is disabled
 	if rf.OperatorManagedFailover() {
-		return r.checkAndHealOperatorManagedMode(rf)
+		return r.checkAndHealOperatorManagedMode(ctx, rf)
 	}
 
 	// Number of redis is equal as the set on the RF spec
@@ -123,10 +136,7 @@ func (r *RedisFailoverHandler) CheckAndHeal(rf *redisfailoverv1.RedisFailover) e
 
 	if !r.rfChecker.IsRedisRunning(rf) {
 		errorMsg := "not all replicas running"
-		rf.Status = redisfailoverv1.RedisFailoverStatus{
-			State:   redisfailoverv1.NotHealthyState,
-			Message: errorMsg,
-		}
+		setNotHealthy(rf, redisfailoverv1.ConditionReplicasInSync, redisfailoverv1.ReasonReplicasNotRunning, errorMsg)
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.REDIS_REPLICA_MISMATCH, metrics.NOT_APPLICABLE, errors.New(errorMsg))
 		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Number of redis mismatch, waiting for redis statefulset reconcile")
 		return nil
@@ -134,39 +144,31 @@ func (r *RedisFailoverHandler) CheckAndHeal(rf *redisfailoverv1.RedisFailover) e
 
 	if !r.rfChecker.IsSentinelRunning(rf) {
 		errorMsg := "not all replicas running"
-		rf.Status = redisfailoverv1.RedisFailoverStatus{
-			State:   redisfailoverv1.NotHealthyState,
-			Message: errorMsg,
-		}
+		setNotHealthy(rf, redisfailoverv1.ConditionSentinelsInQuorum, redisfailoverv1.ReasonSentinelsNotRunning, errorMsg)
 		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_REPLICA_MISMATCH, metrics.NOT_APPLICABLE, errors.New(errorMsg))
 		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Number of sentinel mismatch, waiting for sentinel deployment reconcile")
 		return nil
 	}
 
-	nMasters, err := r.rfChecker.GetNumberMasters(rf)
+	nMasters, err := r.rfChecker.GetNumberMasters(ctx, rf)
 	if err != nil {
-		rf.Status = redisfailoverv1.RedisFailoverStatus{
-			State:   redisfailoverv1.NotHealthyState,
-			Message: "unable to get number of masters",
-		}
+		setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonCheckFailed, "unable to get number of masters")
 		return err
 	}
 
 	switch nMasters {
 	case 0:
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, errors.New("no masters detected"))
+		setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonNoMaster, "no master detected")
 		//when number of redis replicas is 1 , the redis is configured for standalone master mode
 		//Configure to master
 		if rf.Spec.Redis.Replicas == 1 {
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Resource spec with standalone master - operator will set the master")
-			err = r.rfHealer.SetOldestAsMaster(rf)
+			err = r.rfHealer.SetOldestAsMaster(ctx, rf)
 			setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, err)
 			if err != nil {
 				errorMsg := "Error in Setting oldest Pod as master"
-				rf.Status = redisfailoverv1.RedisFailoverStatus{
-					State:   redisfailoverv1.NotHealthyState,
-					Message: errorMsg,
-				}
+				setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonFailoverFailed, errorMsg)
 				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf(errorMsg)
 				return err
 			}
@@ -180,51 +182,39 @@ func (r *RedisFailoverHandler) CheckAndHeal(rf *redisfailoverv1.RedisFailover) e
 		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Number of Masters running is 0")
 		maxUptime, err := r.rfChecker.GetMaxRedisPodTime(rf)
 		if err != nil {
-			rf.Status = redisfailoverv1.RedisFailoverStatus{
-				State:   redisfailoverv1.NotHealthyState,
-				Message: "unable to get Redis POD time",
-			}
+			setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonCheckFailed, "unable to get Redis POD time")
 			return err
 		}
 
 		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("No master avaiable but max pod up time is : %f", maxUptime.Round(time.Second).Seconds())
 		//Check If Sentinel has quorum to take a failover decision
-		noqrmCnt, err := r.rfChecker.CheckSentinelQuorum(rf)
+		noqrmCnt, err := r.rfChecker.CheckSentinelQuorum(ctx, rf)
 		if err != nil {
 			// Sentinels are not in a situation to choose a master we pick one
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Quorum not available for sentinel to choose master,estimated unhealthy sentinels :%d , Operator to step-in", noqrmCnt)
-			err2 := r.rfHealer.SetOldestAsMaster(rf)
+			err2 := r.rfHealer.SetOldestAsMaster(ctx, rf)
 			setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, err2)
 			if err2 != nil {
 				errorMsg := "Error in Setting oldest Pod as master"
-				rf.Status = redisfailoverv1.RedisFailoverStatus{
-					State:   redisfailoverv1.NotHealthyState,
-					Message: errorMsg,
-				}
+				setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonFailoverFailed, errorMsg)
 				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf(errorMsg)
 				return err2
 			}
 		} else {
 			//sentinels are having a quorum to make a failover , but check if redis are not having local hostip (first boot) as master
-			status, err2 := r.rfChecker.CheckIfMasterLocalhost(rf)
+			status, err2 := r.rfChecker.CheckIfMasterLocalhost(ctx, rf)
 			if err2 != nil {
-				rf.Status = redisfailoverv1.RedisFailoverStatus{
-					State:   redisfailoverv1.NotHealthyState,
-					Message: "unable to check if master localhost",
-				}
+				setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonCheckFailed, "unable to check if master localhost")
 				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("CheckIfMasterLocalhost failed retry later")
 				return err2
 			} else if status {
 				// all avaialable redis pods have local host ip as master
 				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("all available redis is having local loop back as master , operator initiates master selection")
-				err3 := r.rfHealer.SetOldestAsMaster(rf)
+				err3 := r.rfHealer.SetOldestAsMaster(ctx, rf)
 				setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, err3)
 				if err3 != nil {
 					errorMsg := "Error in Setting oldest Pod as master"
-					rf.Status = redisfailoverv1.RedisFailoverStatus{
-						State:   redisfailoverv1.NotHealthyState,
-						Message: errorMsg,
-					}
+					setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonFailoverFailed, errorMsg)
 					r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf(errorMsg)
 					return err3
 				}
@@ -233,6 +223,7 @@ func (r *RedisFailoverHandler) CheckAndHeal(rf *redisfailoverv1.RedisFailover) e
 
 				// We'll wait until failover is done
 				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("no master found, wait until failover or fix manually")
+				setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonNoMaster, "no master found, waiting for sentinel failover")
 				setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, errors.New("no master not fixed, wait until failover or fix manually"))
 				return nil
 			}
@@ -241,102 +232,85 @@ func (r *RedisFailoverHandler) CheckAndHeal(rf *redisfailoverv1.RedisFailover) e
 
 	case 1:
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NUMBER_OF_MASTERS, metrics.NOT_APPLICABLE, nil)
+		rf.SetCondition(redisfailoverv1.ConditionMasterAvailable, metav1.ConditionTrue, redisfailoverv1.ReasonMasterElected, "one master is serving")
 	default:
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NUMBER_OF_MASTERS, metrics.NOT_APPLICABLE, errors.New("multiple masters detected"))
 		errorMsg := "more than one master, fix manually"
-		rf.Status = redisfailoverv1.RedisFailoverStatus{
-			State:   redisfailoverv1.NotHealthyState,
-			Message: errorMsg,
-		}
+		setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonMultipleMasters, errorMsg)
 		return errors.New(errorMsg)
 	}
 
-	master, err := r.rfChecker.GetMasterIP(rf)
+	master, err := r.rfChecker.GetMasterIP(ctx, rf)
 	if err != nil {
-		rf.Status = redisfailoverv1.RedisFailoverStatus{
-			State:   redisfailoverv1.NotHealthyState,
-			Message: "unable to get master IP",
-		}
+		setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonCheckFailed, "unable to get master IP")
 		return err
 	}
 
-	err = r.rfChecker.CheckAllSlavesFromMaster(master, rf)
+	err = r.rfChecker.CheckAllSlavesFromMaster(ctx, master, rf)
 	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.SLAVE_WRONG_MASTER, metrics.NOT_APPLICABLE, err)
 	if err != nil {
 		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Slave not associated to master: %s", err.Error())
-		if err = r.rfHealer.SetMasterOnAll(master, rf); err != nil {
-			rf.Status = redisfailoverv1.RedisFailoverStatus{
-				State: redisfailoverv1.NotHealthyState,
-			}
+		if err = r.rfHealer.SetMasterOnAll(ctx, master, rf); err != nil {
+			setNotHealthy(rf, redisfailoverv1.ConditionReplicasInSync, redisfailoverv1.ReasonReplicasNotSynced, "unable to set master on all replicas")
 			return err
 		}
+		rf.SetCondition(redisfailoverv1.ConditionReplicasInSync, metav1.ConditionFalse, redisfailoverv1.ReasonReplicasNotSynced, "replicas reconfigured to follow the master")
+	} else {
+		rf.SetCondition(redisfailoverv1.ConditionReplicasInSync, metav1.ConditionTrue, redisfailoverv1.ReasonReplicasSynced, "all replicas follow the master")
 	}
 
-	err = r.applyRedisCustomConfig(rf)
+	err = r.SyncRedisConfig(ctx, rf)
 	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_REDIS_CONFIG, metrics.NOT_APPLICABLE, err)
 	if err != nil {
-		rf.Status = redisfailoverv1.RedisFailoverStatus{
-			State:   redisfailoverv1.NotHealthyState,
-			Message: "unable to apply custom config",
-		}
+		setNotHealthy(rf, redisfailoverv1.ConditionResourcesReconciled, redisfailoverv1.ReasonReconcileFailed, "unable to apply custom config")
 		return err
 	}
+	rf.SetCondition(redisfailoverv1.ConditionResourcesReconciled, metav1.ConditionTrue, redisfailoverv1.ReasonReconciled, "resources and configuration applied")
 
-	err = r.UpdateRedisesPods(rf)
+	err = r.UpdateRedisesPods(ctx, rf)
 	if err != nil {
-		rf.Status = redisfailoverv1.RedisFailoverStatus{
-			State:   redisfailoverv1.NotHealthyState,
-			Message: "unable to update redis PODs",
-		}
+		setNotHealthy(rf, redisfailoverv1.ConditionUpgrading, redisfailoverv1.ReasonRollingUpdateFailed, "unable to update redis PODs")
 		return err
 	}
 
 	sentinels, err := r.rfChecker.GetSentinelsIPs(rf)
 	if err != nil {
-		rf.Status = redisfailoverv1.RedisFailoverStatus{
-			State:   redisfailoverv1.NotHealthyState,
-			Message: "unable to get sentinels IPs",
-		}
+		setNotHealthy(rf, redisfailoverv1.ConditionSentinelsInQuorum, redisfailoverv1.ReasonCheckFailed, "unable to get sentinels IPs")
 		return err
 	}
 
 	port := getRedisPort(rf.Spec.Redis.Port)
 	for _, sip := range sentinels {
-		err = r.rfChecker.CheckSentinelMonitor(sip, master, port)
+		err = r.rfChecker.CheckSentinelMonitor(ctx, sip, rf, master, port)
 		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_WRONG_MASTER, sip, err)
 		if err != nil {
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Fixing sentinel not monitoring expected master: %s", err.Error())
-			if err := r.rfHealer.NewSentinelMonitor(sip, master, rf); err != nil {
-				rf.Status = redisfailoverv1.RedisFailoverStatus{
-					State: redisfailoverv1.NotHealthyState,
-				}
+			if err := r.rfHealer.NewSentinelMonitor(ctx, sip, master, rf); err != nil {
+				setNotHealthy(rf, redisfailoverv1.ConditionSentinelsInQuorum, redisfailoverv1.ReasonSentinelsNotSynced, "unable to set sentinel monitor")
 				return err
 			}
 		}
 	}
-	return r.checkAndHealSentinels(rf, sentinels)
+	return r.checkAndHealSentinels(ctx, rf, sentinels)
 }
 
 // checkAndHealOperatorManagedMode handles failover when Sentinel is disabled.
 // The operator directly manages master election and failover.
-func (r *RedisFailoverHandler) checkAndHealOperatorManagedMode(rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHandler) checkAndHealOperatorManagedMode(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	// There are no sentinels to report on in this mode.
+	rf.RemoveCondition(redisfailoverv1.ConditionSentinelsInQuorum)
+
 	if !r.rfChecker.IsRedisRunning(rf) {
 		errorMsg := "not all replicas running"
-		rf.Status = redisfailoverv1.RedisFailoverStatus{
-			State:   redisfailoverv1.NotHealthyState,
-			Message: errorMsg,
-		}
+		setNotHealthy(rf, redisfailoverv1.ConditionReplicasInSync, redisfailoverv1.ReasonReplicasNotRunning, errorMsg)
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.REDIS_REPLICA_MISMATCH, metrics.NOT_APPLICABLE, errors.New(errorMsg))
 		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Number of redis mismatch, waiting for redis statefulset reconcile")
 		return nil
 	}
 
-	nMasters, err := r.rfChecker.GetNumberMasters(rf)
+	nMasters, err := r.rfChecker.GetNumberMasters(ctx, rf)
 	if err != nil {
-		rf.Status = redisfailoverv1.RedisFailoverStatus{
-			State:   redisfailoverv1.NotHealthyState,
-			Message: "unable to get number of masters",
-		}
+		setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonCheckFailed, "unable to get number of masters")
 		return err
 	}
 
@@ -345,35 +319,30 @@ func (r *RedisFailoverHandler) checkAndHealOperatorManagedMode(rf *redisfailover
 		// No master available - elect one
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, errors.New("no masters detected"))
 		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("No master available, operator will elect one")
+		setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonNoMaster, "no master detected, electing one")
 
 		// Try to select best replica by replication offset
-		bestReplica, err := r.rfChecker.GetBestReplicaForPromotion(rf)
+		bestReplica, err := r.rfChecker.GetBestReplicaForPromotion(ctx, rf)
 		if err != nil {
 			// Fall back to oldest pod if we can't determine best replica
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).
 				Warnf("Could not determine best replica: %v, falling back to oldest", err)
-			err = r.rfHealer.SetOldestAsMaster(rf)
+			err = r.rfHealer.SetOldestAsMaster(ctx, rf)
 			setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, err)
 			if err != nil {
-				rf.Status = redisfailoverv1.RedisFailoverStatus{
-					State:   redisfailoverv1.NotHealthyState,
-					Message: "failed to elect master",
-				}
+				setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonFailoverFailed, "failed to elect master")
 				return err
 			}
 		} else {
 			// Promote the best replica
-			err = r.rfHealer.PromoteBestReplica(bestReplica.IP, rf)
+			err = r.rfHealer.PromoteBestReplica(ctx, bestReplica.IP, rf)
 			setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, err)
 			if err != nil {
 				msg := "failed to promote replica"
 				if errors.Is(err, rfservice.ErrPartialReconciliation) {
 					msg = "failover incomplete: replica reconfiguration failed"
 				}
-				rf.Status = redisfailoverv1.RedisFailoverStatus{
-					State:   redisfailoverv1.NotHealthyState,
-					Message: msg,
-				}
+				setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonFailoverFailed, msg)
 				return err
 			}
 		}
@@ -383,139 +352,125 @@ func (r *RedisFailoverHandler) checkAndHealOperatorManagedMode(rf *redisfailover
 		// Exactly one master - check its health
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NUMBER_OF_MASTERS, metrics.NOT_APPLICABLE, nil)
 
-		healthy, masterIP, err := r.rfChecker.CheckMasterHealth(rf)
+		healthy, masterIP, err := r.rfChecker.CheckMasterHealth(ctx, rf)
 		if err != nil {
-			rf.Status = redisfailoverv1.RedisFailoverStatus{
-				State:   redisfailoverv1.NotHealthyState,
-				Message: "unable to check master health",
-			}
+			setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonCheckFailed, "unable to check master health")
 			return err
 		}
 
 		if !healthy {
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).
 				Warningf("Master %s is unhealthy, initiating failover", masterIP)
+			setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonMasterUnhealthy, "master is unhealthy, failing over")
 
 			// Master is unhealthy - promote a replica
-			bestReplica, err := r.rfChecker.GetBestReplicaForPromotion(rf)
+			bestReplica, err := r.rfChecker.GetBestReplicaForPromotion(ctx, rf)
 			if err != nil {
-				rf.Status = redisfailoverv1.RedisFailoverStatus{
-					State:   redisfailoverv1.NotHealthyState,
-					Message: "no healthy replica available for failover",
-				}
+				setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonFailoverFailed, "no healthy replica available for failover")
 				return err
 			}
 
-			err = r.rfHealer.PromoteBestReplica(bestReplica.IP, rf)
+			err = r.rfHealer.PromoteBestReplica(ctx, bestReplica.IP, rf)
 			if err != nil {
 				msg := "failover failed"
 				if errors.Is(err, rfservice.ErrPartialReconciliation) {
 					msg = "failover incomplete: replica reconfiguration failed"
 				}
-				rf.Status = redisfailoverv1.RedisFailoverStatus{
-					State:   redisfailoverv1.NotHealthyState,
-					Message: msg,
-				}
+				setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonFailoverFailed, msg)
 				return err
 			}
 			return nil
 		}
+		rf.SetCondition(redisfailoverv1.ConditionMasterAvailable, metav1.ConditionTrue, redisfailoverv1.ReasonMasterElected, "one healthy master is serving")
 
 		// Master is healthy - ensure all slaves are connected to it
-		err = r.rfChecker.CheckAllSlavesFromMaster(masterIP, rf)
+		err = r.rfChecker.CheckAllSlavesFromMaster(ctx, masterIP, rf)
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.SLAVE_WRONG_MASTER, metrics.NOT_APPLICABLE, err)
 		if err != nil {
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).
 				Warningf("Slave not associated to master: %s", err.Error())
-			if err = r.rfHealer.SetMasterOnAll(masterIP, rf); err != nil {
-				rf.Status = redisfailoverv1.RedisFailoverStatus{
-					State:   redisfailoverv1.NotHealthyState,
-					Message: "failed to configure slaves",
-				}
+			if err = r.rfHealer.SetMasterOnAll(ctx, masterIP, rf); err != nil {
+				setNotHealthy(rf, redisfailoverv1.ConditionReplicasInSync, redisfailoverv1.ReasonReplicasNotSynced, "failed to configure slaves")
 				return err
 			}
+			rf.SetCondition(redisfailoverv1.ConditionReplicasInSync, metav1.ConditionFalse, redisfailoverv1.ReasonReplicasNotSynced, "replicas reconfigured to follow the master")
+		} else {
+			rf.SetCondition(redisfailoverv1.ConditionReplicasInSync, metav1.ConditionTrue, redisfailoverv1.ReasonReplicasSynced, "all replicas follow the master")
 		}
 
 	default:
-		// Multiple masters - error state
+		// Multiple masters - an old master came back after a failover, fence it
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NUMBER_OF_MASTERS, metrics.NOT_APPLICABLE, errors.New("multiple masters detected"))
-		errorMsg := "multiple masters detected, fix manually"
-		rf.Status = redisfailoverv1.RedisFailoverStatus{
-			State:   redisfailoverv1.NotHealthyState,
-			Message: errorMsg,
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Multiple masters detected, demoting the stale ones")
+		if err = r.rfHealer.DemoteStaleMasters(ctx, rf); err != nil {
+			errorMsg := "multiple masters detected, fix manually"
+			setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonMultipleMasters, errorMsg)
+			return fmt.Errorf("%s: %w", errorMsg, err)
 		}
-		return errors.New(errorMsg)
+		setNotHealthy(rf, redisfailoverv1.ConditionMasterAvailable, redisfailoverv1.ReasonStaleMasterFenced, "stale master demoted")
+		return nil
 	}
 
 	// Apply custom Redis configuration
-	err = r.applyRedisCustomConfig(rf)
+	err = r.SyncRedisConfig(ctx, rf)
 	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_REDIS_CONFIG, metrics.NOT_APPLICABLE, err)
 	if err != nil {
-		rf.Status = redisfailoverv1.RedisFailoverStatus{
-			State:   redisfailoverv1.NotHealthyState,
-			Message: "unable to apply custom config",
-		}
+		setNotHealthy(rf, redisfailoverv1.ConditionResourcesReconciled, redisfailoverv1.ReasonReconcileFailed, "unable to apply custom config")
 		return err
 	}
+	rf.SetCondition(redisfailoverv1.ConditionResourcesReconciled, metav1.ConditionTrue, redisfailoverv1.ReasonReconciled, "resources and configuration applied")
 
 	// Update stale pods
-	err = r.UpdateRedisesPods(rf)
+	err = r.UpdateRedisesPods(ctx, rf)
 	if err != nil {
-		rf.Status = redisfailoverv1.RedisFailoverStatus{
-			State:   redisfailoverv1.NotHealthyState,
-			Message: "unable to update redis pods",
-		}
+		setNotHealthy(rf, redisfailoverv1.ConditionUpgrading, redisfailoverv1.ReasonRollingUpdateFailed, "unable to update redis pods")
 		return err
 	}
 
 	return nil
 }
 
-func (r *RedisFailoverHandler) checkAndHealBootstrapMode(rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHandler) checkAndHealBootstrapMode(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	// The master lives outside of this RedisFailover while bootstrapping.
+	rf.RemoveCondition(redisfailoverv1.ConditionMasterAvailable)
+	if !rf.SentinelsAllowed() {
+		rf.RemoveCondition(redisfailoverv1.ConditionSentinelsInQuorum)
+	}
 
 	if !r.rfChecker.IsRedisRunning(rf) {
 		errorMsg := "not all replicas running"
+		rf.SetCondition(redisfailoverv1.ConditionReplicasInSync, metav1.ConditionFalse, redisfailoverv1.ReasonReplicasNotRunning, errorMsg)
 		r.k8sservice.UpdateRedisFailoverStatus(context.Background(), rf.Namespace, rf, metav1.PatchOptions{})
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.REDIS_REPLICA_MISMATCH, metrics.NOT_APPLICABLE, errors.New(errorMsg))
 		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Number of redis mismatch, waiting for redis statefulset reconcile")
 		return nil
 	}
 
-	err := r.UpdateRedisesPods(rf)
+	err := r.UpdateRedisesPods(ctx, rf)
 	if err != nil {
-		rf.Status = redisfailoverv1.RedisFailoverStatus{
-			State:   redisfailoverv1.NotHealthyState,
-			Message: "unable to update Redis PODs",
-		}
+		setNotHealthy(rf, redisfailoverv1.ConditionUpgrading, redisfailoverv1.ReasonRollingUpdateFailed, "unable to update Redis PODs")
 	}
-	err = r.applyRedisCustomConfig(rf)
+	err = r.SyncRedisConfig(ctx, rf)
 	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_REDIS_CONFIG, metrics.NOT_APPLICABLE, err)
 	if err != nil {
-		rf.Status = redisfailoverv1.RedisFailoverStatus{
-			State:   redisfailoverv1.NotHealthyState,
-			Message: "unable to set Redis custom config",
-		}
+		setNotHealthy(rf, redisfailoverv1.ConditionResourcesReconciled, redisfailoverv1.ReasonReconcileFailed, "unable to set Redis custom config")
 		return err
 	}
+	rf.SetCondition(redisfailoverv1.ConditionResourcesReconciled, metav1.ConditionTrue, redisfailoverv1.ReasonReconciled, "resources and configuration applied")
 
 	bootstrapSettings := rf.Spec.BootstrapNode
-	err = r.rfHealer.SetExternalMasterOnAll(bootstrapSettings.Host, bootstrapSettings.Port, rf)
+	err = r.rfHealer.SetExternalMasterOnAll(ctx, bootstrapSettings.Host, bootstrapSettings.Port, rf)
 	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_EXTERNAL_MASTER, metrics.NOT_APPLICABLE, err)
 	if err != nil {
-		rf.Status = redisfailoverv1.RedisFailoverStatus{
-			State:   redisfailoverv1.NotHealthyState,
-			Message: "unable to set external master to all",
-		}
+		setNotHealthy(rf, redisfailoverv1.ConditionReplicasInSync, redisfailoverv1.ReasonReplicasNotSynced, "unable to set external master to all")
 		return err
 	}
+	rf.SetCondition(redisfailoverv1.ConditionReplicasInSync, metav1.ConditionTrue, redisfailoverv1.ReasonReplicasSynced, "all replicas follow the bootstrap node")
 
 	if rf.SentinelsAllowed() {
 		if !r.rfChecker.IsSentinelRunning(rf) {
 			errorMsg := "not all replicas running"
-			rf.Status = redisfailoverv1.RedisFailoverStatus{
-				State:   redisfailoverv1.NotHealthyState,
-				Message: errorMsg,
-			}
+			setNotHealthy(rf, redisfailoverv1.ConditionSentinelsInQuorum, redisfailoverv1.ReasonSentinelsNotRunning, errorMsg)
 			r.k8sservice.UpdateRedisFailoverStatus(context.Background(), rf.Namespace, rf, metav1.PatchOptions{})
 			setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_REPLICA_MISMATCH, metrics.NOT_APPLICABLE, errors.New(errorMsg))
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Number of sentinel mismatch, waiting for sentinel deployment reconcile")
@@ -526,73 +481,61 @@ func (r *RedisFailoverHandler) checkAndHealBootstrapMode(rf *redisfailoverv1.Red
 
 		sentinels, err := r.rfChecker.GetSentinelsIPs(rf)
 		if err != nil {
-			rf.Status = redisfailoverv1.RedisFailoverStatus{
-				State:   redisfailoverv1.NotHealthyState,
-				Message: "unable to get sentinels IPs",
-			}
+			setNotHealthy(rf, redisfailoverv1.ConditionSentinelsInQuorum, redisfailoverv1.ReasonCheckFailed, "unable to get sentinels IPs")
 			return err
 		}
 		for _, sip := range sentinels {
-			err = r.rfChecker.CheckSentinelMonitor(sip, bootstrapSettings.Host, bootstrapSettings.Port)
+			err = r.rfChecker.CheckSentinelMonitor(ctx, sip, rf, bootstrapSettings.Host, bootstrapSettings.Port)
 			setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_WRONG_MASTER, sip, err)
 			if err != nil {
 				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Fixing sentinel not monitoring expected master: %s", err.Error())
-				if err := r.rfHealer.NewSentinelMonitorWithPort(sip, bootstrapSettings.Host, bootstrapSettings.Port, rf); err != nil {
-					rf.Status = redisfailoverv1.RedisFailoverStatus{
-						State:   redisfailoverv1.NotHealthyState,
-						Message: "unable to check sentinel monitor",
-					}
+				if err := r.rfHealer.NewSentinelMonitorWithPort(ctx, sip, bootstrapSettings.Host, bootstrapSettings.Port, rf); err != nil {
+					setNotHealthy(rf, redisfailoverv1.ConditionSentinelsInQuorum, redisfailoverv1.ReasonSentinelsNotSynced, "unable to check sentinel monitor")
 					return err
 				}
 			}
 		}
-		return r.checkAndHealSentinels(rf, sentinels)
+		return r.checkAndHealSentinels(ctx, rf, sentinels)
 	}
 	return nil
 }
 
-func (r *RedisFailoverHandler) applyRedisCustomConfig(rf *redisfailoverv1.RedisFailover) error {
-	redises, err := r.rfChecker.GetRedisesIPs(rf)
-	if err != nil {
-		return err
-	}
-	for _, rip := range redises {
-		if err := r.rfHealer.SetRedisCustomConfig(rip, rf); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (r *RedisFailoverHandler) checkAndHealSentinels(rf *redisfailoverv1.RedisFailover, sentinels []string) error {
+func (r *RedisFailoverHandler) checkAndHealSentinels(ctx context.Context, rf *redisfailoverv1.RedisFailover, sentinels []string) error {
 	for _, sip := range sentinels {
-		err := r.rfChecker.CheckSentinelNumberInMemory(sip, rf)
+		err := r.rfChecker.CheckSentinelNumberInMemory(ctx, sip, rf)
 		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_NUMBER_IN_MEMORY_MISMATCH, sip, err)
 		if err != nil {
 			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Sentinel %s mismatch number of sentinels in memory. resetting", sip)
-			if err := r.rfHealer.RestoreSentinel(sip); err != nil {
+			if err := r.rfHealer.RestoreSentinel(ctx, sip, rf); err != nil {
+				setNotHealthy(rf, redisfailoverv1.ConditionSentinelsInQuorum, redisfailoverv1.ReasonSentinelsNotSynced, "unable to reset sentinel")
 				return err
 			}
 		}
 
 	}
-	for _, sip := range sentinels {
-		err := r.rfChecker.CheckSentinelSlavesNumberInMemory(sip, rf)
-		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.REDIS_SLAVES_NUMBER_IN_MEMORY_MISMATCH, sip, err)
-		if err != nil {
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Sentinel %s mismatch number of expected slaves in memory. resetting", sip)
-			if err := r.rfHealer.RestoreSentinel(sip); err != nil {
-				return err
+	// While scaling down, the sentinels are reset one at a time by ScaleDown
+	if !scalingDown(rf) {
+		for _, sip := range sentinels {
+			err := r.rfChecker.CheckSentinelSlavesNumberInMemory(ctx, sip, rf)
+			setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.REDIS_SLAVES_NUMBER_IN_MEMORY_MISMATCH, sip, err)
+			if err != nil {
+				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Sentinel %s mismatch number of expected slaves in memory. resetting", sip)
+				if err := r.rfHealer.RestoreSentinel(ctx, sip, rf); err != nil {
+					setNotHealthy(rf, redisfailoverv1.ConditionSentinelsInQuorum, redisfailoverv1.ReasonSentinelsNotSynced, "unable to reset sentinel")
+					return err
+				}
 			}
 		}
 	}
 	for _, sip := range sentinels {
-		err := r.rfHealer.SetSentinelCustomConfig(sip, rf)
+		err := r.rfHealer.SetSentinelCustomConfig(ctx, sip, rf)
 		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.APPLY_SENTINEL_CONFIG, sip, err)
 		if err != nil {
+			setNotHealthy(rf, redisfailoverv1.ConditionResourcesReconciled, redisfailoverv1.ReasonReconcileFailed, "unable to apply sentinel custom config")
 			return err
 		}
 	}
+	rf.SetCondition(redisfailoverv1.ConditionSentinelsInQuorum, metav1.ConditionTrue, redisfailoverv1.ReasonSentinelsSynced, "all sentinels monitor the master")
 	return nil
 }
 
@@ -617,9 +560,76 @@ func setRedisCheckerMetrics(metricsClient metrics.Recorder, mode /* redis or sen
 	}
 }
 
+// updateReplicationStatus refreshes the master, replicas and Redis version reported on the RedisFailover status.
+func (r *RedisFailoverHandler) updateReplicationStatus(ctx context.Context, rf *redisfailoverv1.RedisFailover) {
+	nodes, err := r.rfChecker.GetRedisesReplication(ctx, rf)
+	if err != nil {
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Unable to get replication status: %s", err.Error())
+		return
+	}
+	setReplicationStatus(rf, nodes)
+}
+
+// setReplicationStatus fills the replication related fields of the status from the state reported by the pods.
+// The master is only reported when exactly one pod claims the role.
+func setReplicationStatus(rf *redisfailoverv1.RedisFailover, nodes []rfservice.RedisNodeReplication) {
+	var masters []rfservice.RedisNodeReplication
+	var replicas []rfservice.RedisNodeReplication
+	for _, node := range nodes {
+		if node.Replication.Role == "master" {
+			masters = append(masters, node)
+		} else {
+			replicas = append(replicas, node)
+		}
+	}
+
+	rf.Status.Master = nil
+	var masterOffset int64
+	version := ""
+	if len(masters) == 1 {
+		rf.Status.Master = &redisfailoverv1.RedisMasterStatus{
+			PodName: masters[0].PodName,
+			IP:      masters[0].IP,
+		}
+		masterOffset = masters[0].Replication.MasterReplOffset
+		version = masters[0].RedisVersion
+	}
+
+	sort.Slice(replicas, func(i, j int) bool { return replicas[i].PodName < replicas[j].PodName })
+	rf.Status.Replicas = nil
+	for _, replica := range replicas {
+		var lag int64
+		if rf.Status.Master != nil && masterOffset > replica.Replication.SlaveReplOffset {
+			lag = masterOffset - replica.Replication.SlaveReplOffset
+		}
+		rf.Status.Replicas = append(rf.Status.Replicas, redisfailoverv1.RedisReplicaStatus{
+			PodName:          replica.PodName,
+			IP:               replica.IP,
+			MasterLinkStatus: replica.Replication.MasterLinkStatus,
+			Lag:              lag,
+			SyncInProgress:   replica.Replication.SyncInProgress,
+		})
+		if version == "" {
+			version = replica.RedisVersion
+		}
+	}
+
+	if version != "" {
+		rf.Status.RedisVersion = version
+	}
+}
+
+// setNotHealthy marks the RedisFailover as not healthy and reports the failure on the given condition.
+func setNotHealthy(rf *redisfailoverv1.RedisFailover, conditionType string, reason string, message string) {
+	rf.Status.State = redisfailoverv1.NotHealthyState
+	rf.Status.Message = message
+	rf.SetCondition(conditionType, metav1.ConditionFalse, reason, message)
+}
+
 func updateStatus(k8sservice k8s.Services, rf *redisfailoverv1.RedisFailover, oldState string) {
 	if oldState != rf.Status.State {
 		rf.Status.LastChanged = time.Now().Format(time.RFC3339)
 	}
+	rf.Status.ObservedGeneration = rf.Generation
 	k8sservice.UpdateRedisFailoverStatus(context.Background(), rf.Namespace, rf, metav1.PatchOptions{})
 }
@@ -1,6 +1,7 @@
 package redisfailover_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	v1 "github.com/saremox/redis-operator/api/redisfailover/v1"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -18,6 +20,8 @@ import (
 	mRFService "github.com/saremox/redis-operator/mocks/operator/redisfailover/service"
 	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
 	rfOperator "github.com/saremox/redis-operator/operator/redisfailover"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+	"github.com/saremox/redis-operator/service/redis"
 )
 
 func TestCheckAndHeal(t *testing.T) {
@@ -302,6 +306,10 @@ func TestCheckAndHeal(t *testing.T) {
 			mrfc := &mRFService.RedisFailoverCheck{}
 			mrfh := &mRFService.RedisFailoverHeal{}
 
+			mrfc.On("GetRedisesReplication", mock.Anything, rf).Once().Return([]rfservice.RedisNodeReplication{
+				{PodName: "rfr-test-0", IP: master, RedisVersion: "7.2.12", Replication: &redis.ReplicationInfo{Role: "master", MasterReplOffset: 100}},
+			}, nil)
+
 			if test.redisCheckNumberOK {
 				mrfc.On("IsRedisRunning", rf).Once().Return(true)
 			} else {
@@ -314,44 +322,46 @@ func TestCheckAndHeal(t *testing.T) {
 			}
 
 			if bootstrappingTests && continueTests {
-				// once to get ips for config update, once for the UpdateRedisesPods go right
-				mrfc.On("GetRedisesIPs", rf).Twice().Return([]string{"0.0.0.1", "0.0.0.2", "0.0.0.3"}, nil)
-				mrfh.On("SetRedisCustomConfig", "0.0.0.1", rf).Once().Return(nil)
-				mrfh.On("SetRedisCustomConfig", "0.0.0.2", rf).Once().Return(nil)
-				mrfh.On("SetRedisCustomConfig", "0.0.0.3", rf).Once().Return(nil)
-				mrfc.On("CheckRedisSlavesReady", "0.0.0.1", rf).Once().Return(true, nil)
-				mrfc.On("CheckRedisSlavesReady", "0.0.0.2", rf).Once().Return(true, nil)
-				mrfc.On("CheckRedisSlavesReady", "0.0.0.3", rf).Once().Return(true, nil)
+				// the pods for the config update, the ips for the UpdateRedisesPods go right
+				mrfc.On("GetRedisesIPs", rf).Once().Return([]string{"0.0.0.1", "0.0.0.2", "0.0.0.3"}, nil)
+				mk.On("GetStatefulSetPods", namespace, "rfr-test").Once().Return(generateRedisPods("", "0.0.0.1", "0.0.0.2", "0.0.0.3"), nil)
+				mk.On("UpdatePod", namespace, mock.Anything).Times(3).Return(nil)
+				mrfh.On("SetRedisCustomConfig", mock.Anything, "0.0.0.1", rf).Once().Return(nil)
+				mrfh.On("SetRedisCustomConfig", mock.Anything, "0.0.0.2", rf).Once().Return(nil)
+				mrfh.On("SetRedisCustomConfig", mock.Anything, "0.0.0.3", rf).Once().Return(nil)
+				mrfc.On("CheckRedisSlavesReady", mock.Anything, "0.0.0.1", rf).Once().Return(true, nil)
+				mrfc.On("CheckRedisSlavesReady", mock.Anything, "0.0.0.2", rf).Once().Return(true, nil)
+				mrfc.On("CheckRedisSlavesReady", mock.Anything, "0.0.0.3", rf).Once().Return(true, nil)
 				mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
-				mrfc.On("GetRedisesSlavesPods", rf).Once().Return([]string{}, nil)
+				mrfc.On("GetRedisesSlavesPods", mock.Anything, rf).Once().Return([]string{}, nil)
 
 				if test.redisSetMasterOnAllOK {
-					mrfh.On("SetExternalMasterOnAll", bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
+					mrfh.On("SetExternalMasterOnAll", mock.Anything, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
 				} else {
 					expErr = true
-					mrfh.On("SetExternalMasterOnAll", bootstrapMaster, bootstrapMasterPort, rf).Once().Return(errors.New(""))
+					mrfh.On("SetExternalMasterOnAll", mock.Anything, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(errors.New(""))
 				}
 			} else if continueTests {
-				mrfc.On("GetNumberMasters", rf).Once().Return(test.nMasters, nil)
+				mrfc.On("GetNumberMasters", mock.Anything, rf).Once().Return(test.nMasters, nil)
 				switch test.nMasters {
 				case 0:
 					//mrfc.On("GetRedisesIPs", rf).Once().Return(make([]string, test.nRedis), nil)
 					if rf.Spec.Redis.Replicas == 1 {
-						mrfh.On("SetOldestAsMaster", rf).Once().Return(nil)
+						mrfh.On("SetOldestAsMaster", mock.Anything, rf).Once().Return(nil)
 						continueTests = false
 						break
 					}
 					mrfc.On("GetMaxRedisPodTime", rf).Once().Return(1*time.Hour, nil)
 					if test.forceNewMasterNoQrm {
-						mrfc.On("CheckSentinelQuorum", rf).Once().Return(1, errors.New(""))
-						mrfh.On("SetOldestAsMaster", rf).Once().Return(nil)
+						mrfc.On("CheckSentinelQuorum", mock.Anything, rf).Once().Return(1, errors.New(""))
+						mrfh.On("SetOldestAsMaster", mock.Anything, rf).Once().Return(nil)
 					} else if test.forceNewMasterFirstBoot {
-						mrfc.On("CheckSentinelQuorum", rf).Once().Return(3, nil)
-						mrfc.On("CheckIfMasterLocalhost", rf).Once().Return(true, nil)
-						mrfh.On("SetOldestAsMaster", rf).Once().Return(nil)
+						mrfc.On("CheckSentinelQuorum", mock.Anything, rf).Once().Return(3, nil)
+						mrfc.On("CheckIfMasterLocalhost", mock.Anything, rf).Once().Return(true, nil)
+						mrfh.On("SetOldestAsMaster", mock.Anything, rf).Once().Return(nil)
 					} else {
-						mrfc.On("CheckSentinelQuorum", rf).Once().Return(3, nil)
-						mrfc.On("CheckIfMasterLocalhost", rf).Once().Return(false, nil)
+						mrfc.On("CheckSentinelQuorum", mock.Anything, rf).Once().Return(3, nil)
+						mrfc.On("CheckIfMasterLocalhost", mock.Anything, rf).Once().Return(false, nil)
 						continueTests = false
 					}
 
@@ -362,25 +372,27 @@ func TestCheckAndHeal(t *testing.T) {
 					expErr = true
 				}
 				if !expErr && continueTests {
-					mrfc.On("GetMasterIP", rf).Twice().Return(master, nil)
+					mrfc.On("GetMasterIP", mock.Anything, rf).Twice().Return(master, nil)
 					if test.slavesOK {
-						mrfc.On("CheckAllSlavesFromMaster", master, rf).Once().Return(nil)
+						mrfc.On("CheckAllSlavesFromMaster", mock.Anything, master, rf).Once().Return(nil)
 					} else {
-						mrfc.On("CheckAllSlavesFromMaster", master, rf).Once().Return(errors.New(""))
+						mrfc.On("CheckAllSlavesFromMaster", mock.Anything, master, rf).Once().Return(errors.New(""))
 						if test.redisSetMasterOnAllOK {
-							mrfh.On("SetMasterOnAll", master, rf).Once().Return(nil)
+							mrfh.On("SetMasterOnAll", mock.Anything, master, rf).Once().Return(nil)
 						} else {
 							expErr = true
-							mrfh.On("SetMasterOnAll", master, rf).Once().Return(errors.New(""))
+							mrfh.On("SetMasterOnAll", mock.Anything, master, rf).Once().Return(errors.New(""))
 						}
 
 					}
-					mrfc.On("GetRedisesIPs", rf).Twice().Return([]string{master}, nil)
+					mrfc.On("GetRedisesIPs", rf).Once().Return([]string{master}, nil)
+					mk.On("GetStatefulSetPods", namespace, "rfr-test").Once().Return(generateRedisPods("", master), nil)
+					mk.On("UpdatePod", namespace, mock.Anything).Once().Return(nil)
 					mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
-					mrfc.On("GetRedisesSlavesPods", rf).Once().Return([]string{}, nil)
-					mrfc.On("GetRedisesMasterPod", rf).Once().Return(master, nil)
+					mrfc.On("GetRedisesSlavesPods", mock.Anything, rf).Once().Return([]string{}, nil)
+					mrfc.On("GetRedisesMasterPod", mock.Anything, rf).Once().Return(master, nil)
 					mrfc.On("GetRedisRevisionHash", master, rf).Once().Return("1", nil)
-					mrfh.On("SetRedisCustomConfig", master, rf).Once().Return(nil)
+					mrfh.On("SetRedisCustomConfig", mock.Anything, master, rf).Once().Return(nil)
 				}
 			}
 
@@ -388,44 +400,76 @@ func TestCheckAndHeal(t *testing.T) {
 				mrfc.On("GetSentinelsIPs", rf).Once().Return([]string{sentinel}, nil)
 				if test.sentinelMonitorOK {
 					if test.bootstrapping {
-						mrfc.On("CheckSentinelMonitor", sentinel, bootstrapMaster, bootstrapMasterPort).Once().Return(nil)
+						mrfc.On("CheckSentinelMonitor", mock.Anything, sentinel, rf, bootstrapMaster, bootstrapMasterPort).Once().Return(nil)
 					} else {
-						mrfc.On("CheckSentinelMonitor", sentinel, master, "0").Once().Return(nil)
+						mrfc.On("CheckSentinelMonitor", mock.Anything, sentinel, rf, master, "0").Once().Return(nil)
 					}
 				} else {
 					if test.bootstrapping {
-						mrfc.On("CheckSentinelMonitor", sentinel, bootstrapMaster, bootstrapMasterPort).Once().Return(errors.New(""))
-						mrfh.On("NewSentinelMonitorWithPort", sentinel, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
+						mrfc.On("CheckSentinelMonitor", mock.Anything, sentinel, rf, bootstrapMaster, bootstrapMasterPort).Once().Return(errors.New(""))
+						mrfh.On("NewSentinelMonitorWithPort", mock.Anything, sentinel, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
 					} else {
-						mrfc.On("CheckSentinelMonitor", sentinel, master, "0").Once().Return(errors.New(""))
-						mrfh.On("NewSentinelMonitor", sentinel, master, rf).Once().Return(nil)
+						mrfc.On("CheckSentinelMonitor", mock.Anything, sentinel, rf, master, "0").Once().Return(errors.New(""))
+						mrfh.On("NewSentinelMonitor", mock.Anything, sentinel, master, rf).Once().Return(nil)
 					}
 				}
 				if test.sentinelNumberInMemoryOK {
-					mrfc.On("CheckSentinelNumberInMemory", sentinel, rf).Once().Return(nil)
+					mrfc.On("CheckSentinelNumberInMemory", mock.Anything, sentinel, rf).Once().Return(nil)
 				} else {
-					mrfc.On("CheckSentinelNumberInMemory", sentinel, rf).Once().Return(errors.New(""))
-					mrfh.On("RestoreSentinel", sentinel).Once().Return(nil)
+					mrfc.On("CheckSentinelNumberInMemory", mock.Anything, sentinel, rf).Once().Return(errors.New(""))
+					mrfh.On("RestoreSentinel", mock.Anything, sentinel, rf).Once().Return(nil)
 				}
 				if test.sentinelSlavesNumberInMemoryOK {
-					mrfc.On("CheckSentinelSlavesNumberInMemory", sentinel, rf).Once().Return(nil)
+					mrfc.On("CheckSentinelSlavesNumberInMemory", mock.Anything, sentinel, rf).Once().Return(nil)
 				} else {
-					mrfc.On("CheckSentinelSlavesNumberInMemory", sentinel, rf).Once().Return(errors.New(""))
-					mrfh.On("RestoreSentinel", sentinel).Once().Return(nil)
+					mrfc.On("CheckSentinelSlavesNumberInMemory", mock.Anything, sentinel, rf).Once().Return(errors.New(""))
+					mrfh.On("RestoreSentinel", mock.Anything, sentinel, rf).Once().Return(nil)
 				}
-				mrfh.On("SetSentinelCustomConfig", sentinel, rf).Once().Return(nil)
+				mrfh.On("SetSentinelCustomConfig", mock.Anything, sentinel, rf).Once().Return(nil)
 			}
 
-			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
-			err := handler.CheckAndHeal(rf)
+			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			err := handler.CheckAndHeal(context.TODO(), rf)
 
 			if expErr {
 				assertTest.Error(err)
 				assertTest.Equal(v1.NotHealthyState, rf.Status.State)
+			} else if test.nMasters == 0 && test.redisCheckNumberOK && !test.bootstrapping {
+				// The master is not available during the reconcile electing one
+				assertTest.NoError(err)
+				assertTest.Equal(v1.NotHealthyState, rf.Status.State)
 			} else {
 				assertTest.NoError(err)
 				assertTest.Equal(v1.HealthyState, rf.Status.State)
 			}
+
+			if !test.redisCheckNumberOK {
+				assertTest.False(rf.IsConditionTrue(v1.ConditionReplicasInSync))
+			} else if !test.bootstrapping {
+				masterCondition := rf.GetCondition(v1.ConditionMasterAvailable)
+				if assertTest.NotNil(masterCondition) {
+					switch {
+					case test.nMasters == 1:
+						assertTest.Equal(metav1.ConditionTrue, masterCondition.Status)
+					case test.nMasters > 1:
+						assertTest.Equal(metav1.ConditionFalse, masterCondition.Status)
+						assertTest.Equal(v1.ReasonMultipleMasters, masterCondition.Reason)
+					default:
+						assertTest.Equal(metav1.ConditionFalse, masterCondition.Status)
+						assertTest.Equal(v1.ReasonNoMaster, masterCondition.Reason)
+					}
+				}
+			} else {
+				assertTest.Nil(rf.GetCondition(v1.ConditionMasterAvailable))
+			}
+			if !expErr && continueTests {
+				assertTest.Equal(test.slavesOK || test.bootstrapping, rf.IsConditionTrue(v1.ConditionReplicasInSync))
+				if allowSentinels {
+					assertTest.True(rf.IsConditionTrue(v1.ConditionSentinelsInQuorum))
+				}
+			}
+			assertTest.Equal("rfr-test-0", rf.Status.Master.PodName)
+			assertTest.Equal("7.2.12", rf.Status.RedisVersion)
 			mrfc.AssertExpectations(t)
 			mrfh.AssertExpectations(t)
 		})
@@ -890,12 +934,12 @@ func TestUpdate(t *testing.T) {
 				if test.noMaster {
 					master = ""
 				}
-				mrfc.On("GetMasterIP", rf).Once().Return(master, nil)
+				mrfc.On("GetMasterIP", mock.Anything, rf).Once().Return(master, nil)
 			}
 
 			for _, pod := range test.pods {
 				if !pod.master {
-					mrfc.On("CheckRedisSlavesReady", pod.pod.Status.PodIP, rf).Once().Return(pod.ready, nil)
+					mrfc.On("CheckRedisSlavesReady", mock.Anything, pod.pod.Status.PodIP, rf).Once().Return(pod.ready, nil)
 				}
 				if !pod.ready {
 					next = false
@@ -910,11 +954,17 @@ func TestUpdate(t *testing.T) {
 					replicas = append(replicas, "slave3")
 				}
 				mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return(test.ssVersion, nil)
-				mrfc.On("GetRedisesSlavesPods", rf).Once().Return(replicas, nil)
+				mrfc.On("GetRedisesSlavesPods", mock.Anything, rf).Once().Return(replicas, nil)
 
 				for _, pod := range test.pods {
 					mrfc.On("GetRedisRevisionHash", pod.pod.Name, rf).Once().Return(pod.pod.Labels[appsv1.ControllerRevisionHashLabelKey], nil)
 					if pod.pod.Labels[appsv1.ControllerRevisionHashLabelKey] != test.ssVersion {
+						if pod.master {
+							mrfc.On("GetBestReplicaForPromotion", mock.Anything, rf).Once().Return(&rfservice.ReplicaInfo{IP: "0.0.0.0", PodName: "slave1", IsReady: true}, nil)
+							mrfc.On("GetSentinelsIPs", rf).Once().Return([]string{"2.2.2.2"}, nil)
+							mrfh.On("SentinelSwitchover", mock.Anything, "2.2.2.2", "1.1.1.1", "0.0.0.0", rf).Once().Return(nil)
+							mrfc.On("GetMasterIP", mock.Anything, rf).Once().Return("0.0.0.0", nil)
+						}
 						mrfh.On("DeletePod", pod.pod.Name, rf).Once().Return(nil)
 						if pod.master == false {
 							next = false
@@ -925,17 +975,17 @@ func TestUpdate(t *testing.T) {
 				fmt.Printf("%v - %v\n", test.name, next)
 				if next && !test.bootstrapping {
 					if test.noMaster {
-						mrfc.On("GetRedisesMasterPod", rf).Once().Return("", errors.New(""))
+						mrfc.On("GetRedisesMasterPod", mock.Anything, rf).Once().Return("", errors.New(""))
 					} else {
-						mrfc.On("GetRedisesMasterPod", rf).Once().Return("master", nil)
+						mrfc.On("GetRedisesMasterPod", mock.Anything, rf).Once().Return("master", nil)
 					}
 				}
 			}
 
 			mk := &mK8SService.Services{}
 
-			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
-			err := handler.UpdateRedisesPods(rf)
+			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			err := handler.UpdateRedisesPods(context.TODO(), rf)
 
 			if test.errExpected {
 				assertTest.Error(err)
@@ -949,3 +999,186 @@ func TestUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateStaleMasterSwitchover(t *testing.T) {
+	tests := []struct {
+		name            string
+		operatorManaged bool
+		replica         *rfservice.ReplicaInfo
+		replicaErr      error
+		switchoverErr   error
+		newMasterIP     string
+		expSwitchover   bool
+		expDelete       bool
+		errExpected     bool
+	}{
+		{
+			name:            "switchover with operator managed failover then delete the master",
+			operatorManaged: true,
+			replica:         &rfservice.ReplicaInfo{IP: "0.0.0.1", PodName: "slave1", IsReady: true},
+			newMasterIP:     "0.0.0.1",
+			expSwitchover:   true,
+			expDelete:       true,
+		},
+		{
+			name:       "no replica to switch over to, delete the master",
+			replicaErr: errors.New(""),
+			expDelete:  true,
+		},
+		{
+			name:        "replica not in sync, keep the master",
+			replica:     &rfservice.ReplicaInfo{IP: "0.0.0.1", PodName: "slave1", IsReady: false},
+			errExpected: true,
+		},
+		{
+			name:            "switchover fails, keep the master",
+			operatorManaged: true,
+			replica:         &rfservice.ReplicaInfo{IP: "0.0.0.1", PodName: "slave1", IsReady: true},
+			switchoverErr:   errors.New(""),
+			expSwitchover:   true,
+			errExpected:     true,
+		},
+		{
+			name:            "replica not serving as master after the switchover, keep the master",
+			operatorManaged: true,
+			replica:         &rfservice.ReplicaInfo{IP: "0.0.0.1", PodName: "slave1", IsReady: true},
+			newMasterIP:     "1.1.1.1",
+			expSwitchover:   true,
+			errExpected:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			if test.operatorManaged {
+				disabled := false
+				rf.Spec.Sentinel.Enabled = &disabled
+			}
+
+			mrfs := &mRFService.RedisFailoverClient{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+			mk := &mK8SService.Services{}
+
+			mrfc.On("GetRedisesIPs", rf).Once().Return([]string{"0.0.0.1", "1.1.1.1"}, nil)
+			mrfc.On("GetMasterIP", mock.Anything, rf).Once().Return("1.1.1.1", nil)
+			mrfc.On("CheckRedisSlavesReady", mock.Anything, "0.0.0.1", rf).Once().Return(true, nil)
+			mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("10", nil)
+			mrfc.On("GetRedisesSlavesPods", mock.Anything, rf).Once().Return([]string{"slave1"}, nil)
+			mrfc.On("GetRedisRevisionHash", "slave1", rf).Once().Return("10", nil)
+			mrfc.On("GetRedisesMasterPod", mock.Anything, rf).Once().Return("master", nil)
+			mrfc.On("GetRedisRevisionHash", "master", rf).Once().Return("9", nil)
+			mrfc.On("GetBestReplicaForPromotion", mock.Anything, rf).Once().Return(test.replica, test.replicaErr)
+			if test.expSwitchover {
+				mrfh.On("Switchover", mock.Anything, "1.1.1.1", "0.0.0.1", rf).Once().Return(test.switchoverErr)
+				if test.switchoverErr == nil {
+					mrfc.On("GetMasterIP", mock.Anything, rf).Once().Return(test.newMasterIP, nil)
+				}
+			}
+			if test.expDelete {
+				mrfh.On("DeletePod", "master", rf).Once().Return(nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			err := handler.UpdateRedisesPods(context.TODO(), rf)
+
+			if test.errExpected {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			if !test.expDelete {
+				mrfh.AssertNotCalled(t, "DeletePod", "master", rf)
+			}
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckAndHealOperatorManagedMultipleMasters(t *testing.T) {
+	tests := []struct {
+		name      string
+		demoteErr error
+		expErr    bool
+	}{
+		{
+			name: "stale master is fenced",
+		},
+		{
+			name:      "stale master can't be told apart",
+			demoteErr: errors.New(""),
+			expErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			disabled := false
+			rf.Spec.Sentinel.Enabled = &disabled
+
+			mk := &mK8SService.Services{}
+			mrfs := &mRFService.RedisFailoverClient{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+
+			mrfc.On("IsRedisRunning", rf).Once().Return(true)
+			mrfc.On("GetNumberMasters", mock.Anything, rf).Once().Return(2, nil)
+			mrfc.On("GetRedisesReplication", mock.Anything, rf).Once().Return([]rfservice.RedisNodeReplication{}, nil)
+			mrfh.On("DemoteStaleMasters", mock.Anything, rf).Once().Return(test.demoteErr)
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			err := handler.CheckAndHeal(context.TODO(), rf)
+
+			condition := rf.GetCondition(v1.ConditionMasterAvailable)
+			if assert.NotNil(condition) {
+				assert.Equal(metav1.ConditionFalse, condition.Status)
+			}
+			if test.expErr {
+				assert.Error(err)
+				assert.Equal(v1.ReasonMultipleMasters, condition.Reason)
+			} else {
+				assert.NoError(err)
+				assert.Equal(v1.ReasonStaleMasterFenced, condition.Reason)
+			}
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckAndHealReplicationStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false)
+
+	mk := &mK8SService.Services{}
+	mrfs := &mRFService.RedisFailoverClient{}
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfh := &mRFService.RedisFailoverHeal{}
+
+	mrfc.On("IsRedisRunning", rf).Once().Return(false)
+	mrfc.On("GetRedisesReplication", mock.Anything, rf).Once().Return([]rfservice.RedisNodeReplication{
+		{PodName: "rfr-test-2", IP: "0.0.0.2", RedisVersion: "7.2.12", Replication: &redis.ReplicationInfo{Role: "slave", MasterLinkStatus: "down", SlaveReplOffset: 40, SyncInProgress: true}},
+		{PodName: "rfr-test-0", IP: "0.0.0.0", RedisVersion: "7.2.12", Replication: &redis.ReplicationInfo{Role: "master", MasterReplOffset: 100}},
+		{PodName: "rfr-test-1", IP: "0.0.0.1", RedisVersion: "7.2.12", Replication: &redis.ReplicationInfo{Role: "slave", MasterLinkStatus: "up", SlaveReplOffset: 100}},
+	}, nil)
+
+	handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+	err := handler.CheckAndHeal(context.TODO(), rf)
+	assert.NoError(err)
+
+	assert.Equal(&v1.RedisMasterStatus{PodName: "rfr-test-0", IP: "0.0.0.0"}, rf.Status.Master)
+	assert.Equal([]v1.RedisReplicaStatus{
+		{PodName: "rfr-test-1", IP: "0.0.0.1", MasterLinkStatus: "up", Lag: 0, SyncInProgress: false},
+		{PodName: "rfr-test-2", IP: "0.0.0.2", MasterLinkStatus: "down", Lag: 60, SyncInProgress: true},
+	}, rf.Status.Replicas)
+	assert.Equal("7.2.12", rf.Status.RedisVersion)
+	mrfc.AssertExpectations(t)
+}
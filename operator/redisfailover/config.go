@@ -7,4 +7,7 @@ type Config struct {
 	Concurrency              int
 	SyncInterval             int
 	SupportedNamespacesRegex string
+	// ClusterDomain is the DNS domain of the cluster, used in the names of the certificates
+	// generated by the operator
+	ClusterDomain string
 }
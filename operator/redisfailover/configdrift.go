@@ -0,0 +1,101 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+// appliedConfigChecksumAnnotationKey holds the checksum of the managed redis configuration last
+// applied to a redis pod, so the pods restarted or not configured since the spec changed are told
+// apart from the ones whose configuration drifted
+const appliedConfigChecksumAnnotationKey = "redisfailovers.databases.spotahome.com/applied-config-checksum"
+
+// SyncRedisConfig applies the managed redis configuration to the redis pods it was not
+// applied to yet. The other pods are checked for parameters changed with CONFIG SET, which are
+// reported on the ConfigInSync condition and applied again according to spec.redis.driftPolicy.
+// The customConfig parameters unknown to the redis version are skipped and reported on the
+// CustomConfigAccepted condition.
+func (r *RedisFailoverHandler) SyncRedisConfig(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	if unknown := rf.UnknownCustomConfig(); len(unknown) != 0 {
+		rf.SetCondition(redisfailoverv1.ConditionCustomConfigAccepted, metav1.ConditionFalse, redisfailoverv1.ReasonUnknownParameters, fmt.Sprintf("skipped parameters unknown to redis %d: %s", rf.RedisMajorVersion(), strings.Join(unknown, ", ")))
+	} else {
+		rf.SetCondition(redisfailoverv1.ConditionCustomConfigAccepted, metav1.ConditionTrue, redisfailoverv1.ReasonCustomConfigKnown, "every customConfig parameter is applied")
+	}
+
+	pods, err := r.k8sservice.GetStatefulSetPods(rf.Namespace, rfservice.GetRedisName(rf))
+	if err != nil {
+		return err
+	}
+
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+	policy := rf.RedisDriftPolicy()
+	checksum := rfservice.GetConfigChecksum(rf.RedisConfig())
+	drift := []string{}
+	instances := []string{}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		instances = append(instances, pod.Name)
+		if pod.Annotations[appliedConfigChecksumAnnotationKey] != checksum {
+			if err := r.rfHealer.SetRedisCustomConfig(ctx, rf.PodAddress(&pod), rf); err != nil {
+				return err
+			}
+			if err := r.setAppliedConfigChecksum(rf, pod, checksum); err != nil {
+				return err
+			}
+			r.mClient.SetRedisConfigDrift(rf.Namespace, rf.Name, pod.Name, 0)
+			continue
+		}
+		if policy == redisfailoverv1.DriftPolicyIgnore {
+			continue
+		}
+
+		parameters, err := r.rfChecker.GetRedisConfigDrift(ctx, rf.PodAddress(&pod), rf)
+		if err != nil {
+			return err
+		}
+		r.mClient.SetRedisConfigDrift(rf.Namespace, rf.Name, pod.Name, len(parameters))
+		if len(parameters) == 0 {
+			continue
+		}
+		drift = append(drift, fmt.Sprintf("%s: %s", pod.Name, strings.Join(parameters, ", ")))
+		if policy == redisfailoverv1.DriftPolicyEnforce {
+			if err := r.rfHealer.SetRedisCustomConfig(ctx, rf.PodAddress(&pod), rf); err != nil {
+				return err
+			}
+		}
+	}
+	// The drift of the pods gone or not checked anymore is not reported
+	r.mClient.PruneRedisConfigDrift(rf.Namespace, rf.Name, instances)
+
+	switch {
+	case policy == redisfailoverv1.DriftPolicyIgnore:
+		rf.RemoveCondition(redisfailoverv1.ConditionConfigInSync)
+	case len(drift) != 0 && policy == redisfailoverv1.DriftPolicyEnforce:
+		logger.Warningf("Corrected redis configuration drift: %s", strings.Join(drift, "; "))
+		rf.SetCondition(redisfailoverv1.ConditionConfigInSync, metav1.ConditionFalse, redisfailoverv1.ReasonConfigDrifted, fmt.Sprintf("corrected drift: %s", strings.Join(drift, "; ")))
+	case len(drift) != 0:
+		logger.Warningf("Redis configuration drift: %s", strings.Join(drift, "; "))
+		rf.SetCondition(redisfailoverv1.ConditionConfigInSync, metav1.ConditionFalse, redisfailoverv1.ReasonConfigDrifted, fmt.Sprintf("drift: %s", strings.Join(drift, "; ")))
+	default:
+		rf.SetCondition(redisfailoverv1.ConditionConfigInSync, metav1.ConditionTrue, redisfailoverv1.ReasonConfigSynced, "managed configuration applied to every redis node")
+	}
+	return nil
+}
+
+// setAppliedConfigChecksum annotates the redis pod with the checksum of the configuration applied to it
+func (r *RedisFailoverHandler) setAppliedConfigChecksum(rf *redisfailoverv1.RedisFailover, pod corev1.Pod, checksum string) error {
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[appliedConfigChecksumAnnotationKey] = checksum
+	return r.k8sservice.UpdatePod(rf.Namespace, &pod)
+}
@@ -0,0 +1,212 @@
+package redisfailover_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+	mRFService "github.com/saremox/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/saremox/redis-operator/operator/redisfailover"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+// generateRedisPods returns the running redis pods with the given IPs, annotated with the
+// checksum of the configuration applied to them when given
+func generateRedisPods(checksum string, ips ...string) *corev1.PodList {
+	pods := &corev1.PodList{}
+	for i, ip := range ips {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("rfr-test-%d", i)},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+		}
+		if checksum != "" {
+			pod.Annotations = map[string]string{"redisfailovers.databases.spotahome.com/applied-config-checksum": checksum}
+		}
+		pods.Items = append(pods.Items, pod)
+	}
+	return pods
+}
+
+func TestSyncRedisConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       redisfailoverv1.DriftPolicy
+		applied      bool
+		drift        []string
+		expApply     bool
+		expReason    string
+		expCondition bool
+	}{
+		{
+			name:         "Configuration applied to new pods",
+			expApply:     true,
+			expReason:    redisfailoverv1.ReasonConfigSynced,
+			expCondition: true,
+		},
+		{
+			name:         "No drift",
+			applied:      true,
+			drift:        []string{},
+			expReason:    redisfailoverv1.ReasonConfigSynced,
+			expCondition: true,
+		},
+		{
+			name:      "Drift enforced",
+			applied:   true,
+			drift:     []string{"maxclients"},
+			expApply:  true,
+			expReason: redisfailoverv1.ReasonConfigDrifted,
+		},
+		{
+			name:      "Drift reported",
+			policy:    redisfailoverv1.DriftPolicyReport,
+			applied:   true,
+			drift:     []string{"maxclients"},
+			expReason: redisfailoverv1.ReasonConfigDrifted,
+		},
+		{
+			name:    "Drift ignored",
+			policy:  redisfailoverv1.DriftPolicyIgnore,
+			applied: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			rf.Spec.Redis.CustomConfig = []string{"maxclients 100"}
+			rf.Spec.Redis.DriftPolicy = test.policy
+			checksum := ""
+			if test.applied {
+				checksum = rfservice.GetConfigChecksum(rf.RedisConfig())
+			}
+
+			mk := &mK8SService.Services{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+			mk.On("GetStatefulSetPods", namespace, "rfr-test").Once().Return(generateRedisPods(checksum, "0.0.0.0"), nil)
+			if !test.applied {
+				mk.On("UpdatePod", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+					pod := args.Get(1).(*corev1.Pod)
+					assert.Equal(rfservice.GetConfigChecksum(rf.RedisConfig()), pod.Annotations["redisfailovers.databases.spotahome.com/applied-config-checksum"])
+				}).Return(nil)
+			}
+			if test.drift != nil {
+				mrfc.On("GetRedisConfigDrift", mock.Anything, "0.0.0.0", rf).Once().Return(test.drift, nil)
+			}
+			if test.expApply {
+				mrfh.On("SetRedisCustomConfig", mock.Anything, "0.0.0.0", rf).Once().Return(nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, mrfc, mrfh, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			assert.NoError(handler.SyncRedisConfig(context.TODO(), rf))
+
+			condition := rf.GetCondition(redisfailoverv1.ConditionConfigInSync)
+			if test.expReason == "" {
+				assert.Nil(condition)
+			} else if assert.NotNil(condition) {
+				assert.Equal(test.expReason, condition.Reason)
+				assert.Equal(test.expCondition, condition.Status == metav1.ConditionTrue)
+			}
+			mk.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSyncRedisConfigUnknownParameters(t *testing.T) {
+	tests := []struct {
+		name         string
+		customConfig []string
+		expReason    string
+		expMessage   string
+	}{
+		{
+			name:         "Known parameters",
+			customConfig: []string{"maxclients 100"},
+			expReason:    redisfailoverv1.ReasonCustomConfigKnown,
+			expMessage:   "every customConfig parameter is applied",
+		},
+		{
+			name:         "Unknown parameters skipped",
+			customConfig: []string{"maxclients 100", "maxmemory-polcy allkeys-lru", "list-max-ziplist-size -2", "Some-Parameter yes"},
+			expReason:    redisfailoverv1.ReasonUnknownParameters,
+			expMessage:   "skipped parameters unknown to redis 7: maxmemory-polcy, some-parameter",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			rf.Spec.Redis.CustomConfig = test.customConfig
+			rf.Spec.Redis.DriftPolicy = redisfailoverv1.DriftPolicyIgnore
+
+			mk := &mK8SService.Services{}
+			mk.On("GetStatefulSetPods", namespace, "rfr-test").Once().Return(generateRedisPods(rfservice.GetConfigChecksum(rf.RedisConfig()), "0.0.0.0"), nil)
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, &mRFService.RedisFailoverCheck{}, &mRFService.RedisFailoverHeal{}, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			assert.NoError(handler.SyncRedisConfig(context.TODO(), rf))
+
+			condition := rf.GetCondition(redisfailoverv1.ConditionCustomConfigAccepted)
+			if assert.NotNil(condition) {
+				assert.Equal(test.expReason, condition.Reason)
+				assert.Equal(test.expMessage, condition.Message)
+			}
+			assert.NotContains(rf.RedisConfig(), "maxmemory-polcy allkeys-lru")
+			mk.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSyncRedisConfigPrunesDriftMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false)
+	rf.Spec.Redis.CustomConfig = []string{"maxclients 100"}
+	checksum := rfservice.GetConfigChecksum(rf.RedisConfig())
+
+	mk := &mK8SService.Services{}
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mk.On("GetStatefulSetPods", namespace, "rfr-test").Once().Return(generateRedisPods(checksum, "0.0.0.0", "1.1.1.1"), nil)
+	mrfc.On("GetRedisConfigDrift", mock.Anything, mock.Anything, rf).Return([]string{}, nil)
+	// The second pod is gone on the next reconcile
+	mk.On("GetStatefulSetPods", namespace, "rfr-test").Once().Return(generateRedisPods(checksum, "0.0.0.0"), nil)
+
+	reg := prometheus.NewRegistry()
+	handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, mrfc, &mRFService.RedisFailoverHeal{}, &mRFService.RedisFailoverBackup{}, mk, metrics.NewRecorder("test", reg), log.Dummy)
+	assert.NoError(handler.SyncRedisConfig(context.TODO(), rf))
+	assert.Equal(2, countDriftMetrics(t, reg))
+
+	assert.NoError(handler.SyncRedisConfig(context.TODO(), rf))
+	assert.Equal(1, countDriftMetrics(t, reg))
+	mk.AssertExpectations(t)
+}
+
+// countDriftMetrics returns the number of redis instances with a config drift metric
+func countDriftMetrics(t *testing.T, reg *prometheus.Registry) int {
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() == "test_controller_redis_config_drifted_parameters" {
+			return len(family.GetMetric())
+		}
+	}
+	return 0
+}
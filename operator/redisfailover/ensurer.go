@@ -1,6 +1,8 @@
 package redisfailover
 
 import (
+	"context"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
@@ -8,8 +10,18 @@ import (
 )
 
 // Ensure is called to ensure all of the resources associated with a RedisFailover are created
-func (w *RedisFailoverHandler) Ensure(rf *redisfailoverv1.RedisFailover, labels map[string]string, or []metav1.OwnerReference, metricsClient metrics.Recorder) error {
-	if rf.Spec.Redis.Exporter.Enabled {
+func (w *RedisFailoverHandler) Ensure(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, or []metav1.OwnerReference, metricsClient metrics.Recorder) error {
+	if rf.TLSManaged() {
+		if err := w.rfService.EnsureTLSSecrets(rf, labels, or); err != nil {
+			return err
+		}
+	}
+
+	if err := w.rfService.EnsureRedisAuthSecret(rf, labels, or); err != nil {
+		return err
+	}
+
+	if rf.Spec.Redis.Exporter.Enabled || rf.Spec.AnnounceHostnames {
 		if err := w.rfService.EnsureRedisService(rf, labels, or); err != nil {
 			return err
 		}
@@ -51,6 +63,11 @@ func (w *RedisFailoverHandler) Ensure(rf *redisfailoverv1.RedisFailover, labels
 	if err := w.rfService.EnsureRedisConfigMap(rf, labels, or); err != nil {
 		return err
 	}
+	if rf.Restoring() {
+		if err := w.rfService.EnsureRedisRestoreSecret(ctx, rf, labels, or); err != nil {
+			return err
+		}
+	}
 	if err := w.rfService.EnsureRedisStatefulset(rf, labels, or); err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package redisfailover_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -68,6 +69,8 @@ func TestEnsure(t *testing.T) {
 		exporter                    bool
 		bootstrapping               bool
 		bootstrappingAllowSentinels bool
+		managedTLS                  bool
+		announceHostnames           bool
 	}{
 		{
 			name:                        "Call everything, use exporter",
@@ -93,6 +96,15 @@ func TestEnsure(t *testing.T) {
 			bootstrapping:               true,
 			bootstrappingAllowSentinels: true,
 		},
+		{
+			name:       "Issue the certificates when TLS is managed",
+			managedTLS: true,
+		},
+		{
+			name:              "Keep the redis service for the hostnames without exporter",
+			exporter:          false,
+			announceHostnames: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -103,13 +115,21 @@ func TestEnsure(t *testing.T) {
 			if test.bootstrapping {
 				rf.Spec.BootstrapNode.AllowSentinels = test.bootstrappingAllowSentinels
 			}
+			if test.managedTLS {
+				rf.Spec.TLS = &redisfailoverv1.TLSSettings{SecretName: "rftls-test", Managed: true}
+			}
+			rf.Spec.AnnounceHostnames = test.announceHostnames
 
 			config := generateConfig()
 			mk := &mK8SService.Services{}
 			mrfc := &mRFService.RedisFailoverCheck{}
 			mrfh := &mRFService.RedisFailoverHeal{}
 			mrfs := &mRFService.RedisFailoverClient{}
-			if test.exporter {
+			if test.managedTLS {
+				mrfs.On("EnsureTLSSecrets", rf, mock.Anything, mock.Anything).Once().Return(nil)
+			}
+			mrfs.On("EnsureRedisAuthSecret", rf, mock.Anything, mock.Anything).Once().Return(nil)
+			if test.exporter || test.announceHostnames {
 				mrfs.On("EnsureRedisService", rf, mock.Anything, mock.Anything).Once().Return(nil)
 			} else {
 				mrfs.On("EnsureNotPresentRedisService", rf).Once().Return(nil)
@@ -132,8 +152,8 @@ func TestEnsure(t *testing.T) {
 			mrfs.On("EnsureRedisStatefulset", rf, mock.Anything, mock.Anything).Once().Return(nil)
 
 			// Create the Kops client and call the valid logic.
-			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
-			err := handler.Ensure(rf, map[string]string{}, []metav1.OwnerReference{}, metrics.Dummy)
+			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			err := handler.Ensure(context.TODO(), rf, map[string]string{}, []metav1.OwnerReference{}, metrics.Dummy)
 
 			assert.NoError(err)
 			mrfs.AssertExpectations(t)
@@ -23,21 +23,23 @@ import (
 )
 
 const (
-	operatorName = "redis-operator"
-	lockKey      = "redis-failover-lease"
+	operatorName  = "redis-operator"
+	lockKey       = "redis-failover-lease"
+	backupLockKey = "redis-failover-backup-lease"
 )
 
 // New will create an operator that is responsible for managing all the required stuff
 // to create redis failovers.
 func New(cfg Config, k8sService k8s.Services, k8sClient kubernetes.Interface, lockNamespace string, redisClient redis.Client, kooperMetricsRecorder metrics.Recorder, logger log.Logger) (controller.Controller, error) {
 	// Create internal services.
-	rfService := rfservice.NewRedisFailoverKubeClient(k8sService, logger, kooperMetricsRecorder)
+	rfService := rfservice.NewRedisFailoverKubeClient(k8sService, logger, kooperMetricsRecorder, cfg.ClusterDomain)
 	rfChecker := rfservice.NewRedisFailoverChecker(k8sService, redisClient, logger, kooperMetricsRecorder)
 	rfHealer := rfservice.NewRedisFailoverHealer(k8sService, redisClient, logger)
+	rfBackup := rfservice.NewRedisFailoverBackupper(k8sService, redisClient, logger)
 
 	// Create the handlers.
-	rfHandler := NewRedisFailoverHandler(cfg, rfService, rfChecker, rfHealer, k8sService, kooperMetricsRecorder, logger)
-	rfRetriever := NewRedisFailoverRetriever(cfg, k8sService)
+	rfHandler := NewRedisFailoverHandler(cfg, rfService, rfChecker, rfHealer, rfBackup, k8sService, kooperMetricsRecorder, logger)
+	rfRetriever := NewRedisFailoverRetriever(cfg, k8sService, logger)
 
 	kooperLogger := kooperlogger{Logger: logger.WithField("operator", "redisfailover")}
 	// Leader election service.
@@ -59,7 +61,38 @@ func New(cfg Config, k8sService k8s.Services, k8sClient kubernetes.Interface, lo
 	})
 }
 
-func NewRedisFailoverRetriever(cfg Config, cli k8s.Services) controller.Retriever {
+// NewBackupController will create a controller that is responsible for taking the backups
+// requested by the RedisFailoverBackups.
+func NewBackupController(cfg Config, k8sService k8s.Services, k8sClient kubernetes.Interface, lockNamespace string, redisClient redis.Client, kooperMetricsRecorder metrics.Recorder, logger log.Logger) (controller.Controller, error) {
+	// Create internal services.
+	rfChecker := rfservice.NewRedisFailoverChecker(k8sService, redisClient, logger, kooperMetricsRecorder)
+	rfBackup := rfservice.NewRedisFailoverBackupper(k8sService, redisClient, logger)
+
+	// Create the handlers.
+	backupHandler := NewRedisFailoverBackupHandler(rfChecker, rfBackup, k8sService, logger)
+	backupRetriever := NewRedisFailoverBackupRetriever(cfg, k8sService)
+
+	kooperLogger := kooperlogger{Logger: logger.WithField("operator", "redisfailoverbackup")}
+	// Leader election service.
+	leSVC, err := leaderelection.NewDefault(backupLockKey, lockNamespace, k8sClient, kooperLogger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create our controller.
+	return controller.New(&controller.Config{
+		Handler:           backupHandler,
+		Retriever:         backupRetriever,
+		LeaderElector:     leSVC,
+		MetricsRecorder:   kooperMetricsRecorder,
+		Logger:            kooperLogger,
+		Name:              "redisfailoverbackup",
+		ResyncInterval:    time.Duration(cfg.SyncInterval) * time.Second,
+		ConcurrentWorkers: cfg.Concurrency,
+	})
+}
+
+func NewRedisFailoverRetriever(cfg Config, cli k8s.Services, logger log.Logger) controller.Retriever {
 	isNamespaceSupported := func(rf redisfailoverv1.RedisFailover) bool {
 		match, _ := regexp.Match(cfg.SupportedNamespacesRegex, []byte(rf.Namespace))
 		return match
@@ -85,6 +118,27 @@ func NewRedisFailoverRetriever(cfg Config, cli k8s.Services) controller.Retrieve
 		},
 		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
 			watcher, err := cli.WatchRedisFailovers(context.Background(), "", options)
+			if err != nil {
+				return nil, err
+			}
+			// The secrets are watched from now on, the resource version is the one of the RedisFailovers
+			secretOptions := metav1.ListOptions{TimeoutSeconds: options.TimeoutSeconds}
+			secretWatcher, err := cli.WatchSecrets(context.Background(), "", secretOptions)
+			if err != nil {
+				watcher.Stop()
+				return nil, err
+			}
+			listRedisFailovers := func(namespace string) ([]redisfailoverv1.RedisFailover, error) {
+				if match, _ := regexp.MatchString(cfg.SupportedNamespacesRegex, namespace); !match {
+					return nil, nil
+				}
+				rfList, err := cli.ListRedisFailovers(context.Background(), namespace, metav1.ListOptions{})
+				if err != nil {
+					return nil, err
+				}
+				return rfList.Items, nil
+			}
+			watcher = newSecretsWatcher(watcher, secretWatcher, options.ResourceVersion, listRedisFailovers, logger)
 			watcher = watch.Filter(watcher, func(event watch.Event) (watch.Event, bool) {
 				rf, ok := event.Object.(*redisfailoverv1.RedisFailover)
 				if !ok {
@@ -92,7 +146,46 @@ func NewRedisFailoverRetriever(cfg Config, cli k8s.Services) controller.Retrieve
 				}
 				return event, isNamespaceSupported(*rf)
 			})
-			return watcher, err
+			return watcher, nil
+		},
+	})
+}
+
+func NewRedisFailoverBackupRetriever(cfg Config, cli k8s.Services) controller.Retriever {
+	isNamespaceSupported := func(namespace string) bool {
+		match, _ := regexp.MatchString(cfg.SupportedNamespacesRegex, namespace)
+		return match
+	}
+
+	return controller.MustRetrieverFromListerWatcher(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			backupList, err := cli.ListRedisFailoverBackups(context.Background(), "", options)
+			if err != nil {
+				return backupList, err
+			}
+
+			targetBackups := make([]redisfailoverv1.RedisFailoverBackup, 0)
+			for _, backup := range backupList.Items {
+				if isNamespaceSupported(backup.Namespace) {
+					targetBackups = append(targetBackups, backup)
+				}
+			}
+			backupList.Items = targetBackups
+
+			return backupList, err
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			watcher, err := cli.WatchRedisFailoverBackups(context.Background(), "", options)
+			if err != nil {
+				return nil, err
+			}
+			return watch.Filter(watcher, func(event watch.Event) (watch.Event, bool) {
+				backup, ok := event.Object.(*redisfailoverv1.RedisFailoverBackup)
+				if !ok {
+					return event, false
+				}
+				return event, isNamespaceSupported(backup.Namespace)
+			}), nil
 		},
 	})
 }
@@ -0,0 +1,78 @@
+package redisfailover_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	v1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/saremox/redis-operator/operator/redisfailover"
+)
+
+func TestRetrieverWatchesSecrets(t *testing.T) {
+	assert := assert.New(t)
+
+	rfList := &v1.RedisFailoverList{Items: []v1.RedisFailover{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "auth", Namespace: "testns", ResourceVersion: "50"},
+			Spec:       v1.RedisFailoverSpec{Auth: v1.AuthSettings{SecretPath: "redis-auth"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "users", Namespace: "testns", ResourceVersion: "51"},
+			Spec: v1.RedisFailoverSpec{Auth: v1.AuthSettings{
+				Users: []v1.RedisUser{{Name: "app", SecretPath: "redis-auth"}},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "testns", ResourceVersion: "52"},
+			Spec:       v1.RedisFailoverSpec{Auth: v1.AuthSettings{SecretPath: "other-auth"}},
+		},
+	}}
+
+	rfWatcher := watch.NewFakeWithChanSize(1, false)
+	secretWatcher := watch.NewFakeWithChanSize(1, false)
+	mk := &mK8SService.Services{}
+	mk.On("WatchRedisFailovers", mock.Anything, "", metav1.ListOptions{ResourceVersion: "10"}).Once().Return(rfWatcher, nil)
+	mk.On("WatchSecrets", mock.Anything, "", metav1.ListOptions{}).Once().Return(secretWatcher, nil)
+	mk.On("ListRedisFailovers", mock.Anything, "testns", metav1.ListOptions{}).Once().Return(rfList, nil)
+
+	retriever := rfOperator.NewRedisFailoverRetriever(generateConfig(), mk, log.Dummy)
+	watcher, err := retriever.Watch(context.Background(), metav1.ListOptions{ResourceVersion: "10"})
+	require.NoError(t, err)
+	defer watcher.Stop()
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "redis-auth", Namespace: "testns"}}
+
+	// The secrets added when the watch starts are ignored
+	secretWatcher.Add(secret)
+	rfWatcher.Modify(&v1.RedisFailover{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "testns", ResourceVersion: "20"}})
+	event := <-watcher.ResultChan()
+	assert.Equal(watch.Modified, event.Type)
+	assert.Equal("other", event.Object.(*v1.RedisFailover).Name)
+
+	// The redis failovers using a modified secret are updated
+	secretWatcher.Modify(secret)
+	for _, name := range []string{"auth", "users"} {
+		event := <-watcher.ResultChan()
+		require.Equal(t, watch.Modified, event.Type)
+		rf := event.Object.(*v1.RedisFailover)
+		assert.Equal(name, rf.Name)
+		// The watch is resumed from the last redis failover event
+		assert.Equal("20", rf.ResourceVersion)
+	}
+
+	// Both watches are restarted when one ends
+	secretWatcher.Stop()
+	_, ok := <-watcher.ResultChan()
+	assert.False(ok)
+	assert.True(rfWatcher.IsStopped())
+	mk.AssertExpectations(t)
+}
@@ -0,0 +1,185 @@
+package redisfailover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+// Reasons of the events recorded on the RedisFailover
+const (
+	eventReasonFinalSnapshotTaken  = "FinalSnapshotTaken"
+	eventReasonFinalSnapshotFailed = "FinalSnapshotFailed"
+)
+
+// finalSnapshotTimeout is how long after the deletion the final snapshot is taken again, the
+// finalizer is removed without it afterwards
+const finalSnapshotTimeout = 15 * time.Minute
+
+// EnsureFinalizer makes sure the Redis failover holds the final snapshot finalizer only while
+// a final snapshot is requested.
+func (r *RedisFailoverHandler) EnsureFinalizer(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	hasFinalizer := slices.Contains(rf.Finalizers, redisfailoverv1.FinalSnapshotFinalizer)
+	switch {
+	case rf.FinalSnapshotEnabled() && !hasFinalizer:
+		return r.k8sservice.AddRedisFailoverFinalizer(ctx, rf, redisfailoverv1.FinalSnapshotFinalizer)
+	case !rf.FinalSnapshotEnabled() && hasFinalizer:
+		return r.k8sservice.RemoveRedisFailoverFinalizer(ctx, rf, redisfailoverv1.FinalSnapshotFinalizer)
+	}
+	return nil
+}
+
+// Finalize takes the final snapshot of a deleted Redis failover and records where it went in an
+// event. The finalizer is only removed then, so the owned resources are not garbage collected
+// before. The snapshot is taken again until the finalizer is removed. It is given up, with a
+// warning event, when no redis pod is left or finalSnapshotTimeout after the deletion.
+func (r *RedisFailoverHandler) Finalize(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	if !slices.Contains(rf.Finalizers, redisfailoverv1.FinalSnapshotFinalizer) {
+		return nil
+	}
+
+	if rf.FinalSnapshotEnabled() {
+		logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+		message, err := r.takeFinalSnapshot(ctx, rf)
+		if err != nil {
+			logger.Errorf("Unable to take the final snapshot: %s", err.Error())
+			reason, giveUpErr := r.giveUpFinalSnapshot(rf)
+			if giveUpErr != nil {
+				return giveUpErr
+			}
+			if reason == "" {
+				return err
+			}
+			message := fmt.Sprintf("Final snapshot not taken, %s: %s", reason, err.Error())
+			logger.Warn(message)
+			if err := r.recordEvent(rf, corev1.EventTypeWarning, eventReasonFinalSnapshotFailed, message); err != nil {
+				return err
+			}
+			return r.k8sservice.RemoveRedisFailoverFinalizer(ctx, rf, redisfailoverv1.FinalSnapshotFinalizer)
+		}
+		logger.Info(message)
+		if err := r.recordEvent(rf, corev1.EventTypeNormal, eventReasonFinalSnapshotTaken, message); err != nil {
+			return err
+		}
+	}
+	return r.k8sservice.RemoveRedisFailoverFinalizer(ctx, rf, redisfailoverv1.FinalSnapshotFinalizer)
+}
+
+// giveUpFinalSnapshot returns why the final snapshot that could not be taken is given up, empty
+// when it is taken again. The redis pods may be gone already, e.g. with a foreground deletion or
+// when the namespace is deleted, and never come back.
+func (r *RedisFailoverHandler) giveUpFinalSnapshot(rf *redisfailoverv1.RedisFailover) (string, error) {
+	pods, err := r.k8sservice.GetStatefulSetPods(rf.Namespace, rfservice.GetRedisName(rf))
+	switch {
+	case k8serrors.IsNotFound(err):
+		return "no redis pod left", nil
+	case err != nil:
+		return "", err
+	case len(pods.Items) == 0:
+		return "no redis pod left", nil
+	case time.Since(rf.DeletionTimestamp.Time) > finalSnapshotTimeout:
+		return fmt.Sprintf("given up %s after the deletion", finalSnapshotTimeout), nil
+	}
+	return "", nil
+}
+
+// takeFinalSnapshot takes the snapshot of the most up to date redis node and uploads it to the
+// object storage, or keeps the persistent volume claim it is saved on. It returns where the
+// snapshot went.
+func (r *RedisFailoverHandler) takeFinalSnapshot(ctx context.Context, rf *redisfailoverv1.RedisFailover) (string, error) {
+	node, err := r.getFinalSnapshotNode(ctx, rf)
+	if err != nil {
+		return "", err
+	}
+
+	finalSnapshot := rf.Spec.Redis.Storage.FinalSnapshot
+	if finalSnapshot.S3 != nil {
+		// Named after the deletion, so the snapshot is overwritten when it is taken again
+		name := fmt.Sprintf("%s-final-%s", rf.Name, rf.DeletionTimestamp.UTC().Format("20060102150405"))
+		result, err := r.rfBackup.BackupRedis(ctx, node.IP, rf, &redisfailoverv1.BackupStorage{S3: finalSnapshot.S3}, name)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Final snapshot of %s uploaded to %s (%d bytes, %s)", node.PodName, result.Location, result.Size, result.Checksum), nil
+	}
+
+	if err := r.rfBackup.SaveRedis(ctx, node.IP, rf); err != nil {
+		return "", err
+	}
+	claimName := rfservice.GetRedisDataVolumeClaimName(rf, node.PodName)
+	pvc, err := r.k8sservice.GetPersistentVolumeClaim(rf.Namespace, claimName)
+	if err != nil {
+		return "", err
+	}
+	// Without the owner reference the claim is not garbage collected along with the Redis failover
+	ownerReferences := slices.DeleteFunc(slices.Clone(pvc.OwnerReferences), func(ref metav1.OwnerReference) bool {
+		return ref.UID == rf.UID
+	})
+	if len(ownerReferences) != len(pvc.OwnerReferences) {
+		pvc.OwnerReferences = ownerReferences
+		if err := r.k8sservice.UpdatePersistentVolumeClaim(rf.Namespace, pvc); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("Final snapshot of %s saved in the retained persistentvolumeclaim %s", node.PodName, claimName), nil
+}
+
+// getFinalSnapshotNode returns the master, or the replica with the highest replication offset
+// when there is none
+func (r *RedisFailoverHandler) getFinalSnapshotNode(ctx context.Context, rf *redisfailoverv1.RedisFailover) (*rfservice.RedisNodeReplication, error) {
+	nodes, err := r.rfChecker.GetRedisesReplication(ctx, rf)
+	if err != nil {
+		return nil, err
+	}
+
+	var replica *rfservice.RedisNodeReplication
+	for i := range nodes {
+		node := &nodes[i]
+		if node.Replication.Role == "master" {
+			return node, nil
+		}
+		if replica == nil || node.Replication.SlaveReplOffset > replica.Replication.SlaveReplOffset {
+			replica = node
+		}
+	}
+	if replica == nil {
+		return nil, errors.New("no redis node to take the final snapshot from")
+	}
+	return replica, nil
+}
+
+// recordEvent records an event on the Redis failover
+func (r *RedisFailoverHandler) recordEvent(rf *redisfailoverv1.RedisFailover, eventType, reason, message string) error {
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", rf.Name, now.UnixNano()),
+			Namespace: rf.Namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion:      redisfailoverv1.SchemeGroupVersion.String(),
+			Kind:            redisfailoverv1.RFKind,
+			Name:            rf.Name,
+			Namespace:       rf.Namespace,
+			UID:             rf.UID,
+			ResourceVersion: rf.ResourceVersion,
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		Source:         corev1.EventSource{Component: operatorName},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	return r.k8sservice.CreateEvent(rf.Namespace, event)
+}
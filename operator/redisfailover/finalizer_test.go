@@ -0,0 +1,270 @@
+package redisfailover_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+	mRFService "github.com/saremox/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/saremox/redis-operator/operator/redisfailover"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+	"github.com/saremox/redis-operator/service/redis"
+)
+
+func TestEnsureFinalizer(t *testing.T) {
+	tests := []struct {
+		name          string
+		finalSnapshot bool
+		finalizers    []string
+		expAdd        bool
+		expRemove     bool
+	}{
+		{
+			name: "No final snapshot",
+		},
+		{
+			name:          "Final snapshot requested",
+			finalSnapshot: true,
+			expAdd:        true,
+		},
+		{
+			name:          "Finalizer already added",
+			finalSnapshot: true,
+			finalizers:    []string{redisfailoverv1.FinalSnapshotFinalizer},
+		},
+		{
+			name:       "Final snapshot no longer requested",
+			finalizers: []string{redisfailoverv1.FinalSnapshotFinalizer},
+			expRemove:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			rf.Finalizers = test.finalizers
+			if test.finalSnapshot {
+				rf.Spec.Redis.Storage.FinalSnapshot = &redisfailoverv1.FinalSnapshotSettings{RetainVolumeClaim: true}
+			}
+
+			mk := &mK8SService.Services{}
+			if test.expAdd {
+				mk.On("AddRedisFailoverFinalizer", mock.Anything, rf, redisfailoverv1.FinalSnapshotFinalizer).Once().Return(nil)
+			}
+			if test.expRemove {
+				mk.On("RemoveRedisFailoverFinalizer", mock.Anything, rf, redisfailoverv1.FinalSnapshotFinalizer).Once().Return(nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, &mRFService.RedisFailoverCheck{}, &mRFService.RedisFailoverHeal{}, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			assert.NoError(handler.EnsureFinalizer(context.TODO(), rf))
+			mk.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFinalize(t *testing.T) {
+	master := rfservice.RedisNodeReplication{PodName: "rfr-test-0", IP: "0.0.0.0", Replication: &redis.ReplicationInfo{Role: "master"}}
+	replica := func(pod, ip string, offset int64) rfservice.RedisNodeReplication {
+		return rfservice.RedisNodeReplication{PodName: pod, IP: ip, Replication: &redis.ReplicationInfo{Role: "slave", MasterLinkStatus: "down", SlaveReplOffset: offset}}
+	}
+	s3Storage := &redisfailoverv1.S3Storage{Endpoint: "http://minio:9000", Bucket: "backups", SecretName: "s3-credentials"}
+	rfUID := types.UID("5b8fe1d4")
+
+	tests := []struct {
+		name          string
+		finalSnapshot *redisfailoverv1.FinalSnapshotSettings
+		finalizer     bool
+		nodes         []rfservice.RedisNodeReplication
+		snapshotErr   error
+		redisPods     int
+		deletedSince  time.Duration
+		expIP         string
+		expMessage    string
+		expWarning    string
+		expErr        bool
+	}{
+		{
+			name:      "Finalizer already removed",
+			finalizer: false,
+		},
+		{
+			name:      "Final snapshot no longer requested",
+			finalizer: true,
+		},
+		{
+			name:          "Final snapshot of the master uploaded",
+			finalSnapshot: &redisfailoverv1.FinalSnapshotSettings{S3: s3Storage},
+			finalizer:     true,
+			nodes:         []rfservice.RedisNodeReplication{replica("rfr-test-1", "0.0.0.1", 100), master},
+			expIP:         "0.0.0.0",
+			expMessage:    "Final snapshot of rfr-test-0 uploaded to s3://backups/test/test-final-20240131030000.rdb (1024 bytes, sha256:0123)",
+		},
+		{
+			name:          "Final snapshot of the most up to date replica without master",
+			finalSnapshot: &redisfailoverv1.FinalSnapshotSettings{S3: s3Storage},
+			finalizer:     true,
+			nodes:         []rfservice.RedisNodeReplication{replica("rfr-test-1", "0.0.0.1", 100), replica("rfr-test-2", "0.0.0.2", 200)},
+			expIP:         "0.0.0.2",
+			expMessage:    "Final snapshot of rfr-test-2 uploaded to s3://backups/test/test-final-20240131030000.rdb (1024 bytes, sha256:0123)",
+		},
+		{
+			name:          "Final snapshot in the retained volume claim",
+			finalSnapshot: &redisfailoverv1.FinalSnapshotSettings{RetainVolumeClaim: true},
+			finalizer:     true,
+			nodes:         []rfservice.RedisNodeReplication{master},
+			expIP:         "0.0.0.0",
+			expMessage:    "Final snapshot of rfr-test-0 saved in the retained persistentvolumeclaim redis-data-rfr-test-0",
+		},
+		{
+			name:          "Upload failed",
+			finalSnapshot: &redisfailoverv1.FinalSnapshotSettings{S3: s3Storage},
+			finalizer:     true,
+			nodes:         []rfservice.RedisNodeReplication{master},
+			snapshotErr:   errors.New("s3: AccessDenied: Access Denied."),
+			redisPods:     3,
+			expIP:         "0.0.0.0",
+			expErr:        true,
+		},
+		{
+			name:          "No redis node answering",
+			finalSnapshot: &redisfailoverv1.FinalSnapshotSettings{S3: s3Storage},
+			finalizer:     true,
+			nodes:         []rfservice.RedisNodeReplication{},
+			redisPods:     3,
+			expErr:        true,
+		},
+		{
+			name:          "Final snapshot given up without redis pod",
+			finalSnapshot: &redisfailoverv1.FinalSnapshotSettings{S3: s3Storage},
+			finalizer:     true,
+			nodes:         []rfservice.RedisNodeReplication{},
+			expWarning:    "Final snapshot not taken, no redis pod left: no redis node to take the final snapshot from",
+		},
+		{
+			name:          "Final snapshot given up after the timeout",
+			finalSnapshot: &redisfailoverv1.FinalSnapshotSettings{S3: s3Storage},
+			finalizer:     true,
+			nodes:         []rfservice.RedisNodeReplication{master},
+			snapshotErr:   errors.New("s3: AccessDenied: Access Denied."),
+			redisPods:     3,
+			deletedSince:  time.Hour,
+			expIP:         "0.0.0.0",
+			expWarning:    "Final snapshot not taken, given up 15m0s after the deletion: s3: AccessDenied: Access Denied.",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			rf.UID = rfUID
+			deletionTime := metav1.NewTime(time.Now().Add(-time.Minute - test.deletedSince))
+			rf.DeletionTimestamp = &deletionTime
+			rf.Spec.Redis.Storage.PersistentVolumeClaim = &redisfailoverv1.EmbeddedPersistentVolumeClaim{
+				EmbeddedObjectMetadata: redisfailoverv1.EmbeddedObjectMetadata{Name: "redis-data"},
+			}
+			rf.Spec.Redis.Storage.FinalSnapshot = test.finalSnapshot
+			if test.finalizer {
+				rf.Finalizers = []string{redisfailoverv1.FinalSnapshotFinalizer}
+			}
+
+			mk := &mK8SService.Services{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfb := &mRFService.RedisFailoverBackup{}
+			if test.nodes != nil {
+				mrfc.On("GetRedisesReplication", mock.Anything, rf).Once().Return(test.nodes, nil)
+			}
+			if test.expIP != "" && test.finalSnapshot.S3 != nil {
+				var result *rfservice.BackupResult
+				if test.snapshotErr == nil {
+					result = &rfservice.BackupResult{Location: "s3://backups/test/test-final-20240131030000.rdb", Size: 1024, Checksum: "sha256:0123"}
+				}
+				mrfb.On("BackupRedis", mock.Anything, test.expIP, rf, &redisfailoverv1.BackupStorage{S3: s3Storage}, "test-final-"+deletionTime.UTC().Format("20060102150405")).Once().Return(result, test.snapshotErr)
+			}
+			var pvc *corev1.PersistentVolumeClaim
+			if test.expIP != "" && test.finalSnapshot.RetainVolumeClaim {
+				mrfb.On("SaveRedis", mock.Anything, test.expIP, rf).Once().Return(test.snapshotErr)
+				mk.On("GetPersistentVolumeClaim", namespace, "redis-data-rfr-test-0").Once().Return(&corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "redis-data-rfr-test-0",
+						OwnerReferences: []metav1.OwnerReference{{Kind: redisfailoverv1.RFKind, Name: rf.Name, UID: rfUID}},
+					},
+				}, nil)
+				mk.On("UpdatePersistentVolumeClaim", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+					pvc = args.Get(1).(*corev1.PersistentVolumeClaim)
+				}).Return(nil)
+			}
+			if test.snapshotErr != nil || (test.nodes != nil && len(test.nodes) == 0) {
+				mk.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(&corev1.PodList{Items: make([]corev1.Pod, test.redisPods)}, nil)
+			}
+			var event *corev1.Event
+			if test.expMessage != "" || test.expWarning != "" {
+				mk.On("CreateEvent", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+					event = args.Get(1).(*corev1.Event)
+				}).Return(nil)
+			}
+			if test.finalizer && !test.expErr {
+				mk.On("RemoveRedisFailoverFinalizer", mock.Anything, rf, redisfailoverv1.FinalSnapshotFinalizer).Once().Return(nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, mrfc, &mRFService.RedisFailoverHeal{}, mrfb, mk, metrics.Dummy, log.Dummy)
+			err := handler.Finalize(context.TODO(), rf)
+
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			if test.expWarning != "" && assert.NotNil(event) {
+				assert.Equal(corev1.EventTypeWarning, event.Type)
+				assert.Equal("FinalSnapshotFailed", event.Reason)
+				assert.Equal(test.expWarning, event.Message)
+			}
+			if test.expMessage != "" && assert.NotNil(event) {
+				assert.Equal(corev1.EventTypeNormal, event.Type)
+				assert.Equal("FinalSnapshotTaken", event.Reason)
+				assert.Equal(test.expMessage, event.Message)
+				assert.Equal(rfUID, event.InvolvedObject.UID)
+			}
+			if pvc != nil {
+				assert.Empty(pvc.OwnerReferences)
+			}
+			mk.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfb.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleFinalizesInvalidRedisFailover(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false)
+	deletionTime := metav1.Now()
+	rf.DeletionTimestamp = &deletionTime
+	rf.Finalizers = []string{redisfailoverv1.FinalSnapshotFinalizer}
+	// Not valid, a secret must be given
+	rf.Spec.TLS = &redisfailoverv1.TLSSettings{}
+	assert.Error(rf.Validate())
+
+	mk := &mK8SService.Services{}
+	mk.On("RemoveRedisFailoverFinalizer", mock.Anything, rf, redisfailoverv1.FinalSnapshotFinalizer).Once().Return(nil)
+
+	handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, &mRFService.RedisFailoverCheck{}, &mRFService.RedisFailoverHeal{}, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+	assert.NoError(handler.Handle(context.TODO(), rf))
+	mk.AssertExpectations(t)
+}
@@ -35,17 +35,19 @@ type RedisFailoverHandler struct {
 	rfService  rfservice.RedisFailoverClient
 	rfChecker  rfservice.RedisFailoverCheck
 	rfHealer   rfservice.RedisFailoverHeal
+	rfBackup   rfservice.RedisFailoverBackup
 	mClient    metrics.Recorder
 	logger     log.Logger
 }
 
 // NewRedisFailoverHandler returns a new RF handler
-func NewRedisFailoverHandler(config Config, rfService rfservice.RedisFailoverClient, rfChecker rfservice.RedisFailoverCheck, rfHealer rfservice.RedisFailoverHeal, k8sservice k8s.Services, mClient metrics.Recorder, logger log.Logger) *RedisFailoverHandler {
+func NewRedisFailoverHandler(config Config, rfService rfservice.RedisFailoverClient, rfChecker rfservice.RedisFailoverCheck, rfHealer rfservice.RedisFailoverHeal, rfBackup rfservice.RedisFailoverBackup, k8sservice k8s.Services, mClient metrics.Recorder, logger log.Logger) *RedisFailoverHandler {
 	return &RedisFailoverHandler{
 		config:     config,
 		rfService:  rfService,
 		rfChecker:  rfChecker,
 		rfHealer:   rfHealer,
+		rfBackup:   rfBackup,
 		mClient:    mClient,
 		k8sservice: k8sservice,
 		logger:     logger,
@@ -53,17 +55,50 @@ func NewRedisFailoverHandler(config Config, rfService rfservice.RedisFailoverCli
 }
 
 // Handle will ensure the redis failover is in the expected state.
-func (r *RedisFailoverHandler) Handle(_ context.Context, obj runtime.Object) error {
+func (r *RedisFailoverHandler) Handle(ctx context.Context, obj runtime.Object) error {
 	rf, ok := obj.(*redisfailoverv1.RedisFailover)
 	if !ok {
 		return fmt.Errorf("can't handle the received object: not a redisfailover")
 	}
 
+	// The redis nodes are left untouched while the Redis failover is deleted. It is finalized
+	// even when its spec is not valid anymore, so its deletion is never blocked.
+	if rf.DeletionTimestamp != nil {
+		return r.Finalize(ctx, rf)
+	}
+
 	if err := rf.Validate(); err != nil {
 		r.mClient.SetClusterError(rf.Namespace, rf.Name)
 		return err
 	}
 
+	if err := r.EnsureFinalizer(ctx, rf); err != nil {
+		r.mClient.SetClusterError(rf.Namespace, rf.Name)
+		return err
+	}
+
+	if err := r.StartRestore(ctx, rf); err != nil {
+		r.mClient.SetClusterError(rf.Namespace, rf.Name)
+		return err
+	}
+
+	if err := r.ScaleDown(ctx, rf); err != nil {
+		r.mClient.SetClusterError(rf.Namespace, rf.Name)
+		updateStatus(r.k8sservice, rf, rf.Status.State)
+		return err
+	}
+
+	if err := r.EnsureClaimsRetention(rf); err != nil {
+		r.mClient.SetClusterError(rf.Namespace, rf.Name)
+		return err
+	}
+
+	if err := r.ExpandStorage(ctx, rf); err != nil {
+		r.mClient.SetClusterError(rf.Namespace, rf.Name)
+		updateStatus(r.k8sservice, rf, rf.Status.State)
+		return err
+	}
+
 	// Create owner refs so the objects manager by this handler have ownership to the
 	// received RF.
 	oRefs := r.createOwnerReferences(rf)
@@ -71,12 +106,35 @@ func (r *RedisFailoverHandler) Handle(_ context.Context, obj runtime.Object) err
 	// Create the labels every object derived from this need to have.
 	labels := r.getLabels(rf)
 
-	if err := r.Ensure(rf, labels, oRefs, r.mClient); err != nil {
+	if err := r.Ensure(ctx, rf, labels, oRefs, r.mClient); err != nil {
 		r.mClient.SetClusterError(rf.Namespace, rf.Name)
+		oldState := rf.Status.State
+		setNotHealthy(rf, redisfailoverv1.ConditionResourcesReconciled, redisfailoverv1.ReasonReconcileFailed, err.Error())
+		updateStatus(r.k8sservice, rf, oldState)
 		return err
 	}
 
-	if err := r.CheckAndHeal(rf); err != nil {
+	// The redis nodes are only healed once the restored one has been promoted
+	if rf.Restoring() {
+		if err := r.CompleteRestore(ctx, rf); err != nil {
+			r.mClient.SetClusterError(rf.Namespace, rf.Name)
+			return err
+		}
+		r.mClient.SetClusterOK(rf.Namespace, rf.Name)
+		return nil
+	}
+
+	r.RotateRedisPassword(ctx, rf)
+	r.SyncACLUsers(ctx, rf)
+
+	if err := r.Switchover(ctx, rf); err != nil {
+		r.mClient.SetClusterError(rf.Namespace, rf.Name)
+		return err
+	}
+
+	r.ScheduleBackup(ctx, rf)
+
+	if err := r.CheckAndHeal(ctx, rf); err != nil {
 		r.mClient.SetClusterError(rf.Namespace, rf.Name)
 		return err
 	}
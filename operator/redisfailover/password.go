@@ -0,0 +1,123 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+// RotateRedisPassword applies the password of the auth secret to the redis nodes without
+// disconnecting the clients. The new password is first accepted along with the previous one and
+// used for the replication, by the sentinels and by the operator. The previous one is only removed
+// once the grace period has elapsed, leaving the clients time to switch.
+func (r *RedisFailoverHandler) RotateRedisPassword(ctx context.Context, rf *redisfailoverv1.RedisFailover) {
+	if rf.Spec.Auth.SecretPath == "" {
+		rf.RemoveCondition(redisfailoverv1.ConditionPasswordInSync)
+		return
+	}
+
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+	fail := func(err error) {
+		logger.Errorf("Unable to rotate the redis password: %s", err.Error())
+		rf.SetCondition(redisfailoverv1.ConditionPasswordInSync, metav1.ConditionFalse, redisfailoverv1.ReasonRotationFailed, err.Error())
+	}
+
+	state, err := r.rfService.GetRedisPasswordState(rf)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	if state.Desired != state.Applied {
+		// The password rotated away from during a rotation still in progress stops being accepted
+		if state.Rotating && state.Previous != state.Desired {
+			if err := r.removeRedisPassword(ctx, rf, state.Previous); err != nil {
+				fail(err)
+				return
+			}
+		}
+		if err := r.addRedisPassword(ctx, rf, state.Desired); err != nil {
+			fail(err)
+			return
+		}
+		state = &rfservice.RedisPasswordState{
+			Applied:   state.Desired,
+			Desired:   state.Desired,
+			Previous:  state.Applied,
+			Rotating:  true,
+			RotatedAt: time.Now(),
+		}
+		if err := r.rfService.SetRedisPasswordState(rf, state); err != nil {
+			fail(err)
+			return
+		}
+		logger.Infof("New redis password applied, the previous one is accepted for %s", rf.PasswordRotationGracePeriod())
+	}
+
+	if state.Rotating {
+		expiration := state.RotatedAt.Add(rf.PasswordRotationGracePeriod())
+		if time.Now().Before(expiration) {
+			rf.SetCondition(redisfailoverv1.ConditionPasswordInSync, metav1.ConditionFalse, redisfailoverv1.ReasonPasswordRotating, fmt.Sprintf("previous password accepted until %s", expiration.UTC().Format(time.RFC3339)))
+			return
+		}
+		if err := r.removeRedisPassword(ctx, rf, state.Previous); err != nil {
+			fail(err)
+			return
+		}
+		state.Previous = ""
+		state.Rotating = false
+		if err := r.rfService.SetRedisPasswordState(rf, state); err != nil {
+			fail(err)
+			return
+		}
+		logger.Infof("Previous redis password removed")
+	}
+
+	rf.SetCondition(redisfailoverv1.ConditionPasswordInSync, metav1.ConditionTrue, redisfailoverv1.ReasonPasswordApplied, "the redis nodes only accept the password of the auth secret")
+}
+
+// addRedisPassword makes every redis node accept the password and the sentinels use it
+func (r *RedisFailoverHandler) addRedisPassword(ctx context.Context, rf *redisfailoverv1.RedisFailover, password string) error {
+	redises, err := r.rfChecker.GetRedisesIPs(rf)
+	if err != nil {
+		return err
+	}
+	for _, rip := range redises {
+		if err := r.rfHealer.AddRedisPassword(ctx, rip, password, rf); err != nil {
+			return fmt.Errorf("%s: %w", rip, err)
+		}
+	}
+
+	if !rf.SentinelsAllowed() {
+		return nil
+	}
+	sentinels, err := r.rfChecker.GetSentinelsIPs(rf)
+	if err != nil {
+		return err
+	}
+	for _, sip := range sentinels {
+		if err := r.rfHealer.SetSentinelAuthPass(ctx, sip, password, rf); err != nil {
+			return fmt.Errorf("%s: %w", sip, err)
+		}
+	}
+	return nil
+}
+
+// removeRedisPassword makes every redis node stop accepting the password
+func (r *RedisFailoverHandler) removeRedisPassword(ctx context.Context, rf *redisfailoverv1.RedisFailover, password string) error {
+	redises, err := r.rfChecker.GetRedisesIPs(rf)
+	if err != nil {
+		return err
+	}
+	for _, rip := range redises {
+		if err := r.rfHealer.RemoveRedisPassword(ctx, rip, password, rf); err != nil {
+			return fmt.Errorf("%s: %w", rip, err)
+		}
+	}
+	return nil
+}
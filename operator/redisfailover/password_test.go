@@ -0,0 +1,146 @@
+package redisfailover_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+	mRFService "github.com/saremox/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/saremox/redis-operator/operator/redisfailover"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+func TestRotateRedisPassword(t *testing.T) {
+	tests := []struct {
+		name         string
+		secretPath   string
+		sentinel     bool
+		state        *rfservice.RedisPasswordState
+		healErr      error
+		expAdded     string
+		expRemoved   []string
+		expState     *rfservice.RedisPasswordState
+		expCondition metav1.ConditionStatus
+		expReason    string
+	}{
+		{
+			name: "No auth",
+		},
+		{
+			name:         "Password in sync",
+			secretPath:   "redis-auth",
+			state:        &rfservice.RedisPasswordState{Applied: "old", Desired: "old"},
+			expCondition: metav1.ConditionTrue,
+			expReason:    v1.ReasonPasswordApplied,
+		},
+		{
+			name:         "New password applied along with the previous one",
+			secretPath:   "redis-auth",
+			sentinel:     true,
+			state:        &rfservice.RedisPasswordState{Applied: "old", Desired: "new"},
+			expAdded:     "new",
+			expState:     &rfservice.RedisPasswordState{Applied: "new", Desired: "new", Previous: "old", Rotating: true},
+			expCondition: metav1.ConditionFalse,
+			expReason:    v1.ReasonPasswordRotating,
+		},
+		{
+			name:         "Previous password kept during the grace period",
+			secretPath:   "redis-auth",
+			state:        &rfservice.RedisPasswordState{Applied: "new", Desired: "new", Previous: "old", Rotating: true, RotatedAt: time.Now()},
+			expCondition: metav1.ConditionFalse,
+			expReason:    v1.ReasonPasswordRotating,
+		},
+		{
+			name:         "Previous password removed after the grace period",
+			secretPath:   "redis-auth",
+			state:        &rfservice.RedisPasswordState{Applied: "new", Desired: "new", Previous: "old", Rotating: true, RotatedAt: time.Now().Add(-time.Hour)},
+			expRemoved:   []string{"old"},
+			expState:     &rfservice.RedisPasswordState{Applied: "new", Desired: "new"},
+			expCondition: metav1.ConditionTrue,
+			expReason:    v1.ReasonPasswordApplied,
+		},
+		{
+			name:         "Rotation during a rotation removes the first password",
+			secretPath:   "redis-auth",
+			state:        &rfservice.RedisPasswordState{Applied: "new", Desired: "newer", Previous: "old", Rotating: true, RotatedAt: time.Now()},
+			expRemoved:   []string{"old"},
+			expAdded:     "newer",
+			expState:     &rfservice.RedisPasswordState{Applied: "newer", Desired: "newer", Previous: "new", Rotating: true},
+			expCondition: metav1.ConditionFalse,
+			expReason:    v1.ReasonPasswordRotating,
+		},
+		{
+			name:         "Rotation fails",
+			secretPath:   "redis-auth",
+			state:        &rfservice.RedisPasswordState{Applied: "old", Desired: "new"},
+			healErr:      errors.New(""),
+			expAdded:     "new",
+			expCondition: metav1.ConditionFalse,
+			expReason:    v1.ReasonRotationFailed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			rf.Spec.Auth.SecretPath = test.secretPath
+			rf.Spec.Sentinel.Enabled = &test.sentinel
+
+			mk := &mK8SService.Services{}
+			mrfs := &mRFService.RedisFailoverClient{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+			if test.state != nil {
+				mrfs.On("GetRedisPasswordState", rf).Once().Return(test.state, nil)
+			}
+			if test.expAdded != "" || len(test.expRemoved) > 0 {
+				mrfc.On("GetRedisesIPs", rf).Return([]string{"0.0.0.0", "0.0.0.1"}, nil)
+			}
+			for _, password := range test.expRemoved {
+				mrfh.On("RemoveRedisPassword", mock.Anything, "0.0.0.0", password, rf).Once().Return(nil)
+				mrfh.On("RemoveRedisPassword", mock.Anything, "0.0.0.1", password, rf).Once().Return(nil)
+			}
+			if test.expAdded != "" {
+				mrfh.On("AddRedisPassword", mock.Anything, "0.0.0.0", test.expAdded, rf).Once().Return(test.healErr)
+				if test.healErr == nil {
+					mrfh.On("AddRedisPassword", mock.Anything, "0.0.0.1", test.expAdded, rf).Once().Return(nil)
+				}
+			}
+			if test.sentinel {
+				mrfc.On("GetSentinelsIPs", rf).Once().Return([]string{"1.1.1.1"}, nil)
+				mrfh.On("SetSentinelAuthPass", mock.Anything, "1.1.1.1", test.expAdded, rf).Once().Return(nil)
+			}
+			if test.expState != nil {
+				mrfs.On("SetRedisPasswordState", rf, mock.MatchedBy(func(state *rfservice.RedisPasswordState) bool {
+					return state.Applied == test.expState.Applied && state.Previous == test.expState.Previous && state.Rotating == test.expState.Rotating
+				})).Once().Return(nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			handler.RotateRedisPassword(context.TODO(), rf)
+
+			condition := rf.GetCondition(v1.ConditionPasswordInSync)
+			if test.secretPath == "" {
+				assert.Nil(condition)
+			} else if assert.NotNil(condition) {
+				assert.Equal(test.expCondition, condition.Status)
+				assert.Equal(test.expReason, condition.Reason)
+			}
+
+			mrfs.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
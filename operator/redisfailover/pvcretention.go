@@ -0,0 +1,83 @@
+package redisfailover
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+// redisClaim is the persistent volume claim of a redis pod
+type redisClaim struct {
+	*corev1.PersistentVolumeClaim
+	ordinal int
+}
+
+// EnsureClaimsRetention applies pvcRetentionPolicy whenDeleted to the persistent volume claims
+// of the redis pods: they are owned by the Redis failover, so garbage collected along with it,
+// only when they are deleted with it. The statefulset only sets the owner on the claims it
+// creates, this keeps the existing ones in line when the policy changes.
+func (r *RedisFailoverHandler) EnsureClaimsRetention(rf *redisfailoverv1.RedisFailover) error {
+	// The selector is only known once the redis statefulset exists
+	if rf.Status.Selector == "" {
+		return nil
+	}
+	claims, err := r.listRedisClaims(rf, rf.Status.Selector)
+	if err != nil {
+		return err
+	}
+
+	owned := rf.DeleteClaimsWhenDeleted()
+	for _, claim := range claims {
+		isOwned := slices.ContainsFunc(claim.OwnerReferences, func(ref metav1.OwnerReference) bool {
+			return ref.UID == rf.UID
+		})
+		if isOwned == owned {
+			continue
+		}
+		if owned {
+			claim.OwnerReferences = append(claim.OwnerReferences, r.createOwnerReferences(rf)...)
+		} else {
+			claim.OwnerReferences = slices.DeleteFunc(claim.OwnerReferences, func(ref metav1.OwnerReference) bool {
+				return ref.UID == rf.UID
+			})
+		}
+		if err := r.k8sservice.UpdatePersistentVolumeClaim(rf.Namespace, claim.PersistentVolumeClaim); err != nil {
+			return err
+		}
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Persistent volume claim %s retention updated, deleted with the Redis failover: %t", claim.Name, owned)
+	}
+	return nil
+}
+
+// listRedisClaims returns the persistent volume claims created from the volume claim template of
+// the redis statefulset
+func (r *RedisFailoverHandler) listRedisClaims(rf *redisfailoverv1.RedisFailover, selector string) ([]redisClaim, error) {
+	if rf.Spec.Redis.Storage.PersistentVolumeClaim == nil {
+		return nil, nil
+	}
+	pvcs, err := r.k8sservice.ListPersistentVolumeClaims(rf.Namespace, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := rfservice.GetRedisDataVolumeClaimName(rf, rfservice.GetRedisName(rf)) + "-"
+	claims := []redisClaim{}
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		if !strings.HasPrefix(pvc.Name, prefix) {
+			continue
+		}
+		ordinal, err := strconv.Atoi(strings.TrimPrefix(pvc.Name, prefix))
+		if err != nil {
+			continue
+		}
+		claims = append(claims, redisClaim{PersistentVolumeClaim: pvc, ordinal: ordinal})
+	}
+	return claims, nil
+}
@@ -0,0 +1,109 @@
+package redisfailover_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+	mRFService "github.com/saremox/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/saremox/redis-operator/operator/redisfailover"
+)
+
+func TestEnsureClaimsRetention(t *testing.T) {
+	rfUID := types.UID("5b8fe1d4")
+	selector := "app.kubernetes.io/component=redis,app.kubernetes.io/name=test"
+	owner := metav1.OwnerReference{Kind: redisfailoverv1.RFKind, Name: "test", UID: rfUID}
+	claims := func(owned bool) *corev1.PersistentVolumeClaimList {
+		pvcs := &corev1.PersistentVolumeClaimList{}
+		for _, name := range []string{"redis-data-rfr-test-0", "redis-data-rfr-test-1", "other-data"} {
+			pvc := corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name}}
+			if owned {
+				pvc.OwnerReferences = []metav1.OwnerReference{owner}
+			}
+			pvcs.Items = append(pvcs.Items, pvc)
+		}
+		return pvcs
+	}
+
+	tests := []struct {
+		name       string
+		selector   string
+		policy     *redisfailoverv1.PVCRetentionPolicy
+		claims     *corev1.PersistentVolumeClaimList
+		expUpdated []string
+		expOwned   bool
+	}{
+		{
+			name: "Statefulset not created yet",
+		},
+		{
+			name:     "Claims already deleted with the Redis failover",
+			selector: selector,
+			claims:   claims(true),
+		},
+		{
+			name:       "Claims retained when deleted",
+			selector:   selector,
+			policy:     &redisfailoverv1.PVCRetentionPolicy{WhenDeleted: redisfailoverv1.RetainPVCRetentionPolicyType},
+			claims:     claims(true),
+			expUpdated: []string{"redis-data-rfr-test-0", "redis-data-rfr-test-1"},
+		},
+		{
+			name:       "Claims deleted with the Redis failover again",
+			selector:   selector,
+			policy:     &redisfailoverv1.PVCRetentionPolicy{WhenDeleted: redisfailoverv1.DeletePVCRetentionPolicyType},
+			claims:     claims(false),
+			expUpdated: []string{"redis-data-rfr-test-0", "redis-data-rfr-test-1"},
+			expOwned:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			rf.UID = rfUID
+			rf.Status.Selector = test.selector
+			rf.Spec.Redis.Storage.PVCRetentionPolicy = test.policy
+			rf.Spec.Redis.Storage.PersistentVolumeClaim = &redisfailoverv1.EmbeddedPersistentVolumeClaim{
+				EmbeddedObjectMetadata: redisfailoverv1.EmbeddedObjectMetadata{Name: "redis-data"},
+			}
+
+			mk := &mK8SService.Services{}
+			if test.claims != nil {
+				mk.On("ListPersistentVolumeClaims", namespace, metav1.ListOptions{LabelSelector: selector}).Once().Return(test.claims, nil)
+			}
+			updated := []*corev1.PersistentVolumeClaim{}
+			if len(test.expUpdated) != 0 {
+				mk.On("UpdatePersistentVolumeClaim", namespace, mock.Anything).Times(len(test.expUpdated)).Run(func(args mock.Arguments) {
+					updated = append(updated, args.Get(1).(*corev1.PersistentVolumeClaim))
+				}).Return(nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, &mRFService.RedisFailoverCheck{}, &mRFService.RedisFailoverHeal{}, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			assert.NoError(handler.EnsureClaimsRetention(rf))
+
+			if assert.Len(updated, len(test.expUpdated)) {
+				for i, pvc := range updated {
+					assert.Equal(test.expUpdated[i], pvc.Name)
+					if test.expOwned {
+						assert.Len(pvc.OwnerReferences, 1)
+						assert.Equal(rfUID, pvc.OwnerReferences[0].UID)
+					} else {
+						assert.Empty(pvc.OwnerReferences)
+					}
+				}
+			}
+			mk.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+// StartRestore starts the restore of the snapshot of spec.restore when the Redis failover is
+// created. Restoring into a Redis failover that already exists replaces its data, so it is
+// refused unless the overwrite flag is set. A snapshot is only restored once.
+func (r *RedisFailoverHandler) StartRestore(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	if rf.Spec.Restore == nil {
+		rf.RemoveCondition(redisfailoverv1.ConditionRestored)
+		return nil
+	}
+
+	condition := rf.GetCondition(redisfailoverv1.ConditionRestored)
+	if condition == nil {
+		// The cache may not have caught up with the start of the restore yet
+		current, err := r.k8sservice.GetRedisFailover(ctx, rf.Namespace, rf.Name)
+		if err != nil {
+			return err
+		}
+		if condition = current.GetCondition(redisfailoverv1.ConditionRestored); condition != nil {
+			rf.SetCondition(condition.Type, condition.Status, condition.Reason, condition.Message)
+		}
+	}
+	if condition != nil && (condition.Reason != redisfailoverv1.ReasonRestoreRefused || !rf.Spec.Restore.Overwrite) {
+		return nil
+	}
+
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+	_, err := r.k8sservice.GetStatefulSet(rf.Namespace, rfservice.GetRedisName(rf))
+	switch {
+	case k8serrors.IsNotFound(err):
+	case err != nil:
+		return err
+	case !rf.Spec.Restore.Overwrite:
+		logger.Warningf("Refusing to restore %s into the existing redis nodes", rf.Spec.Restore.Source())
+		rf.SetCondition(redisfailoverv1.ConditionRestored, metav1.ConditionFalse, redisfailoverv1.ReasonRestoreRefused, "the redisfailover already exists, set restore.overwrite to replace its data")
+		return nil
+	}
+
+	logger.Infof("Restoring %s", rf.Spec.Restore.Source())
+	rf.SetCondition(redisfailoverv1.ConditionRestored, metav1.ConditionFalse, redisfailoverv1.ReasonRestoreInProgress, "restoring "+rf.Spec.Restore.Source())
+	// Recorded before the redis nodes are changed, so the restore is resumed on the next loops
+	updateStatus(r.k8sservice, rf, rf.Status.State)
+	return nil
+}
+
+// CompleteRestore promotes the first redis pod once its data volume has been seeded with the
+// snapshot, the replicas are then started and synced from it. A first pod started before the
+// restore is deleted, so it is seeded too.
+func (r *RedisFailoverHandler) CompleteRestore(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	oldState := rf.Status.State
+	defer updateStatus(r.k8sservice, rf, oldState)
+
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+	podName := fmt.Sprintf("%s-0", rfservice.GetRedisName(rf))
+	pod, err := r.k8sservice.GetPod(rf.Namespace, podName)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if pod.DeletionTimestamp != nil {
+		return nil
+	}
+
+	if !hasInitContainer(pod, rfservice.RedisRestoreContainerName) {
+		logger.Infof("Deleting %s so it is seeded with the snapshot", podName)
+		return r.rfHealer.DeletePod(podName, rf)
+	}
+	// The pod only runs once the snapshot has been seeded
+	if pod.Status.Phase != corev1.PodRunning || rf.PodIP(pod) == "" {
+		return nil
+	}
+
+	if err := r.rfHealer.MakeMaster(ctx, rf.PodAddress(pod), rf); err != nil {
+		return err
+	}
+	// The append only file was off for the snapshot to be loaded, it is rewritten from the
+	// loaded data once turned on
+	if err := r.rfHealer.SetRedisCustomConfig(ctx, rf.PodAddress(pod), rf); err != nil {
+		return err
+	}
+	logger.Infof("Restore of %s completed, %s is the master", rf.Spec.Restore.Source(), podName)
+	rf.SetCondition(redisfailoverv1.ConditionRestored, metav1.ConditionTrue, redisfailoverv1.ReasonRestoreCompleted, fmt.Sprintf("%s restored into %s", rf.Spec.Restore.Source(), podName))
+	return nil
+}
+
+func hasInitContainer(pod *corev1.Pod, name string) bool {
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package redisfailover_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+	mRFService "github.com/saremox/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/saremox/redis-operator/operator/redisfailover"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+func TestStartRestore(t *testing.T) {
+	tests := []struct {
+		name            string
+		restore         bool
+		overwrite       bool
+		condition       string
+		cachedCondition bool
+		statefulSet     bool
+		expReason       string
+	}{
+		{
+			name: "No restore",
+		},
+		{
+			name:      "Restore into a new redisfailover",
+			restore:   true,
+			expReason: redisfailoverv1.ReasonRestoreInProgress,
+		},
+		{
+			name:        "Restore into an existing redisfailover refused",
+			restore:     true,
+			statefulSet: true,
+			expReason:   redisfailoverv1.ReasonRestoreRefused,
+		},
+		{
+			name:        "Restore into an existing redisfailover overwritten",
+			restore:     true,
+			overwrite:   true,
+			statefulSet: true,
+			expReason:   redisfailoverv1.ReasonRestoreInProgress,
+		},
+		{
+			name:            "Refused restore overwritten",
+			restore:         true,
+			overwrite:       true,
+			condition:       redisfailoverv1.ReasonRestoreRefused,
+			cachedCondition: true,
+			statefulSet:     true,
+			expReason:       redisfailoverv1.ReasonRestoreInProgress,
+		},
+		{
+			name:            "Restore in progress",
+			restore:         true,
+			condition:       redisfailoverv1.ReasonRestoreInProgress,
+			cachedCondition: true,
+			expReason:       redisfailoverv1.ReasonRestoreInProgress,
+		},
+		{
+			name:      "Restore in progress not in the cache yet",
+			restore:   true,
+			condition: redisfailoverv1.ReasonRestoreInProgress,
+			expReason: redisfailoverv1.ReasonRestoreInProgress,
+		},
+		{
+			name:            "Restore completed",
+			restore:         true,
+			overwrite:       true,
+			condition:       redisfailoverv1.ReasonRestoreCompleted,
+			cachedCondition: true,
+			expReason:       redisfailoverv1.ReasonRestoreCompleted,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			current := rf.DeepCopy()
+			if test.restore {
+				rf.Spec.Restore = &redisfailoverv1.RestoreSettings{BackupName: "test-202401310300", Overwrite: test.overwrite}
+			}
+			if test.condition != "" {
+				current.SetCondition(redisfailoverv1.ConditionRestored, metav1.ConditionFalse, test.condition, "")
+				if test.cachedCondition {
+					rf.SetCondition(redisfailoverv1.ConditionRestored, metav1.ConditionFalse, test.condition, "")
+				}
+			}
+
+			mk := &mK8SService.Services{}
+			if test.restore && !test.cachedCondition {
+				mk.On("GetRedisFailover", mock.Anything, namespace, name).Once().Return(current, nil)
+			}
+			if test.restore && (test.condition == "" || test.condition == redisfailoverv1.ReasonRestoreRefused) {
+				if test.statefulSet {
+					mk.On("GetStatefulSet", namespace, rfservice.GetRedisName(rf)).Once().Return(&appsv1.StatefulSet{}, nil)
+				} else {
+					mk.On("GetStatefulSet", namespace, rfservice.GetRedisName(rf)).Once().Return(nil, k8serrors.NewNotFound(schema.GroupResource{}, rfservice.GetRedisName(rf)))
+				}
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, &mRFService.RedisFailoverCheck{}, &mRFService.RedisFailoverHeal{}, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			assert.NoError(handler.StartRestore(context.TODO(), rf))
+
+			condition := rf.GetCondition(redisfailoverv1.ConditionRestored)
+			if test.expReason == "" {
+				assert.Nil(condition)
+			} else if assert.NotNil(condition) {
+				assert.Equal(test.expReason, condition.Reason)
+			}
+			assert.Equal(test.expReason == redisfailoverv1.ReasonRestoreInProgress, rf.Restoring())
+			mk.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCompleteRestore(t *testing.T) {
+	restoreContainers := []corev1.Container{{Name: rfservice.RedisRestoreContainerName}}
+
+	tests := []struct {
+		name          string
+		pod           *corev1.Pod
+		makeMasterErr error
+		expDeleted    bool
+		expPromoted   bool
+		expErr        bool
+	}{
+		{
+			name: "Pod not created yet",
+		},
+		{
+			name: "Pod started before the restore",
+			pod: &corev1.Pod{
+				Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "0.0.0.0"},
+			},
+			expDeleted: true,
+		},
+		{
+			name: "Snapshot being seeded",
+			pod: &corev1.Pod{
+				Spec:   corev1.PodSpec{InitContainers: restoreContainers},
+				Status: corev1.PodStatus{Phase: corev1.PodPending},
+			},
+		},
+		{
+			name: "Snapshot still loading",
+			pod: &corev1.Pod{
+				Spec:   corev1.PodSpec{InitContainers: restoreContainers},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "0.0.0.0"},
+			},
+			makeMasterErr: errors.New("LOADING Redis is loading the dataset in memory"),
+			expErr:        true,
+		},
+		{
+			name: "Restored pod promoted",
+			pod: &corev1.Pod{
+				Spec:   corev1.PodSpec{InitContainers: restoreContainers},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "0.0.0.0"},
+			},
+			expPromoted: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			rf.Spec.Restore = &redisfailoverv1.RestoreSettings{BackupName: "test-202401310300"}
+			rf.SetCondition(redisfailoverv1.ConditionRestored, metav1.ConditionFalse, redisfailoverv1.ReasonRestoreInProgress, "")
+			podName := rfservice.GetRedisName(rf) + "-0"
+
+			mk := &mK8SService.Services{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+			if test.pod != nil {
+				mk.On("GetPod", namespace, podName).Once().Return(test.pod, nil)
+			} else {
+				mk.On("GetPod", namespace, podName).Once().Return(nil, k8serrors.NewNotFound(schema.GroupResource{}, podName))
+			}
+			if test.expDeleted {
+				mrfh.On("DeletePod", podName, rf).Once().Return(nil)
+			}
+			if test.expPromoted || test.makeMasterErr != nil {
+				mrfh.On("MakeMaster", mock.Anything, "0.0.0.0", rf).Once().Return(test.makeMasterErr)
+			}
+			if test.expPromoted {
+				mrfh.On("SetRedisCustomConfig", mock.Anything, "0.0.0.0", rf).Once().Return(nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, &mRFService.RedisFailoverCheck{}, mrfh, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			err := handler.CompleteRestore(context.TODO(), rf)
+
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(!test.expPromoted, rf.Restoring())
+			if test.expPromoted {
+				assert.True(rf.IsConditionTrue(redisfailoverv1.ConditionRestored))
+				assert.Equal(redisfailoverv1.ReasonRestoreCompleted, rf.GetCondition(redisfailoverv1.ConditionRestored).Reason)
+			}
+			mk.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+// ScaleDown reports the redis pods to the scale subresource and makes the redis statefulset
+// safe to shrink to spec.redis.replicas: the master is switched over to one of the remaining
+// pods before the statefulset is updated. Once the removed pods are gone, the sentinels are
+// reset one at a time so they forget them, and their persistent volume claims are deleted when
+// the pvcRetentionPolicy requests it.
+// The progress is reported on the ScaleDown condition.
+func (r *RedisFailoverHandler) ScaleDown(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	ss, err := r.k8sservice.GetStatefulSet(rf.Namespace, rfservice.GetRedisName(rf))
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var selector map[string]string
+	if ss.Spec.Selector != nil {
+		selector = ss.Spec.Selector.MatchLabels
+	}
+	rf.Status.CurrentReplicas = ss.Status.Replicas
+	rf.Status.Selector = labels.FormatLabels(selector)
+	// The statefulset is shrunk to a single pod while restoring
+	if rf.Restoring() {
+		return nil
+	}
+
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+	replicas := rf.Spec.Redis.Replicas
+	if ss.Spec.Replicas != nil && *ss.Spec.Replicas > replicas {
+		if err := r.switchoverRemovedMaster(ctx, rf); err != nil {
+			logger.Errorf("Unable to scale down the redis nodes: %s", err.Error())
+			rf.SetCondition(redisfailoverv1.ConditionScaleDown, metav1.ConditionFalse, redisfailoverv1.ReasonScaleDownFailed, err.Error())
+			return err
+		}
+		logger.Infof("Scaling down the redis nodes from %d to %d", *ss.Spec.Replicas, replicas)
+		rf.SetCondition(redisfailoverv1.ConditionScaleDown, metav1.ConditionFalse, redisfailoverv1.ReasonScalingDown, fmt.Sprintf("scaling down from %d to %d redis nodes", *ss.Spec.Replicas, replicas))
+		return nil
+	}
+
+	// The removed pods are still terminating
+	if ss.Status.Replicas > replicas {
+		return nil
+	}
+	// Also done outside of a scale down, for the claims left by the ones before the policy was set
+	if rf.DeleteClaimsWhenScaled() {
+		if err := r.deleteRemovedClaims(rf, selector); err != nil {
+			return err
+		}
+	}
+
+	if !scalingDown(rf) {
+		return nil
+	}
+
+	if rf.SentinelEnabled() {
+		if done, err := r.resetSentinel(ctx, rf); err != nil || !done {
+			return err
+		}
+	}
+	logger.Infof("Scale down to %d redis nodes completed", replicas)
+	rf.SetCondition(redisfailoverv1.ConditionScaleDown, metav1.ConditionTrue, redisfailoverv1.ReasonScaleDownCompleted, fmt.Sprintf("scaled down to %d redis nodes", replicas))
+	return nil
+}
+
+// resetSentinel resets the first sentinel still knowing of the removed redis pods, a single one
+// per reconcile so the others keep the quorum. The next one is only reset once the sentinel reset
+// before reports the remaining replicas again. It returns true once every sentinel does.
+func (r *RedisFailoverHandler) resetSentinel(ctx context.Context, rf *redisfailoverv1.RedisFailover) (bool, error) {
+	sentinels, err := r.rfChecker.GetSentinelsIPs(rf)
+	if err != nil {
+		return false, err
+	}
+	expected := rf.Spec.Redis.Replicas - 1
+	if rf.Bootstrapping() {
+		expected = rf.Spec.Redis.Replicas
+	}
+	for _, sip := range sentinels {
+		replicas, err := r.rfChecker.GetSentinelSlavesNumberInMemory(ctx, sip, rf)
+		if err != nil {
+			return false, err
+		}
+		if replicas == expected {
+			continue
+		}
+		// A sentinel knowing of fewer replicas was reset and is discovering them from the master
+		if replicas > expected {
+			if err := r.rfHealer.RestoreSentinel(ctx, sip, rf); err != nil {
+				return false, err
+			}
+			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Sentinel %s reset to forget the removed redis nodes", sip)
+		}
+		rf.SetCondition(redisfailoverv1.ConditionScaleDown, metav1.ConditionFalse, redisfailoverv1.ReasonScalingDown, fmt.Sprintf("waiting for sentinel %s to report %d replicas", sip, expected))
+		return false, nil
+	}
+	return true, nil
+}
+
+// scalingDown returns true while a scale down of the redis nodes is not completed
+func scalingDown(rf *redisfailoverv1.RedisFailover) bool {
+	condition := rf.GetCondition(redisfailoverv1.ConditionScaleDown)
+	return condition != nil && condition.Status != metav1.ConditionTrue
+}
+
+// switchoverRemovedMaster switches the master over to the most up to date replica kept by the
+// scale down, when it is one of the removed pods
+func (r *RedisFailoverHandler) switchoverRemovedMaster(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	// The master is outside of the Redis failover while bootstrapping
+	if rf.Bootstrapping() {
+		return nil
+	}
+	masterPod, err := r.rfChecker.GetRedisesMasterPod(ctx, rf)
+	if err != nil {
+		// No master to keep, it is elected among the remaining pods once they are healed
+		return nil
+	}
+	if ordinal, ok := podOrdinal(masterPod); !ok || ordinal < int(rf.Spec.Redis.Replicas) {
+		return nil
+	}
+
+	if err := r.switchoverMaster(ctx, rf, masterPod, func(podName string) bool {
+		ordinal, ok := podOrdinal(podName)
+		return ok && ordinal < int(rf.Spec.Redis.Replicas)
+	}); err != nil {
+		return err
+	}
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Master switched over from %s before the scale down", masterPod)
+	return nil
+}
+
+// switchoverMaster switches the master pod over to the most up to date replica in sync among the
+// eligible ones
+func (r *RedisFailoverHandler) switchoverMaster(ctx context.Context, rf *redisfailoverv1.RedisFailover, masterPod string, eligible func(podName string) bool) error {
+	replicas, err := r.rfChecker.GetReplicaReplicationOffsets(ctx, rf)
+	if err != nil {
+		return err
+	}
+	var replica *rfservice.ReplicaInfo
+	for i := range replicas {
+		if !eligible(replicas[i].PodName) || !replicas[i].IsReady {
+			continue
+		}
+		if replica == nil || replicas[i].ReplicationOffset > replica.ReplicationOffset {
+			replica = &replicas[i]
+		}
+	}
+	if replica == nil {
+		return fmt.Errorf("no replica in sync among the remaining redis nodes to switch the master %s over to", masterPod)
+	}
+
+	masterIP, err := r.rfChecker.GetMasterIP(ctx, rf)
+	if err != nil {
+		return err
+	}
+	if err := r.promote(ctx, rf, masterIP, replica); err != nil {
+		return err
+	}
+	newMasterIP, err := r.rfChecker.GetMasterIP(ctx, rf)
+	if err != nil {
+		return err
+	}
+	if newMasterIP != replica.IP {
+		return fmt.Errorf("replica %s is not serving as master after the switchover", replica.PodName)
+	}
+	return nil
+}
+
+// deleteRemovedClaims deletes the persistent volume claims of the redis pods beyond
+// spec.redis.replicas. The claim of a pod that still exists is never deleted.
+func (r *RedisFailoverHandler) deleteRemovedClaims(rf *redisfailoverv1.RedisFailover, selector map[string]string) error {
+	claims, err := r.listRedisClaims(rf, labels.FormatLabels(selector))
+	if err != nil {
+		return err
+	}
+
+	redisName := rfservice.GetRedisName(rf)
+	for _, claim := range claims {
+		if claim.ordinal < int(rf.Spec.Redis.Replicas) {
+			continue
+		}
+		_, err = r.k8sservice.GetPod(rf.Namespace, fmt.Sprintf("%s-%d", redisName, claim.ordinal))
+		if err == nil {
+			continue
+		}
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		if err := r.k8sservice.DeletePersistentVolumeClaim(rf.Namespace, claim.Name); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Persistent volume claim %s of a removed redis pod deleted", claim.Name)
+	}
+	return nil
+}
+
+// podOrdinal returns the ordinal of a statefulset pod
+func podOrdinal(podName string) (int, bool) {
+	i := strings.LastIndex(podName, "-")
+	if i < 0 {
+		return 0, false
+	}
+	ordinal, err := strconv.Atoi(podName[i+1:])
+	return ordinal, err == nil
+}
@@ -0,0 +1,251 @@
+package redisfailover_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+	mRFService "github.com/saremox/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/saremox/redis-operator/operator/redisfailover"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+func TestScaleDown(t *testing.T) {
+	selector := map[string]string{"app.kubernetes.io/component": "redis", "app.kubernetes.io/name": "test"}
+	statefulSet := func(specReplicas, statusReplicas int32) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: &specReplicas,
+				Selector: &metav1.LabelSelector{MatchLabels: selector},
+			},
+			Status: appsv1.StatefulSetStatus{Replicas: statusReplicas},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		statefulSet     *appsv1.StatefulSet
+		replicas        int32
+		sentinel        bool
+		sentinelSlaves  []int32
+		expReset        string
+		retentionPolicy *redisfailoverv1.PVCRetentionPolicy
+		condition       string
+		masterPod       string
+		replicaInfos    []rfservice.ReplicaInfo
+		expSwitchoverTo string
+		expReason       string
+		expErr          bool
+	}{
+		{
+			name:     "Statefulset not created yet",
+			replicas: 3,
+		},
+		{
+			name:        "No scale down",
+			statefulSet: statefulSet(3, 3),
+			replicas:    3,
+		},
+		{
+			name:        "Scale down keeping the master",
+			statefulSet: statefulSet(3, 3),
+			replicas:    2,
+			masterPod:   "rfr-test-0",
+			expReason:   redisfailoverv1.ReasonScalingDown,
+		},
+		{
+			name:        "Scale down switching the master over",
+			statefulSet: statefulSet(3, 3),
+			replicas:    2,
+			masterPod:   "rfr-test-2",
+			replicaInfos: []rfservice.ReplicaInfo{
+				{PodName: "rfr-test-0", IP: "0.0.0.0", ReplicationOffset: 100, IsReady: true},
+				{PodName: "rfr-test-1", IP: "0.0.0.1", ReplicationOffset: 200, IsReady: true},
+			},
+			expSwitchoverTo: "0.0.0.1",
+			expReason:       redisfailoverv1.ReasonScalingDown,
+		},
+		{
+			name:        "Scale down without replica in sync to switch over to",
+			statefulSet: statefulSet(3, 3),
+			replicas:    1,
+			masterPod:   "rfr-test-2",
+			replicaInfos: []rfservice.ReplicaInfo{
+				{PodName: "rfr-test-0", IP: "0.0.0.0", ReplicationOffset: 100, IsReady: false},
+				{PodName: "rfr-test-1", IP: "0.0.0.1", ReplicationOffset: 200, IsReady: true},
+			},
+			expReason: redisfailoverv1.ReasonScaleDownFailed,
+			expErr:    true,
+		},
+		{
+			name:        "Removed pods terminating",
+			statefulSet: statefulSet(2, 3),
+			replicas:    2,
+			condition:   redisfailoverv1.ReasonScalingDown,
+			expReason:   redisfailoverv1.ReasonScalingDown,
+		},
+		{
+			name:        "Scale down completed",
+			statefulSet: statefulSet(2, 2),
+			replicas:    2,
+			condition:   redisfailoverv1.ReasonScalingDown,
+			expReason:   redisfailoverv1.ReasonScaleDownCompleted,
+		},
+		{
+			name:           "First sentinel reset",
+			statefulSet:    statefulSet(2, 2),
+			replicas:       2,
+			sentinel:       true,
+			sentinelSlaves: []int32{2, 2},
+			condition:      redisfailoverv1.ReasonScalingDown,
+			expReset:       "1.1.1.1",
+			expReason:      redisfailoverv1.ReasonScalingDown,
+		},
+		{
+			name:           "Reset sentinel discovering the replicas",
+			statefulSet:    statefulSet(2, 2),
+			replicas:       2,
+			sentinel:       true,
+			sentinelSlaves: []int32{0, 2},
+			condition:      redisfailoverv1.ReasonScalingDown,
+			expReason:      redisfailoverv1.ReasonScalingDown,
+		},
+		{
+			name:           "Next sentinel reset",
+			statefulSet:    statefulSet(2, 2),
+			replicas:       2,
+			sentinel:       true,
+			sentinelSlaves: []int32{1, 2},
+			condition:      redisfailoverv1.ReasonScalingDown,
+			expReset:       "1.1.1.2",
+			expReason:      redisfailoverv1.ReasonScalingDown,
+		},
+		{
+			name:           "Scale down completed, sentinels reset",
+			statefulSet:    statefulSet(2, 2),
+			replicas:       2,
+			sentinel:       true,
+			sentinelSlaves: []int32{1, 1},
+			condition:      redisfailoverv1.ReasonScalingDown,
+			expReason:      redisfailoverv1.ReasonScaleDownCompleted,
+		},
+		{
+			name:            "Scale down completed, claims deleted",
+			statefulSet:     statefulSet(2, 2),
+			replicas:        2,
+			retentionPolicy: &redisfailoverv1.PVCRetentionPolicy{WhenScaled: redisfailoverv1.DeletePVCRetentionPolicyType},
+			condition:       redisfailoverv1.ReasonScalingDown,
+			expReason:       redisfailoverv1.ReasonScaleDownCompleted,
+		},
+		{
+			name:            "Claims of an earlier scale down deleted",
+			statefulSet:     statefulSet(2, 2),
+			replicas:        2,
+			retentionPolicy: &redisfailoverv1.PVCRetentionPolicy{WhenScaled: redisfailoverv1.DeletePVCRetentionPolicyType},
+		},
+		{
+			name:            "Claims retained",
+			statefulSet:     statefulSet(2, 2),
+			replicas:        2,
+			retentionPolicy: &redisfailoverv1.PVCRetentionPolicy{WhenScaled: redisfailoverv1.RetainPVCRetentionPolicyType},
+			condition:       redisfailoverv1.ReasonScalingDown,
+			expReason:       redisfailoverv1.ReasonScaleDownCompleted,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			rf.Spec.Sentinel.Enabled = &test.sentinel
+			rf.Spec.Redis.Replicas = test.replicas
+			rf.Spec.Redis.Storage.PVCRetentionPolicy = test.retentionPolicy
+			rf.Spec.Redis.Storage.PersistentVolumeClaim = &redisfailoverv1.EmbeddedPersistentVolumeClaim{
+				EmbeddedObjectMetadata: redisfailoverv1.EmbeddedObjectMetadata{Name: "redis-data"},
+			}
+			if test.condition != "" {
+				rf.SetCondition(redisfailoverv1.ConditionScaleDown, metav1.ConditionFalse, test.condition, "")
+			}
+
+			mk := &mK8SService.Services{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+			if test.statefulSet != nil {
+				mk.On("GetStatefulSet", namespace, "rfr-test").Once().Return(test.statefulSet, nil)
+			} else {
+				mk.On("GetStatefulSet", namespace, "rfr-test").Once().Return(nil, k8serrors.NewNotFound(schema.GroupResource{}, "rfr-test"))
+			}
+			if test.masterPod != "" {
+				mrfc.On("GetRedisesMasterPod", mock.Anything, rf).Once().Return(test.masterPod, nil)
+			}
+			if test.replicaInfos != nil {
+				mrfc.On("GetReplicaReplicationOffsets", mock.Anything, rf).Once().Return(test.replicaInfos, nil)
+			}
+			if test.expSwitchoverTo != "" {
+				mrfc.On("GetMasterIP", mock.Anything, rf).Once().Return("0.0.0.2", nil)
+				mrfh.On("Switchover", mock.Anything, "0.0.0.2", test.expSwitchoverTo, rf).Once().Return(nil)
+				mrfc.On("GetMasterIP", mock.Anything, rf).Once().Return(test.expSwitchoverTo, nil)
+			}
+			if test.sentinelSlaves != nil {
+				sentinels := []string{"1.1.1.1", "1.1.1.2"}
+				mrfc.On("GetSentinelsIPs", rf).Once().Return(sentinels, nil)
+				// The sentinels are checked up to the first one not reporting the remaining replica
+				for i, slaves := range test.sentinelSlaves {
+					mrfc.On("GetSentinelSlavesNumberInMemory", mock.Anything, sentinels[i], rf).Once().Return(slaves, nil)
+					if slaves != 1 {
+						break
+					}
+				}
+			}
+			if test.expReset != "" {
+				mrfh.On("RestoreSentinel", mock.Anything, test.expReset, rf).Once().Return(nil)
+			}
+			if rf.DeleteClaimsWhenScaled() {
+				pvcs := &corev1.PersistentVolumeClaimList{}
+				for _, name := range []string{"redis-data-rfr-test-0", "redis-data-rfr-test-1", "redis-data-rfr-test-2", "redis-data-rfr-test-3"} {
+					pvcs.Items = append(pvcs.Items, corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name}})
+				}
+				mk.On("ListPersistentVolumeClaims", namespace, metav1.ListOptions{LabelSelector: "app.kubernetes.io/component=redis,app.kubernetes.io/name=test"}).Once().Return(pvcs, nil)
+				mk.On("GetPod", namespace, "rfr-test-2").Once().Return(nil, k8serrors.NewNotFound(schema.GroupResource{}, "rfr-test-2"))
+				// A claim is never deleted while its pod exists
+				mk.On("GetPod", namespace, "rfr-test-3").Once().Return(&corev1.Pod{}, nil)
+				mk.On("DeletePersistentVolumeClaim", namespace, "redis-data-rfr-test-2").Once().Return(nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, mrfc, mrfh, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			err := handler.ScaleDown(context.TODO(), rf)
+
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			condition := rf.GetCondition(redisfailoverv1.ConditionScaleDown)
+			if test.expReason == "" {
+				assert.Nil(condition)
+			} else if assert.NotNil(condition) {
+				assert.Equal(test.expReason, condition.Reason)
+				assert.Equal(test.expReason == redisfailoverv1.ReasonScaleDownCompleted, condition.Status == metav1.ConditionTrue)
+			}
+			if test.statefulSet != nil {
+				assert.Equal(test.statefulSet.Status.Replicas, rf.Status.CurrentReplicas)
+				assert.Equal("app.kubernetes.io/component=redis,app.kubernetes.io/name=test", rf.Status.Selector)
+			}
+			mk.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
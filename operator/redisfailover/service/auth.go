@@ -0,0 +1,242 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/service/k8s"
+)
+
+// generatePassword returns a random password
+func generatePassword() (string, error) {
+	b := make([]byte, generatedPasswordBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// RedisPasswordState is the password of the default user applied on the redis nodes, along with
+// the one of the auth secret it has to be rotated to. While a rotation is in progress the nodes
+// keep accepting the previous password, until the grace period since RotatedAt has elapsed.
+type RedisPasswordState struct {
+	Applied   string
+	Desired   string
+	Previous  string
+	Rotating  bool
+	RotatedAt time.Time
+}
+
+// getRedisPasswordState reads the applied password from the operator secret, which is nil if
+// it does not exist yet. The desired password is applied when none was recorded.
+func getRedisPasswordState(rf *redisfailoverv1.RedisFailover, authSecret *corev1.Secret, desired string) *RedisPasswordState {
+	state := &RedisPasswordState{Applied: desired, Desired: desired}
+	if rf.Spec.Auth.SecretPath == "" || authSecret == nil {
+		return state
+	}
+	if applied, ok := authSecret.Data[passwordKey]; ok {
+		state.Applied = string(applied)
+	}
+	if previous, ok := authSecret.Data[previousPasswordKey]; ok {
+		state.Previous = string(previous)
+		state.Rotating = true
+		state.RotatedAt, _ = time.Parse(time.RFC3339, authSecret.Annotations[passwordRotatedAtAnnotationKey])
+	}
+	return state
+}
+
+// getAppliedRedisPassword returns the password of the default user accepted by every redis node
+func getAppliedRedisPassword(k8sService k8s.Services, rf *redisfailoverv1.RedisFailover) (string, error) {
+	if rf.Spec.Auth.SecretPath == "" {
+		return "", nil
+	}
+	authSecret, err := k8sService.GetSecret(rf.Namespace, GetRedisAuthSecretName(rf))
+	if err != nil && !errors.IsNotFound(err) {
+		return "", err
+	}
+	if authSecret != nil {
+		if applied, ok := authSecret.Data[passwordKey]; ok {
+			return string(applied), nil
+		}
+	}
+	return k8s.GetRedisPassword(k8sService, rf)
+}
+
+// generateRedisAuthSecret returns the secret holding the passwords generated by the operator,
+// keeping the passwords of the current secret. The password of the auth secret is recorded as
+// the applied one when there is none yet.
+func generateRedisAuthSecret(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, current *corev1.Secret, password string) (*corev1.Secret, error) {
+	data := map[string][]byte{}
+	annotations := map[string]string{}
+	if current != nil {
+		for k, v := range current.Data {
+			data[k] = v
+		}
+		for k, v := range current.Annotations {
+			annotations[k] = v
+		}
+	}
+
+	for _, key := range []string{operatorPasswordKey, probePasswordKey} {
+		if len(data[key]) > 0 {
+			continue
+		}
+		generated, err := generatePassword()
+		if err != nil {
+			return nil, err
+		}
+		data[key] = []byte(generated)
+	}
+
+	if _, ok := data[passwordKey]; !ok && rf.Spec.Auth.SecretPath != "" {
+		data[passwordKey] = []byte(password)
+	}
+	if rf.Spec.Auth.SecretPath == "" {
+		// Without auth secret there is nothing to rotate
+		delete(data, passwordKey)
+		delete(data, previousPasswordKey)
+		delete(annotations, passwordRotatedAtAnnotationKey)
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            GetRedisAuthSecretName(rf),
+			Namespace:       rf.Namespace,
+			Labels:          labels,
+			Annotations:     annotations,
+			OwnerReferences: ownerRefs,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}, nil
+}
+
+// generateRedisAuthConfig returns the redis configuration holding the users and the password of
+// the default user, applied on the redis nodes when they start. While a rotation is in progress the
+// default user accepts the previous password as well, so the nodes restarted during the grace
+// period keep accepting the clients not updated yet.
+func generateRedisAuthConfig(password, previous string, users map[string][]string) string {
+	names := make([]string, 0, len(users))
+	for name := range users {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var config strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&config, "user %s %s\n", name, strings.Join(users[name], " "))
+	}
+	switch {
+	case password == "":
+	case previous != "":
+		fmt.Fprintf(&config, "user default on %s %s ~* &* +@all\nmasterauth %s\n", getACLPasswordRule(password), getACLPasswordRule(previous), password)
+	default:
+		fmt.Fprintf(&config, "masterauth %s\nrequirepass %s\n", password, password)
+	}
+	return config.String()
+}
+
+// isRedisAuthSecretUpToDate returns true if the current secret already holds the data of the
+// generated one
+func isRedisAuthSecretUpToDate(current, secret *corev1.Secret) bool {
+	return current != nil &&
+		maps.Equal(current.Annotations, secret.Annotations) &&
+		maps.EqualFunc(current.Data, secret.Data, bytes.Equal)
+}
+
+// setRedisPasswordState records the applied password on the operator secret, along with the
+// previous one and the time it was rotated at while the rotation is in progress
+func setRedisPasswordState(authSecret *corev1.Secret, state *RedisPasswordState) *corev1.Secret {
+	secret := authSecret.DeepCopy()
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Data[passwordKey] = []byte(state.Applied)
+	if state.Rotating {
+		secret.Data[previousPasswordKey] = []byte(state.Previous)
+		secret.Annotations[passwordRotatedAtAnnotationKey] = state.RotatedAt.UTC().Format(time.RFC3339)
+	} else {
+		delete(secret.Data, previousPasswordKey)
+		delete(secret.Annotations, passwordRotatedAtAnnotationKey)
+	}
+	return secret
+}
+
+// getACLPasswordRule returns the ACL rule setting the password by its hash, so the password
+// itself is not part of the configuration
+func getACLPasswordRule(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return "#" + hex.EncodeToString(sum[:])
+}
+
+// getACLPasswordRemovalRule returns the ACL rule removing the password by its hash
+func getACLPasswordRemovalRule(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return "!" + hex.EncodeToString(sum[:])
+}
+
+// getDefaultUserPasswordRules returns the ACL rules making the default user accept the password
+// along with the ones it already has
+func getDefaultUserPasswordRules(password string) []string {
+	if password == "" {
+		return []string{"nopass"}
+	}
+	return []string{getACLPasswordRule(password)}
+}
+
+func getProbeUserRules(authSecret *corev1.Secret) []string {
+	return []string{"on", getACLPasswordRule(string(authSecret.Data[probePasswordKey])), "-@all", "+ping"}
+}
+
+func getOperatorUserRules(authSecret *corev1.Secret) []string {
+	return []string{"on", getACLPasswordRule(string(authSecret.Data[operatorPasswordKey])), "~*", "&*", "+@all"}
+}
+
+func getUserRules(user redisfailoverv1.RedisUser, password string) []string {
+	rules := []string{"on", getACLPasswordRule(password)}
+	for _, key := range user.Keys {
+		rules = append(rules, "~"+key)
+	}
+	for _, channel := range user.Channels {
+		rules = append(rules, "&"+channel)
+	}
+	return append(rules, user.Commands...)
+}
+
+// getACLUsers returns the ACL rules by user name of the declared users and of the operator user,
+// or nothing when the ACL users are not managed
+func getACLUsers(k8sService k8s.Services, rf *redisfailoverv1.RedisFailover, authSecret *corev1.Secret) (map[string][]string, error) {
+	users := map[string][]string{}
+	if !rf.ACLEnabled() {
+		return users, nil
+	}
+
+	users[redisfailoverv1.OperatorUserName] = getOperatorUserRules(authSecret)
+	for _, user := range rf.Spec.Auth.Users {
+		secret, err := k8sService.GetSecret(rf.Namespace, user.SecretPath)
+		if err != nil {
+			return nil, err
+		}
+		password, ok := secret.Data["password"]
+		if !ok {
+			return nil, fmt.Errorf("secret \"%s\" does not have a password field", user.SecretPath)
+		}
+		users[user.Name] = getUserRules(user, string(password))
+	}
+	return users, nil
+}
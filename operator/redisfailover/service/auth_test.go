@@ -0,0 +1,309 @@
+package service_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+const authSecretName = "rfauth-test"
+
+func passwordHash(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return "#" + hex.EncodeToString(sum[:])
+}
+
+func TestEnsureRedisAuthSecret(t *testing.T) {
+	probeUser := "user pinger on " + passwordHash("probe") + " -@all +ping\n"
+	tests := []struct {
+		name          string
+		auth          string
+		current       *corev1.Secret
+		expWrite      bool
+		expApplied    []byte
+		expAuthConfig string
+	}{
+		{
+			name:     "Generates the passwords",
+			expWrite: true,
+		},
+		{
+			name: "Generates the missing passwords",
+			current: &corev1.Secret{
+				Data: map[string][]byte{"operator-password": []byte("operator")},
+			},
+			expWrite: true,
+		},
+		{
+			name: "Keeps the existing passwords",
+			current: &corev1.Secret{
+				Data: map[string][]byte{"operator-password": []byte("operator"), "probe-password": []byte("probe"), "auth.conf": []byte(probeUser)},
+			},
+		},
+		{
+			name: "Writes the credentials of existing clusters to the secret",
+			current: &corev1.Secret{
+				Data: map[string][]byte{"operator-password": []byte("operator"), "probe-password": []byte("probe")},
+			},
+			expWrite:      true,
+			expAuthConfig: probeUser,
+		},
+		{
+			name: "Records the password of the auth secret as applied",
+			auth: "redis-auth",
+			current: &corev1.Secret{
+				Data: map[string][]byte{"operator-password": []byte("operator"), "probe-password": []byte("probe"), "auth.conf": []byte(probeUser)},
+			},
+			expWrite:      true,
+			expApplied:    []byte("new"),
+			expAuthConfig: probeUser + "masterauth new\nrequirepass new\n",
+		},
+		{
+			name: "Keeps the applied password",
+			auth: "redis-auth",
+			current: &corev1.Secret{
+				Data: map[string][]byte{"operator-password": []byte("operator"), "probe-password": []byte("probe"), "password": []byte("old"), "auth.conf": []byte(probeUser + "masterauth old\nrequirepass old\n")},
+			},
+		},
+		{
+			name: "Forgets the applied password once auth is disabled",
+			current: &corev1.Secret{
+				Data: map[string][]byte{"operator-password": []byte("operator"), "probe-password": []byte("probe"), "password": []byte("old"), "previous-password": []byte("older"), "auth.conf": []byte(probeUser + "masterauth old\nrequirepass old\n")},
+			},
+			expWrite:      true,
+			expAuthConfig: probeUser,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRF()
+			rf.Spec.Auth.SecretPath = test.auth
+
+			var written *corev1.Secret
+			ms := &mK8SService.Services{}
+			if test.auth != "" {
+				ms.On("GetSecret", namespace, test.auth).Return(&corev1.Secret{
+					Data: map[string][]byte{"password": []byte("new")},
+				}, nil)
+			}
+			if test.current != nil {
+				ms.On("GetSecret", namespace, authSecretName).Once().Return(test.current, nil)
+			} else {
+				ms.On("GetSecret", namespace, authSecretName).Once().Return(nil, kubeerrors.NewNotFound(schema.GroupResource{}, authSecretName))
+			}
+			if test.expWrite {
+				ms.On("CreateOrUpdateSecret", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+					written = args.Get(1).(*corev1.Secret)
+				}).Return(nil)
+			}
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+			require.NoError(t, client.EnsureRedisAuthSecret(rf, map[string]string{"app": "redis"}, []metav1.OwnerReference{{Name: name}}))
+
+			ms.AssertExpectations(t)
+			if !test.expWrite {
+				return
+			}
+			assert.Equal(authSecretName, written.Name)
+			assert.Equal(map[string]string{"app": "redis"}, written.Labels)
+			assert.Equal([]metav1.OwnerReference{{Name: name}}, written.OwnerReferences)
+			assert.NotEmpty(written.Data["operator-password"])
+			assert.NotEmpty(written.Data["probe-password"])
+			if test.current != nil {
+				assert.Equal(test.current.Data["operator-password"], written.Data["operator-password"])
+			}
+			assert.Equal(test.expApplied, written.Data["password"])
+			if test.expAuthConfig != "" {
+				assert.Equal(test.expAuthConfig, string(written.Data["auth.conf"]))
+			}
+			if test.auth == "" {
+				assert.NotContains(written.Data, "previous-password")
+			}
+		})
+	}
+}
+
+func TestRedisAuthConfigUsers(t *testing.T) {
+	tests := []struct {
+		name     string
+		users    []redisfailoverv1.RedisUser
+		expUsers []string
+	}{
+		{
+			name: "Only the probe user without declared users",
+			expUsers: []string{
+				"user pinger on " + passwordHash("probe") + " -@all +ping",
+			},
+		},
+		{
+			name: "Declared users along with the operator user",
+			users: []redisfailoverv1.RedisUser{
+				{Name: "app", SecretPath: "app-secret", Commands: []string{"+@read", "-keys"}, Keys: []string{"app:*"}, Channels: []string{"events"}},
+			},
+			expUsers: []string{
+				"user app on " + passwordHash("app") + " ~app:* &events +@read -keys",
+				"user pinger on " + passwordHash("probe") + " -@all +ping",
+				"user redis-operator on " + passwordHash("operator") + " ~* &* +@all",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRF()
+			rf.Spec.Auth.Users = test.users
+
+			var config string
+			ms := &mK8SService.Services{}
+			ms.On("GetSecret", namespace, authSecretName).Return(&corev1.Secret{
+				Data: map[string][]byte{"operator-password": []byte("operator"), "probe-password": []byte("probe")},
+			}, nil)
+			ms.On("GetSecret", namespace, "app-secret").Return(&corev1.Secret{
+				Data: map[string][]byte{"password": []byte("app")},
+			}, nil)
+			ms.On("CreateOrUpdateSecret", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				config = string(args.Get(1).(*corev1.Secret).Data["auth.conf"])
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+			require.NoError(t, client.EnsureRedisAuthSecret(rf, nil, []metav1.OwnerReference{}))
+
+			users := []string{}
+			for _, line := range strings.Split(config, "\n") {
+				if strings.HasPrefix(line, "user ") {
+					users = append(users, line)
+				}
+			}
+			assert.Equal(test.expUsers, users)
+			// Only the password hashes are written to the configuration
+			assert.NotContains(config, ">")
+		})
+	}
+}
+
+func TestConfigMapsWithoutPasswords(t *testing.T) {
+	assert := assert.New(t)
+	rf := generateRF()
+	rf.Spec.Auth.SecretPath = "redis-auth"
+	rf.Spec.Auth.Users = []redisfailoverv1.RedisUser{{Name: "app", SecretPath: "app-secret", Commands: []string{"+@all"}}}
+
+	passwords := []string{"redis-password", "operator-password", "probe-password", "app-password"}
+	authSecret := &corev1.Secret{
+		Data: map[string][]byte{"operator-password": []byte("operator-password"), "probe-password": []byte("probe-password")},
+	}
+
+	configMaps := map[string]*corev1.ConfigMap{}
+	var written *corev1.Secret
+	ms := &mK8SService.Services{}
+	ms.On("GetSecret", namespace, authSecretName).Return(authSecret, nil)
+	ms.On("GetSecret", namespace, "redis-auth").Return(&corev1.Secret{
+		Data: map[string][]byte{"password": []byte("redis-password")},
+	}, nil)
+	ms.On("GetSecret", namespace, "app-secret").Return(&corev1.Secret{
+		Data: map[string][]byte{"password": []byte("app-password")},
+	}, nil)
+	ms.On("CreateOrUpdateSecret", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		written = args.Get(1).(*corev1.Secret)
+	}).Return(nil)
+	ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Run(func(args mock.Arguments) {
+		cm := args.Get(1).(*corev1.ConfigMap)
+		configMaps[cm.Name] = cm
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+	require.NoError(t, client.EnsureRedisAuthSecret(rf, nil, []metav1.OwnerReference{}))
+	require.NoError(t, client.EnsureSentinelConfigMap(rf, nil, []metav1.OwnerReference{}))
+	require.NoError(t, client.EnsureRedisConfigMap(rf, nil, []metav1.OwnerReference{}))
+	require.NoError(t, client.EnsureRedisShutdownConfigMap(rf, nil, []metav1.OwnerReference{}))
+	require.NoError(t, client.EnsureRedisReadinessConfigMap(rf, nil, []metav1.OwnerReference{}))
+
+	require.Len(t, configMaps, 4)
+	for name, cm := range configMaps {
+		for key, data := range cm.Data {
+			for _, password := range passwords {
+				assert.NotContains(data, password, "%s/%s", name, key)
+				assert.NotContains(data, passwordHash(password)[1:], "%s/%s", name, key)
+			}
+		}
+	}
+
+	// The credentials are included from the operator secret instead
+	assert.Contains(configMaps[rfservice.GetRedisName(rf)].Data["redis.conf"], "include /redis-auth/auth.conf")
+	authConfig := string(written.Data["auth.conf"])
+	assert.Contains(authConfig, "masterauth redis-password\nrequirepass redis-password\n")
+	assert.Contains(authConfig, "user app on "+passwordHash("app-password")+" +@all\n")
+}
+
+func TestRedisPasswordState(t *testing.T) {
+	assert := assert.New(t)
+	rf := generateRF()
+	rf.Spec.Auth.SecretPath = "redis-auth"
+	rotatedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	authSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: authSecretName, Namespace: namespace},
+		Data:       map[string][]byte{"operator-password": []byte("operator"), "probe-password": []byte("probe"), "password": []byte("old")},
+	}
+	ms := &mK8SService.Services{}
+	ms.On("GetSecret", namespace, authSecretName).Return(authSecret, nil)
+	ms.On("GetSecret", namespace, "redis-auth").Return(&corev1.Secret{
+		Data: map[string][]byte{"password": []byte("new")},
+	}, nil)
+	ms.On("UpdateSecret", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		authSecret = args.Get(1).(*corev1.Secret)
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+
+	state, err := client.GetRedisPasswordState(rf)
+	require.NoError(t, err)
+	assert.Equal(&rfservice.RedisPasswordState{Applied: "old", Desired: "new"}, state)
+
+	state = &rfservice.RedisPasswordState{Applied: "new", Desired: "new", Previous: "old", Rotating: true, RotatedAt: rotatedAt}
+	require.NoError(t, client.SetRedisPasswordState(rf, state))
+	assert.Equal([]byte("operator"), authSecret.Data["operator-password"])
+	assert.Equal([]byte("new"), authSecret.Data["password"])
+	assert.Equal([]byte("old"), authSecret.Data["previous-password"])
+	// The nodes restarted during the rotation accept both passwords
+	authConfig := string(authSecret.Data["auth.conf"])
+	assert.Contains(authConfig, "user default on "+passwordHash("new")+" "+passwordHash("old")+" ~* &* +@all\nmasterauth new\n")
+	assert.NotContains(authConfig, "requirepass")
+
+	// The state is read back from the operator secret
+	ms.ExpectedCalls = ms.ExpectedCalls[:0]
+	ms.On("GetSecret", namespace, authSecretName).Return(authSecret, nil)
+	ms.On("GetSecret", namespace, "redis-auth").Return(&corev1.Secret{
+		Data: map[string][]byte{"password": []byte("new")},
+	}, nil)
+	read, err := client.GetRedisPasswordState(rf)
+	require.NoError(t, err)
+	assert.Equal(state, read)
+
+	// Only the new password is accepted once the rotation is over
+	ms.On("UpdateSecret", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		authSecret = args.Get(1).(*corev1.Secret)
+	}).Return(nil)
+	require.NoError(t, client.SetRedisPasswordState(rf, &rfservice.RedisPasswordState{Applied: "new", Desired: "new"}))
+	authConfig = string(authSecret.Data["auth.conf"])
+	assert.Contains(authConfig, "masterauth new\nrequirepass new\n")
+	assert.NotContains(authConfig, "user default")
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/service/k8s"
+	"github.com/saremox/redis-operator/service/redis"
+	"github.com/saremox/redis-operator/service/s3"
+)
+
+// Keys of the object storage credentials in the secret of the backup storage
+const (
+	backupAccessKeyIDKey     = "accessKeyID"
+	backupSecretAccessKeyKey = "secretAccessKey"
+)
+
+// backupPollInterval is how often the persistence state is polled while the snapshot is taken.
+var backupPollInterval = time.Second
+
+// backupSaveTimeout is how long a snapshot may take before the backup is given up.
+const backupSaveTimeout = time.Hour
+
+// BackupResult describes the snapshot uploaded to the object storage
+type BackupResult struct {
+	// Location is the URL of the snapshot, as s3://<bucket>/<key>
+	Location string
+	Size     int64
+	// Checksum is the SHA-256 of the snapshot, as sha256:<hex>
+	Checksum string
+}
+
+// RedisFailoverBackup defines the interface able to back up the redis nodes to object storage
+type RedisFailoverBackup interface {
+	SaveRedis(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	BackupRedis(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover, storage *redisfailoverv1.BackupStorage, name string) (*BackupResult, error)
+	DeleteBackup(ctx context.Context, namespace string, storage *redisfailoverv1.BackupStorage, location string) error
+}
+
+// RedisFailoverBackupper is our implementation of RedisFailoverBackup interface
+type RedisFailoverBackupper struct {
+	k8sService  k8s.Services
+	redisClient redis.Client
+	logger      log.Logger
+}
+
+// NewRedisFailoverBackupper creates an object of the RedisFailoverBackupper struct
+func NewRedisFailoverBackupper(k8sService k8s.Services, redisClient redis.Client, logger log.Logger) *RedisFailoverBackupper {
+	logger = logger.With("service", "redis.backupper")
+	return &RedisFailoverBackupper{
+		k8sService:  k8sService,
+		redisClient: redisClient,
+		logger:      logger,
+	}
+}
+
+// GetBackupKey returns the key of the snapshot of a backup in the object storage
+func GetBackupKey(storage *redisfailoverv1.BackupStorage, rfName, backupName string) string {
+	return strings.TrimPrefix(path.Join(storage.S3.Prefix, rfName, backupName+".rdb"), "/")
+}
+
+// BackupRedis streams a snapshot of the dataset of the redis node to the object storage, under
+// the key of the named backup. The snapshot is the RDB the node forks to send along a full
+// resynchronization, so no BGSAVE is needed beforehand.
+func (r *RedisFailoverBackupper) BackupRedis(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover, storage *redisfailoverv1.BackupStorage, name string) (*BackupResult, error) {
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return nil, err
+	}
+	s3Client, err := r.getS3Client(rf.Namespace, storage)
+	if err != nil {
+		return nil, err
+	}
+	port := getRedisPort(rf.Spec.Redis.Port)
+
+	rdb, size, err := redisClient.SyncRDB(ctx, ip, port, password)
+	if err != nil {
+		return nil, err
+	}
+	defer rdb.Close()
+
+	key := GetBackupKey(storage, rf.Name, name)
+	hash := sha256.New()
+	if err := s3Client.PutObject(ctx, storage.S3.Bucket, key, io.TeeReader(rdb, hash), size); err != nil {
+		return nil, err
+	}
+	r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).Infof("Snapshot of %s uploaded to %s (%d bytes)", ip, key, size)
+
+	return &BackupResult{
+		Location: fmt.Sprintf("s3://%s/%s", storage.S3.Bucket, key),
+		Size:     size,
+		Checksum: "sha256:" + hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// SaveRedis saves the dataset of the redis node to its RDB file (BGSAVE) and waits for it to be
+// written.
+func (r *RedisFailoverBackupper) SaveRedis(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return err
+	}
+	return r.saveRedis(ctx, redisClient, ip, getRedisPort(rf.Spec.Redis.Port), password)
+}
+
+func (r *RedisFailoverBackupper) saveRedis(ctx context.Context, redisClient redis.Client, ip, port, password string) error {
+	if err := redisClient.BackgroundSave(ctx, ip, port, password); err != nil {
+		return err
+	}
+	return r.waitBackgroundSave(ctx, redisClient, ip, port, password)
+}
+
+// waitBackgroundSave waits for the BGSAVE in progress on the redis node to end
+func (r *RedisFailoverBackupper) waitBackgroundSave(ctx context.Context, redisClient redis.Client, ip, port, password string) error {
+	deadline := time.Now().Add(backupSaveTimeout)
+	for {
+		info, err := redisClient.GetPersistenceInfo(ctx, ip, port, password)
+		if err != nil {
+			return err
+		}
+		if !info.BgsaveInProgress {
+			if info.LastBgsaveStatus != "ok" {
+				return errors.New("background save failed, check the logs of the redis node")
+			}
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("background save still in progress after %s", backupSaveTimeout)
+		}
+		if err := sleepContext(ctx, backupPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// DeleteBackup deletes the snapshot at the given s3://<bucket>/<key> location
+func (r *RedisFailoverBackupper) DeleteBackup(ctx context.Context, namespace string, storage *redisfailoverv1.BackupStorage, location string) error {
+	bucket, key, err := parseBackupLocation(location)
+	if err != nil {
+		return err
+	}
+	s3Client, err := r.getS3Client(namespace, storage)
+	if err != nil {
+		return err
+	}
+	return s3Client.DeleteObject(ctx, bucket, key)
+}
+
+// parseBackupLocation returns the bucket and the key of an s3://<bucket>/<key> location
+func parseBackupLocation(location string) (string, string, error) {
+	u, err := url.Parse(location)
+	if err != nil || u.Scheme != "s3" || u.Host == "" || u.Path == "" {
+		return "", "", fmt.Errorf("invalid backup location %q", location)
+	}
+	return u.Host, strings.TrimPrefix(u.Path, "/"), nil
+}
+
+// getS3Client returns the client of the object storage, authenticating with the credentials of
+// the storage secret
+func (r *RedisFailoverBackupper) getS3Client(namespace string, storage *redisfailoverv1.BackupStorage) (s3.Client, error) {
+	return newS3Client(r.k8sService, namespace, storage)
+}
+
+// newS3Client returns the client of the object storage of the storage secret credentials
+func newS3Client(k8sService k8s.Services, namespace string, storage *redisfailoverv1.BackupStorage) (s3.Client, error) {
+	if err := storage.Validate(); err != nil {
+		return nil, err
+	}
+	secret, err := k8sService.GetSecret(namespace, storage.S3.SecretName)
+	if err != nil {
+		return nil, err
+	}
+	accessKeyID, ok := secret.Data[backupAccessKeyIDKey]
+	if !ok {
+		return nil, fmt.Errorf("secret \"%s\" does not have a %s field", storage.S3.SecretName, backupAccessKeyIDKey)
+	}
+	secretAccessKey, ok := secret.Data[backupSecretAccessKeyKey]
+	if !ok {
+		return nil, fmt.Errorf("secret \"%s\" does not have a %s field", storage.S3.SecretName, backupSecretAccessKeyKey)
+	}
+	return s3.New(s3.Config{
+		Endpoint:        storage.S3.Endpoint,
+		Region:          storage.S3.Region,
+		AccessKeyID:     string(accessKeyID),
+		SecretAccessKey: string(secretAccessKey),
+	})
+}
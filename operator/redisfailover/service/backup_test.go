@@ -0,0 +1,192 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
+	mRedisService "github.com/saremox/redis-operator/mocks/service/redis"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+	"github.com/saremox/redis-operator/service/redis"
+	"github.com/saremox/redis-operator/service/s3/s3test"
+)
+
+func generateBackupStorage(server *s3test.Server) *redisfailoverv1.BackupStorage {
+	return &redisfailoverv1.BackupStorage{
+		S3: &redisfailoverv1.S3Storage{
+			Endpoint:   server.URL,
+			Region:     s3test.Region,
+			Bucket:     "backups",
+			Prefix:     "/redis/",
+			SecretName: "s3-credentials",
+		},
+	}
+}
+
+func generateBackupCredentials(accessKeyID string) *corev1.Secret {
+	return &corev1.Secret{
+		Data: map[string][]byte{
+			"accessKeyID":     []byte(accessKeyID),
+			"secretAccessKey": []byte(s3test.SecretAccessKey),
+		},
+	}
+}
+
+func TestGetBackupKey(t *testing.T) {
+	storage := &redisfailoverv1.BackupStorage{S3: &redisfailoverv1.S3Storage{}}
+	assert.Equal(t, "test/test-202401310300.rdb", rfservice.GetBackupKey(storage, "test", "test-202401310300"))
+
+	storage.S3.Prefix = "/redis/prod/"
+	assert.Equal(t, "redis/prod/test/test-202401310300.rdb", rfservice.GetBackupKey(storage, "test", "test-202401310300"))
+}
+
+func TestBackupRedis(t *testing.T) {
+	rdb := []byte("REDIS0011\xfa\tredis-ver\x057.2.4\xff")
+	rdbSum := sha256.Sum256(rdb)
+
+	tests := []struct {
+		name        string
+		syncErr     error
+		accessKeyID string
+		expError    string
+	}{
+		{
+			name:        "snapshot uploaded",
+			accessKeyID: s3test.AccessKeyID,
+		},
+		{
+			name:        "sync failed",
+			syncErr:     errors.New("ERR Replica can't interact with the keyspace"),
+			accessKeyID: s3test.AccessKeyID,
+			expError:    "ERR Replica can't interact with the keyspace",
+		},
+		{
+			name:        "wrong credentials",
+			accessKeyID: "unknown",
+			expError:    "s3: InvalidAccessKeyId: The Access Key Id you provided does not exist in our records.",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			server := s3test.NewServer("backups")
+			defer server.Close()
+
+			rf := generateRF()
+			storage := generateBackupStorage(server)
+
+			ms := &mK8SService.Services{}
+			ms.On("GetSecret", namespace, "s3-credentials").Once().Return(generateBackupCredentials(test.accessKeyID), nil)
+			mr := &mRedisService.Client{}
+			// The snapshot sent along the resynchronization is uploaded, no background save is run
+			if test.syncErr != nil {
+				mr.On("SyncRDB", mock.Anything, "1.1.1.1", "0", "").Once().Return(nil, int64(0), test.syncErr)
+			} else {
+				mr.On("SyncRDB", mock.Anything, "1.1.1.1", "0", "").Once().Return(io.NopCloser(bytes.NewReader(rdb)), int64(len(rdb)), nil)
+			}
+
+			backupper := rfservice.NewRedisFailoverBackupper(ms, mr, log.DummyLogger{})
+			result, err := backupper.BackupRedis(context.TODO(), "1.1.1.1", rf, storage, "test-202401310300")
+
+			if test.expError != "" {
+				assert.EqualError(err, test.expError)
+				assert.Empty(server.Keys("backups"))
+			} else {
+				assert.NoError(err)
+				assert.Equal(&rfservice.BackupResult{
+					Location: "s3://backups/redis/test/test-202401310300.rdb",
+					Size:     int64(len(rdb)),
+					Checksum: "sha256:" + hex.EncodeToString(rdbSum[:]),
+				}, result)
+				data, ok := server.Object("backups", "redis/test/test-202401310300.rdb")
+				assert.True(ok)
+				assert.Equal(rdb, data)
+			}
+			ms.AssertExpectations(t)
+			mr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSaveRedis(t *testing.T) {
+	tests := []struct {
+		name            string
+		bgsaveErr       error
+		persistenceInfo []*redis.PersistenceInfo
+		expError        string
+	}{
+		{
+			name: "saved once the background save ends",
+			persistenceInfo: []*redis.PersistenceInfo{
+				{BgsaveInProgress: true},
+				{LastBgsaveStatus: "ok"},
+			},
+		},
+		{
+			name:      "background save rejected",
+			bgsaveErr: errors.New("NOAUTH Authentication required."),
+			expError:  "NOAUTH Authentication required.",
+		},
+		{
+			name: "background save failed",
+			persistenceInfo: []*redis.PersistenceInfo{
+				{LastBgsaveStatus: "err"},
+			},
+			expError: "background save failed, check the logs of the redis node",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			ms := &mK8SService.Services{}
+			mr := &mRedisService.Client{}
+			mr.On("BackgroundSave", mock.Anything, "1.1.1.1", "0", "").Once().Return(test.bgsaveErr)
+			for _, info := range test.persistenceInfo {
+				mr.On("GetPersistenceInfo", mock.Anything, "1.1.1.1", "0", "").Once().Return(info, nil)
+			}
+
+			backupper := rfservice.NewRedisFailoverBackupper(ms, mr, log.DummyLogger{})
+			err := backupper.SaveRedis(context.TODO(), "1.1.1.1", generateRF())
+			if test.expError != "" {
+				assert.EqualError(err, test.expError)
+			} else {
+				assert.NoError(err)
+			}
+			mr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteBackup(t *testing.T) {
+	assert := assert.New(t)
+
+	server := s3test.NewServer("backups")
+	defer server.Close()
+	server.PutObject("backups", "redis/test/test-202401310300.rdb", []byte("REDIS0011"))
+	server.PutObject("backups", "redis/test/test-202402010300.rdb", []byte("REDIS0011"))
+
+	ms := &mK8SService.Services{}
+	ms.On("GetSecret", namespace, "s3-credentials").Return(generateBackupCredentials(s3test.AccessKeyID), nil)
+	backupper := rfservice.NewRedisFailoverBackupper(ms, &mRedisService.Client{}, log.DummyLogger{})
+
+	storage := generateBackupStorage(server)
+	assert.NoError(backupper.DeleteBackup(context.TODO(), namespace, storage, "s3://backups/redis/test/test-202401310300.rdb"))
+	assert.Equal([]string{"redis/test/test-202402010300.rdb"}, server.Keys("backups"))
+
+	assert.EqualError(backupper.DeleteBackup(context.TODO(), namespace, storage, "/redis/test/test-202402010300.rdb"), `invalid backup location "/redis/test/test-202402010300.rdb"`)
+}
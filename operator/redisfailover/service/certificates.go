@@ -0,0 +1,229 @@
+package service
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/service/k8s"
+)
+
+// variables refering to the certificates generated by the operator, which are renewed
+// once less than a third of their validity remains
+const (
+	tlsCAValidity          = 10 * 365 * 24 * time.Hour
+	tlsCertificateValidity = 365 * 24 * time.Hour
+	tlsClockSkew           = time.Hour
+)
+
+// tlsKeyPair is a certificate along with its private key
+type tlsKeyPair struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTLSKeyPair issues a certificate from the template for the key, or a new one when key is
+// nil, signed by the parent or self-signed when parent is nil
+func newTLSKeyPair(template *x509.Certificate, parent *tlsKeyPair, key *ecdsa.PrivateKey) (*tlsKeyPair, error) {
+	var err error
+	if key == nil {
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+	}
+	template.SerialNumber, err = rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tlsKeyPair{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
+
+// parseTLSKeyPair reads the key pair stored in a TLS secret, or returns nil if the secret
+// does not hold a valid one
+func parseTLSKeyPair(secret *corev1.Secret) *tlsKeyPair {
+	if secret == nil {
+		return nil
+	}
+	certPEM, keyPEM := secret.Data[k8s.TLSCertKey], secret.Data[k8s.TLSPrivateKeyKey]
+	certBlock, _ := pem.Decode(certPEM)
+	keyBlock, _ := pem.Decode(keyPEM)
+	if certBlock == nil || keyBlock == nil {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil
+	}
+	if pub, ok := cert.PublicKey.(*ecdsa.PublicKey); !ok || !pub.Equal(&key.PublicKey) {
+		return nil
+	}
+	return &tlsKeyPair{cert: cert, key: key, certPEM: certPEM, keyPEM: keyPEM}
+}
+
+// needsRenewal returns true when less than a third of the certificate validity remains
+func needsRenewal(cert *x509.Certificate, now time.Time) bool {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return now.After(cert.NotAfter.Add(-lifetime / 3))
+}
+
+func generateTLSCATemplate(rf *redisfailoverv1.RedisFailover, now time.Time) *x509.Certificate {
+	return &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   fmt.Sprintf("%s.%s CA", rf.Name, rf.Namespace),
+			Organization: []string{appLabel},
+		},
+		NotBefore:             now.Add(-tlsClockSkew),
+		NotAfter:              now.Add(tlsCAValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+}
+
+func generateTLSCertificateTemplate(rf *redisfailoverv1.RedisFailover, clusterDomain string, now time.Time) *x509.Certificate {
+	return &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   GetRedisName(rf),
+			Organization: []string{appLabel},
+		},
+		DNSNames:    getTLSDNSNames(rf, clusterDomain),
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+		NotBefore:   now.Add(-tlsClockSkew),
+		NotAfter:    now.Add(tlsCertificateValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		// The certificate is also presented by the replicas and sentinels when connecting to the master
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+}
+
+// getTLSDNSNames returns the names of the services and pods the certificate is valid for, the
+// fully qualified ones in the given cluster domain
+func getTLSDNSNames(rf *redisfailoverv1.RedisFailover, clusterDomain string) []string {
+	names := []string{"localhost"}
+	for _, svc := range []string{GetRedisMasterName(rf), GetRedisSlaveName(rf), GetSentinelName(rf), GetRedisName(rf)} {
+		names = append(names,
+			svc,
+			fmt.Sprintf("%s.%s", svc, rf.Namespace),
+			fmt.Sprintf("%s.%s.svc", svc, rf.Namespace),
+			fmt.Sprintf("%s.%s.svc.%s", svc, rf.Namespace, clusterDomain),
+		)
+	}
+	// The redis pods are named after the governing service of the statefulset
+	names = append(names,
+		fmt.Sprintf("*.%s.%s.svc", GetRedisName(rf), rf.Namespace),
+		fmt.Sprintf("*.%s.%s.svc.%s", GetRedisName(rf), rf.Namespace, clusterDomain),
+	)
+	return names
+}
+
+func generateTLSSecret(name string, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, keyPair *tlsKeyPair, caPEM []byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       rf.Namespace,
+			Labels:          labels,
+			OwnerReferences: ownerRefs,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			k8s.TLSCertKey:       keyPair.certPEM,
+			k8s.TLSPrivateKeyKey: keyPair.keyPEM,
+			k8s.TLSCAKey:         caPEM,
+		},
+	}
+}
+
+// isOwnedBy returns true when the secret is owned by the RedisFailover
+func isOwnedBy(secret *corev1.Secret, rf *redisfailoverv1.RedisFailover) bool {
+	return slices.ContainsFunc(secret.OwnerReferences, func(ref metav1.OwnerReference) bool {
+		return ref.UID == rf.UID
+	})
+}
+
+// renewTLSSecrets returns the CA and certificate secrets that have to be created or updated,
+// or nil for the ones that are still valid. The CA is renewed with the same key and subject, so
+// the certificates signed by the previous CA and the pods trusting it keep working until they
+// are rolled.
+func renewTLSSecrets(rf *redisfailoverv1.RedisFailover, clusterDomain string, labels map[string]string, ownerRefs []metav1.OwnerReference, caSecret, secret *corev1.Secret, now time.Time) (*corev1.Secret, *corev1.Secret, error) {
+	var newCASecret, newSecret *corev1.Secret
+
+	ca := parseTLSKeyPair(caSecret)
+	if ca == nil || !ca.cert.IsCA || needsRenewal(ca.cert, now) {
+		var key *ecdsa.PrivateKey
+		if ca != nil && ca.cert.IsCA {
+			key = ca.key
+		}
+		var err error
+		ca, err = newTLSKeyPair(generateTLSCATemplate(rf, now), nil, key)
+		if err != nil {
+			return nil, nil, err
+		}
+		newCASecret = generateTLSSecret(GetTLSCASecretName(rf), rf, labels, ownerRefs, ca, ca.certPEM)
+	}
+
+	cert := parseTLSKeyPair(secret)
+	if cert == nil || cert.cert.CheckSignatureFrom(ca.cert) != nil || needsRenewal(cert.cert, now) ||
+		!slices.Equal(cert.cert.DNSNames, getTLSDNSNames(rf, clusterDomain)) || !bytes.Equal(secret.Data[k8s.TLSCAKey], ca.certPEM) {
+		var err error
+		cert, err = newTLSKeyPair(generateTLSCertificateTemplate(rf, clusterDomain, now), ca, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		newSecret = generateTLSSecret(GetTLSSecretName(rf), rf, labels, ownerRefs, cert, ca.certPEM)
+	}
+
+	return newCASecret, newSecret, nil
+}
+
+// getTLSChecksum returns a checksum of the certificates held by a TLS secret
+func getTLSChecksum(secret *corev1.Secret) string {
+	h := sha256.New()
+	h.Write(secret.Data[k8s.TLSCertKey])
+	h.Write(secret.Data[k8s.TLSCAKey])
+	return hex.EncodeToString(h.Sum(nil))
+}
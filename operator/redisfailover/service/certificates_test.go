@@ -0,0 +1,235 @@
+package service_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+const (
+	tlsSecretName   = "rftls-test"
+	tlsCASecretName = "rfca-test"
+)
+
+func parsePEMCertificates(t *testing.T, data []byte) []*x509.Certificate {
+	certs := []*x509.Certificate{}
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		certs = append(certs, cert)
+	}
+	return certs
+}
+
+// reissue replaces the key pair held by the secret with one valid between notBefore and
+// notAfter, signed by the signer secret or self-signed when it is nil
+func reissue(t *testing.T, secret, signer *corev1.Secret, notBefore, notAfter time.Time) *corev1.Secret {
+	current := parsePEMCertificates(t, secret.Data["tls.crt"])[0]
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               current.Subject,
+		DNSNames:              current.DNSNames,
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              current.KeyUsage,
+		ExtKeyUsage:           current.ExtKeyUsage,
+		BasicConstraintsValid: true,
+		IsCA:                  current.IsCA,
+	}
+	parent, parentKey := template, key
+	if signer != nil {
+		parent = parsePEMCertificates(t, signer.Data["tls.crt"])[0]
+		block, _ := pem.Decode(signer.Data["tls.key"])
+		parentKey, err = x509.ParseECPrivateKey(block.Bytes)
+		require.NoError(t, err)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	reissued := secret.DeepCopy()
+	reissued.Data["tls.crt"] = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	reissued.Data["tls.key"] = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return reissued
+}
+
+func TestEnsureTLSSecretsNotOwned(t *testing.T) {
+	assert := assert.New(t)
+	rf := generateRF()
+	rf.UID = "rf-uid"
+	rf.Spec.TLS = &redisfailoverv1.TLSSettings{SecretName: "redis-tls", Managed: true}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetSecret", namespace, tlsCASecretName).Once().Return(nil, kubeerrors.NewNotFound(schema.GroupResource{}, tlsCASecretName))
+	// The secret was created by the user
+	ms.On("GetSecret", namespace, "redis-tls").Once().Return(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "redis-tls", Namespace: namespace},
+	}, nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+	err := client.EnsureTLSSecrets(rf, map[string]string{"app": "redis"}, []metav1.OwnerReference{{Name: name, UID: rf.UID}})
+	assert.ErrorContains(err, `secret "redis-tls" is not owned by the redis failover`)
+	// Nothing is written
+	ms.AssertExpectations(t)
+}
+
+// ensureTLSSecrets runs EnsureTLSSecrets in the cluster.local domain against the given secrets
+// and returns the ones it wrote
+func ensureTLSSecrets(t *testing.T, rf *redisfailoverv1.RedisFailover, secrets map[string]*corev1.Secret) map[string]*corev1.Secret {
+	return ensureTLSSecretsInDomain(t, rf, "cluster.local", secrets)
+}
+
+func ensureTLSSecretsInDomain(t *testing.T, rf *redisfailoverv1.RedisFailover, clusterDomain string, secrets map[string]*corev1.Secret) map[string]*corev1.Secret {
+	written := map[string]*corev1.Secret{}
+	ms := &mK8SService.Services{}
+	for _, name := range []string{tlsCASecretName, tlsSecretName} {
+		if secret, ok := secrets[name]; ok {
+			ms.On("GetSecret", namespace, name).Once().Return(secret, nil)
+		} else {
+			ms.On("GetSecret", namespace, name).Once().Return(nil, kubeerrors.NewNotFound(schema.GroupResource{}, name))
+		}
+	}
+	ms.On("CreateOrUpdateSecret", namespace, mock.Anything).Run(func(args mock.Arguments) {
+		s := args.Get(1).(*corev1.Secret)
+		written[s.Name] = s
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, clusterDomain)
+	require.NoError(t, client.EnsureTLSSecrets(rf, map[string]string{"app": "redis"}, []metav1.OwnerReference{{Name: name}}))
+	return written
+}
+
+func TestEnsureTLSSecrets(t *testing.T) {
+	rf := generateRF()
+	rf.Spec.TLS = &redisfailoverv1.TLSSettings{SecretName: tlsSecretName, Managed: true}
+	now := time.Now()
+
+	// The first run issues the CA and the certificate the other cases start from
+	issued := ensureTLSSecrets(t, rf, map[string]*corev1.Secret{})
+	require.Len(t, issued, 2)
+	caSecret, secret := issued[tlsCASecretName], issued[tlsSecretName]
+
+	t.Run("Issues the CA and the certificate", func(t *testing.T) {
+		assert := assert.New(t)
+
+		for _, s := range issued {
+			assert.Equal(corev1.SecretTypeTLS, s.Type)
+			assert.Equal(namespace, s.Namespace)
+			assert.Equal(map[string]string{"app": "redis"}, s.Labels)
+			assert.Equal([]metav1.OwnerReference{{Name: name}}, s.OwnerReferences)
+		}
+		assert.Equal(caSecret.Data["tls.crt"], caSecret.Data["ca.crt"])
+		assert.Equal(caSecret.Data["ca.crt"], secret.Data["ca.crt"])
+
+		roots := x509.NewCertPool()
+		roots.AppendCertsFromPEM(secret.Data["ca.crt"])
+		cert := parsePEMCertificates(t, secret.Data["tls.crt"])[0]
+		for _, dnsName := range []string{
+			"rfrm-test.testns.svc",
+			"rfrs-test.testns.svc.cluster.local",
+			"rfs-test",
+			"rfr-test-0.rfr-test.testns.svc",
+			"localhost",
+		} {
+			_, err := cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: dnsName})
+			assert.NoError(err, dnsName)
+		}
+		assert.Contains(cert.ExtKeyUsage, x509.ExtKeyUsageClientAuth)
+	})
+
+	t.Run("Keeps valid certificates", func(t *testing.T) {
+		written := ensureTLSSecrets(t, rf, issued)
+		assert.Empty(t, written)
+	})
+
+	t.Run("Renews an expiring certificate", func(t *testing.T) {
+		assert := assert.New(t)
+
+		expiring := reissue(t, secret, caSecret, now.Add(-300*24*time.Hour), now.Add(60*24*time.Hour))
+		written := ensureTLSSecrets(t, rf, map[string]*corev1.Secret{tlsCASecretName: caSecret, tlsSecretName: expiring})
+
+		assert.NotContains(written, tlsCASecretName)
+		if assert.Contains(written, tlsSecretName) {
+			cert := parsePEMCertificates(t, written[tlsSecretName].Data["tls.crt"])[0]
+			assert.True(cert.NotAfter.After(now.Add(300 * 24 * time.Hour)))
+			assert.NoError(cert.CheckSignatureFrom(parsePEMCertificates(t, caSecret.Data["tls.crt"])[0]))
+		}
+	})
+
+	t.Run("Renews the certificate for another cluster domain", func(t *testing.T) {
+		assert := assert.New(t)
+
+		written := ensureTLSSecretsInDomain(t, rf, "example.internal", issued)
+		assert.NotContains(written, tlsCASecretName)
+		if assert.Contains(written, tlsSecretName) {
+			roots := x509.NewCertPool()
+			roots.AppendCertsFromPEM(caSecret.Data["ca.crt"])
+			cert := parsePEMCertificates(t, written[tlsSecretName].Data["tls.crt"])[0]
+			_, err := cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: "rfrm-test.testns.svc.example.internal"})
+			assert.NoError(err)
+			_, err = cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: "rfr-test-0.rfr-test.testns.svc.example.internal"})
+			assert.NoError(err)
+			assert.NotContains(cert.DNSNames, "rfrm-test.testns.svc.cluster.local")
+		}
+	})
+
+	t.Run("Renews a certificate not signed by the CA", func(t *testing.T) {
+		foreign := reissue(t, secret, nil, now.Add(-time.Hour), now.Add(300*24*time.Hour))
+		written := ensureTLSSecrets(t, rf, map[string]*corev1.Secret{tlsCASecretName: caSecret, tlsSecretName: foreign})
+
+		assert.NotContains(t, written, tlsCASecretName)
+		assert.Contains(t, written, tlsSecretName)
+	})
+
+	t.Run("Renews an expiring CA with the same key", func(t *testing.T) {
+		assert := assert.New(t)
+
+		expiringCA := reissue(t, caSecret, nil, now.Add(-9*365*24*time.Hour), now.Add(365*24*time.Hour))
+		previous := reissue(t, secret, expiringCA, now.Add(-time.Hour), now.Add(300*24*time.Hour))
+		previous.Data["ca.crt"] = expiringCA.Data["tls.crt"]
+		written := ensureTLSSecrets(t, rf, map[string]*corev1.Secret{tlsCASecretName: expiringCA, tlsSecretName: previous})
+
+		if assert.Contains(written, tlsCASecretName) && assert.Contains(written, tlsSecretName) {
+			oldCA := parsePEMCertificates(t, expiringCA.Data["tls.crt"])[0]
+			newCA := parsePEMCertificates(t, written[tlsCASecretName].Data["tls.crt"])[0]
+			assert.True(newCA.NotAfter.After(now.Add(9 * 365 * 24 * time.Hour)))
+			assert.Equal(oldCA.PublicKey, newCA.PublicKey)
+			assert.Equal(written[tlsCASecretName].Data["tls.crt"], written[tlsSecretName].Data["ca.crt"])
+
+			// The pods are rolled one at a time, so the old and new certificates must be trusted by both CAs
+			oldRoots, newRoots := x509.NewCertPool(), x509.NewCertPool()
+			oldRoots.AddCert(oldCA)
+			newRoots.AddCert(newCA)
+			oldCert := parsePEMCertificates(t, previous.Data["tls.crt"])[0]
+			newCert := parsePEMCertificates(t, written[tlsSecretName].Data["tls.crt"])[0]
+			for _, cert := range []*x509.Certificate{oldCert, newCert} {
+				for _, roots := range []*x509.CertPool{oldRoots, newRoots} {
+					_, err := cert.Verify(x509.VerifyOptions{Roots: roots})
+					assert.NoError(err)
+				}
+			}
+		}
+	})
+}
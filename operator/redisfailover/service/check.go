@@ -1,9 +1,13 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -25,33 +29,45 @@ type ReplicaInfo struct {
 	IsReady           bool
 }
 
+// RedisNodeReplication holds the replication state reported by a Redis pod
+type RedisNodeReplication struct {
+	PodName      string
+	IP           string
+	RedisVersion string
+	Replication  *redis.ReplicationInfo
+}
+
 // RedisFailoverCheck defines the interface able to check the correct status of redis failover
 type RedisFailoverCheck interface {
 	CheckRedisNumber(rFailover *redisfailoverv1.RedisFailover) error
 	CheckSentinelNumber(rFailover *redisfailoverv1.RedisFailover) error
-	CheckAllSlavesFromMaster(master string, rFailover *redisfailoverv1.RedisFailover) error
-	CheckSentinelNumberInMemory(sentinel string, rFailover *redisfailoverv1.RedisFailover) error
-	CheckSentinelSlavesNumberInMemory(sentinel string, rFailover *redisfailoverv1.RedisFailover) error
-	CheckSentinelQuorum(rFailover *redisfailoverv1.RedisFailover) (int, error)
-	CheckIfMasterLocalhost(rFailover *redisfailoverv1.RedisFailover) (bool, error)
-	CheckSentinelMonitor(sentinel string, monitor ...string) error
-	GetMasterIP(rFailover *redisfailoverv1.RedisFailover) (string, error)
-	GetNumberMasters(rFailover *redisfailoverv1.RedisFailover) (int, error)
+	CheckAllSlavesFromMaster(ctx context.Context, master string, rFailover *redisfailoverv1.RedisFailover) error
+	CheckSentinelNumberInMemory(ctx context.Context, sentinel string, rFailover *redisfailoverv1.RedisFailover) error
+	CheckSentinelSlavesNumberInMemory(ctx context.Context, sentinel string, rFailover *redisfailoverv1.RedisFailover) error
+	GetSentinelSlavesNumberInMemory(ctx context.Context, sentinel string, rFailover *redisfailoverv1.RedisFailover) (int32, error)
+	GetACLUsersChecksum(rFailover *redisfailoverv1.RedisFailover) (string, error)
+	CheckSentinelQuorum(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (int, error)
+	CheckIfMasterLocalhost(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (bool, error)
+	CheckSentinelMonitor(ctx context.Context, sentinel string, rFailover *redisfailoverv1.RedisFailover, monitor ...string) error
+	GetMasterIP(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (string, error)
+	GetNumberMasters(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (int, error)
 	GetRedisesIPs(rFailover *redisfailoverv1.RedisFailover) ([]string, error)
 	GetSentinelsIPs(rFailover *redisfailoverv1.RedisFailover) ([]string, error)
 	GetMaxRedisPodTime(rFailover *redisfailoverv1.RedisFailover) (time.Duration, error)
-	GetRedisesSlavesPods(rFailover *redisfailoverv1.RedisFailover) ([]string, error)
-	GetRedisesMasterPod(rFailover *redisfailoverv1.RedisFailover) (string, error)
+	GetRedisesSlavesPods(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) ([]string, error)
+	GetRedisesMasterPod(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (string, error)
 	GetStatefulSetUpdateRevision(rFailover *redisfailoverv1.RedisFailover) (string, error)
 	GetRedisRevisionHash(podName string, rFailover *redisfailoverv1.RedisFailover) (string, error)
-	CheckRedisSlavesReady(slaveIP string, rFailover *redisfailoverv1.RedisFailover) (bool, error)
+	CheckRedisSlavesReady(ctx context.Context, slaveIP string, rFailover *redisfailoverv1.RedisFailover) (bool, error)
 	IsRedisRunning(rFailover *redisfailoverv1.RedisFailover) bool
 	IsSentinelRunning(rFailover *redisfailoverv1.RedisFailover) bool
 	IsClusterRunning(rFailover *redisfailoverv1.RedisFailover) bool
 	// Operator-managed failover methods
-	CheckMasterHealth(rFailover *redisfailoverv1.RedisFailover) (bool, string, error)
-	GetBestReplicaForPromotion(rFailover *redisfailoverv1.RedisFailover) (*ReplicaInfo, error)
-	GetReplicaReplicationOffsets(rFailover *redisfailoverv1.RedisFailover) ([]ReplicaInfo, error)
+	CheckMasterHealth(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (bool, string, error)
+	GetBestReplicaForPromotion(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (*ReplicaInfo, error)
+	GetReplicaReplicationOffsets(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) ([]ReplicaInfo, error)
+	GetRedisesReplication(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) ([]RedisNodeReplication, error)
+	GetRedisConfigDrift(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) ([]string, error)
 }
 
 // RedisFailoverChecker is our implementation of RedisFailoverCheck interface
@@ -115,20 +131,25 @@ func (r *RedisFailoverChecker) setSlaveLabelIfNecessary(namespace string, pod co
 }
 
 // CheckAllSlavesFromMaster controlls that all slaves have the same master (the real one)
-func (r *RedisFailoverChecker) CheckAllSlavesFromMaster(master string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverChecker) CheckAllSlavesFromMaster(ctx context.Context, master string, rf *redisfailoverv1.RedisFailover) error {
 	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
 		return err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
 	if err != nil {
 		return err
 	}
 
 	rport := getRedisPort(rf.Spec.Redis.Port)
 	for _, rp := range rps.Items {
-		if rp.Status.PodIP == master {
+		if redisfailoverv1.SameAddress(rf.PodAddress(&rp), master) {
 			err = r.setMasterLabelIfNecessary(rf.Namespace, rp)
 			if err != nil {
 				return err
@@ -140,21 +161,25 @@ func (r *RedisFailoverChecker) CheckAllSlavesFromMaster(master string, rf *redis
 			}
 		}
 
-		slave, err := r.redisClient.GetSlaveOf(rp.Status.PodIP, rport, password)
+		slave, err := redisClient.GetSlaveOf(ctx, rf.PodAddress(&rp), rport, password)
 		if err != nil {
-			r.logger.Errorf("Get slave of master failed, maybe this node is not ready, pod ip: %s", rp.Status.PodIP)
+			r.logger.Errorf("Get slave of master failed, maybe this node is not ready, pod ip: %s", rf.PodAddress(&rp))
 			return err
 		}
-		if slave != "" && slave != master {
-			return fmt.Errorf("slave %s don't have the master %s, has %s", rp.Status.PodIP, master, slave)
+		if slave != "" && !redisfailoverv1.SameAddress(slave, master) {
+			return fmt.Errorf("slave %s don't have the master %s, has %s", rf.PodAddress(&rp), master, slave)
 		}
 	}
 	return nil
 }
 
 // CheckSentinelNumberInMemory controls that the provided sentinel has only the living sentinels on its memory.
-func (r *RedisFailoverChecker) CheckSentinelNumberInMemory(sentinel string, rf *redisfailoverv1.RedisFailover) error {
-	nSentinels, err := r.redisClient.GetNumberSentinelsInMemory(sentinel)
+func (r *RedisFailoverChecker) CheckSentinelNumberInMemory(ctx context.Context, sentinel string, rf *redisfailoverv1.RedisFailover) error {
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return err
+	}
+	nSentinels, err := redisClient.GetNumberSentinelsInMemory(ctx, sentinel)
 	if err != nil {
 		return err
 	} else if nSentinels != rf.Spec.Sentinel.Replicas {
@@ -168,7 +193,7 @@ func (r *RedisFailoverChecker) CheckSentinelNumberInMemory(sentinel string, rf *
 // This function returns true if it all available pods have local host ip as master,
 // false if atleast one of the ip is not local hostip
 // false and error if any function fails
-func (r *RedisFailoverChecker) CheckIfMasterLocalhost(rFailover *redisfailoverv1.RedisFailover) (bool, error) {
+func (r *RedisFailoverChecker) CheckIfMasterLocalhost(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (bool, error) {
 
 	var lhmaster = 0
 	redisIps, err := r.GetRedisesIPs(rFailover)
@@ -176,14 +201,19 @@ func (r *RedisFailoverChecker) CheckIfMasterLocalhost(rFailover *redisfailoverv1
 		r.logger.Warningf("CheckIfMasterLocalhost GetRedisesIPs Failed- unable to fetch any redis Ips Currently")
 		return false, errors.New("unable to fetch any redis Ips Currently")
 	}
-	password, err := k8s.GetRedisPassword(r.k8sService, rFailover)
+	password, err := getRedisPassword(r.k8sService, rFailover)
 	if err != nil {
 		r.logger.Errorf("CheckIfMasterLocalhost -- GetRedisPassword Failed")
 		return false, err
 	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rFailover)
+	if err != nil {
+		return false, err
+	}
 	rport := getRedisPort(rFailover.Spec.Redis.Port)
 	for _, sip := range redisIps {
-		master, err := r.redisClient.GetSlaveOf(sip, rport, password)
+		master, err := redisClient.GetSlaveOf(ctx, sip, rport, password)
 		if err != nil {
 			r.logger.Warningf("CheckIfMasterLocalhost -- GetSlaveOf Failed")
 			return false, err
@@ -206,7 +236,11 @@ func (r *RedisFailoverChecker) CheckIfMasterLocalhost(rFailover *redisfailoverv1
 
 // CheckSentinelQuorum This function will call the sentinel client apis to check with sentinel if the sentinel is in a state
 // to heal the redis system
-func (r *RedisFailoverChecker) CheckSentinelQuorum(rFailover *redisfailoverv1.RedisFailover) (int, error) {
+func (r *RedisFailoverChecker) CheckSentinelQuorum(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (int, error) {
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rFailover)
+	if err != nil {
+		return 0, err
+	}
 
 	var unhealthyCnt = -1
 
@@ -223,7 +257,7 @@ func (r *RedisFailoverChecker) CheckSentinelQuorum(rFailover *redisfailoverv1.Re
 
 	unhealthyCnt = 0
 	for _, sip := range sentinels {
-		err = r.redisClient.SentinelCheckQuorum(sip)
+		err = redisClient.SentinelCheckQuorum(ctx, sip)
 		if err != nil {
 			unhealthyCnt += 1
 		} else {
@@ -239,8 +273,12 @@ func (r *RedisFailoverChecker) CheckSentinelQuorum(rFailover *redisfailoverv1.Re
 }
 
 // CheckSentinelSlavesNumberInMemory controls that the provided sentinel has only the expected slaves number.
-func (r *RedisFailoverChecker) CheckSentinelSlavesNumberInMemory(sentinel string, rf *redisfailoverv1.RedisFailover) error {
-	nSlaves, err := r.redisClient.GetNumberSentinelSlavesInMemory(sentinel)
+func (r *RedisFailoverChecker) CheckSentinelSlavesNumberInMemory(ctx context.Context, sentinel string, rf *redisfailoverv1.RedisFailover) error {
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return err
+	}
+	nSlaves, err := redisClient.GetNumberSentinelSlavesInMemory(ctx, sentinel)
 	if err != nil {
 		return err
 	} else {
@@ -258,31 +296,69 @@ func (r *RedisFailoverChecker) CheckSentinelSlavesNumberInMemory(sentinel string
 
 }
 
+// GetACLUsersChecksum returns a checksum of the ACL rules of the declared users and of the
+// operator user, which hold the hashes of their passwords
+func (r *RedisFailoverChecker) GetACLUsersChecksum(rf *redisfailoverv1.RedisFailover) (string, error) {
+	authSecret, err := r.k8sService.GetSecret(rf.Namespace, GetRedisAuthSecretName(rf))
+	if err != nil {
+		return "", err
+	}
+	users, err := getACLUsers(r.k8sService, rf, authSecret)
+	if err != nil {
+		return "", err
+	}
+	rules := make([]string, 0, len(users))
+	for name, userRules := range users {
+		rules = append(rules, fmt.Sprintf("user %s %s", name, strings.Join(userRules, " ")))
+	}
+	sort.Strings(rules)
+	return GetConfigChecksum(rules), nil
+}
+
+// GetSentinelSlavesNumberInMemory returns the number of replicas the provided sentinel knows of,
+// including the ones it sees down
+func (r *RedisFailoverChecker) GetSentinelSlavesNumberInMemory(ctx context.Context, sentinel string, rf *redisfailoverv1.RedisFailover) (int32, error) {
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return 0, err
+	}
+	return redisClient.GetNumberSentinelSlavesInMemory(ctx, sentinel)
+}
+
 // CheckSentinelMonitor controls if the sentinels are monitoring the expected master
-func (r *RedisFailoverChecker) CheckSentinelMonitor(sentinel string, monitor ...string) error {
+func (r *RedisFailoverChecker) CheckSentinelMonitor(ctx context.Context, sentinel string, rf *redisfailoverv1.RedisFailover, monitor ...string) error {
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return err
+	}
 	monitorIP := monitor[0]
 	monitorPort := ""
 	if len(monitor) > 1 {
 		monitorPort = monitor[1]
 	}
-	actualMonitorIP, actualMonitorPort, err := r.redisClient.GetSentinelMonitor(sentinel)
+	actualMonitorIP, actualMonitorPort, err := redisClient.GetSentinelMonitor(ctx, sentinel)
 	if err != nil {
 		return err
 	}
-	if actualMonitorIP != monitorIP || (monitorPort != "" && monitorPort != actualMonitorPort) {
-		return fmt.Errorf("sentinel monitoring %s:%s instead %s:%s", actualMonitorIP, actualMonitorPort, monitorIP, monitorPort)
+	if !redisfailoverv1.SameAddress(actualMonitorIP, monitorIP) || (monitorPort != "" && monitorPort != actualMonitorPort) {
+		return fmt.Errorf("sentinel monitoring %s instead %s", net.JoinHostPort(actualMonitorIP, actualMonitorPort), net.JoinHostPort(monitorIP, monitorPort))
 	}
 	return nil
 }
 
 // GetMasterIP connects to all redis and returns the master of the redis failover
-func (r *RedisFailoverChecker) GetMasterIP(rf *redisfailoverv1.RedisFailover) (string, error) {
+func (r *RedisFailoverChecker) GetMasterIP(ctx context.Context, rf *redisfailoverv1.RedisFailover) (string, error) {
 	rips, err := r.GetRedisesIPs(rf)
 	if err != nil {
 		return "", err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return "", err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
 	if err != nil {
 		return "", err
 	}
@@ -290,7 +366,7 @@ func (r *RedisFailoverChecker) GetMasterIP(rf *redisfailoverv1.RedisFailover) (s
 	var masters []string
 	rport := getRedisPort(rf.Spec.Redis.Port)
 	for _, rip := range rips {
-		master, err := r.redisClient.IsMaster(rip, rport, password)
+		master, err := redisClient.IsMaster(ctx, rip, rport, password)
 		if err != nil {
 			r.logger.Errorf("Get redis info failed, maybe this node is not ready, pod ip: %s", rip)
 			continue
@@ -307,7 +383,7 @@ func (r *RedisFailoverChecker) GetMasterIP(rf *redisfailoverv1.RedisFailover) (s
 }
 
 // GetNumberMasters returns the number of redis nodes that are working as a master
-func (r *RedisFailoverChecker) GetNumberMasters(rf *redisfailoverv1.RedisFailover) (int, error) {
+func (r *RedisFailoverChecker) GetNumberMasters(ctx context.Context, rf *redisfailoverv1.RedisFailover) (int, error) {
 	nMasters := 0
 	rips, err := r.GetRedisesIPs(rf)
 	if err != nil {
@@ -315,15 +391,20 @@ func (r *RedisFailoverChecker) GetNumberMasters(rf *redisfailoverv1.RedisFailove
 		return nMasters, err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := getRedisPassword(r.k8sService, rf)
 	if err != nil {
 		r.logger.Errorf("Error getting password: %s", err.Error())
 		return nMasters, err
 	}
 
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return nMasters, err
+	}
+
 	rport := getRedisPort(rf.Spec.Redis.Port)
 	for _, rip := range rips {
-		master, err := r.redisClient.IsMaster(rip, rport, password)
+		master, err := redisClient.IsMaster(ctx, rip, rport, password)
 		if err != nil {
 			r.logger.Errorf("Get redis info failed, maybe this node is not ready, pod ip: %s", rip)
 			continue
@@ -344,7 +425,7 @@ func (r *RedisFailoverChecker) GetRedisesIPs(rf *redisfailoverv1.RedisFailover)
 	}
 	for _, rp := range rps.Items {
 		if rp.Status.Phase == corev1.PodRunning && rp.DeletionTimestamp == nil { // Only work with running pods
-			redises = append(redises, rp.Status.PodIP)
+			redises = append(redises, rf.PodAddress(&rp))
 		}
 	}
 	return redises, nil
@@ -359,7 +440,7 @@ func (r *RedisFailoverChecker) GetSentinelsIPs(rf *redisfailoverv1.RedisFailover
 	}
 	for _, sp := range rps.Items {
 		if sp.Status.Phase == corev1.PodRunning && sp.DeletionTimestamp == nil { // Only work with running pods
-			sentinels = append(sentinels, sp.Status.PodIP)
+			sentinels = append(sentinels, rf.PodIP(&sp))
 		}
 	}
 	return sentinels, nil
@@ -378,7 +459,7 @@ func (r *RedisFailoverChecker) GetMaxRedisPodTime(rf *redisfailoverv1.RedisFailo
 		}
 		start := redisNode.Status.StartTime.Round(time.Second)
 		alive := time.Since(start)
-		r.logger.Debugf("Pod %s has been alive for %.f seconds", redisNode.Status.PodIP, alive.Seconds())
+		r.logger.Debugf("Pod %s has been alive for %.f seconds", rf.PodAddress(&redisNode), alive.Seconds())
 		if alive > maxTime {
 			maxTime = alive
 		}
@@ -387,14 +468,19 @@ func (r *RedisFailoverChecker) GetMaxRedisPodTime(rf *redisfailoverv1.RedisFailo
 }
 
 // GetRedisesSlavesPods returns pods names of the Redis secondary nodes
-func (r *RedisFailoverChecker) GetRedisesSlavesPods(rf *redisfailoverv1.RedisFailover) ([]string, error) {
+func (r *RedisFailoverChecker) GetRedisesSlavesPods(ctx context.Context, rf *redisfailoverv1.RedisFailover) ([]string, error) {
 	redises := []string{}
 	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
 		return nil, err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return redises, err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
 	if err != nil {
 		return redises, err
 	}
@@ -402,7 +488,7 @@ func (r *RedisFailoverChecker) GetRedisesSlavesPods(rf *redisfailoverv1.RedisFai
 	rport := getRedisPort(rf.Spec.Redis.Port)
 	for _, rp := range rps.Items {
 		if rp.Status.Phase == corev1.PodRunning && rp.DeletionTimestamp == nil { // Only work with running
-			master, err := r.redisClient.IsMaster(rp.Status.PodIP, rport, password)
+			master, err := redisClient.IsMaster(ctx, rf.PodAddress(&rp), rport, password)
 			if err != nil {
 				return []string{}, err
 			}
@@ -415,13 +501,18 @@ func (r *RedisFailoverChecker) GetRedisesSlavesPods(rf *redisfailoverv1.RedisFai
 }
 
 // GetRedisesMasterPod returns pods names of the Redis secondary nodes
-func (r *RedisFailoverChecker) GetRedisesMasterPod(rFailover *redisfailoverv1.RedisFailover) (string, error) {
+func (r *RedisFailoverChecker) GetRedisesMasterPod(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (string, error) {
 	rps, err := r.k8sService.GetStatefulSetPods(rFailover.Namespace, GetRedisName(rFailover))
 	if err != nil {
 		return "", err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rFailover)
+	password, err := getRedisPassword(r.k8sService, rFailover)
+	if err != nil {
+		return "", err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rFailover)
 	if err != nil {
 		return "", err
 	}
@@ -429,7 +520,7 @@ func (r *RedisFailoverChecker) GetRedisesMasterPod(rFailover *redisfailoverv1.Re
 	rport := getRedisPort(rFailover.Spec.Redis.Port)
 	for _, rp := range rps.Items {
 		if rp.Status.Phase == corev1.PodRunning && rp.DeletionTimestamp == nil { // Only work with running
-			master, err := r.redisClient.IsMaster(rp.Status.PodIP, rport, password)
+			master, err := redisClient.IsMaster(ctx, rFailover.PodAddress(&rp), rport, password)
 			if err != nil {
 				return "", err
 			}
@@ -477,14 +568,19 @@ func (r *RedisFailoverChecker) GetRedisRevisionHash(podName string, rFailover *r
 }
 
 // CheckRedisSlavesReady returns true if the slave is ready (sync, connected, etc.)
-func (r *RedisFailoverChecker) CheckRedisSlavesReady(ip string, rFailover *redisfailoverv1.RedisFailover) (bool, error) {
-	password, err := k8s.GetRedisPassword(r.k8sService, rFailover)
+func (r *RedisFailoverChecker) CheckRedisSlavesReady(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) (bool, error) {
+	password, err := getRedisPassword(r.k8sService, rFailover)
+	if err != nil {
+		return false, err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rFailover)
 	if err != nil {
 		return false, err
 	}
 
 	port := getRedisPort(rFailover.Spec.Redis.Port)
-	return r.redisClient.SlaveIsReady(ip, port, password)
+	return redisClient.SlaveIsReady(ctx, ip, port, password)
 }
 
 // IsRedisRunning returns true if all the pods are Running
@@ -506,14 +602,19 @@ func (r *RedisFailoverChecker) IsClusterRunning(rFailover *redisfailoverv1.Redis
 
 // CheckMasterHealth checks if the current master is healthy and reachable.
 // Returns (healthy, masterIP, error)
-func (r *RedisFailoverChecker) CheckMasterHealth(rf *redisfailoverv1.RedisFailover) (bool, string, error) {
-	masterIP, err := r.GetMasterIP(rf)
+func (r *RedisFailoverChecker) CheckMasterHealth(ctx context.Context, rf *redisfailoverv1.RedisFailover) (bool, string, error) {
+	masterIP, err := r.GetMasterIP(ctx, rf)
 	if err != nil {
 		// No master found
 		return false, "", nil
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return false, masterIP, err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
 	if err != nil {
 		return false, masterIP, err
 	}
@@ -521,7 +622,7 @@ func (r *RedisFailoverChecker) CheckMasterHealth(rf *redisfailoverv1.RedisFailov
 	port := getRedisPort(rf.Spec.Redis.Port)
 
 	// Check if master responds to ping
-	isMaster, err := r.redisClient.IsMaster(masterIP, port, password)
+	isMaster, err := redisClient.IsMaster(ctx, masterIP, port, password)
 	if err != nil {
 		r.logger.WithField("ip", masterIP).Warnf("Master health check failed: %v", err)
 		return false, masterIP, nil
@@ -532,8 +633,8 @@ func (r *RedisFailoverChecker) CheckMasterHealth(rf *redisfailoverv1.RedisFailov
 
 // GetBestReplicaForPromotion returns the best replica to promote as master.
 // Selection is based on replication offset (highest wins) to minimize data loss.
-func (r *RedisFailoverChecker) GetBestReplicaForPromotion(rf *redisfailoverv1.RedisFailover) (*ReplicaInfo, error) {
-	replicas, err := r.GetReplicaReplicationOffsets(rf)
+func (r *RedisFailoverChecker) GetBestReplicaForPromotion(ctx context.Context, rf *redisfailoverv1.RedisFailover) (*ReplicaInfo, error) {
+	replicas, err := r.GetReplicaReplicationOffsets(ctx, rf)
 	if err != nil {
 		return nil, err
 	}
@@ -573,13 +674,18 @@ func (r *RedisFailoverChecker) GetBestReplicaForPromotion(rf *redisfailoverv1.Re
 }
 
 // GetReplicaReplicationOffsets returns replication offset information for all replicas
-func (r *RedisFailoverChecker) GetReplicaReplicationOffsets(rf *redisfailoverv1.RedisFailover) ([]ReplicaInfo, error) {
+func (r *RedisFailoverChecker) GetReplicaReplicationOffsets(ctx context.Context, rf *redisfailoverv1.RedisFailover) ([]ReplicaInfo, error) {
 	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
 		return nil, err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
 	if err != nil {
 		return nil, err
 	}
@@ -592,9 +698,9 @@ func (r *RedisFailoverChecker) GetReplicaReplicationOffsets(rf *redisfailoverv1.
 			continue
 		}
 
-		replInfo, err := r.redisClient.GetReplicationInfo(rp.Status.PodIP, port, password)
+		replInfo, err := redisClient.GetReplicationInfo(ctx, rf.PodAddress(&rp), port, password)
 		if err != nil {
-			r.logger.WithField("ip", rp.Status.PodIP).Warnf("Failed to get replication info: %v", err)
+			r.logger.WithField("ip", rf.PodAddress(&rp)).Warnf("Failed to get replication info: %v", err)
 			continue
 		}
 
@@ -607,7 +713,7 @@ func (r *RedisFailoverChecker) GetReplicaReplicationOffsets(rf *redisfailoverv1.
 		isReady := !replInfo.SyncInProgress && replInfo.MasterLinkStatus == "up"
 
 		replicas = append(replicas, ReplicaInfo{
-			IP:                rp.Status.PodIP,
+			IP:                rf.PodAddress(&rp),
 			PodName:           rp.Name,
 			ReplicationOffset: replInfo.SlaveReplOffset,
 			IsReady:           isReady,
@@ -617,10 +723,113 @@ func (r *RedisFailoverChecker) GetReplicaReplicationOffsets(rf *redisfailoverv1.
 	return replicas, nil
 }
 
+// GetRedisesReplication returns the replication state of every running Redis pod.
+// Pods that can't be queried are skipped.
+func (r *RedisFailoverChecker) GetRedisesReplication(ctx context.Context, rf *redisfailoverv1.RedisFailover) ([]RedisNodeReplication, error) {
+	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return nil, err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	nodes := []RedisNodeReplication{}
+	for _, rp := range rps.Items {
+		if rp.Status.Phase != corev1.PodRunning || rp.DeletionTimestamp != nil {
+			continue
+		}
+
+		replInfo, err := redisClient.GetReplicationInfo(ctx, rf.PodAddress(&rp), port, password)
+		if err != nil {
+			r.logger.WithField("ip", rf.PodAddress(&rp)).Warnf("Failed to get replication info: %v", err)
+			continue
+		}
+
+		version, err := redisClient.GetRedisVersion(ctx, rf.PodAddress(&rp), port, password)
+		if err != nil {
+			r.logger.WithField("ip", rf.PodAddress(&rp)).Warnf("Failed to get redis version: %v", err)
+		}
+
+		nodes = append(nodes, RedisNodeReplication{
+			PodName:      rp.Name,
+			IP:           rf.PodAddress(&rp),
+			RedisVersion: version,
+			Replication:  replInfo,
+		})
+	}
+
+	return nodes, nil
+}
+
 func getRedisPort(p int32) string {
 	return strconv.Itoa(int(p))
 }
 
+// getRedisClient returns the redis client configured with the connection settings of the RedisFailover,
+// authenticating as the operator user when the ACL users are managed
+func getRedisClient(k8sService k8s.Services, redisClient redis.Client, rf *redisfailoverv1.RedisFailover) (redis.Client, error) {
+	username := ""
+	if rf.ACLEnabled() {
+		username = redisfailoverv1.OperatorUserName
+	}
+	return getRedisClientAs(k8sService, redisClient, rf, username)
+}
+
+// getRedisClientAs returns the redis client configured with the connection settings of the RedisFailover,
+// authenticating as the given user or the default one when empty
+func getRedisClientAs(k8sService k8s.Services, redisClient redis.Client, rf *redisfailoverv1.RedisFailover, username string) (redis.Client, error) {
+	tlsConfig, err := k8s.GetRedisTLSConfig(k8sService, rf, GetTLSSecretName(rf))
+	if err != nil {
+		return nil, err
+	}
+	options := redis.ConnectionOptions{TLSConfig: tlsConfig, Username: username, MasterName: rf.Spec.Sentinel.MasterName}
+	if rf.Spec.Sentinel.Port > 0 {
+		options.SentinelPort = strconv.Itoa(int(rf.Spec.Sentinel.Port))
+	}
+	if len(rf.Spec.Redis.CustomCommandRenames) > 0 {
+		options.CommandRenames = getCommandRenames(rf)
+	}
+	if options.TLSConfig == nil && options.Username == "" && options.MasterName == "" && options.SentinelPort == "" && options.CommandRenames == nil {
+		return redisClient, nil
+	}
+	return redisClient.WithOptions(options), nil
+}
+
+// getCommandRenames returns the names the commands renamed by customCommandRenames are sent
+// under, keyed by their upper case name
+func getCommandRenames(rf *redisfailoverv1.RedisFailover) map[string]string {
+	renames := map[string]string{}
+	for _, rename := range rf.Spec.Redis.CustomCommandRenames {
+		renames[strings.ToUpper(rename.From)] = rename.To
+	}
+	return renames
+}
+
+// getRedisPassword returns the password the operator authenticates with, the one of the operator
+// user when the ACL users are managed
+func getRedisPassword(k8sService k8s.Services, rf *redisfailoverv1.RedisFailover) (string, error) {
+	if !rf.ACLEnabled() {
+		return getAppliedRedisPassword(k8sService, rf)
+	}
+	secret, err := k8sService.GetSecret(rf.Namespace, GetRedisAuthSecretName(rf))
+	if err != nil {
+		return "", err
+	}
+	if password, ok := secret.Data[operatorPasswordKey]; ok {
+		return string(password), nil
+	}
+	return "", fmt.Errorf("secret \"%s\" does not have a %s field", GetRedisAuthSecretName(rf), operatorPasswordKey)
+}
+
 func AreAllRunning(pods *corev1.PodList, expectedRunningPods int) bool {
 	var runningPods int
 	for _, pod := range pods.Items {
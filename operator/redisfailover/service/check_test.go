@@ -1,7 +1,15 @@
 package service_test
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
+	"math/big"
 	"testing"
 	"time"
 
@@ -18,6 +26,7 @@ import (
 	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
 	mRedisService "github.com/saremox/redis-operator/mocks/service/redis"
 	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+	"github.com/saremox/redis-operator/service/redis"
 )
 
 func generateRF() *redisfailoverv1.RedisFailover {
@@ -163,7 +172,7 @@ func TestCheckAllSlavesFromMasterGetStatefulSetError(t *testing.T) {
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckAllSlavesFromMaster("", rf)
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "", rf)
 	assert.Error(err)
 }
 
@@ -187,11 +196,11 @@ func TestCheckAllSlavesFromMasterGetSlaveOfError(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("GetSlaveOf", "", "0", "").Once().Return("", errors.New(""))
+	mr.On("GetSlaveOf", mock.Anything, "", "0", "").Once().Return("", errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckAllSlavesFromMaster("", rf)
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "", rf)
 	assert.Error(err)
 }
 
@@ -215,11 +224,11 @@ func TestCheckAllSlavesFromMasterDifferentMaster(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("GetSlaveOf", "0.0.0.0", "0", "").Once().Return("1.1.1.1", nil)
+	mr.On("GetSlaveOf", mock.Anything, "0.0.0.0", "0", "").Once().Return("1.1.1.1", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckAllSlavesFromMaster("0.0.0.0", rf)
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "0.0.0.0", rf)
 	assert.Error(err)
 }
 
@@ -243,11 +252,73 @@ func TestCheckAllSlavesFromMaster(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("GetSlaveOf", "0.0.0.0", "0", "").Once().Return("1.1.1.1", nil)
+	mr.On("GetSlaveOf", mock.Anything, "0.0.0.0", "0", "").Once().Return("1.1.1.1", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckAllSlavesFromMaster("1.1.1.1", rf)
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "1.1.1.1", rf)
+	assert.NoError(err)
+}
+
+func TestCheckAllSlavesFromMasterIPv6(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.IPFamily = corev1.IPv6Protocol
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				Status: corev1.PodStatus{
+					PodIP:  "10.0.0.1",
+					PodIPs: []corev1.PodIP{{IP: "10.0.0.1"}, {IP: "fd00::1"}},
+					Phase:  corev1.PodRunning,
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetSlaveOf", mock.Anything, "fd00::1", "0", "").Once().Return("fd00::2", nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "fd00::2", rf)
+	assert.NoError(err)
+}
+
+func TestCheckAllSlavesFromMasterAnnounceHostnames(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.AnnounceHostnames = true
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "redis-1", Namespace: namespace},
+				Spec:       corev1.PodSpec{Hostname: "redis-1", Subdomain: "redis"},
+				Status: corev1.PodStatus{
+					PodIP: "0.0.0.0",
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
+	mr := &mRedisService.Client{}
+	// The replica reports the name of the master qualified with the cluster domain
+	mr.On("GetSlaveOf", mock.Anything, "redis-1.redis.testns.svc", "0", "").Once().Return("redis-0.redis.testns.svc.cluster.local", nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	err := checker.CheckAllSlavesFromMaster(context.TODO(), "redis-0.redis.testns.svc", rf)
 	assert.NoError(err)
 }
 
@@ -258,11 +329,11 @@ func TestCheckSentinelNumberInMemoryGetDeploymentPodsError(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelsInMemory", "1.1.1.1").Once().Return(int32(0), errors.New("expected error"))
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1").Once().Return(int32(0), errors.New("expected error"))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -273,11 +344,11 @@ func TestCheckSentinelNumberInMemoryGetNumberSentinelInMemoryError(t *testing.T)
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelsInMemory", "1.1.1.1").Once().Return(int32(0), errors.New(""))
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1").Once().Return(int32(0), errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -288,11 +359,11 @@ func TestCheckSentinelNumberInMemoryNumberMismatch(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelsInMemory", "1.1.1.1").Once().Return(int32(4), nil)
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1").Once().Return(int32(4), nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -303,11 +374,11 @@ func TestCheckSentinelNumberInMemory(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelsInMemory", "1.1.1.1").Once().Return(int32(3), nil)
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1").Once().Return(int32(3), nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.NoError(err)
 }
 
@@ -318,11 +389,11 @@ func TestCheckSentinelSlavesNumberInMemoryGetNumberSentinelSlavesInMemoryError(t
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelSlavesInMemory", "1.1.1.1").Once().Return(int32(0), errors.New(""))
+	mr.On("GetNumberSentinelSlavesInMemory", mock.Anything, "1.1.1.1").Once().Return(int32(0), errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelSlavesNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelSlavesNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -333,11 +404,11 @@ func TestCheckSentinelSlavesNumberInMemoryReplicasMismatch(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelSlavesInMemory", "1.1.1.1").Once().Return(int32(3), nil)
+	mr.On("GetNumberSentinelSlavesInMemory", mock.Anything, "1.1.1.1").Once().Return(int32(3), nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelSlavesNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelSlavesNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -349,89 +420,149 @@ func TestCheckSentinelSlavesNumberInMemory(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelSlavesInMemory", "1.1.1.1").Once().Return(int32(4), nil)
+	mr.On("GetNumberSentinelSlavesInMemory", mock.Anything, "1.1.1.1").Once().Return(int32(4), nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelSlavesNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelSlavesNumberInMemory(context.TODO(), "1.1.1.1", rf)
 	assert.NoError(err)
 }
 
+func TestGetACLUsersChecksum(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Auth.Users = []redisfailoverv1.RedisUser{{Name: "app", SecretPath: "app-secret", Commands: []string{"+@read"}}}
+	authSecret := &corev1.Secret{Data: map[string][]byte{"operator-password": []byte("operator")}}
+
+	checksum := func(password string) string {
+		ms := &mK8SService.Services{}
+		ms.On("GetSecret", namespace, "rfauth-"+name).Once().Return(authSecret, nil)
+		ms.On("GetSecret", namespace, "app-secret").Once().Return(&corev1.Secret{Data: map[string][]byte{"password": []byte(password)}}, nil)
+		checker := rfservice.NewRedisFailoverChecker(ms, &mRedisService.Client{}, log.DummyLogger{}, metrics.Dummy)
+		checksum, err := checker.GetACLUsersChecksum(rf)
+		assert.NoError(err)
+		ms.AssertExpectations(t)
+		return checksum
+	}
+
+	first := checksum("secret")
+	assert.NotEmpty(first)
+	assert.Equal(first, checksum("secret"))
+	// A new password changes the rules of the user, its hash being part of them
+	assert.NotEqual(first, checksum("rotated"))
+	rf.Spec.Auth.Users[0].Commands = []string{"+@write"}
+	assert.NotEqual(first, checksum("secret"))
+}
+
 func TestCheckSentinelMonitorGetSentinelMonitorError(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("", "", errors.New(""))
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0").Once().Return("", "", errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "1.1.1.1")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1")
 	assert.Error(err)
 }
 
 func TestCheckSentinelMonitorMismatch(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("2.2.2.2", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0").Once().Return("2.2.2.2", "6379", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "1.1.1.1")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1")
 	assert.Error(err)
 }
 
 func TestCheckSentinelMonitor(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "1.1.1.1")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1")
 	assert.NoError(err)
 }
 
+func TestCheckSentinelMonitorMasterNameAndPort(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Sentinel.MasterName = "cache"
+	rf.Spec.Sentinel.Port = 26380
+
+	ms := &mK8SService.Services{}
+	mrSentinel := &mRedisService.Client{}
+	mrSentinel.On("GetSentinelMonitor", mock.Anything, "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+	mr := &mRedisService.Client{}
+	mr.On("WithOptions", redis.ConnectionOptions{MasterName: "cache", SentinelPort: "26380"}).Once().Return(mrSentinel)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1")
+	assert.NoError(err)
+	mr.AssertExpectations(t)
+	mrSentinel.AssertExpectations(t)
+}
+
 func TestCheckSentinelMonitorWithPort(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "1.1.1.1", "6379")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1", "6379")
 	assert.NoError(err)
 }
 
 func TestCheckSentinelMonitorWithPortMismatch(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "0.0.0.0", "6379")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "0.0.0.0", "6379")
 	assert.Error(err)
 }
 
 func TestCheckSentinelMonitorWithPortIPMismatch(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "1.1.1.1", "6380")
+	err := checker.CheckSentinelMonitor(context.TODO(), "0.0.0.0", rf, "1.1.1.1", "6380")
 	assert.Error(err)
 }
 
@@ -446,7 +577,7 @@ func TestGetMasterIPGetStatefulSetPodsError(t *testing.T) {
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetMasterIP(rf)
+	_, err := checker.GetMasterIP(context.TODO(), rf)
 	assert.Error(err)
 }
 
@@ -469,11 +600,11 @@ func TestGetMasterIPIsMasterError(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(false, errors.New(""))
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(false, errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetMasterIP(rf)
+	_, err := checker.GetMasterIP(context.TODO(), rf)
 	assert.Error(err)
 }
 
@@ -502,12 +633,12 @@ func TestGetMasterIPMultipleMastersError(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(true, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetMasterIP(rf)
+	_, err := checker.GetMasterIP(context.TODO(), rf)
 	assert.Error(err)
 }
 
@@ -536,12 +667,12 @@ func TestGetMasterIP(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(false, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	master, err := checker.GetMasterIP(rf)
+	master, err := checker.GetMasterIP(context.TODO(), rf)
 	assert.NoError(err)
 	assert.Equal("0.0.0.0", master, "the master should be the expected")
 }
@@ -557,7 +688,7 @@ func TestGetNumberMastersGetStatefulSetPodsError(t *testing.T) {
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetNumberMasters(rf)
+	_, err := checker.GetNumberMasters(context.TODO(), rf)
 	assert.Error(err)
 }
 
@@ -580,11 +711,11 @@ func TestGetNumberMastersIsMasterError(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, errors.New(""))
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(true, errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetNumberMasters(rf)
+	_, err := checker.GetNumberMasters(context.TODO(), rf)
 	assert.NoError(err)
 }
 
@@ -613,12 +744,12 @@ func TestGetNumberMasters(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(false, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	masterNumber, err := checker.GetNumberMasters(rf)
+	masterNumber, err := checker.GetNumberMasters(context.TODO(), rf)
 	assert.NoError(err)
 	assert.Equal(1, masterNumber, "the master number should be ok")
 }
@@ -648,12 +779,12 @@ func TestGetNumberMastersTwo(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(true, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	masterNumber, err := checker.GetNumberMasters(rf)
+	masterNumber, err := checker.GetNumberMasters(context.TODO(), rf)
 	assert.NoError(err)
 	assert.Equal(2, masterNumber, "the master number should be ok")
 }
@@ -753,21 +884,21 @@ func TestGetRedisPodsNames(t *testing.T) {
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Twice().Return(false, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Twice().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(true, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
-	master, err := checker.GetRedisesMasterPod(rf)
+	master, err := checker.GetRedisesMasterPod(context.TODO(), rf)
 
 	assert.NoError(err)
 
 	assert.Equal(master, "master")
 
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
-	mr.On("IsMaster", "0.0.0.0", "0", "").Twice().Return(false, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Twice().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(true, nil)
 
-	namePods, err := checker.GetRedisesSlavesPods(rf)
+	namePods, err := checker.GetRedisesSlavesPods(context.TODO(), rf)
 
 	assert.NoError(err)
 
@@ -956,3 +1087,197 @@ func TestClusterRunning(t *testing.T) {
 	assert.False(checker.IsClusterRunning(rf))
 
 }
+
+func TestGetRedisesReplication(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-0"},
+				Status: corev1.PodStatus{
+					PodIP: "0.0.0.0",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-1"},
+				Status: corev1.PodStatus{
+					PodIP: "1.1.1.1",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-2"},
+				Status: corev1.PodStatus{
+					PodIP: "2.2.2.2",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-3"},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+				},
+			},
+		},
+	}
+
+	masterInfo := &redis.ReplicationInfo{Role: "master", MasterReplOffset: 100}
+	replicaInfo := &redis.ReplicationInfo{Role: "slave", MasterLinkStatus: "up", SlaveReplOffset: 90}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetReplicationInfo", mock.Anything, "0.0.0.0", "0", "").Once().Return(masterInfo, nil)
+	mr.On("GetRedisVersion", mock.Anything, "0.0.0.0", "0", "").Once().Return("7.2.12", nil)
+	mr.On("GetReplicationInfo", mock.Anything, "1.1.1.1", "0", "").Once().Return(replicaInfo, nil)
+	mr.On("GetRedisVersion", mock.Anything, "1.1.1.1", "0", "").Once().Return("", errors.New(""))
+	mr.On("GetReplicationInfo", mock.Anything, "2.2.2.2", "0", "").Once().Return(nil, errors.New(""))
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	nodes, err := checker.GetRedisesReplication(context.TODO(), rf)
+	assert.NoError(err)
+	assert.Equal([]rfservice.RedisNodeReplication{
+		{PodName: "rfr-test-0", IP: "0.0.0.0", RedisVersion: "7.2.12", Replication: masterInfo},
+		{PodName: "rfr-test-1", IP: "1.1.1.1", Replication: replicaInfo},
+	}, nodes)
+	mr.AssertExpectations(t)
+}
+
+func TestGetNumberMastersWithTLS(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.TLS = &redisfailoverv1.TLSSettings{SecretName: "redis-tls"}
+
+	// Self-signed certificate used as both the redis certificate and the CA
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "redis"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"tls.crt": certPEM,
+			"tls.key": pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+			"ca.crt":  certPEM,
+		},
+	}
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				Status: corev1.PodStatus{
+					PodIP: "0.0.0.0",
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("GetSecret", namespace, "redis-tls").Once().Return(secret, nil)
+	mrTLS := &mRedisService.Client{}
+	mrTLS.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(true, nil)
+	mr := &mRedisService.Client{}
+	mr.On("WithOptions", mock.MatchedBy(func(o redis.ConnectionOptions) bool {
+		return o.TLSConfig != nil && len(o.TLSConfig.Certificates) == 1
+	})).Once().Return(mrTLS)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	masterNumber, err := checker.GetNumberMasters(context.TODO(), rf)
+	assert.NoError(err)
+	assert.Equal(1, masterNumber)
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
+	mrTLS.AssertExpectations(t)
+}
+
+func TestGetRedisConfigDrift(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   []string
+		current  map[string]string
+		expDrift []string
+	}{
+		{
+			name:     "Values reported in another form",
+			config:   []string{"maxmemory 100mb", "appendonly Yes", "notify-keyspace-events KEA", `dbfilename "dump.rdb"`, "client-output-buffer-limit replica 256mb 64mb 60"},
+			current:  map[string]string{"maxmemory": "104857600", "appendonly": "yes", "notify-keyspace-events": "AKE", "dbfilename": "dump.rdb", "client-output-buffer-limit": "normal 0 0 0 slave 268435456 67108864 60 pubsub 33554432 8388608 60"},
+			expDrift: []string{},
+		},
+		{
+			name:     "Drifted parameters",
+			config:   []string{"maxclients 100", "timeout 300", "client-output-buffer-limit pubsub 64mb 16mb 60"},
+			current:  map[string]string{"maxclients": "200", "timeout": "300", "client-output-buffer-limit": "normal 0 0 0 slave 268435456 67108864 60 pubsub 33554432 8388608 60"},
+			expDrift: []string{"maxclients", "client-output-buffer-limit pubsub"},
+		},
+		{
+			name:     "Parameter unknown to the redis node",
+			config:   []string{"maxclients 100"},
+			current:  map[string]string{},
+			expDrift: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Redis.CustomConfig = test.config
+
+			ms := &mK8SService.Services{}
+			mr := &mRedisService.Client{}
+			mr.On("GetRedisConfig", mock.Anything, "0.0.0.0", "0", "", mock.Anything).Once().Return(test.current, nil)
+
+			checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+			drift, err := checker.GetRedisConfigDrift(context.TODO(), "0.0.0.0", rf)
+			assert.NoError(err)
+			assert.Equal(test.expDrift, drift)
+			mr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetRedisConfigDriftTemplateConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.AnnounceHostnames = true
+	rf.Spec.Auth.SecretPath = "app-secret"
+	address := "rfr-test-0.rfr-test.testns.svc"
+
+	ms := &mK8SService.Services{}
+	ms.On("GetSecret", "testns", rfservice.GetRedisAuthSecretName(rf)).Return(&corev1.Secret{
+		Data: map[string][]byte{"password": []byte("Secret")},
+	}, nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetRedisConfig", mock.Anything, address, "0", "Secret", []string{"tcp-keepalive", "save", "replica-announce-ip", "masterauth"}).Once().Return(map[string]string{
+		"tcp-keepalive":       "300",
+		"save":                "900 1 300 10",
+		"replica-announce-ip": address,
+		// The passwords are compared as they are
+		"masterauth": "secret",
+	}, nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+	drift, err := checker.GetRedisConfigDrift(context.TODO(), address, rf)
+	assert.NoError(err)
+	assert.Equal([]string{"tcp-keepalive", "masterauth"}, drift)
+	mr.AssertExpectations(t)
+}
@@ -1,6 +1,12 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -26,6 +32,11 @@ type RedisFailoverClient interface {
 	EnsureRedisConfigMap(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
 	EnsureNotPresentRedisService(rFailover *redisfailoverv1.RedisFailover) error
 	EnsureNotPresentSentinelResources(rFailover *redisfailoverv1.RedisFailover) error
+	EnsureTLSSecrets(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureRedisAuthSecret(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureRedisRestoreSecret(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	GetRedisPasswordState(rFailover *redisfailoverv1.RedisFailover) (*RedisPasswordState, error)
+	SetRedisPasswordState(rFailover *redisfailoverv1.RedisFailover, state *RedisPasswordState) error
 }
 
 // RedisFailoverKubeClient implements the required methods to talk with kubernetes
@@ -33,14 +44,18 @@ type RedisFailoverKubeClient struct {
 	K8SService    k8s.Services
 	logger        log.Logger
 	metricsClient metrics.Recorder
+	// clusterDomain is the DNS domain of the cluster the certificates generated by the operator
+	// are issued for
+	clusterDomain string
 }
 
 // NewRedisFailoverKubeClient creates a new RedisFailoverKubeClient
-func NewRedisFailoverKubeClient(k8sService k8s.Services, logger log.Logger, metricsClient metrics.Recorder) *RedisFailoverKubeClient {
+func NewRedisFailoverKubeClient(k8sService k8s.Services, logger log.Logger, metricsClient metrics.Recorder, clusterDomain string) *RedisFailoverKubeClient {
 	return &RedisFailoverKubeClient{
 		K8SService:    k8sService,
 		logger:        logger,
 		metricsClient: metricsClient,
+		clusterDomain: clusterDomain,
 	}
 }
 
@@ -92,6 +107,9 @@ func (r *RedisFailoverKubeClient) EnsureSentinelDeployment(rf *redisfailoverv1.R
 		}
 	}
 	d := generateSentinelDeployment(rf, labels, ownerRefs)
+	if err := r.setTLSChecksum(rf, &d.Spec.Template); err != nil {
+		return err
+	}
 	err := r.K8SService.CreateOrUpdateDeployment(rf.Namespace, d)
 
 	r.setEnsureOperationMetrics(d.Namespace, d.Name, "Deployment", rf.Name, err)
@@ -106,6 +124,9 @@ func (r *RedisFailoverKubeClient) EnsureRedisStatefulset(rf *redisfailoverv1.Red
 		}
 	}
 	ss := generateRedisStatefulSet(rf, labels, ownerRefs)
+	if err := r.setTLSChecksum(rf, &ss.Spec.Template); err != nil {
+		return err
+	}
 	err := r.K8SService.CreateOrUpdateStatefulSet(rf.Namespace, ss)
 
 	r.setEnsureOperationMetrics(ss.Namespace, ss.Name, "StatefulSet", rf.Name, err)
@@ -114,14 +135,8 @@ func (r *RedisFailoverKubeClient) EnsureRedisStatefulset(rf *redisfailoverv1.Red
 
 // EnsureRedisConfigMap makes sure the Redis ConfigMap exists
 func (r *RedisFailoverKubeClient) EnsureRedisConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-
-	password, err := k8s.GetRedisPassword(r.K8SService, rf)
-	if err != nil {
-		return err
-	}
-
-	cm := generateRedisConfigMap(rf, labels, ownerRefs, password)
-	err = r.K8SService.CreateOrUpdateConfigMap(rf.Namespace, cm)
+	cm := generateRedisConfigMap(rf, labels, ownerRefs)
+	err := r.K8SService.CreateOrUpdateConfigMap(rf.Namespace, cm)
 
 	r.setEnsureOperationMetrics(cm.Namespace, cm.Name, "ConfigMap", rf.Name, err)
 	return err
@@ -229,6 +244,133 @@ func (r *RedisFailoverKubeClient) EnsureRedisSlaveService(rf *redisfailoverv1.Re
 	return err
 }
 
+// EnsureTLSSecrets makes sure the CA and the certificate generated by the operator exist and
+// renews them before they expire
+func (r *RedisFailoverKubeClient) EnsureTLSSecrets(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	caSecret, err := r.getSecretIfPresent(rf.Namespace, GetTLSCASecretName(rf))
+	if err != nil {
+		return err
+	}
+	secret, err := r.getSecretIfPresent(rf.Namespace, GetTLSSecretName(rf))
+	if err != nil {
+		return err
+	}
+	// The secrets created by the users or by another redis failover are never overwritten
+	for _, s := range []*corev1.Secret{caSecret, secret} {
+		if s != nil && !isOwnedBy(s, rf) {
+			return fmt.Errorf("secret \"%s\" is not owned by the redis failover, tls.managed can't be used with it", s.Name)
+		}
+	}
+
+	newCASecret, newSecret, err := renewTLSSecrets(rf, r.clusterDomain, labels, ownerRefs, caSecret, secret, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, s := range []*corev1.Secret{newCASecret, newSecret} {
+		if s == nil {
+			continue
+		}
+		r.logger.WithField("namespace", s.Namespace).WithField("secret", s.Name).Infof("issuing TLS certificate")
+		err := r.K8SService.CreateOrUpdateSecret(rf.Namespace, s)
+		r.setEnsureOperationMetrics(s.Namespace, s.Name, "Secret", rf.Name, err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnsureRedisAuthSecret makes sure the secret holding the passwords of the operator and probe
+// users exists, generating the missing ones
+func (r *RedisFailoverKubeClient) EnsureRedisAuthSecret(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	current, err := r.getSecretIfPresent(rf.Namespace, GetRedisAuthSecretName(rf))
+	if err != nil {
+		return err
+	}
+	password, err := k8s.GetRedisPassword(r.K8SService, rf)
+	if err != nil {
+		return err
+	}
+	secret, err := generateRedisAuthSecret(rf, labels, ownerRefs, current, password)
+	if err != nil {
+		return err
+	}
+	if err := r.setRedisAuthConfig(rf, secret); err != nil {
+		return err
+	}
+	if isRedisAuthSecretUpToDate(current, secret) {
+		return nil
+	}
+	err = r.K8SService.CreateOrUpdateSecret(rf.Namespace, secret)
+	r.setEnsureOperationMetrics(secret.Namespace, secret.Name, "Secret", rf.Name, err)
+	return err
+}
+
+// GetRedisPasswordState returns the password applied on the redis nodes and the one of the auth secret
+func (r *RedisFailoverKubeClient) GetRedisPasswordState(rf *redisfailoverv1.RedisFailover) (*RedisPasswordState, error) {
+	authSecret, err := r.K8SService.GetSecret(rf.Namespace, GetRedisAuthSecretName(rf))
+	if err != nil {
+		return nil, err
+	}
+	desired, err := k8s.GetRedisPassword(r.K8SService, rf)
+	if err != nil {
+		return nil, err
+	}
+	return getRedisPasswordState(rf, authSecret, desired), nil
+}
+
+// SetRedisPasswordState records the password applied on the redis nodes and the rotation in progress
+func (r *RedisFailoverKubeClient) SetRedisPasswordState(rf *redisfailoverv1.RedisFailover, state *RedisPasswordState) error {
+	authSecret, err := r.K8SService.GetSecret(rf.Namespace, GetRedisAuthSecretName(rf))
+	if err != nil {
+		return err
+	}
+	authSecret = setRedisPasswordState(authSecret, state)
+	// The nodes restarted during the rotation start with the password just applied
+	if err := r.setRedisAuthConfig(rf, authSecret); err != nil {
+		return err
+	}
+	return r.K8SService.UpdateSecret(rf.Namespace, authSecret)
+}
+
+// setRedisAuthConfig writes the redis configuration holding the credentials to the operator
+// secret: the ACL users, the probe user and the applied password of the default user, along with
+// the previous one during a rotation
+func (r *RedisFailoverKubeClient) setRedisAuthConfig(rf *redisfailoverv1.RedisFailover, authSecret *corev1.Secret) error {
+	users, err := getACLUsers(r.K8SService, rf, authSecret)
+	if err != nil {
+		return err
+	}
+	users[redisfailoverv1.ProbeUserName] = getProbeUserRules(authSecret)
+	authSecret.Data[redisAuthConfigKey] = []byte(generateRedisAuthConfig(string(authSecret.Data[passwordKey]), string(authSecret.Data[previousPasswordKey]), users))
+	return nil
+}
+
+// getSecretIfPresent returns the secret, or nil if it does not exist
+func (r *RedisFailoverKubeClient) getSecretIfPresent(namespace, name string) (*corev1.Secret, error) {
+	secret, err := r.K8SService.GetSecret(namespace, name)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	return secret, err
+}
+
+// setTLSChecksum annotates the pod template with the checksum of the certificates, so the pods
+// are rolled when they are renewed
+func (r *RedisFailoverKubeClient) setTLSChecksum(rf *redisfailoverv1.RedisFailover, template *corev1.PodTemplateSpec) error {
+	if !rf.TLSEnabled() {
+		return nil
+	}
+	secret, err := r.K8SService.GetSecret(rf.Namespace, GetTLSSecretName(rf))
+	if err != nil {
+		return err
+	}
+	template.Annotations = util.MergeAnnotations(template.Annotations, map[string]string{
+		tlsChecksumAnnotationKey: getTLSChecksum(secret),
+	})
+	return nil
+}
+
 // ensurePodDisruptionBudget creates or updates a PDB for the given component.
 // replicas must be the replica count of the component being protected (not a different component).
 func (r *RedisFailoverKubeClient) ensurePodDisruptionBudget(rf *redisfailoverv1.RedisFailover, name string, component string, labels map[string]string, ownerRefs []metav1.OwnerReference, replicas int32) error {
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/service/k8s"
+)
+
+// memoryValueRE matches the values given with a memory unit, which CONFIG GET reports in bytes
+var memoryValueRE = regexp.MustCompile(`^(\d+)(k|kb|m|mb|g|gb)$`)
+
+// memoryUnits are the multipliers of the memory units accepted by redis
+var memoryUnits = map[string]int64{
+	"k":  1000,
+	"kb": 1024,
+	"m":  1000 * 1000,
+	"mb": 1024 * 1024,
+	"g":  1000 * 1000 * 1000,
+	"gb": 1024 * 1024 * 1024,
+}
+
+// getManagedRedisConfig returns the redis configuration managed by the operator on the redis node
+// at the address: the parameters the configuration template and the pod arguments set, which
+// RedisConfig can override, then RedisConfig.
+func getManagedRedisConfig(k8sService k8s.Services, rf *redisfailoverv1.RedisFailover, address string) ([]string, error) {
+	config := []string{"tcp-keepalive 60"}
+	if rf.Spec.Redis.Persistence == nil {
+		config = append(config, "save 900 1 300 10")
+	}
+	if rf.Spec.AnnounceHostnames && redisfailoverv1.IPFamilyOf(address) == "" {
+		config = append(config, fmt.Sprintf("replica-announce-ip %s", address))
+	}
+	masterauth, err := getAppliedRedisPassword(k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+	if masterauth != "" {
+		config = append(config, fmt.Sprintf("masterauth %s", masterauth))
+	}
+	return append(config, rf.RedisConfig()...), nil
+}
+
+// GetRedisConfigDrift returns the managed configuration parameters whose value on the redis node
+// differs from the declared one. The parameters unknown to the redis node are not checked.
+func (r *RedisFailoverChecker) GetRedisConfigDrift(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) ([]string, error) {
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return nil, err
+	}
+	config, err := getManagedRedisConfig(r.k8sService, rf, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := map[string]string{}
+	parameters := []string{}
+	for _, config := range config {
+		fields := strings.Fields(config)
+		if len(fields) == 0 {
+			continue
+		}
+		parameter := strings.ToLower(fields[0])
+		value := strings.Join(fields[1:], " ")
+		key := parameter
+		if parameter == "client-output-buffer-limit" && value != "" {
+			// Every class of clients is set on its own
+			key = parameter + " " + strings.ToLower(fields[1])
+		}
+		if _, ok := desired[key]; !ok {
+			parameters = append(parameters, key)
+		}
+		desired[key] = value
+	}
+	if len(parameters) == 0 {
+		return nil, nil
+	}
+
+	names := []string{}
+	for _, key := range parameters {
+		names = append(names, strings.Fields(key)[0])
+	}
+	port := getRedisPort(rf.Spec.Redis.Port)
+	current, err := redisClient.GetRedisConfig(ctx, ip, port, password, deduplicate(names))
+	if err != nil {
+		return nil, err
+	}
+
+	drift := []string{}
+	for _, key := range parameters {
+		parameter := strings.Fields(key)[0]
+		value, ok := current[parameter]
+		if !ok {
+			continue
+		}
+		if !redisConfigValueEqual(parameter, desired[key], value) {
+			drift = append(drift, key)
+		}
+	}
+	return drift, nil
+}
+
+// normalizeRedisConfigValue returns the value in the form CONFIG GET reports it: unquoted, lower
+// case, with single spaces and the memory in bytes
+func normalizeRedisConfigValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+	fields := strings.Fields(strings.ToLower(value))
+	for i, field := range fields {
+		match := memoryValueRE.FindStringSubmatch(field)
+		if match == nil {
+			continue
+		}
+		n, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		fields[i] = strconv.FormatInt(n*memoryUnits[match[2]], 10)
+	}
+	return strings.Join(fields, " ")
+}
+
+// redisConfigValueEqual returns true when the values of the parameter are equal once normalized.
+// The passwords are compared as they are.
+func redisConfigValueEqual(parameter, desired, current string) bool {
+	if parameter == "masterauth" {
+		return desired == current
+	}
+	desired = normalizeRedisConfigValue(desired)
+	current = normalizeRedisConfigValue(current)
+	switch parameter {
+	case "client-output-buffer-limit":
+		// Every class of clients is reported at once, the replica one as slave
+		desired = strings.Replace(desired, "replica ", "slave ", 1)
+		return strings.Contains(" "+current+" ", " "+desired+" ")
+	case "notify-keyspace-events":
+		// The flags are reported in another order
+		return sortedChars(desired) == sortedChars(current)
+	}
+	return desired == current
+}
+
+func sortedChars(value string) string {
+	chars := strings.Split(value, "")
+	sort.Strings(chars)
+	return strings.Join(chars, "")
+}
+
+func deduplicate(values []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -1,5 +1,7 @@
 package service
 
+import "time"
+
 // variables refering to the redis exporter port
 const (
 	exporterPort                  = 9121
@@ -29,8 +31,68 @@ const (
 	hostnameTopologyKey    = "kubernetes.io/hostname"
 )
 
+// variables refering to the TLS certificates mounted in the redis and sentinel pods
+const (
+	tlsVolumeName     = "redis-tls"
+	tlsMountPath      = "/tls"
+	tlsCertFileName   = "tls.crt"
+	tlsKeyFileName    = "tls.key"
+	tlsCACertFileName = "ca.crt"
+	tlsCAName         = "ca"
+	tlsName           = "tls"
+	// tlsChecksumAnnotationKey rolls the pods when the certificates change, as they are only read at startup
+	tlsChecksumAnnotationKey = "redisfailovers.databases.spotahome.com/tls-checksum"
+)
+
+// configChecksumAnnotationKey rolls the redis pods when the custom configuration only read at
+// startup changes
+const configChecksumAnnotationKey = "redisfailovers.databases.spotahome.com/config-checksum"
+
+// sentinelHostnamesConfig has the sentinels resolve and announce the names of the redis nodes
+var sentinelHostnamesConfig = []string{"resolve-hostnames yes", "announce-hostnames yes"}
+
+// variables refering to the passwords of the ACL users managed by the operator
+const (
+	redisAuthName          = "auth"
+	operatorPasswordKey    = "operator-password"
+	probePasswordKey       = "probe-password"
+	probePasswordEnvName   = "REDIS_PROBE_PASSWORD"
+	podNameEnvName         = "POD_NAME"
+	generatedPasswordBytes = 32
+)
+
+// variables refering to the password of the default user applied on the redis nodes, which is
+// kept along with the previous one while it is rotated
+const (
+	passwordKey                    = "password"
+	previousPasswordKey            = "previous-password"
+	passwordRotatedAtAnnotationKey = "redisfailovers.databases.spotahome.com/password-rotated-at"
+)
+
+// variables refering to the redis configuration holding the credentials, which is kept in the
+// operator secret and included by the redis configuration, so no password is in the configmap
+const (
+	redisAuthConfigKey  = "auth.conf"
+	redisAuthVolumeName = "redis-auth"
+	redisAuthMountPath  = "/redis-auth"
+)
+
+// variables refering to the restore of the snapshot of spec.restore into the first redis pod
+const (
+	redisRestoreName       = "restore"
+	redisRestoreVolumeName = "redis-restore"
+	redisRestoreMountPath  = "/restore"
+	restoreURLKey          = "url"
+	restoreChecksumKey     = "checksum"
+	restoreURLExpiration   = 2 * time.Hour
+	// RedisRestoreContainerName is the init container seeding the data volume with the snapshot
+	RedisRestoreContainerName = "redis-restore"
+)
+
 const (
 	redisRoleLabelKey    = "redisfailovers-role"
 	redisRoleLabelMaster = "master"
 	redisRoleLabelSlave  = "slave"
+	// redisEpochLabelKey holds the failover epoch at which a pod was promoted to master
+	redisEpochLabelKey = "redisfailovers-epoch"
 )
@@ -2,6 +2,8 @@ package service
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"text/template"
@@ -19,22 +21,63 @@ import (
 
 const (
 	redisConfigurationVolumeName = "redis-config"
-	// Template used to build the Redis configuration
+	// Template used to build the Redis configuration. The append only file is off while a
+	// snapshot is restored, redis ignoring the snapshot otherwise, and turned on once it is loaded.
 	redisConfigTemplate = `slaveof 127.0.0.1 {{.Spec.Redis.Port}}
+{{- if .Spec.TLS}}
+port 0
+tls-port {{.Spec.Redis.Port}}
+tls-cert-file /tls/tls.crt
+tls-key-file /tls/tls.key
+tls-ca-cert-file /tls/ca.crt
+tls-auth-clients optional
+tls-replication yes
+tls-cluster yes
+{{- else}}
 port {{.Spec.Redis.Port}}
+{{- end}}
 tcp-keepalive 60
+{{- if .Spec.Redis.Persistence}}
+{{- range persistenceConfigFile .PersistenceConfig}}
+{{.}}
+{{- end}}
+{{- else}}
 save 900 1
 save 300 10
-user pinger -@all +ping on >pingpass
+{{- end}}
+{{- range .MaxMemoryConfig}}
+{{.}}
+{{- end}}
+{{- range .RestartCustomConfig}}
+{{.}}
+{{- end}}
+{{- if .Restoring}}
+appendonly no
+{{- end}}
 {{- range .Spec.Redis.CustomCommandRenames}}
 rename-command "{{.From}}" "{{.To}}"
 {{- end}}
 `
 
-	sentinelConfigTemplate = `sentinel monitor mymaster 127.0.0.1 {{.Spec.Redis.Port}} 2
-sentinel down-after-milliseconds mymaster 1000
-sentinel failover-timeout mymaster 3000
-sentinel parallel-syncs mymaster 2`
+	sentinelConfigTemplate = `sentinel monitor {{.SentinelMasterName}} 127.0.0.1 {{.Spec.Redis.Port}} 2
+sentinel down-after-milliseconds {{.SentinelMasterName}} 1000
+sentinel failover-timeout {{.SentinelMasterName}} 3000
+sentinel parallel-syncs {{.SentinelMasterName}} 2
+{{- if .Spec.AnnounceHostnames}}
+sentinel resolve-hostnames yes
+sentinel announce-hostnames yes
+{{- end}}
+{{- if .Spec.TLS}}
+port 0
+tls-port {{.SentinelPort}}
+tls-cert-file /tls/tls.crt
+tls-key-file /tls/tls.key
+tls-ca-cert-file /tls/ca.crt
+tls-auth-clients optional
+tls-replication yes
+{{- else}}
+port {{.SentinelPort}}
+{{- end}}`
 
 	redisShutdownConfigurationVolumeName   = "redis-shutdown-config"
 	redisStartupConfigurationVolumeName    = "redis-startup-config"
@@ -49,7 +92,7 @@ func generateSentinelService(rf *redisfailoverv1.RedisFailover, labels map[strin
 	name := GetSentinelName(rf)
 	namespace := rf.Namespace
 
-	sentinelTargetPort := intstr.FromInt32(26379)
+	sentinelTargetPort := intstr.FromInt32(rf.SentinelPort())
 	selectorLabels := generateSelectorLabels(sentinelRoleName, rf.Name)
 	labels = util.MergeLabels(labels, selectorLabels)
 
@@ -62,11 +105,13 @@ func generateSentinelService(rf *redisfailoverv1.RedisFailover, labels map[strin
 			Annotations:     rf.Spec.Sentinel.ServiceAnnotations,
 		},
 		Spec: corev1.ServiceSpec{
-			Selector: selectorLabels,
+			Selector:       selectorLabels,
+			IPFamilyPolicy: getIPFamilyPolicy(rf),
+			IPFamilies:     getIPFamilies(rf),
 			Ports: []corev1.ServicePort{
 				{
 					Name:       "sentinel",
-					Port:       26379,
+					Port:       rf.SentinelPort(),
 					TargetPort: sentinelTargetPort,
 					Protocol:   "TCP",
 				},
@@ -87,6 +132,18 @@ func generateRedisService(rf *redisfailoverv1.RedisFailover, labels map[string]s
 		"prometheus.io/path":   "/metrics",
 	}
 	annotations := util.MergeLabels(defaultAnnotations, rf.Spec.Redis.ServiceAnnotations)
+	ports := []corev1.ServicePort{
+		{
+			Port:     exporterPort,
+			Protocol: corev1.ProtocolTCP,
+			Name:     exporterPortName,
+		},
+	}
+	if rf.Spec.AnnounceHostnames && !rf.Spec.Redis.Exporter.Enabled {
+		// Only kept for the names of the pods they announce
+		annotations = rf.Spec.Redis.ServiceAnnotations
+		ports = nil
+	}
 
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -97,16 +154,14 @@ func generateRedisService(rf *redisfailoverv1.RedisFailover, labels map[string]s
 			Annotations:     annotations,
 		},
 		Spec: corev1.ServiceSpec{
-			Type:      corev1.ServiceTypeClusterIP,
-			ClusterIP: corev1.ClusterIPNone,
-			Ports: []corev1.ServicePort{
-				{
-					Port:     exporterPort,
-					Protocol: corev1.ProtocolTCP,
-					Name:     exporterPortName,
-				},
-			},
-			Selector: selectorLabels,
+			Type:           corev1.ServiceTypeClusterIP,
+			ClusterIP:      corev1.ClusterIPNone,
+			IPFamilyPolicy: getIPFamilyPolicy(rf),
+			IPFamilies:     getIPFamilies(rf),
+			Ports:          ports,
+			Selector:       selectorLabels,
+			// The names of the pods must resolve before they are ready, for them to replicate
+			PublishNotReadyAddresses: rf.Spec.AnnounceHostnames,
 		},
 	}
 }
@@ -130,7 +185,9 @@ func generateRedisMasterService(rf *redisfailoverv1.RedisFailover, labels map[st
 			Annotations:     rf.Spec.Redis.ServiceAnnotations,
 		},
 		Spec: corev1.ServiceSpec{
-			Type: corev1.ServiceTypeClusterIP,
+			Type:           corev1.ServiceTypeClusterIP,
+			IPFamilyPolicy: getIPFamilyPolicy(rf),
+			IPFamilies:     getIPFamilies(rf),
 			Ports: []corev1.ServicePort{
 				{
 					Name:       "redis",
@@ -163,7 +220,9 @@ func generateRedisSlaveService(rf *redisfailoverv1.RedisFailover, labels map[str
 			Annotations:     rf.Spec.Redis.ServiceAnnotations,
 		},
 		Spec: corev1.ServiceSpec{
-			Type: corev1.ServiceTypeClusterIP,
+			Type:           corev1.ServiceTypeClusterIP,
+			IPFamilyPolicy: getIPFamilyPolicy(rf),
+			IPFamilies:     getIPFamilies(rf),
 			Ports: []corev1.ServicePort{
 				{
 					Name:       "redis",
@@ -177,6 +236,24 @@ func generateRedisSlaveService(rf *redisfailoverv1.RedisFailover, labels map[str
 	}
 }
 
+// getIPFamilyPolicy returns the IP family policy of the services: the cluster default when
+// spec.ipFamily is not set, dual-stack when the cluster supports it otherwise.
+func getIPFamilyPolicy(rf *redisfailoverv1.RedisFailover) *corev1.IPFamilyPolicy {
+	if rf.Spec.IPFamily == "" {
+		return nil
+	}
+	policy := corev1.IPFamilyPolicyPreferDualStack
+	return &policy
+}
+
+// getIPFamilies returns the IP families of the services, spec.ipFamily being the primary one
+func getIPFamilies(rf *redisfailoverv1.RedisFailover) []corev1.IPFamily {
+	if rf.Spec.IPFamily == "" {
+		return nil
+	}
+	return []corev1.IPFamily{rf.Spec.IPFamily}
+}
+
 func generateSentinelConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.ConfigMap {
 	name := GetSentinelName(rf)
 	namespace := rf.Namespace
@@ -208,11 +285,11 @@ func generateSentinelConfigMap(rf *redisfailoverv1.RedisFailover, labels map[str
 	}
 }
 
-func generateRedisConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, password string) *corev1.ConfigMap {
+func generateRedisConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.ConfigMap {
 	name := GetRedisName(rf)
 	labels = util.MergeLabels(labels, generateSelectorLabels(redisRoleName, rf.Name))
 
-	tmpl, err := template.New("redis").Parse(redisConfigTemplate)
+	tmpl, err := template.New("redis").Funcs(template.FuncMap{"persistenceConfigFile": persistenceConfigFile}).Parse(redisConfigTemplate)
 	if err != nil {
 		panic(err)
 	}
@@ -222,11 +299,8 @@ func generateRedisConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string
 		panic(err)
 	}
 
-	redisConfigFileContent := tplOutput.String()
-
-	if password != "" {
-		redisConfigFileContent = fmt.Sprintf("%s\nmasterauth %s\nrequirepass %s", redisConfigFileContent, password, password)
-	}
+	// The credentials are read from the operator secret mounted in the pods
+	redisConfigFileContent := fmt.Sprintf("%s\ninclude %s/%s", tplOutput.String(), redisAuthMountPath, redisAuthConfigKey)
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -241,6 +315,29 @@ func generateRedisConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string
 	}
 }
 
+// GetConfigChecksum returns the checksum of the given redis configuration
+func GetConfigChecksum(config []string) string {
+	h := sha256.Sum256([]byte(strings.Join(config, "\n")))
+	return hex.EncodeToString(h[:])
+}
+
+// persistenceConfigFile returns the persistence configuration as redis.conf directives: the
+// save points can't be given on a single line to every redis version.
+func persistenceConfigFile(config []string) []string {
+	directives := []string{}
+	for _, c := range config {
+		fields := strings.Fields(c)
+		if fields[0] != "save" || len(fields) < 3 {
+			directives = append(directives, c)
+			continue
+		}
+		for i := 1; i+1 < len(fields); i += 2 {
+			directives = append(directives, fmt.Sprintf("save %s %s", fields[i], fields[i+1]))
+		}
+	}
+	return directives
+}
+
 func generateRedisShutdownConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.ConfigMap {
 	name := GetRedisShutdownConfigMapName(rf)
 	port := rf.Spec.Redis.Port
@@ -248,17 +345,21 @@ func generateRedisShutdownConfigMap(rf *redisfailoverv1.RedisFailover, labels ma
 	rfName := strings.ReplaceAll(strings.ToUpper(rf.Name), "-", "_")
 
 	labels = util.MergeLabels(labels, generateSelectorLabels(redisRoleName, rf.Name))
-	shutdownContent := fmt.Sprintf(`master=$(redis-cli -h ${RFS_%[1]v_SERVICE_HOST} -p ${RFS_%[1]v_SERVICE_PORT_SENTINEL} --csv SENTINEL get-master-addr-by-name mymaster | tr ',' ' ' | tr -d '\"' |cut -d' ' -f1)
-if [ "$master" = "$(hostname -i)" ]; then
-  redis-cli -h ${RFS_%[1]v_SERVICE_HOST} -p ${RFS_%[1]v_SERVICE_PORT_SENTINEL} SENTINEL failover mymaster
+	// hostname -i lists every IP of the pod on dual-stack clusters, the master one is looked up among them.
+	// The sentinels announce the master by its hostname when announceHostnames is enabled.
+	addresses := "$(hostname -i)"
+	if rf.Spec.AnnounceHostnames {
+		addresses = fmt.Sprintf("%s $(hostname).%s.%s.svc", addresses, GetRedisName(rf), rf.Namespace)
+	}
+	shutdownContent := fmt.Sprintf(`master=$(redis-cli -h ${RFS_%[1]v_SERVICE_HOST} -p ${RFS_%[1]v_SERVICE_PORT_SENTINEL}%[3]v --csv SENTINEL get-master-addr-by-name %[5]v | tr ',' ' ' | tr -d '\"' |cut -d' ' -f1)
+if [ -n "$master" ] && echo " %[6]v " | grep -qF " $master "; then
+  redis-cli -h ${RFS_%[1]v_SERVICE_HOST} -p ${RFS_%[1]v_SERVICE_PORT_SENTINEL}%[3]v SENTINEL failover %[5]v
   sleep 31
 fi
-cmd="redis-cli -p %[2]v"
-if [ ! -z "${REDIS_PASSWORD}" ]; then
-	export REDISCLI_AUTH=${REDIS_PASSWORD}
-fi
+cmd="redis-cli -p %[2]v%[3]v"
+%[4]v
 save_command="${cmd} save"
-eval $save_command`, rfName, port)
+eval $save_command`, rfName, port, getRedisCliTLSArgs(rf), getRedisCliAuthScript(), rf.SentinelMasterName(), addresses)
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -285,11 +386,8 @@ ROLE_SLAVE="role:slave"
 IN_SYNC="master_sync_in_progress:1"
 NO_MASTER="master_host:127.0.0.1"
 
-cmd="redis-cli -p %[1]v"
-if [ ! -z "${REDIS_PASSWORD}" ]; then
-	export REDISCLI_AUTH=${REDIS_PASSWORD}
-fi
-
+cmd="redis-cli -p %[1]v%[2]v"
+%[3]v
 cmd="${cmd} info replication"
 
 check_master(){
@@ -318,7 +416,7 @@ case $role in
 		*)
 				echo "unexpected"
 				exit 1
-esac`, port)
+esac`, port, getRedisCliTLSArgs(rf), getRedisCliAuthScript())
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -427,7 +525,7 @@ func generateRedisStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[stri
 			Spec:   rf.Spec.Redis.Storage.PersistentVolumeClaim.Spec,
 			Status: rf.Spec.Redis.Storage.PersistentVolumeClaim.Status,
 		}
-		if !rf.Spec.Redis.Storage.KeepAfterDeletion {
+		if rf.DeleteClaimsWhenDeleted() {
 			// Set an owner reference so the persistent volumes are deleted when the RF is
 			pvc.OwnerReferences = ownerRefs
 		}
@@ -449,7 +547,7 @@ func generateRedisStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[stri
 					Command: []string{
 						"sh",
 						"-c",
-						fmt.Sprintf("redis-cli -h $(hostname) -p %[1]v --user pinger --pass pingpass --no-auth-warning ping | grep PONG", rf.Spec.Redis.Port),
+						fmt.Sprintf("redis-cli -h $(hostname) -p %[1]v%[2]v --user %[3]v --pass \"${%[4]v}\" --no-auth-warning ping | grep PONG", rf.Spec.Redis.Port, getRedisCliTLSArgs(rf), redisfailoverv1.ProbeUserName, probePasswordEnvName),
 					},
 				},
 			},
@@ -486,11 +584,28 @@ func generateRedisStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[stri
 		}
 	}
 
+	if config := rf.RestartCustomConfig(); len(config) != 0 {
+		ss.Spec.Template.Annotations = util.MergeAnnotations(ss.Spec.Template.Annotations, map[string]string{
+			configChecksumAnnotationKey: GetConfigChecksum(config),
+		})
+	}
+
 	if rf.Spec.Redis.Exporter.Enabled {
 		exporter := createRedisExporterContainer(rf)
 		ss.Spec.Template.Spec.Containers = append(ss.Spec.Template.Spec.Containers, exporter)
 	}
 
+	if rf.Restoring() {
+		// The first pod is seeded with the snapshot alone, the replicas are only started once
+		// it has been promoted
+		replicas := int32(1)
+		ss.Spec.Replicas = &replicas
+		ss.Spec.Template.Spec.InitContainers = append(ss.Spec.Template.Spec.InitContainers, generateRedisRestoreContainer(rf))
+		if rf.Spec.Restore.PersistentVolumeClaim != nil {
+			ss.Spec.Template.Spec.Volumes = append(ss.Spec.Template.Spec.Volumes, getRedisRestoreVolume(rf))
+		}
+	}
+
 	if rf.Spec.Redis.InitContainers != nil {
 		initContainers := getInitContainersWithRedisEnv(rf)
 		ss.Spec.Template.Spec.InitContainers = append(ss.Spec.Template.Spec.InitContainers, initContainers...)
@@ -589,7 +704,7 @@ func generateSentinelDeployment(rf *redisfailoverv1.RedisFailover, labels map[st
 							Ports: []corev1.ContainerPort{
 								{
 									Name:          "sentinel",
-									ContainerPort: 26379,
+									ContainerPort: rf.SentinelPort(),
 									Protocol:      corev1.ProtocolTCP,
 								},
 							},
@@ -615,7 +730,7 @@ func generateSentinelDeployment(rf *redisfailoverv1.RedisFailover, labels map[st
 					Command: []string{
 						"sh",
 						"-c",
-						"redis-cli -h $(hostname) -p 26379 ping",
+						fmt.Sprintf("redis-cli -h $(hostname) -p %[1]v%[2]v ping", rf.SentinelPort(), getRedisCliTLSArgs(rf)),
 					},
 				},
 			},
@@ -633,7 +748,7 @@ func generateSentinelDeployment(rf *redisfailoverv1.RedisFailover, labels map[st
 					Command: []string{
 						"sh",
 						"-c",
-						"redis-cli -h $(hostname) -p 26379 sentinel get-master-addr-by-name mymaster | head -n 1 | grep -vq '127.0.0.1'",
+						fmt.Sprintf("redis-cli -h $(hostname) -p %[1]v%[2]v sentinel get-master-addr-by-name %[3]v | head -n 1 | grep -vq '127.0.0.1'", rf.SentinelPort(), getRedisCliTLSArgs(rf), rf.SentinelMasterName()),
 					},
 				},
 			},
@@ -732,6 +847,11 @@ func createRedisExporterContainer(rf *redisfailoverv1.RedisFailover) corev1.Cont
 	redisEnv := getRedisExporterEnv(rf)
 	container.Env = append(container.Env, redisEnv...)
 
+	if rf.TLSEnabled() {
+		container.Env = append(container.Env, getExporterTLSEnv()...)
+		container.VolumeMounts = append(container.VolumeMounts, getTLSVolumeMount())
+	}
+
 	return container
 }
 
@@ -758,7 +878,7 @@ func createSentinelExporterContainer(rf *redisfailoverv1.RedisFailover) corev1.C
 			Value: fmt.Sprintf("0.0.0.0:%[1]v", sentinelExporterPort),
 		}, corev1.EnvVar{
 			Name:  "REDIS_ADDR",
-			Value: "redis://127.0.0.1:26379",
+			Value: fmt.Sprintf("%[1]v://127.0.0.1:%[2]v", getRedisURLScheme(rf), rf.SentinelPort()),
 		},
 		),
 		Ports: []corev1.ContainerPort{
@@ -771,6 +891,11 @@ func createSentinelExporterContainer(rf *redisfailoverv1.RedisFailover) corev1.C
 		Resources: resources,
 	}
 
+	if rf.TLSEnabled() {
+		container.Env = append(container.Env, getExporterTLSEnv()...)
+		container.VolumeMounts = append(container.VolumeMounts, getTLSVolumeMount())
+	}
+
 	return container
 }
 
@@ -884,6 +1009,16 @@ func getRedisVolumeMounts(rf *redisfailoverv1.RedisFailover) []corev1.VolumeMoun
 		volumeMounts = append(volumeMounts, startupVolumeMount)
 	}
 
+	if rf.TLSEnabled() {
+		volumeMounts = append(volumeMounts, getTLSVolumeMount())
+	}
+
+	volumeMounts = append(volumeMounts, corev1.VolumeMount{
+		Name:      redisAuthVolumeName,
+		MountPath: redisAuthMountPath,
+		ReadOnly:  true,
+	})
+
 	if rf.Spec.Redis.ExtraVolumeMounts != nil {
 		volumeMounts = append(volumeMounts, rf.Spec.Redis.ExtraVolumeMounts...)
 	}
@@ -906,6 +1041,9 @@ func getSentinelVolumeMounts(rf *redisfailoverv1.RedisFailover) []corev1.VolumeM
 		}
 		volumeMounts = append(volumeMounts, startupVolumeMount)
 	}
+	if rf.TLSEnabled() {
+		volumeMounts = append(volumeMounts, getTLSVolumeMount())
+	}
 	if rf.Spec.Sentinel.ExtraVolumeMounts != nil {
 		volumeMounts = append(volumeMounts, rf.Spec.Sentinel.ExtraVolumeMounts...)
 	}
@@ -970,6 +1108,26 @@ func getRedisVolumes(rf *redisfailoverv1.RedisFailover) []corev1.Volume {
 		volumes = append(volumes, startupVolume)
 	}
 
+	if rf.TLSEnabled() {
+		volumes = append(volumes, getTLSVolume(rf))
+	}
+
+	// Only the credentials of the redis configuration and the applied password are mounted, the
+	// secret also holds the passwords of the operator users
+	authItems := []corev1.KeyToPath{{Key: redisAuthConfigKey, Path: redisAuthConfigKey}}
+	if rf.Spec.Auth.SecretPath != "" {
+		authItems = append(authItems, corev1.KeyToPath{Key: passwordKey, Path: passwordKey})
+	}
+	volumes = append(volumes, corev1.Volume{
+		Name: redisAuthVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: GetRedisAuthSecretName(rf),
+				Items:      authItems,
+			},
+		},
+	})
+
 	if rf.Spec.Redis.ExtraVolumes != nil {
 		volumes = append(volumes, rf.Spec.Redis.ExtraVolumes...)
 	}
@@ -1020,6 +1178,10 @@ func getSentinelVolumes(rf *redisfailoverv1.RedisFailover, configMapName string)
 		volumes = append(volumes, startupVolume)
 	}
 
+	if rf.TLSEnabled() {
+		volumes = append(volumes, getTLSVolume(rf))
+	}
+
 	if rf.Spec.Sentinel.ExtraVolumes != nil {
 		volumes = append(volumes, rf.Spec.Sentinel.ExtraVolumes...)
 	}
@@ -1027,6 +1189,73 @@ func getSentinelVolumes(rf *redisfailoverv1.RedisFailover, configMapName string)
 	return volumes
 }
 
+func getTLSVolume(rf *redisfailoverv1.RedisFailover) corev1.Volume {
+	return corev1.Volume{
+		Name: tlsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: GetTLSSecretName(rf),
+			},
+		},
+	}
+}
+
+func getTLSVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      tlsVolumeName,
+		MountPath: tlsMountPath,
+		ReadOnly:  true,
+	}
+}
+
+// getRedisCliAuthScript returns the shell snippet authenticating redis-cli with the password
+// applied on the nodes, which is read from the mounted secret so it follows the rotations
+func getRedisCliAuthScript() string {
+	return fmt.Sprintf(`if [ -f %[1]v/%[2]v ]; then
+	export REDISCLI_AUTH=$(cat %[1]v/%[2]v)
+elif [ ! -z "${REDIS_PASSWORD}" ]; then
+	export REDISCLI_AUTH=${REDIS_PASSWORD}
+fi`, redisAuthMountPath, passwordKey)
+}
+
+// getRedisCliTLSArgs returns the redis-cli arguments needed to connect when TLS is enabled
+func getRedisCliTLSArgs(rf *redisfailoverv1.RedisFailover) string {
+	if !rf.TLSEnabled() {
+		return ""
+	}
+	return fmt.Sprintf(" --tls --cert %[1]v/%[2]v --key %[1]v/%[3]v --cacert %[1]v/%[4]v", tlsMountPath, tlsCertFileName, tlsKeyFileName, tlsCACertFileName)
+}
+
+func getRedisURLScheme(rf *redisfailoverv1.RedisFailover) string {
+	if rf.TLSEnabled() {
+		return "rediss"
+	}
+	return "redis"
+}
+
+// getExporterTLSEnv returns the settings of the exporters when TLS is enabled. The exporters
+// connect through the loopback interface, which the certificate is not expected to cover.
+func getExporterTLSEnv() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name:  "REDIS_EXPORTER_TLS_CLIENT_CERT_FILE",
+			Value: fmt.Sprintf("%v/%v", tlsMountPath, tlsCertFileName),
+		},
+		{
+			Name:  "REDIS_EXPORTER_TLS_CLIENT_KEY_FILE",
+			Value: fmt.Sprintf("%v/%v", tlsMountPath, tlsKeyFileName),
+		},
+		{
+			Name:  "REDIS_EXPORTER_TLS_CA_CERT_FILE",
+			Value: fmt.Sprintf("%v/%v", tlsMountPath, tlsCACertFileName),
+		},
+		{
+			Name:  "REDIS_EXPORTER_SKIP_TLS_VERIFICATION",
+			Value: "true",
+		},
+	}
+}
+
 func getRedisDataVolume(rf *redisfailoverv1.RedisFailover) *corev1.Volume {
 	// This will find the volumed desired by the user. If no volume defined
 	// an EmptyDir will be used by default
@@ -1065,10 +1294,15 @@ func getRedisCommand(rf *redisfailoverv1.RedisFailover) []string {
 	if len(rf.Spec.Redis.Command) > 0 {
 		return rf.Spec.Redis.Command
 	}
-	return []string{
+	command := []string{
 		"redis-server",
 		fmt.Sprintf("/redis/%s", redisConfigFileName),
 	}
+	if rf.Spec.AnnounceHostnames {
+		// The replicas announce their stable name to the master and the sentinels
+		command = append(command, "--replica-announce-ip", fmt.Sprintf("$(%s).%s.%s.svc", podNameEnvName, GetRedisName(rf), rf.Namespace))
+	}
+	return command
 }
 
 func getSentinelCommand(rf *redisfailoverv1.RedisFailover) []string {
@@ -1125,7 +1359,7 @@ func getRedisEnv(rf *redisfailoverv1.RedisFailover) []corev1.EnvVar {
 
 	env = append(env, corev1.EnvVar{
 		Name:  "REDIS_ADDR",
-		Value: fmt.Sprintf("redis://127.0.0.1:%[1]v", rf.Spec.Redis.Port),
+		Value: fmt.Sprintf("%[1]v://127.0.0.1:%[2]v", getRedisURLScheme(rf), rf.Spec.Redis.Port),
 	})
 
 	env = append(env, corev1.EnvVar{
@@ -1138,6 +1372,18 @@ func getRedisEnv(rf *redisfailoverv1.RedisFailover) []corev1.EnvVar {
 		Value: "default",
 	})
 
+	env = append(env, corev1.EnvVar{
+		Name: probePasswordEnvName,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: GetRedisAuthSecretName(rf),
+				},
+				Key: probePasswordKey,
+			},
+		},
+	})
+
 	if rf.Spec.Auth.SecretPath != "" {
 		env = append(env, corev1.EnvVar{
 			Name: "REDIS_PASSWORD",
@@ -1152,6 +1398,17 @@ func getRedisEnv(rf *redisfailoverv1.RedisFailover) []corev1.EnvVar {
 		})
 	}
 
+	if rf.Spec.AnnounceHostnames {
+		env = append(env, corev1.EnvVar{
+			Name: podNameEnvName,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.name",
+				},
+			},
+		})
+	}
+
 	return env
 }
 
@@ -1160,7 +1417,7 @@ func getRedisExporterEnv(rf *redisfailoverv1.RedisFailover) []corev1.EnvVar {
 
 	env = append(env, corev1.EnvVar{
 		Name:  "REDIS_ADDR",
-		Value: fmt.Sprintf("redis://127.0.0.1:%[1]v", rf.Spec.Redis.Port),
+		Value: fmt.Sprintf("%[1]v://127.0.0.1:%[2]v", getRedisURLScheme(rf), rf.Spec.Redis.Port),
 	})
 
 	env = append(env, corev1.EnvVar{
@@ -56,6 +56,11 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 											Name:      "redis-data",
 											MountPath: "/data",
 										},
+										{
+											Name:      "redis-auth",
+											MountPath: "/redis-auth",
+											ReadOnly:  true,
+										},
 									},
 								},
 							},
@@ -92,6 +97,15 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 										},
 									},
 								},
+								{
+									Name: "redis-auth",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{
+											SecretName: "rfauth-test",
+											Items:      []corev1.KeyToPath{{Key: "auth.conf", Path: "auth.conf"}},
+										},
+									},
+								},
 								{
 									Name: "redis-data",
 									VolumeSource: corev1.VolumeSource{
@@ -130,6 +144,11 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 											Name:      "redis-data",
 											MountPath: "/data",
 										},
+										{
+											Name:      "redis-auth",
+											MountPath: "/redis-auth",
+											ReadOnly:  true,
+										},
 									},
 								},
 							},
@@ -166,6 +185,15 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 										},
 									},
 								},
+								{
+									Name: "redis-auth",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{
+											SecretName: "rfauth-test",
+											Items:      []corev1.KeyToPath{{Key: "auth.conf", Path: "auth.conf"}},
+										},
+									},
+								},
 								{
 									Name: "redis-data",
 									VolumeSource: corev1.VolumeSource{
@@ -210,6 +238,11 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 											Name:      "pvc-data",
 											MountPath: "/data",
 										},
+										{
+											Name:      "redis-auth",
+											MountPath: "/redis-auth",
+											ReadOnly:  true,
+										},
 									},
 								},
 							},
@@ -246,6 +279,15 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 										},
 									},
 								},
+								{
+									Name: "redis-auth",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{
+											SecretName: "rfauth-test",
+											Items:      []corev1.KeyToPath{{Key: "auth.conf", Path: "auth.conf"}},
+										},
+									},
+								},
 							},
 						},
 					},
@@ -320,6 +362,11 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 											Name:      "pvc-data",
 											MountPath: "/data",
 										},
+										{
+											Name:      "redis-auth",
+											MountPath: "/redis-auth",
+											ReadOnly:  true,
+										},
 									},
 								},
 							},
@@ -356,6 +403,15 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 										},
 									},
 								},
+								{
+									Name: "redis-auth",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{
+											SecretName: "rfauth-test",
+											Items:      []corev1.KeyToPath{{Key: "auth.conf", Path: "auth.conf"}},
+										},
+									},
+								},
 							},
 						},
 					},
@@ -435,6 +491,11 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 											Name:      "pvc-data",
 											MountPath: "/data",
 										},
+										{
+											Name:      "redis-auth",
+											MountPath: "/redis-auth",
+											ReadOnly:  true,
+										},
 									},
 								},
 							},
@@ -471,6 +532,15 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 										},
 									},
 								},
+								{
+									Name: "redis-auth",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{
+											SecretName: "rfauth-test",
+											Items:      []corev1.KeyToPath{{Key: "auth.conf", Path: "auth.conf"}},
+										},
+									},
+								},
 							},
 						},
 					},
@@ -534,7 +604,7 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 			generatedStatefulSet = *ss
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureRedisStatefulset(rf, nil, test.ownerRefs)
 
 		// Check that the storage-related fields are as expected
@@ -588,7 +658,7 @@ func TestRedisStatefulSetCommands(t *testing.T) {
 			gotCommands = ss.Spec.Template.Spec.Containers[0].Command
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
 
 		assert.Equal(test.expectedCommands, gotCommands)
@@ -640,7 +710,7 @@ func TestSentinelDeploymentCommands(t *testing.T) {
 			gotCommands = d.Spec.Template.Spec.Containers[0].Command
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureSentinelDeployment(rf, nil, []metav1.OwnerReference{})
 
 		assert.Equal(test.expectedCommands, gotCommands)
@@ -688,7 +758,7 @@ func TestRedisStatefulSetPodAnnotations(t *testing.T) {
 			gotPodAnnotations = ss.Spec.Template.ObjectMeta.Annotations
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
 
 		assert.Equal(test.expectedPodAnnotations, gotPodAnnotations)
@@ -736,7 +806,7 @@ func TestSentinelDeploymentPodAnnotations(t *testing.T) {
 			gotPodAnnotations = d.Spec.Template.ObjectMeta.Annotations
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureSentinelDeployment(rf, nil, []metav1.OwnerReference{})
 
 		assert.Equal(test.expectedPodAnnotations, gotPodAnnotations)
@@ -778,7 +848,7 @@ func TestRedisStatefulSetServiceAccountName(t *testing.T) {
 			gotServiceAccountName = ss.Spec.Template.Spec.ServiceAccountName
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
 
 		assert.Equal(test.expectedServiceAccountName, gotServiceAccountName)
@@ -820,7 +890,7 @@ func TestSentinelDeploymentServiceAccountName(t *testing.T) {
 			gotServiceAccountName = d.Spec.Template.Spec.ServiceAccountName
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureSentinelDeployment(rf, nil, []metav1.OwnerReference{})
 
 		assert.Equal(test.expectedServiceAccountName, gotServiceAccountName)
@@ -1037,7 +1107,7 @@ func TestSentinelService(t *testing.T) {
 				generatedService = *s
 			}).Return(nil)
 
-			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 			err := client.EnsureSentinelService(rf, test.rfLabels, []metav1.OwnerReference{{Name: "testing"}})
 
 			assert.Equal(test.expectedService, generatedService)
@@ -1285,7 +1355,7 @@ func TestRedisService(t *testing.T) {
 				generatedService = *s
 			}).Return(nil)
 
-			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 			err := client.EnsureRedisService(rf, test.rfLabels, []metav1.OwnerReference{{Name: "testing"}})
 
 			assert.Equal(test.expectedService, generatedService)
@@ -1525,7 +1595,7 @@ func TestRedisMasterService(t *testing.T) {
 				generatedMasterService = *s
 			}).Return(nil)
 
-			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 			err := client.EnsureRedisMasterService(rf, test.rfLabels, []metav1.OwnerReference{{Name: "testing"}})
 
 			assert.Equal(test.expectedService, generatedMasterService)
@@ -1765,7 +1835,7 @@ func TestRedisSlaveService(t *testing.T) {
 				generatedSlaveService = *s
 			}).Return(nil)
 
-			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 			err := client.EnsureRedisSlaveService(rf, test.rfLabels, []metav1.OwnerReference{{Name: "testing"}})
 
 			assert.Equal(test.expectedService, generatedSlaveService)
@@ -1814,7 +1884,7 @@ func TestRedisHostNetworkAndDnsPolicy(t *testing.T) {
 			actualDnsPolicy = ss.Spec.Template.Spec.DNSPolicy
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
 		assert.NoError(err)
 
@@ -1863,7 +1933,7 @@ func TestSentinelHostNetworkAndDnsPolicy(t *testing.T) {
 			actualDnsPolicy = d.Spec.Template.Spec.DNSPolicy
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureSentinelDeployment(rf, nil, []metav1.OwnerReference{})
 		assert.NoError(err)
 
@@ -1913,7 +1983,7 @@ func TestRedisImagePullPolicy(t *testing.T) {
 			exporterPolicy = ss.Spec.Template.Spec.Containers[1].ImagePullPolicy
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
@@ -1959,7 +2029,7 @@ func TestSentinelImagePullPolicy(t *testing.T) {
 			configPolicy = d.Spec.Template.Spec.InitContainers[0].ImagePullPolicy
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureSentinelDeployment(rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
@@ -2030,11 +2100,11 @@ func TestRedisExtraVolumeMounts(t *testing.T) {
 		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
 		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
 			s := args.Get(1).(*appsv1.StatefulSet)
-			extraVolume = s.Spec.Template.Spec.Volumes[3]
-			extraVolumeMount = s.Spec.Template.Spec.Containers[0].VolumeMounts[4]
+			extraVolume = s.Spec.Template.Spec.Volumes[4]
+			extraVolumeMount = s.Spec.Template.Spec.Containers[0].VolumeMounts[5]
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
@@ -2109,7 +2179,7 @@ func TestSentinelExtraVolumeMounts(t *testing.T) {
 			extraVolumeMount = d.Spec.Template.Spec.Containers[0].VolumeMounts[1]
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureSentinelDeployment(rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
@@ -2165,7 +2235,7 @@ func TestCustomPort(t *testing.T) {
 			port = s.Spec.Template.Spec.Containers[0].Ports[0]
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
@@ -2196,6 +2266,17 @@ func TestRedisEnv(t *testing.T) {
 					Name:  "REDIS_USER",
 					Value: "default",
 				},
+				{
+					Name: "REDIS_PROBE_PASSWORD",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: "rfauth-test",
+							},
+							Key: "probe-password",
+						},
+					},
+				},
 			},
 		},
 		{
@@ -2214,6 +2295,17 @@ func TestRedisEnv(t *testing.T) {
 					Name:  "REDIS_USER",
 					Value: "default",
 				},
+				{
+					Name: "REDIS_PROBE_PASSWORD",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: "rfauth-test",
+							},
+							Key: "probe-password",
+						},
+					},
+				},
 				{
 					Name: "REDIS_PASSWORD",
 					ValueFrom: &corev1.EnvVarSource{
@@ -2247,7 +2339,7 @@ func TestRedisEnv(t *testing.T) {
 			env = s.Spec.Template.Spec.Containers[0].Env
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
@@ -2255,6 +2347,56 @@ func TestRedisEnv(t *testing.T) {
 	}
 }
 
+func TestRedisAuthVolume(t *testing.T) {
+	tests := []struct {
+		name     string
+		auth     string
+		expItems []corev1.KeyToPath
+	}{
+		{
+			name:     "without auth",
+			expItems: []corev1.KeyToPath{{Key: "auth.conf", Path: "auth.conf"}},
+		},
+		{
+			name:     "with auth",
+			auth:     "redis-secret",
+			expItems: []corev1.KeyToPath{{Key: "auth.conf", Path: "auth.conf"}, {Key: "password", Path: "password"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var podSpec corev1.PodSpec
+
+			rf := generateRF()
+			rf.Spec.Auth.SecretPath = test.auth
+
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+			ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				podSpec = args.Get(1).(*appsv1.StatefulSet).Spec.Template.Spec
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+			err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
+			assert.NoError(err)
+
+			assert.Contains(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "redis-auth", MountPath: "/redis-auth", ReadOnly: true})
+			assert.Contains(podSpec.Volumes, corev1.Volume{
+				Name: "redis-auth",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: "rfauth-test",
+						Items:      test.expItems,
+					},
+				},
+			})
+		})
+	}
+}
+
 func TestRedisStartupProbe(t *testing.T) {
 	mode := int32(0744)
 	tests := []struct {
@@ -2299,7 +2441,7 @@ func TestRedisStartupProbe(t *testing.T) {
 			startupVolumeMounts = s.Spec.Template.Spec.Containers[0].VolumeMounts
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
@@ -2352,7 +2494,7 @@ func TestSentinelStartupProbe(t *testing.T) {
 			startupVolumeMounts = d.Spec.Template.Spec.Containers[0].VolumeMounts
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureSentinelDeployment(rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
@@ -2413,7 +2555,7 @@ func TestRedisCustomLivenessProbe(t *testing.T) {
 						Command: []string{
 							"sh",
 							"-c",
-							"redis-cli -h $(hostname) -p 6379 --user pinger --pass pingpass --no-auth-warning ping | grep PONG",
+							"redis-cli -h $(hostname) -p 6379 --user pinger --pass \"${REDIS_PROBE_PASSWORD}\" --no-auth-warning ping | grep PONG",
 						},
 					},
 				},
@@ -2435,7 +2577,7 @@ func TestRedisCustomLivenessProbe(t *testing.T) {
 			livenessProbe = s.Spec.Template.Spec.Containers[0].LivenessProbe
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
@@ -2514,7 +2656,7 @@ func TestSentinelCustomLivenessProbe(t *testing.T) {
 			livenessProbe = d.Spec.Template.Spec.Containers[0].LivenessProbe
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureSentinelDeployment(rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
@@ -2581,7 +2723,7 @@ func TestRedisCustomReadinessProbe(t *testing.T) {
 			readinessProbe = s.Spec.Template.Spec.Containers[0].ReadinessProbe
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
@@ -2660,7 +2802,7 @@ func TestSentinelCustomReadinessProbe(t *testing.T) {
 			readinessProbe = d.Spec.Template.Spec.Containers[0].ReadinessProbe
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureSentinelDeployment(rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
@@ -2719,7 +2861,7 @@ func TestRedisCustomStartupProbe(t *testing.T) {
 			startupProbe = s.Spec.Template.Spec.Containers[0].StartupProbe
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
@@ -2786,7 +2928,7 @@ func TestSentinelCustomStartupProbe(t *testing.T) {
 			startupProbe = d.Spec.Template.Spec.Containers[0].StartupProbe
 		}).Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureSentinelDeployment(rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
@@ -2829,7 +2971,7 @@ func TestRedisPDBUsesRedisReplicaCount(t *testing.T) {
 			}).Return(nil)
 			ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Return(nil)
 
-			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 			err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
 
 			assert.NoError(err)
@@ -2874,7 +3016,7 @@ func TestSentinelPDBUsesSentinelReplicaCount(t *testing.T) {
 			}).Return(nil)
 			ms.On("CreateOrUpdateDeployment", namespace, mock.Anything).Once().Return(nil)
 
-			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 			err := client.EnsureSentinelDeployment(rf, nil, []metav1.OwnerReference{})
 
 			assert.NoError(err)
@@ -2907,7 +3049,7 @@ func TestPDBSelectorContainsOnlyStableLabels(t *testing.T) {
 		}).Return(nil)
 		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureRedisStatefulset(rf, extraLabels, []metav1.OwnerReference{})
 
 		assert.NoError(err)
@@ -2936,7 +3078,7 @@ func TestPDBSelectorContainsOnlyStableLabels(t *testing.T) {
 		}).Return(nil)
 		ms.On("CreateOrUpdateDeployment", namespace, mock.Anything).Once().Return(nil)
 
-		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
 		err := client.EnsureSentinelDeployment(rf, extraLabels, []metav1.OwnerReference{})
 
 		assert.NoError(err)
@@ -2951,3 +3093,502 @@ func TestPDBSelectorContainsOnlyStableLabels(t *testing.T) {
 		assert.Len(selector, len(stableKeys))
 	})
 }
+
+func TestRedisTLS(t *testing.T) {
+	tests := []struct {
+		name    string
+		tls     *redisfailoverv1.TLSSettings
+		expTLS  bool
+		expAddr string
+	}{
+		{
+			name:    "TLS disabled",
+			expAddr: "redis://127.0.0.1:6379",
+		},
+		{
+			name:    "TLS enabled",
+			tls:     &redisfailoverv1.TLSSettings{SecretName: "redis-tls-secret"},
+			expTLS:  true,
+			expAddr: "rediss://127.0.0.1:6379",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Redis.Port = 6379
+			rf.Spec.Redis.Exporter.Enabled = true
+			rf.Spec.TLS = test.tls
+
+			configMaps := map[string]*corev1.ConfigMap{}
+			var ss *appsv1.StatefulSet
+
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Run(func(args mock.Arguments) {
+				cm := args.Get(1).(*corev1.ConfigMap)
+				configMaps[cm.Name] = cm
+			}).Return(nil)
+			ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+			ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				ss = args.Get(1).(*appsv1.StatefulSet)
+			}).Return(nil)
+			ms.On("GetSecret", namespace, "redis-tls-secret").Return(&corev1.Secret{
+				Data: map[string][]byte{"tls.crt": []byte("cert"), "ca.crt": []byte("ca")},
+			}, nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+			assert.NoError(client.EnsureRedisConfigMap(rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureRedisShutdownConfigMap(rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureRedisReadinessConfigMap(rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{}))
+
+			config := configMaps[rfservice.GetRedisName(rf)].Data["redis.conf"]
+			shutdown := configMaps[rfservice.GetRedisShutdownConfigMapName(rf)].Data["shutdown.sh"]
+			readiness := configMaps[rfservice.GetRedisReadinessName(rf)].Data["ready.sh"]
+			liveness := ss.Spec.Template.Spec.Containers[0].LivenessProbe.Exec.Command[2]
+			exporter := ss.Spec.Template.Spec.Containers[1]
+
+			tlsVolume := corev1.Volume{
+				Name: "redis-tls",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{SecretName: "redis-tls-secret"},
+				},
+			}
+			tlsVolumeMount := corev1.VolumeMount{Name: "redis-tls", MountPath: "/tls", ReadOnly: true}
+			tlsArgs := "--tls --cert /tls/tls.crt --key /tls/tls.key --cacert /tls/ca.crt"
+
+			assert.Contains(exporter.Env, corev1.EnvVar{Name: "REDIS_ADDR", Value: test.expAddr})
+			if test.expTLS {
+				assert.Contains(config, "port 0\ntls-port 6379\n")
+				assert.Contains(config, "tls-replication yes\n")
+				assert.Contains(config, "tls-cluster yes\n")
+				assert.Contains(config, "tls-ca-cert-file /tls/ca.crt\n")
+				assert.Contains(shutdown, "${RFS_TEST_SERVICE_PORT_SENTINEL} "+tlsArgs+" SENTINEL failover mymaster")
+				assert.Contains(shutdown, `cmd="redis-cli -p 6379 `+tlsArgs+`"`)
+				assert.Contains(readiness, `cmd="redis-cli -p 6379 `+tlsArgs+`"`)
+				assert.Contains(liveness, "-p 6379 "+tlsArgs+" --user pinger")
+				assert.Contains(ss.Spec.Template.Spec.Volumes, tlsVolume)
+				assert.Contains(ss.Spec.Template.Spec.Containers[0].VolumeMounts, tlsVolumeMount)
+				assert.Contains(exporter.VolumeMounts, tlsVolumeMount)
+				assert.Contains(exporter.Env, corev1.EnvVar{Name: "REDIS_EXPORTER_TLS_CA_CERT_FILE", Value: "/tls/ca.crt"})
+				assert.NotEmpty(ss.Spec.Template.Annotations["redisfailovers.databases.spotahome.com/tls-checksum"])
+			} else {
+				assert.Contains(config, "port 6379\ntcp-keepalive 60\n")
+				assert.NotContains(config, "tls-")
+				assert.NotContains(shutdown, "--tls")
+				assert.NotContains(readiness, "--tls")
+				assert.NotContains(liveness, "--tls")
+				assert.NotContains(ss.Spec.Template.Spec.Volumes, tlsVolume)
+				assert.NotContains(exporter.VolumeMounts, tlsVolumeMount)
+				assert.NotContains(ss.Spec.Template.Annotations, "redisfailovers.databases.spotahome.com/tls-checksum")
+			}
+		})
+	}
+}
+
+func TestSentinelTLS(t *testing.T) {
+	tests := []struct {
+		name   string
+		tls    *redisfailoverv1.TLSSettings
+		expTLS bool
+	}{
+		{
+			name: "TLS disabled",
+		},
+		{
+			name:   "TLS enabled",
+			tls:    &redisfailoverv1.TLSSettings{SecretName: "redis-tls-secret"},
+			expTLS: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.TLS = test.tls
+
+			var cm *corev1.ConfigMap
+			var d *appsv1.Deployment
+
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				cm = args.Get(1).(*corev1.ConfigMap)
+			}).Return(nil)
+			ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+			ms.On("CreateOrUpdateDeployment", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				d = args.Get(1).(*appsv1.Deployment)
+			}).Return(nil)
+			ms.On("GetSecret", namespace, "redis-tls-secret").Return(&corev1.Secret{
+				Data: map[string][]byte{"tls.crt": []byte("cert"), "ca.crt": []byte("ca")},
+			}, nil)
+			ms.On("GetSecret", namespace, "rfauth-test").Return(&corev1.Secret{
+				Data: map[string][]byte{"operator-password": []byte("operator"), "probe-password": []byte("probe")},
+			}, nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+			assert.NoError(client.EnsureSentinelConfigMap(rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureSentinelDeployment(rf, nil, []metav1.OwnerReference{}))
+
+			config := cm.Data["sentinel.conf"]
+			sentinel := d.Spec.Template.Spec.Containers[0]
+			tlsVolumeMount := corev1.VolumeMount{Name: "redis-tls", MountPath: "/tls", ReadOnly: true}
+
+			if test.expTLS {
+				assert.Contains(config, "\nport 0\ntls-port 26379\n")
+				assert.Contains(config, "tls-replication yes")
+				assert.Contains(sentinel.VolumeMounts, tlsVolumeMount)
+				assert.Contains(sentinel.LivenessProbe.Exec.Command[2], "-p 26379 --tls --cert /tls/tls.crt --key /tls/tls.key --cacert /tls/ca.crt ping")
+				assert.Contains(sentinel.ReadinessProbe.Exec.Command[2], "-p 26379 --tls")
+				assert.NotEmpty(d.Spec.Template.Annotations["redisfailovers.databases.spotahome.com/tls-checksum"])
+			} else {
+				assert.NotContains(config, "tls-")
+				assert.NotContains(sentinel.VolumeMounts, tlsVolumeMount)
+				assert.Equal("redis-cli -h $(hostname) -p 26379 ping", sentinel.LivenessProbe.Exec.Command[2])
+			}
+		})
+	}
+}
+
+func TestRedisConfigMapPersistence(t *testing.T) {
+	rewritePercentage := int32(50)
+	rewriteMinSize := resource.MustParse("128Mi")
+
+	tests := []struct {
+		name        string
+		persistence *redisfailoverv1.PersistenceSettings
+		expected    string
+	}{
+		{
+			name:     "Default save points",
+			expected: "tcp-keepalive 60\nsave 900 1\nsave 300 10\n",
+		},
+		{
+			name:        "Persistence disabled",
+			persistence: &redisfailoverv1.PersistenceSettings{Disabled: true},
+			expected:    "tcp-keepalive 60\nsave \"\"\nappendonly no\n",
+		},
+		{
+			name: "RDB and AOF",
+			persistence: &redisfailoverv1.PersistenceSettings{
+				RDB: &redisfailoverv1.RDBSettings{SavePoints: []redisfailoverv1.RDBSavePoint{{Seconds: 3600, Changes: 1}, {Seconds: 60, Changes: 10000}}},
+				AOF: &redisfailoverv1.AOFSettings{Enabled: true, Fsync: redisfailoverv1.AppendFsyncAlways, RewritePercentage: &rewritePercentage, RewriteMinSize: &rewriteMinSize},
+			},
+			expected: "tcp-keepalive 60\nsave 3600 1\nsave 60 10000\nappendonly yes\nappendfsync always\nauto-aof-rewrite-percentage 50\nauto-aof-rewrite-min-size 134217728\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Redis.Persistence = test.persistence
+
+			var redisConfig string
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				redisConfig = args.Get(1).(*corev1.ConfigMap).Data["redis.conf"]
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+			assert.NoError(client.EnsureRedisConfigMap(rf, nil, []metav1.OwnerReference{}))
+			assert.Contains(redisConfig, test.expected)
+		})
+	}
+}
+
+func TestRedisConfigMapMaxMemory(t *testing.T) {
+	tests := []struct {
+		name        string
+		memoryLimit string
+		policy      redisfailoverv1.MaxMemoryPolicy
+		expected    string
+		notExpected string
+	}{
+		{
+			name:        "No maxmemory settings",
+			memoryLimit: "1Gi",
+			notExpected: "maxmemory",
+		},
+		{
+			name:        "Maxmemory derived from the memory limit",
+			memoryLimit: "1Gi",
+			policy:      "volatile-lru",
+			expected:    "save 300 10\nmaxmemory 805306368\nmaxmemory-policy volatile-lru\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Redis.Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(test.memoryLimit)}
+			rf.Spec.Redis.MaxMemoryPolicy = test.policy
+
+			var redisConfig string
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				redisConfig = args.Get(1).(*corev1.ConfigMap).Data["redis.conf"]
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+			assert.NoError(client.EnsureRedisConfigMap(rf, nil, []metav1.OwnerReference{}))
+			if test.expected != "" {
+				assert.Contains(redisConfig, test.expected)
+			}
+			if test.notExpected != "" {
+				assert.NotContains(redisConfig, test.notExpected)
+			}
+		})
+	}
+}
+
+func TestRedisRestartCustomConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		customConfig   []string
+		expectedConfig string
+		expectChecksum bool
+	}{
+		{
+			name:         "Runtime parameters only",
+			customConfig: []string{"maxclients 100"},
+		},
+		{
+			name:           "Restart parameters written to redis.conf",
+			customConfig:   []string{"maxclients 100", "databases 32"},
+			expectedConfig: "save 300 10\ndatabases 32\n",
+			expectChecksum: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Redis.CustomConfig = test.customConfig
+
+			var redisConfig string
+			var podAnnotations map[string]string
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				redisConfig = args.Get(1).(*corev1.ConfigMap).Data["redis.conf"]
+			}).Return(nil)
+			ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+			ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				podAnnotations = args.Get(1).(*appsv1.StatefulSet).Spec.Template.Annotations
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+			assert.NoError(client.EnsureRedisConfigMap(rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{}))
+
+			assert.NotContains(redisConfig, "maxclients")
+			if test.expectedConfig != "" {
+				assert.Contains(redisConfig, test.expectedConfig)
+			}
+			_, checksum := podAnnotations["redisfailovers.databases.spotahome.com/config-checksum"]
+			assert.Equal(test.expectChecksum, checksum)
+		})
+	}
+}
+
+func TestServicesIPFamily(t *testing.T) {
+	preferDualStack := corev1.IPFamilyPolicyPreferDualStack
+
+	tests := []struct {
+		name             string
+		ipFamily         corev1.IPFamily
+		expectedPolicy   *corev1.IPFamilyPolicy
+		expectedFamilies []corev1.IPFamily
+	}{
+		{
+			name: "cluster default",
+		},
+		{
+			name:             "IPv6",
+			ipFamily:         corev1.IPv6Protocol,
+			expectedPolicy:   &preferDualStack,
+			expectedFamilies: []corev1.IPFamily{corev1.IPv6Protocol},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.IPFamily = test.ipFamily
+
+			services := []*corev1.Service{}
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdateService", namespace, mock.Anything).Times(4).Run(func(args mock.Arguments) {
+				services = append(services, args.Get(1).(*corev1.Service))
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+			assert.NoError(client.EnsureSentinelService(rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureRedisService(rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureRedisMasterService(rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureRedisSlaveService(rf, nil, []metav1.OwnerReference{}))
+
+			assert.Len(services, 4)
+			for _, service := range services {
+				assert.Equal(test.expectedPolicy, service.Spec.IPFamilyPolicy, service.Name)
+				assert.Equal(test.expectedFamilies, service.Spec.IPFamilies, service.Name)
+			}
+		})
+	}
+}
+
+func TestRedisShutdownConfigMapMatchesEveryPodIP(t *testing.T) {
+	assert := assert.New(t)
+
+	var shutdownScript string
+	ms := &mK8SService.Services{}
+	ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		shutdownScript = args.Get(1).(*corev1.ConfigMap).Data["shutdown.sh"]
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+	assert.NoError(client.EnsureRedisShutdownConfigMap(generateRF(), nil, []metav1.OwnerReference{}))
+
+	// hostname -i lists both IPs of the pod on dual-stack clusters
+	assert.Contains(shutdownScript, `echo " $(hostname -i) " | grep -qF " $master "`)
+	assert.NotContains(shutdownScript, `"$master" = "$(hostname -i)"`)
+}
+
+func TestRedisShutdownConfigMapMatchesHostname(t *testing.T) {
+	assert := assert.New(t)
+
+	var shutdownScript string
+	ms := &mK8SService.Services{}
+	ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		shutdownScript = args.Get(1).(*corev1.ConfigMap).Data["shutdown.sh"]
+	}).Return(nil)
+
+	rf := generateRF()
+	rf.Spec.AnnounceHostnames = true
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+	assert.NoError(client.EnsureRedisShutdownConfigMap(rf, nil, []metav1.OwnerReference{}))
+
+	// The sentinels announce the master by its hostname
+	assert.Contains(shutdownScript, `echo " $(hostname -i) $(hostname).rfr-test.testns.svc " | grep -qF " $master "`)
+}
+
+func TestAnnounceHostnames(t *testing.T) {
+	tests := []struct {
+		name              string
+		announceHostnames bool
+	}{
+		{
+			name: "pod IPs",
+		},
+		{
+			name:              "hostnames",
+			announceHostnames: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.AnnounceHostnames = test.announceHostnames
+
+			var ss *appsv1.StatefulSet
+			var sentinelConfig string
+			var service *corev1.Service
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+			ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				ss = args.Get(1).(*appsv1.StatefulSet)
+			}).Return(nil)
+			ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				sentinelConfig = args.Get(1).(*corev1.ConfigMap).Data["sentinel.conf"]
+			}).Return(nil)
+			ms.On("CreateOrUpdateService", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				service = args.Get(1).(*corev1.Service)
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+			assert.NoError(client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureSentinelConfigMap(rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureRedisService(rf, nil, []metav1.OwnerReference{}))
+
+			redis := ss.Spec.Template.Spec.Containers[0]
+			podNameEnv := corev1.EnvVar{
+				Name:      "POD_NAME",
+				ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}},
+			}
+			if test.announceHostnames {
+				assert.Equal([]string{"redis-server", "/redis/redis.conf", "--replica-announce-ip", "$(POD_NAME).rfr-test.testns.svc"}, redis.Command)
+				assert.Contains(redis.Env, podNameEnv)
+				assert.Contains(sentinelConfig, "\nsentinel resolve-hostnames yes\nsentinel announce-hostnames yes")
+				assert.True(service.Spec.PublishNotReadyAddresses)
+				assert.Empty(service.Spec.Ports, "the exporter is disabled")
+			} else {
+				assert.Equal([]string{"redis-server", "/redis/redis.conf"}, redis.Command)
+				assert.NotContains(redis.Env, podNameEnv)
+				assert.NotContains(sentinelConfig, "hostnames")
+				assert.False(service.Spec.PublishNotReadyAddresses)
+			}
+			assert.Equal(rfservice.GetRedisName(rf), ss.Spec.ServiceName)
+		})
+	}
+}
+
+func TestSentinelMasterNameAndPort(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Sentinel.MasterName = "cache"
+	rf.Spec.Sentinel.Port = 26380
+
+	var sentinelConfig, shutdownScript string
+	var service *corev1.Service
+	var d *appsv1.Deployment
+	ms := &mK8SService.Services{}
+	ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Twice().Run(func(args mock.Arguments) {
+		cm := args.Get(1).(*corev1.ConfigMap)
+		if config, ok := cm.Data["sentinel.conf"]; ok {
+			sentinelConfig = config
+		} else {
+			shutdownScript = cm.Data["shutdown.sh"]
+		}
+	}).Return(nil)
+	ms.On("CreateOrUpdateService", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		service = args.Get(1).(*corev1.Service)
+	}).Return(nil)
+	ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+	ms.On("CreateOrUpdateDeployment", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		d = args.Get(1).(*appsv1.Deployment)
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+	assert.NoError(client.EnsureSentinelConfigMap(rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureRedisShutdownConfigMap(rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureSentinelService(rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureSentinelDeployment(rf, nil, []metav1.OwnerReference{}))
+
+	assert.Contains(sentinelConfig, "sentinel monitor cache 127.0.0.1 0 2\n")
+	assert.Contains(sentinelConfig, "sentinel parallel-syncs cache 2\n")
+	assert.Contains(sentinelConfig, "\nport 26380")
+	assert.NotContains(sentinelConfig, "mymaster")
+	assert.Contains(shutdownScript, "SENTINEL get-master-addr-by-name cache |")
+	assert.Contains(shutdownScript, "SENTINEL failover cache\n")
+	assert.Equal(int32(26380), service.Spec.Ports[0].Port)
+	assert.Equal(intstr.FromInt32(26380), service.Spec.Ports[0].TargetPort)
+
+	sentinel := d.Spec.Template.Spec.Containers[0]
+	assert.Equal(int32(26380), sentinel.Ports[0].ContainerPort)
+	assert.Equal("redis-cli -h $(hostname) -p 26380 ping", sentinel.LivenessProbe.Exec.Command[2])
+	assert.Equal("redis-cli -h $(hostname) -p 26380 sentinel get-master-addr-by-name cache | head -n 1 | grep -vq '127.0.0.1'", sentinel.ReadinessProbe.Exec.Command[2])
+}
@@ -1,10 +1,15 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
 	"github.com/saremox/redis-operator/log"
@@ -19,19 +24,32 @@ import (
 // failover, not a total failure.
 var ErrPartialReconciliation = errors.New("promotion succeeded but replica reconciliation incomplete")
 
+// switchoverPollInterval is how often the replication state is polled during a switchover.
+var switchoverPollInterval = 100 * time.Millisecond
+
+// sentinelInfoPeriod is how often the sentinels refresh the INFO of the replicas.
+const sentinelInfoPeriod = 10 * time.Second
+
 // RedisFailoverHeal defines the interface able to fix the problems on the redis failovers
 type RedisFailoverHeal interface {
-	MakeMaster(ip string, rFailover *redisfailoverv1.RedisFailover) error
-	SetOldestAsMaster(rFailover *redisfailoverv1.RedisFailover) error
-	SetMasterOnAll(masterIP string, rFailover *redisfailoverv1.RedisFailover) error
-	SetExternalMasterOnAll(masterIP string, masterPort string, rFailover *redisfailoverv1.RedisFailover) error
-	NewSentinelMonitor(ip string, monitor string, rFailover *redisfailoverv1.RedisFailover) error
-	NewSentinelMonitorWithPort(ip string, monitor string, port string, rFailover *redisfailoverv1.RedisFailover) error
-	RestoreSentinel(ip string) error
-	SetSentinelCustomConfig(ip string, rFailover *redisfailoverv1.RedisFailover) error
-	SetRedisCustomConfig(ip string, rFailover *redisfailoverv1.RedisFailover) error
+	MakeMaster(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	SetOldestAsMaster(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) error
+	SetMasterOnAll(ctx context.Context, masterIP string, rFailover *redisfailoverv1.RedisFailover) error
+	SetExternalMasterOnAll(ctx context.Context, masterIP string, masterPort string, rFailover *redisfailoverv1.RedisFailover) error
+	NewSentinelMonitor(ctx context.Context, ip string, monitor string, rFailover *redisfailoverv1.RedisFailover) error
+	NewSentinelMonitorWithPort(ctx context.Context, ip string, monitor string, port string, rFailover *redisfailoverv1.RedisFailover) error
+	RestoreSentinel(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	SetSentinelCustomConfig(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	SetRedisCustomConfig(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
 	DeletePod(podName string, rFailover *redisfailoverv1.RedisFailover) error
-	PromoteBestReplica(newMasterIP string, rFailover *redisfailoverv1.RedisFailover) error
+	PromoteBestReplica(ctx context.Context, newMasterIP string, rFailover *redisfailoverv1.RedisFailover) error
+	Switchover(ctx context.Context, masterIP string, newMasterIP string, rFailover *redisfailoverv1.RedisFailover) error
+	SentinelSwitchover(ctx context.Context, sentinelIP string, masterIP string, newMasterIP string, rFailover *redisfailoverv1.RedisFailover) error
+	DemoteStaleMasters(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) error
+	SyncACLUsers(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) ([]string, error)
+	AddRedisPassword(ctx context.Context, ip string, password string, rFailover *redisfailoverv1.RedisFailover) error
+	RemoveRedisPassword(ctx context.Context, ip string, password string, rFailover *redisfailoverv1.RedisFailover) error
+	SetSentinelAuthPass(ctx context.Context, ip string, password string, rFailover *redisfailoverv1.RedisFailover) error
 }
 
 // RedisFailoverHealer is our implementation of RedisFailoverCheck interface
@@ -51,16 +69,13 @@ func NewRedisFailoverHealer(k8sService k8s.Services, redisClient redis.Client, l
 	}
 }
 
-func (r *RedisFailoverHealer) setMasterLabelIfNecessary(namespace string, pod v1.Pod) error {
-	for labelKey, labelValue := range pod.Labels {
-		if labelKey == redisRoleLabelKey && labelValue == redisRoleLabelMaster {
-			return nil
+func (r *RedisFailoverHealer) setSlaveLabelIfNecessary(namespace string, pod v1.Pod) error {
+	// A demoted master loses the failover epoch it was elected at
+	if _, ok := pod.Labels[redisEpochLabelKey]; ok {
+		if err := r.k8sService.RemovePodLabels(namespace, pod.Name, []string{redisEpochLabelKey}); err != nil {
+			return err
 		}
 	}
-	return r.k8sService.UpdatePodLabels(namespace, pod.Name, generateRedisMasterRoleLabel())
-}
-
-func (r *RedisFailoverHealer) setSlaveLabelIfNecessary(namespace string, pod v1.Pod) error {
 	for labelKey, labelValue := range pod.Labels {
 		if labelKey == redisRoleLabelKey && labelValue == redisRoleLabelSlave {
 			return nil
@@ -69,14 +84,33 @@ func (r *RedisFailoverHealer) setSlaveLabelIfNecessary(namespace string, pod v1.
 	return r.k8sService.UpdatePodLabels(namespace, pod.Name, generateRedisSlaveRoleLabel())
 }
 
-func (r *RedisFailoverHealer) MakeMaster(ip string, rf *redisfailoverv1.RedisFailover) error {
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+// setNewMasterLabel labels the pod elected master with the next failover epoch, so a returning
+// old master can be told apart and fenced. The epoch is only raised once the pod holds it, so a
+// failed update doesn't leave the status ahead of every master.
+func (r *RedisFailoverHealer) setNewMasterLabel(rf *redisfailoverv1.RedisFailover, pod v1.Pod) error {
+	epoch := rf.Status.FailoverEpoch + 1
+	labels := generateRedisMasterRoleLabel()
+	labels[redisEpochLabelKey] = strconv.FormatInt(epoch, 10)
+	if err := r.k8sService.UpdatePodLabels(rf.Namespace, pod.Name, labels); err != nil {
+		return err
+	}
+	rf.Status.FailoverEpoch = epoch
+	return nil
+}
+
+func (r *RedisFailoverHealer) MakeMaster(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
 	if err != nil {
 		return err
 	}
 
 	port := getRedisPort(rf.Spec.Redis.Port)
-	err = r.redisClient.MakeMaster(ip, port, password)
+	err = redisClient.MakeMaster(ctx, ip, port, password)
 	if err != nil {
 		return err
 	}
@@ -86,15 +120,15 @@ func (r *RedisFailoverHealer) MakeMaster(ip string, rf *redisfailoverv1.RedisFai
 		return err
 	}
 	for _, rp := range rps.Items {
-		if rp.Status.PodIP == ip {
-			return r.setMasterLabelIfNecessary(rf.Namespace, rp)
+		if redisfailoverv1.SameAddress(rf.PodAddress(&rp), ip) {
+			return r.setNewMasterLabel(rf, rp)
 		}
 	}
 	return nil
 }
 
 // SetOldestAsMaster puts all redis to the same master, choosen by order of appearance
-func (r *RedisFailoverHealer) SetOldestAsMaster(rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) SetOldestAsMaster(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
 	ssp, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
 		return err
@@ -108,7 +142,12 @@ func (r *RedisFailoverHealer) SetOldestAsMaster(rf *redisfailoverv1.RedisFailove
 		return ssp.Items[i].CreationTimestamp.Before(&ssp.Items[j].CreationTimestamp)
 	})
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
 	if err != nil {
 		return err
 	}
@@ -117,24 +156,24 @@ func (r *RedisFailoverHealer) SetOldestAsMaster(rf *redisfailoverv1.RedisFailove
 	newMasterIP := ""
 	for _, pod := range ssp.Items {
 		if newMasterIP == "" {
-			newMasterIP = pod.Status.PodIP
+			newMasterIP = rf.PodAddress(&pod)
 			r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).Infof("New master is %s with ip %s", pod.Name, newMasterIP)
-			if err := r.redisClient.MakeMaster(newMasterIP, port, password); err != nil {
+			if err := redisClient.MakeMaster(ctx, newMasterIP, port, password); err != nil {
 				newMasterIP = ""
-				r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).Errorf("Make new master failed, master ip: %s, error: %v", pod.Status.PodIP, err)
+				r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).Errorf("Make new master failed, master ip: %s, error: %v", rf.PodAddress(&pod), err)
 				continue
 			}
 
-			err = r.setMasterLabelIfNecessary(rf.Namespace, pod)
+			err = r.setNewMasterLabel(rf, pod)
 			if err != nil {
 				return err
 			}
 
-			newMasterIP = pod.Status.PodIP
+			newMasterIP = rf.PodAddress(&pod)
 		} else {
 			r.logger.Infof("Making pod %s slave of %s", pod.Name, newMasterIP)
-			if err := r.redisClient.MakeSlaveOfWithPort(pod.Status.PodIP, newMasterIP, port, password); err != nil {
-				r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).Errorf("Make slave failed, slave pod ip: %s, master ip: %s, error: %v", pod.Status.PodIP, newMasterIP, err)
+			if err := redisClient.MakeSlaveOfWithPort(ctx, rf.PodAddress(&pod), newMasterIP, port, password); err != nil {
+				r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).Errorf("Make slave failed, slave pod ip: %s, master ip: %s, error: %v", rf.PodAddress(&pod), newMasterIP, err)
 			}
 
 			err = r.setSlaveLabelIfNecessary(rf.Namespace, pod)
@@ -151,13 +190,18 @@ func (r *RedisFailoverHealer) SetOldestAsMaster(rf *redisfailoverv1.RedisFailove
 }
 
 // SetMasterOnAll puts all redis nodes as a slave of a given master
-func (r *RedisFailoverHealer) SetMasterOnAll(masterIP string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) SetMasterOnAll(ctx context.Context, masterIP string, rf *redisfailoverv1.RedisFailover) error {
 	ssp, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
 		return err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
 	if err != nil {
 		return err
 	}
@@ -165,17 +209,17 @@ func (r *RedisFailoverHealer) SetMasterOnAll(masterIP string, rf *redisfailoverv
 	port := getRedisPort(rf.Spec.Redis.Port)
 	for _, pod := range ssp.Items {
 		//During this configuration process if there is a new master selected , bailout
-		isMaster, err := r.redisClient.IsMaster(masterIP, port, password)
+		isMaster, err := redisClient.IsMaster(ctx, masterIP, port, password)
 		if err != nil || !isMaster {
 			r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).Errorf("check master failed maybe this node is not ready(ip changed), or sentinel made a switch: %s", masterIP)
 			return err
 		} else {
-			if pod.Status.PodIP == masterIP {
+			if redisfailoverv1.SameAddress(rf.PodAddress(&pod), masterIP) {
 				continue
 			}
 			r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).Infof("Making pod %s slave of %s", pod.Name, masterIP)
-			if err := r.redisClient.MakeSlaveOfWithPort(pod.Status.PodIP, masterIP, port, password); err != nil {
-				r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).Errorf("Make slave failed, slave ip: %s, master ip: %s, error: %v", pod.Status.PodIP, masterIP, err)
+			if err := redisClient.MakeSlaveOfWithPort(ctx, rf.PodAddress(&pod), masterIP, port, password); err != nil {
+				r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).Errorf("Make slave failed, slave ip: %s, master ip: %s, error: %v", rf.PodAddress(&pod), masterIP, err)
 				return err
 			}
 
@@ -190,20 +234,25 @@ func (r *RedisFailoverHealer) SetMasterOnAll(masterIP string, rf *redisfailoverv
 
 // SetExternalMasterOnAll puts all redis nodes as a slave of a given master outside of
 // the current RedisFailover instance
-func (r *RedisFailoverHealer) SetExternalMasterOnAll(masterIP, masterPort string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) SetExternalMasterOnAll(ctx context.Context, masterIP, masterPort string, rf *redisfailoverv1.RedisFailover) error {
 	ssp, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
 		return err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
 	if err != nil {
 		return err
 	}
 
 	for _, pod := range ssp.Items {
-		r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).Infof("Making pod %s slave of %s:%s", pod.Name, masterIP, masterPort)
-		if err := r.redisClient.MakeSlaveOfWithPort(pod.Status.PodIP, masterIP, masterPort, password); err != nil {
+		r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).Infof("Making pod %s slave of %s", pod.Name, net.JoinHostPort(masterIP, masterPort))
+		if err := redisClient.MakeSlaveOfWithPort(ctx, rf.PodAddress(&pod), masterIP, masterPort, password); err != nil {
 			return err
 		}
 
@@ -212,53 +261,93 @@ func (r *RedisFailoverHealer) SetExternalMasterOnAll(masterIP, masterPort string
 }
 
 // NewSentinelMonitor changes the master that Sentinel has to monitor
-func (r *RedisFailoverHealer) NewSentinelMonitor(ip string, monitor string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) NewSentinelMonitor(ctx context.Context, ip string, monitor string, rf *redisfailoverv1.RedisFailover) error {
 	quorum := strconv.Itoa(int(getQuorum(rf)))
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := getAppliedRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
 	if err != nil {
 		return err
 	}
 
 	port := getRedisPort(rf.Spec.Redis.Port)
-	return r.redisClient.MonitorRedisWithPort(ip, monitor, port, quorum, password)
+	return redisClient.MonitorRedisWithPort(ctx, ip, monitor, port, quorum, password)
 }
 
 // NewSentinelMonitorWithPort changes the master that Sentinel has to monitor by the provided IP and Port
-func (r *RedisFailoverHealer) NewSentinelMonitorWithPort(ip string, monitor string, monitorPort string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) NewSentinelMonitorWithPort(ctx context.Context, ip string, monitor string, monitorPort string, rf *redisfailoverv1.RedisFailover) error {
 	quorum := strconv.Itoa(int(getQuorum(rf)))
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := getAppliedRedisPassword(r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
-	return r.redisClient.MonitorRedisWithPort(ip, monitor, monitorPort, quorum, password)
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return err
+	}
+
+	return redisClient.MonitorRedisWithPort(ctx, ip, monitor, monitorPort, quorum, password)
 }
 
 // RestoreSentinel clear the number of sentinels on memory
-func (r *RedisFailoverHealer) RestoreSentinel(ip string) error {
+func (r *RedisFailoverHealer) RestoreSentinel(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return err
+	}
 	r.logger.Debugf("Restoring sentinel %s", ip)
-	return r.redisClient.ResetSentinel(ip)
+	return redisClient.ResetSentinel(ctx, ip)
 }
 
 // SetSentinelCustomConfig will call sentinel to set the configuration given in config
-func (r *RedisFailoverHealer) SetSentinelCustomConfig(ip string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) SetSentinelCustomConfig(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return err
+	}
 	r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).Debugf("Setting the custom config on sentinel %s...", ip)
-	return r.redisClient.SetCustomSentinelConfig(ip, rf.Spec.Sentinel.CustomConfig)
+	if rf.Spec.AnnounceHostnames {
+		// The sentinels started before the option was set only read it from their configuration
+		if err := redisClient.SetSentinelGlobalConfig(ctx, ip, sentinelHostnamesConfig); err != nil {
+			return err
+		}
+	}
+	if len(rf.Spec.Redis.CustomCommandRenames) > 0 {
+		// The renames are only given to the sentinels along with a new monitor otherwise
+		if err := redisClient.SetSentinelCommandRenames(ctx, ip); err != nil {
+			return err
+		}
+	}
+	return redisClient.SetCustomSentinelConfig(ctx, ip, rf.Spec.Sentinel.CustomConfig)
 }
 
-// SetRedisCustomConfig will call redis to set the configuration given in config
-func (r *RedisFailoverHealer) SetRedisCustomConfig(ip string, rf *redisfailoverv1.RedisFailover) error {
+// SetRedisCustomConfig will call redis to set the managed configuration: the one of the
+// configuration template, the persistence configuration and the one given in config
+func (r *RedisFailoverHealer) SetRedisCustomConfig(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
 	r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).Debugf("Setting the custom config on redis %s...", ip)
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
 	if err != nil {
 		return err
 	}
 
+	config, err := getManagedRedisConfig(r.k8sService, rf, ip)
+	if err != nil {
+		return err
+	}
 	port := getRedisPort(rf.Spec.Redis.Port)
-	return r.redisClient.SetCustomRedisConfig(ip, port, rf.Spec.Redis.CustomConfig, password)
+	return redisClient.SetCustomRedisConfig(ctx, ip, port, config, password)
 }
 
 // DeletePod delete a failing pod so kubernetes relaunch it again
@@ -269,8 +358,13 @@ func (r *RedisFailoverHealer) DeletePod(podName string, rFailover *redisfailover
 
 // PromoteBestReplica promotes a replica to master and reconfigures all other replicas.
 // This is used for operator-managed failover when Sentinel is disabled.
-func (r *RedisFailoverHealer) PromoteBestReplica(newMasterIP string, rf *redisfailoverv1.RedisFailover) error {
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+func (r *RedisFailoverHealer) PromoteBestReplica(ctx context.Context, newMasterIP string, rf *redisfailoverv1.RedisFailover) error {
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
 	if err != nil {
 		return err
 	}
@@ -281,21 +375,21 @@ func (r *RedisFailoverHealer) PromoteBestReplica(newMasterIP string, rf *redisfa
 	r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).
 		Infof("Promoting replica %s to master", newMasterIP)
 
-	if err := r.redisClient.MakeMaster(newMasterIP, port, password); err != nil {
+	if err := redisClient.MakeMaster(ctx, newMasterIP, port, password); err != nil {
 		r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).
 			Errorf("Failed to promote replica %s to master: %v", newMasterIP, err)
 		return err
 	}
 
-	// Step 2: Update pod labels for the new master
+	// Step 2: Update pod labels for the new master, recording the failover epoch it was promoted at
 	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
 	if err != nil {
 		return err
 	}
 
 	for _, rp := range rps.Items {
-		if rp.Status.PodIP == newMasterIP {
-			if err := r.setMasterLabelIfNecessary(rf.Namespace, rp); err != nil {
+		if redisfailoverv1.SameAddress(rf.PodAddress(&rp), newMasterIP) {
+			if err := r.setNewMasterLabel(rf, rp); err != nil {
 				r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).
 					Errorf("Failed to set master label on pod %s: %v", rp.Name, err)
 				return err
@@ -310,7 +404,7 @@ func (r *RedisFailoverHealer) PromoteBestReplica(newMasterIP string, rf *redisfa
 
 	var reconcileErrs []error
 	for _, rp := range rps.Items {
-		if rp.Status.PodIP == newMasterIP {
+		if redisfailoverv1.SameAddress(rf.PodAddress(&rp), newMasterIP) {
 			continue
 		}
 		if rp.Status.Phase != v1.PodRunning || rp.DeletionTimestamp != nil {
@@ -320,9 +414,9 @@ func (r *RedisFailoverHealer) PromoteBestReplica(newMasterIP string, rf *redisfa
 		r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).
 			Infof("Making pod %s slave of %s", rp.Name, newMasterIP)
 
-		if err := r.redisClient.MakeSlaveOfWithPort(rp.Status.PodIP, newMasterIP, port, password); err != nil {
+		if err := redisClient.MakeSlaveOfWithPort(ctx, rf.PodAddress(&rp), newMasterIP, port, password); err != nil {
 			r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).
-				Errorf("Failed to make %s slave of %s: %v", rp.Status.PodIP, newMasterIP, err)
+				Errorf("Failed to make %s slave of %s: %v", rf.PodAddress(&rp), newMasterIP, err)
 			reconcileErrs = append(reconcileErrs, err)
 			continue
 		}
@@ -343,3 +437,436 @@ func (r *RedisFailoverHealer) PromoteBestReplica(newMasterIP string, rf *redisfa
 
 	return nil
 }
+
+// Switchover hands the master role over to the given replica without losing acknowledged writes.
+// Writes are paused on the current master until the replica has caught up with it, then the
+// replica is promoted and every other pod, the old master included, is repointed to it.
+func (r *RedisFailoverHealer) Switchover(ctx context.Context, masterIP string, newMasterIP string, rf *redisfailoverv1.RedisFailover) error {
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	timeout := rf.GetFailoverTimeoutDuration()
+
+	r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).
+		Infof("Switching master over from %s to %s", masterIP, newMasterIP)
+
+	// The pause outlives the whole switchover in case the operator can't unpause the old master.
+	if err := redisClient.PauseWrites(ctx, masterIP, port, password, 2*timeout); err != nil {
+		return err
+	}
+	defer r.unpauseClients(context.WithoutCancel(ctx), redisClient, masterIP, port, password, rf)
+
+	if err := r.waitForReplicaCatchUp(ctx, redisClient, masterIP, newMasterIP, port, password, timeout); err != nil {
+		return err
+	}
+
+	return r.PromoteBestReplica(ctx, newMasterIP, rf)
+}
+
+// SentinelSwitchover hands the master role over to the given replica through Sentinel.
+// As Sentinel chooses the replica to promote by itself, every other replica is excluded from the
+// election with a replica-priority of 0 until the failover is done, and the failover only starts
+// once the sentinel sees the new priorities. Writes are then paused on the current master until
+// the replica has caught up with it.
+func (r *RedisFailoverHealer) SentinelSwitchover(ctx context.Context, sentinelIP string, masterIP string, newMasterIP string, rf *redisfailoverv1.RedisFailover) error {
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	timeout := rf.GetFailoverTimeoutDuration()
+
+	r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).
+		Infof("Switching master over from %s to %s through sentinel %s", masterIP, newMasterIP, sentinelIP)
+
+	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+	if err != nil {
+		return err
+	}
+
+	var excluded []string
+	defer func() {
+		// Restore the priorities given by the custom config, even once the context is canceled.
+		for _, ip := range excluded {
+			if err := redisClient.SetCustomRedisConfig(context.WithoutCancel(ctx), ip, port, rf.RedisConfig(), password); err != nil {
+				r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).
+					Warningf("Unable to restore the custom config of %s: %v", ip, err)
+			}
+		}
+	}()
+	for _, rp := range rps.Items {
+		if redisfailoverv1.SameAddress(rf.PodAddress(&rp), masterIP) || redisfailoverv1.SameAddress(rf.PodAddress(&rp), newMasterIP) {
+			continue
+		}
+		if rp.Status.Phase != v1.PodRunning || rp.DeletionTimestamp != nil {
+			continue
+		}
+		if err := redisClient.SetCustomRedisConfig(ctx, rf.PodAddress(&rp), port, []string{"replica-priority 0"}, password); err != nil {
+			return err
+		}
+		excluded = append(excluded, rf.PodAddress(&rp))
+	}
+	if err := r.waitForSentinelExclusion(ctx, redisClient, sentinelIP, excluded, timeout); err != nil {
+		return err
+	}
+
+	if err := redisClient.PauseWrites(ctx, masterIP, port, password, 2*timeout); err != nil {
+		return err
+	}
+	defer r.unpauseClients(context.WithoutCancel(ctx), redisClient, masterIP, port, password, rf)
+
+	if err := r.waitForReplicaCatchUp(ctx, redisClient, masterIP, newMasterIP, port, password, timeout); err != nil {
+		return err
+	}
+
+	if err := redisClient.SentinelFailover(ctx, sentinelIP); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		isMaster, err := redisClient.IsMaster(ctx, newMasterIP, port, password)
+		if err == nil && isMaster {
+			r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).
+				Infof("Switchover completed: %s is now master", newMasterIP)
+			return nil
+		}
+		// The sentinel monitors the promoted replica once the failover is done
+		promotedIP, _, err := redisClient.GetSentinelMonitor(ctx, sentinelIP)
+		if err == nil && !redisfailoverv1.SameAddress(promotedIP, masterIP) && !redisfailoverv1.SameAddress(promotedIP, newMasterIP) {
+			return fmt.Errorf("sentinel promoted %s instead of %s", promotedIP, newMasterIP)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("sentinel did not promote %s in %s", newMasterIP, timeout)
+		}
+		if err := sleepContext(ctx, switchoverPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// waitForSentinelExclusion waits until the sentinel knows every excluded replica with a
+// replica-priority of 0. The sentinel elects the replica to promote from the INFO of the
+// replicas it caches, which is only refreshed every 10 seconds.
+func (r *RedisFailoverHealer) waitForSentinelExclusion(ctx context.Context, redisClient redis.Client, sentinelIP string, excluded []string, timeout time.Duration) error {
+	if len(excluded) == 0 {
+		return nil
+	}
+	// The refresh of the sentinel is waited for on top of the failover timeout
+	deadline := time.Now().Add(timeout + sentinelInfoPeriod)
+	for {
+		priorities, err := redisClient.GetSentinelReplicaPriorities(ctx, sentinelIP)
+		if err != nil {
+			return err
+		}
+		pending := excludedReplicasPending(priorities, excluded)
+		if len(pending) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("sentinel %s did not see the replica-priority 0 of %s", sentinelIP, strings.Join(pending, ", "))
+		}
+		if err := sleepContext(ctx, switchoverPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// excludedReplicasPending returns the excluded replicas the sentinel does not know with a
+// replica-priority of 0 yet
+func excludedReplicasPending(priorities map[string]string, excluded []string) []string {
+	pending := []string{}
+	for _, replica := range excluded {
+		seen := false
+		for address, priority := range priorities {
+			if redisfailoverv1.SameAddress(address, replica) && priority == "0" {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			pending = append(pending, replica)
+		}
+	}
+	return pending
+}
+
+// waitForReplicaCatchUp waits until the replica has processed the whole replication stream of the master.
+func (r *RedisFailoverHealer) waitForReplicaCatchUp(ctx context.Context, redisClient redis.Client, masterIP string, replicaIP string, port string, password string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		masterInfo, err := redisClient.GetReplicationInfo(ctx, masterIP, port, password)
+		if err != nil {
+			return err
+		}
+		replicaInfo, err := redisClient.GetReplicationInfo(ctx, replicaIP, port, password)
+		if err != nil {
+			return err
+		}
+		if replicaInfo.MasterLinkStatus == "up" && replicaInfo.SlaveReplOffset >= masterInfo.MasterReplOffset {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("replica %s did not catch up with master %s in %s (offset %d of %d)", replicaIP, masterIP, timeout, replicaInfo.SlaveReplOffset, masterInfo.MasterReplOffset)
+		}
+		if err := sleepContext(ctx, switchoverPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// sleepContext waits for the given duration, or returns the error of the context once it is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (r *RedisFailoverHealer) unpauseClients(ctx context.Context, redisClient redis.Client, ip string, port string, password string, rf *redisfailoverv1.RedisFailover) {
+	if err := redisClient.UnpauseClients(ctx, ip, port, password); err != nil {
+		r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).
+			Warningf("Unable to unpause clients of %s, they will be unpaused when the pause expires: %v", ip, err)
+	}
+}
+
+// DemoteStaleMasters fences the masters left behind by a failover, e.g. an old master coming back
+// after a network partition still believing it is master. The master elected at the latest
+// failover epoch keeps its role, a master elected before it is stale. The stale ones lose the
+// master label first, so the master service stops routing to them, and are then made replicas of
+// it. When no master holds the latest epoch they are all demoted, so one is elected at the next.
+func (r *RedisFailoverHealer) DemoteStaleMasters(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return err
+	}
+
+	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+	if err != nil {
+		return err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	var masters []v1.Pod
+	for _, rp := range rps.Items {
+		if rp.Status.Phase != v1.PodRunning || rp.DeletionTimestamp != nil {
+			continue
+		}
+		isMaster, err := redisClient.IsMaster(ctx, rf.PodAddress(&rp), port, password)
+		if err != nil {
+			return err
+		}
+		if isMaster {
+			masters = append(masters, rp)
+		}
+	}
+	if len(masters) < 2 {
+		return nil
+	}
+
+	// The status may not have recorded the latest epoch a master was elected at
+	for _, master := range masters {
+		if epoch := getFailoverEpoch(master); epoch > rf.Status.FailoverEpoch {
+			rf.Status.FailoverEpoch = epoch
+		}
+	}
+	var current, stale []v1.Pod
+	for _, master := range masters {
+		if rf.Status.FailoverEpoch != 0 && getFailoverEpoch(master) == rf.Status.FailoverEpoch {
+			current = append(current, master)
+		} else {
+			stale = append(stale, master)
+		}
+	}
+
+	// Replicating the loopback address, as the redis nodes do when they start, leaves them
+	// without master until one is elected
+	masterIP := "127.0.0.1"
+	if len(current) == 1 {
+		masterIP = rf.PodAddress(&current[0])
+	} else {
+		stale = masters
+	}
+	for _, master := range stale {
+		r.logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace).
+			Warningf("Demoting stale master %s (epoch %d), the latest failover epoch is %d", master.Name, getFailoverEpoch(master), rf.Status.FailoverEpoch)
+		if err := r.setSlaveLabelIfNecessary(rf.Namespace, master); err != nil {
+			return err
+		}
+		if err := redisClient.MakeSlaveOfWithPort(ctx, rf.PodAddress(&master), masterIP, port, password); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SyncACLUsers applies the declared ACL users and the operator user to the redis node, and deletes
+// the users that are not declared. It returns the changes that were made to the users of the node.
+func (r *RedisFailoverHealer) SyncACLUsers(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) ([]string, error) {
+	password, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return nil, err
+	}
+	authSecret, err := r.k8sService.GetSecret(rf.Namespace, GetRedisAuthSecretName(rf))
+	if err != nil {
+		return nil, err
+	}
+	desired, err := getACLUsers(r.k8sService, rf, authSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	current, err := redisClient.GetACLUsers(ctx, ip, port, password)
+	if redis.IsAuthError(err) {
+		// The operator user is only created at startup by the nodes started once the users are declared
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Operator user unknown by %s, authenticating as the default user", ip)
+		if password, err = getAppliedRedisPassword(r.k8sService, rf); err != nil {
+			return nil, err
+		}
+		if redisClient, err = getRedisClientAs(r.k8sService, r.redisClient, rf, ""); err != nil {
+			return nil, err
+		}
+		current, err = redisClient.GetACLUsers(ctx, ip, port, password)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := redisClient.SetACLUser(ctx, ip, port, password, name, append([]string{"reset"}, desired[name]...)); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := []string{}
+	for name := range current {
+		if _, ok := desired[name]; ok || name == redisfailoverv1.DefaultUserName || name == redisfailoverv1.ProbeUserName {
+			continue
+		}
+		if err := redisClient.DeleteACLUser(ctx, ip, port, password, name); err != nil {
+			return nil, err
+		}
+		changes = append(changes, fmt.Sprintf("deleted user %s", name))
+	}
+
+	// The rules are compared once applied, as they are normalized by redis
+	applied, err := redisClient.GetACLUsers(ctx, ip, port, password)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		rules, ok := current[name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("created user %s", name))
+		} else if rules != applied[name] {
+			changes = append(changes, fmt.Sprintf("updated user %s", name))
+		}
+	}
+	sort.Strings(changes)
+	return changes, nil
+}
+
+// AddRedisPassword makes the default user of the redis node accept the password along with the
+// ones it already has, and makes the node authenticate with it against its master
+func (r *RedisFailoverHealer) AddRedisPassword(ctx context.Context, ip string, password string, rf *redisfailoverv1.RedisFailover) error {
+	operatorPassword, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	if err := redisClient.SetACLUser(ctx, ip, port, operatorPassword, redisfailoverv1.DefaultUserName, getDefaultUserPasswordRules(password)); err != nil {
+		return err
+	}
+	masterauth := password
+	if masterauth == "" {
+		masterauth = `""`
+	}
+	return redisClient.SetCustomRedisConfig(ctx, ip, port, []string{fmt.Sprintf("masterauth %s", masterauth)}, operatorPassword)
+}
+
+// RemoveRedisPassword makes the default user of the redis node stop accepting the password
+func (r *RedisFailoverHealer) RemoveRedisPassword(ctx context.Context, ip string, password string, rf *redisfailoverv1.RedisFailover) error {
+	if password == "" {
+		// The default user stopped accepting any password once it was given one
+		return nil
+	}
+	operatorPassword, err := getRedisPassword(r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	users, err := redisClient.GetACLUsers(ctx, ip, port, operatorPassword)
+	if err != nil {
+		return err
+	}
+	// Removing a password the user does not have fails, as on the nodes restarted with the new one
+	if !slices.Contains(strings.Fields(users[redisfailoverv1.DefaultUserName]), getACLPasswordRule(password)) {
+		return nil
+	}
+	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Removing the previous password from %s", ip)
+	return redisClient.SetACLUser(ctx, ip, port, operatorPassword, redisfailoverv1.DefaultUserName, []string{getACLPasswordRemovalRule(password)})
+}
+
+// SetSentinelAuthPass changes the password the sentinel authenticates with against the redis nodes
+func (r *RedisFailoverHealer) SetSentinelAuthPass(ctx context.Context, ip string, password string, rf *redisfailoverv1.RedisFailover) error {
+	redisClient, err := getRedisClient(r.k8sService, r.redisClient, rf)
+	if err != nil {
+		return err
+	}
+	if password == "" {
+		password = `""`
+	}
+	return redisClient.SetCustomSentinelConfig(ctx, ip, []string{fmt.Sprintf("auth-pass %s", password)})
+}
+
+// getFailoverEpoch returns the failover epoch the pod was promoted to master at, 0 if unknown.
+func getFailoverEpoch(pod v1.Pod) int64 {
+	epoch, err := strconv.ParseInt(pod.Labels[redisEpochLabelKey], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return epoch
+}
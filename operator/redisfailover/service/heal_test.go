@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -11,10 +12,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
 	"github.com/saremox/redis-operator/log"
 	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
 	mRedisService "github.com/saremox/redis-operator/mocks/service/redis"
 	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+	"github.com/saremox/redis-operator/service/redis"
 )
 
 func TestSetOldestAsMasterNewMasterError(t *testing.T) {
@@ -36,11 +39,11 @@ func TestSetOldestAsMasterNewMasterError(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "0.0.0.0", "0", "").Once().Return(errors.New(""))
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(errors.New(""))
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
 	assert.Error(err)
 }
 
@@ -48,6 +51,7 @@ func TestSetOldestAsMaster(t *testing.T) {
 	assert := assert.New(t)
 
 	rf := generateRF()
+	rf.Status.FailoverEpoch = 2
 
 	pods := &corev1.PodList{
 		Items: []corev1.Pod{
@@ -61,14 +65,48 @@ func TestSetOldestAsMaster(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
-	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
+	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), map[string]string{"redisfailovers-role": "master", "redisfailovers-epoch": "3"}).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(nil)
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
 	assert.NoError(err)
+	assert.Equal(int64(3), rf.Status.FailoverEpoch)
+}
+
+func TestMakeMaster(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Status.FailoverEpoch = 4
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod-0"},
+				Status:     corev1.PodStatus{PodIP: "0.0.0.0"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod-1"},
+				Status:     corev1.PodStatus{PodIP: "1.1.1.1"},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", namespace, "pod-0", map[string]string{"redisfailovers-role": "master", "redisfailovers-epoch": "5"}).Once().Return(nil)
+	mr := &mRedisService.Client{}
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	assert.NoError(healer.MakeMaster(context.TODO(), "0.0.0.0", rf))
+	assert.Equal(int64(5), rf.Status.FailoverEpoch)
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
 }
 
 func TestSetOldestAsMasterMultiplePodsMakeSlaveOfError(t *testing.T) {
@@ -95,12 +133,12 @@ func TestSetOldestAsMasterMultiplePodsMakeSlaveOfError(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "0.0.0.0", "0", "").Once().Return(nil)
-	mr.On("MakeSlaveOfWithPort", "1.1.1.1", "0.0.0.0", "0", "").Once().Return(errors.New(""))
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "0.0.0.0", "0", "").Once().Return(errors.New(""))
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
 	assert.NoError(err)
 }
 
@@ -128,12 +166,12 @@ func TestSetOldestAsMasterMultiplePods(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "0.0.0.0", "0", "").Once().Return(nil)
-	mr.On("MakeSlaveOfWithPort", "1.1.1.1", "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "0.0.0.0", "0", "").Once().Return(nil)
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
 	assert.NoError(err)
 }
 
@@ -171,12 +209,12 @@ func TestSetOldestAsMasterOrdering(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "1.1.1.1", "0", "").Once().Return(nil)
-	mr.On("MakeSlaveOfWithPort", "0.0.0.0", "1.1.1.1", "0", "").Once().Return(nil)
+	mr.On("MakeMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "0", "").Once().Return(nil)
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.TODO(), rf)
 	assert.NoError(err)
 }
 
@@ -204,10 +242,10 @@ func TestSetMasterOnAllMakeMasterError(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Return(false, errors.New(""))
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Return(false, errors.New(""))
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetMasterOnAll("0.0.0.0", rf)
+	err := healer.SetMasterOnAll(context.TODO(), "0.0.0.0", rf)
 	assert.Error(err)
 }
 
@@ -235,12 +273,12 @@ func TestSetMasterOnAllMakeSlaveOfError(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Return(true, nil)
-	mr.On("MakeSlaveOfWithPort", "1.1.1.1", "0.0.0.0", "0", "").Once().Return(errors.New(""))
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Return(true, nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "0.0.0.0", "0", "").Once().Return(errors.New(""))
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetMasterOnAll("0.0.0.0", rf)
+	err := healer.SetMasterOnAll(context.TODO(), "0.0.0.0", rf)
 	assert.Error(err)
 }
 
@@ -268,15 +306,55 @@ func TestSetMasterOnAll(t *testing.T) {
 	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Return(true, nil)
-	mr.On("MakeSlaveOfWithPort", "1.1.1.1", "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Return(true, nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "0.0.0.0", "0", "").Once().Return(nil)
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetMasterOnAll("0.0.0.0", rf)
+	err := healer.SetMasterOnAll(context.TODO(), "0.0.0.0", rf)
 	assert.NoError(err)
 }
 
+func TestSetMasterOnAllAnnounceHostnames(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.AnnounceHostnames = true
+	masterAddress := "redis-0.redis.testns.svc"
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "redis-0", Namespace: namespace},
+				Spec:       corev1.PodSpec{Hostname: "redis-0", Subdomain: "redis"},
+				Status: corev1.PodStatus{
+					PodIP: "0.0.0.0",
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "redis-1", Namespace: namespace},
+				Spec:       corev1.PodSpec{Hostname: "redis-1", Subdomain: "redis"},
+				Status: corev1.PodStatus{
+					PodIP: "1.1.1.1",
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	mr := &mRedisService.Client{}
+	mr.On("IsMaster", mock.Anything, masterAddress, "0", "").Return(true, nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "redis-1.redis.testns.svc", masterAddress, "0", "").Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	err := healer.SetMasterOnAll(context.TODO(), masterAddress, rf)
+	assert.NoError(err)
+	mr.AssertExpectations(t)
+}
+
 func TestSetExternalMasterOnAll(t *testing.T) {
 	tests := []struct {
 		name                  string
@@ -327,18 +405,18 @@ func TestSetExternalMasterOnAll(t *testing.T) {
 
 			mr := &mRedisService.Client{}
 			if !expectError {
-				mr.On("MakeSlaveOfWithPort", "0.0.0.0", "5.5.5.5", "6379", "").Once().Return(nil)
+				mr.On("MakeSlaveOfWithPort", mock.Anything, "0.0.0.0", "5.5.5.5", "6379", "").Once().Return(nil)
 				if test.errorOnMakeSlaveOf {
 					expectError = true
-					mr.On("MakeSlaveOfWithPort", "1.1.1.1", "5.5.5.5", "6379", "").Once().Return(errors.New(""))
+					mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "5.5.5.5", "6379", "").Once().Return(errors.New(""))
 				} else {
-					mr.On("MakeSlaveOfWithPort", "1.1.1.1", "5.5.5.5", "6379", "").Once().Return(nil)
+					mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "5.5.5.5", "6379", "").Once().Return(nil)
 				}
 			}
 
 			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-			err := healer.SetExternalMasterOnAll("5.5.5.5", "6379", rf)
+			err := healer.SetExternalMasterOnAll(context.TODO(), "5.5.5.5", "6379", rf)
 
 			if expectError {
 				assert.Error(err)
@@ -382,13 +460,15 @@ func TestPromoteBestReplicaSuccess(t *testing.T) {
 	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", newMasterIP, "0", "").Once().Return(nil)
-	mr.On("MakeSlaveOfWithPort", replicaIP, newMasterIP, "0", "").Once().Return(nil)
+	mr.On("MakeMaster", mock.Anything, newMasterIP, "0", "").Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, replicaIP, newMasterIP, "0", "").Once().Return(nil)
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.PromoteBestReplica(newMasterIP, rf)
+	err := healer.PromoteBestReplica(context.TODO(), newMasterIP, rf)
 	assert.NoError(err)
+	assert.Equal(int64(1), rf.Status.FailoverEpoch)
+	ms.AssertCalled(t, "UpdatePodLabels", namespace, "pod-master", map[string]string{"redisfailovers-role": "master", "redisfailovers-epoch": "1"})
 	ms.AssertExpectations(t)
 	mr.AssertExpectations(t)
 }
@@ -424,18 +504,53 @@ func TestPromoteBestReplicaReplicaRepointerFails(t *testing.T) {
 	ms.On("UpdatePodLabels", namespace, "pod-master", mock.Anything).Return(nil)
 
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", newMasterIP, "0", "").Once().Return(nil)
-	mr.On("MakeSlaveOfWithPort", replicaIP, newMasterIP, "0", "").Once().Return(errors.New("replica repoint failed"))
+	mr.On("MakeMaster", mock.Anything, newMasterIP, "0", "").Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, replicaIP, newMasterIP, "0", "").Once().Return(errors.New("replica repoint failed"))
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.PromoteBestReplica(newMasterIP, rf)
+	err := healer.PromoteBestReplica(context.TODO(), newMasterIP, rf)
 	assert.Error(err, "partial failover should not be reported as success")
 	assert.True(errors.Is(err, rfservice.ErrPartialReconciliation), "replica repoint failure should be wrapped as ErrPartialReconciliation")
 	ms.AssertExpectations(t)
 	mr.AssertExpectations(t)
 }
 
+func TestPromoteBestReplicaMasterLabelFails(t *testing.T) {
+	assert := assert.New(t)
+	rf := generateRF()
+	rf.Status.FailoverEpoch = 3
+
+	newMasterIP := "1.1.1.1"
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod-master"},
+				Status: corev1.PodStatus{
+					PodIP: newMasterIP,
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", namespace, "pod-master", map[string]string{"redisfailovers-role": "master", "redisfailovers-epoch": "4"}).Once().Return(errors.New("label update failed"))
+
+	mr := &mRedisService.Client{}
+	mr.On("MakeMaster", mock.Anything, newMasterIP, "0", "").Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	err := healer.PromoteBestReplica(context.TODO(), newMasterIP, rf)
+	assert.Error(err)
+	// The epoch is not raised while no pod holds it
+	assert.Equal(int64(3), rf.Status.FailoverEpoch)
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
+
 func TestPromoteBestReplicaLabelUpdateFails(t *testing.T) {
 	assert := assert.New(t)
 	rf := generateRF()
@@ -468,12 +583,12 @@ func TestPromoteBestReplicaLabelUpdateFails(t *testing.T) {
 	ms.On("UpdatePodLabels", namespace, "pod-replica", mock.Anything).Return(errors.New("label update failed"))
 
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", newMasterIP, "0", "").Once().Return(nil)
-	mr.On("MakeSlaveOfWithPort", replicaIP, newMasterIP, "0", "").Once().Return(nil)
+	mr.On("MakeMaster", mock.Anything, newMasterIP, "0", "").Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, replicaIP, newMasterIP, "0", "").Once().Return(nil)
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.PromoteBestReplica(newMasterIP, rf)
+	err := healer.PromoteBestReplica(context.TODO(), newMasterIP, rf)
 	assert.Error(err, "label update failure should not be reported as success")
 	assert.True(errors.Is(err, rfservice.ErrPartialReconciliation), "slave label update failure should be wrapped as ErrPartialReconciliation")
 	ms.AssertExpectations(t)
@@ -488,11 +603,11 @@ func TestPromoteBestReplicaMakeMasterFails(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", newMasterIP, "0", "").Once().Return(errors.New("promotion failed"))
+	mr.On("MakeMaster", mock.Anything, newMasterIP, "0", "").Once().Return(errors.New("promotion failed"))
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.PromoteBestReplica(newMasterIP, rf)
+	err := healer.PromoteBestReplica(context.TODO(), newMasterIP, rf)
 	assert.Error(err, "MakeMaster failure should return an error")
 	assert.False(errors.Is(err, rfservice.ErrPartialReconciliation), "full promotion failure should not be wrapped as ErrPartialReconciliation")
 	ms.AssertExpectations(t)
@@ -523,14 +638,14 @@ func TestNewSentinelMonitor(t *testing.T) {
 
 			if test.errorOnMonitorRedis {
 				errorExpected = true
-				mr.On("MonitorRedisWithPort", "0.0.0.0", "1.1.1.1", "0", "2", "").Once().Return(errors.New(""))
+				mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "0", "2", "").Once().Return(errors.New(""))
 			} else {
-				mr.On("MonitorRedisWithPort", "0.0.0.0", "1.1.1.1", "0", "2", "").Once().Return(nil)
+				mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "0", "2", "").Once().Return(nil)
 			}
 
 			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-			err := healer.NewSentinelMonitor("0.0.0.0", "1.1.1.1", rf)
+			err := healer.NewSentinelMonitor(context.TODO(), "0.0.0.0", "1.1.1.1", rf)
 
 			if errorExpected {
 				assert.Error(err)
@@ -567,14 +682,14 @@ func TestNewSentinelMonitorWithPort(t *testing.T) {
 
 			if test.errorOnMonitorRedis {
 				errorExpected = true
-				mr.On("MonitorRedisWithPort", "0.0.0.0", "1.1.1.1", "6379", "2", "").Once().Return(errors.New(""))
+				mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "6379", "2", "").Once().Return(errors.New(""))
 			} else {
-				mr.On("MonitorRedisWithPort", "0.0.0.0", "1.1.1.1", "6379", "2", "").Once().Return(nil)
+				mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "6379", "2", "").Once().Return(nil)
 			}
 
 			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-			err := healer.NewSentinelMonitorWithPort("0.0.0.0", "1.1.1.1", "6379", rf)
+			err := healer.NewSentinelMonitorWithPort(context.TODO(), "0.0.0.0", "1.1.1.1", "6379", rf)
 
 			if errorExpected {
 				assert.Error(err)
@@ -586,3 +701,550 @@ func TestNewSentinelMonitorWithPort(t *testing.T) {
 		})
 	}
 }
+
+func TestSwitchover(t *testing.T) {
+	assert := assert.New(t)
+	rf := generateRF()
+
+	masterIP := "0.0.0.0"
+	newMasterIP := "1.1.1.1"
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod-master"},
+				Status: corev1.PodStatus{
+					PodIP: masterIP,
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod-replica"},
+				Status: corev1.PodStatus{
+					PodIP: newMasterIP,
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+
+	mr := &mRedisService.Client{}
+	mr.On("PauseWrites", mock.Anything, masterIP, "0", "", 20*time.Second).Once().Return(nil)
+	mr.On("GetReplicationInfo", mock.Anything, masterIP, "0", "").Once().Return(&redis.ReplicationInfo{Role: "master", MasterReplOffset: 100}, nil)
+	mr.On("GetReplicationInfo", mock.Anything, newMasterIP, "0", "").Once().Return(&redis.ReplicationInfo{Role: "slave", MasterLinkStatus: "up", SlaveReplOffset: 100}, nil)
+	mr.On("MakeMaster", mock.Anything, newMasterIP, "0", "").Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, masterIP, newMasterIP, "0", "").Once().Return(nil)
+	mr.On("UnpauseClients", mock.Anything, masterIP, "0", "").Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	err := healer.Switchover(context.TODO(), masterIP, newMasterIP, rf)
+	assert.NoError(err)
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
+
+func TestSwitchoverReplicaDoesNotCatchUp(t *testing.T) {
+	assert := assert.New(t)
+	rf := generateRF()
+	rf.Spec.Sentinel.FailoverTimeout = &metav1.Duration{Duration: time.Millisecond}
+
+	masterIP := "0.0.0.0"
+	newMasterIP := "1.1.1.1"
+
+	ms := &mK8SService.Services{}
+
+	mr := &mRedisService.Client{}
+	mr.On("PauseWrites", mock.Anything, masterIP, "0", "", 2*time.Millisecond).Once().Return(nil)
+	mr.On("GetReplicationInfo", mock.Anything, masterIP, "0", "").Return(&redis.ReplicationInfo{Role: "master", MasterReplOffset: 100}, nil)
+	mr.On("GetReplicationInfo", mock.Anything, newMasterIP, "0", "").Return(&redis.ReplicationInfo{Role: "slave", MasterLinkStatus: "up", SlaveReplOffset: 90}, nil)
+	mr.On("UnpauseClients", mock.Anything, masterIP, "0", "").Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	err := healer.Switchover(context.TODO(), masterIP, newMasterIP, rf)
+	assert.Error(err)
+	mr.AssertNotCalled(t, "MakeMaster", newMasterIP, "0", "")
+	mr.AssertExpectations(t)
+}
+
+// sentinelSwitchoverPods returns the pods of a master, the replica to promote and another replica
+func sentinelSwitchoverPods(masterIP, newMasterIP, otherIP string) *corev1.PodList {
+	return &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod-master"},
+				Status: corev1.PodStatus{
+					PodIP: masterIP,
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod-replica"},
+				Status: corev1.PodStatus{
+					PodIP: newMasterIP,
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod-other"},
+				Status: corev1.PodStatus{
+					PodIP: otherIP,
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+	}
+}
+
+func TestSentinelSwitchover(t *testing.T) {
+	assert := assert.New(t)
+	rf := generateRF()
+	rf.Spec.Redis.CustomConfig = []string{"replica-priority 100"}
+
+	sentinelIP := "9.9.9.9"
+	masterIP := "0.0.0.0"
+	newMasterIP := "1.1.1.1"
+	otherIP := "2.2.2.2"
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(sentinelSwitchoverPods(masterIP, newMasterIP, otherIP), nil)
+
+	mr := &mRedisService.Client{}
+	mr.On("SetCustomRedisConfig", mock.Anything, otherIP, "0", []string{"replica-priority 0"}, "").Once().Return(nil)
+	// The failover waits for the sentinel to refresh the priorities of the replicas
+	mr.On("GetSentinelReplicaPriorities", mock.Anything, sentinelIP).Once().Return(map[string]string{newMasterIP: "100", otherIP: "100"}, nil)
+	mr.On("GetSentinelReplicaPriorities", mock.Anything, sentinelIP).Once().Return(map[string]string{newMasterIP: "100", otherIP: "0"}, nil)
+	mr.On("PauseWrites", mock.Anything, masterIP, "0", "", 20*time.Second).Once().Return(nil)
+	mr.On("GetReplicationInfo", mock.Anything, masterIP, "0", "").Once().Return(&redis.ReplicationInfo{Role: "master", MasterReplOffset: 100}, nil)
+	mr.On("GetReplicationInfo", mock.Anything, newMasterIP, "0", "").Once().Return(&redis.ReplicationInfo{Role: "slave", MasterLinkStatus: "up", SlaveReplOffset: 100}, nil)
+	mr.On("SentinelFailover", mock.Anything, sentinelIP).Once().Return(nil)
+	mr.On("IsMaster", mock.Anything, newMasterIP, "0", "").Once().Return(true, nil)
+	mr.On("SetCustomRedisConfig", mock.Anything, otherIP, "0", []string{"replica-priority 100"}, "").Once().Return(nil)
+	mr.On("UnpauseClients", mock.Anything, masterIP, "0", "").Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	err := healer.SentinelSwitchover(context.TODO(), sentinelIP, masterIP, newMasterIP, rf)
+	assert.NoError(err)
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
+
+func TestSentinelSwitchoverPromotesAnotherReplica(t *testing.T) {
+	assert := assert.New(t)
+	rf := generateRF()
+
+	sentinelIP := "9.9.9.9"
+	masterIP := "0.0.0.0"
+	newMasterIP := "1.1.1.1"
+	otherIP := "2.2.2.2"
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(sentinelSwitchoverPods(masterIP, newMasterIP, otherIP), nil)
+
+	mr := &mRedisService.Client{}
+	mr.On("SetCustomRedisConfig", mock.Anything, otherIP, "0", []string{"replica-priority 0"}, "").Once().Return(nil)
+	mr.On("GetSentinelReplicaPriorities", mock.Anything, sentinelIP).Once().Return(map[string]string{newMasterIP: "100", otherIP: "0"}, nil)
+	mr.On("PauseWrites", mock.Anything, masterIP, "0", "", 20*time.Second).Once().Return(nil)
+	mr.On("GetReplicationInfo", mock.Anything, masterIP, "0", "").Once().Return(&redis.ReplicationInfo{Role: "master", MasterReplOffset: 100}, nil)
+	mr.On("GetReplicationInfo", mock.Anything, newMasterIP, "0", "").Once().Return(&redis.ReplicationInfo{Role: "slave", MasterLinkStatus: "up", SlaveReplOffset: 100}, nil)
+	mr.On("SentinelFailover", mock.Anything, sentinelIP).Once().Return(nil)
+	mr.On("IsMaster", mock.Anything, newMasterIP, "0", "").Once().Return(false, nil)
+	// The sentinel promoted the other replica
+	mr.On("GetSentinelMonitor", mock.Anything, sentinelIP).Once().Return(otherIP, "0", nil)
+	mr.On("SetCustomRedisConfig", mock.Anything, otherIP, "0", []string(nil), "").Once().Return(nil)
+	mr.On("UnpauseClients", mock.Anything, masterIP, "0", "").Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	err := healer.SentinelSwitchover(context.TODO(), sentinelIP, masterIP, newMasterIP, rf)
+	assert.EqualError(err, "sentinel promoted 2.2.2.2 instead of 1.1.1.1")
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
+
+func TestDemoteStaleMasters(t *testing.T) {
+	tests := []struct {
+		name         string
+		statusEpoch  int64
+		staleEpoch   string
+		currentEpoch string
+		expDemoted   []string
+		expMasterIP  string
+		expEpoch     int64
+	}{
+		{
+			name:         "Old master without epoch is demoted",
+			currentEpoch: "1",
+			expDemoted:   []string{"pod-stale"},
+			expMasterIP:  "1.1.1.1",
+			expEpoch:     1,
+		},
+		{
+			name:         "Old master with a lower epoch is demoted",
+			statusEpoch:  2,
+			staleEpoch:   "2",
+			currentEpoch: "3",
+			expDemoted:   []string{"pod-stale"},
+			expMasterIP:  "1.1.1.1",
+			expEpoch:     3,
+		},
+		{
+			name:         "Masters elected before the epoch of the status are demoted",
+			statusEpoch:  4,
+			staleEpoch:   "2",
+			currentEpoch: "3",
+			expDemoted:   []string{"pod-stale", "pod-current"},
+			expMasterIP:  "127.0.0.1",
+			expEpoch:     4,
+		},
+		{
+			name:         "Masters with the same epoch are demoted",
+			staleEpoch:   "2",
+			currentEpoch: "2",
+			expDemoted:   []string{"pod-stale", "pod-current"},
+			expMasterIP:  "127.0.0.1",
+			expEpoch:     2,
+		},
+		{
+			name:        "Masters without epoch are demoted",
+			expDemoted:  []string{"pod-stale", "pod-current"},
+			expMasterIP: "127.0.0.1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRF()
+			rf.Status.FailoverEpoch = test.statusEpoch
+
+			staleIP := "0.0.0.0"
+			currentIP := "1.1.1.1"
+			replicaIP := "2.2.2.2"
+			masterLabels := func(epoch string) map[string]string {
+				labels := map[string]string{"redisfailovers-role": "master"}
+				if epoch != "" {
+					labels["redisfailovers-epoch"] = epoch
+				}
+				return labels
+			}
+
+			pods := &corev1.PodList{
+				Items: []corev1.Pod{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   "pod-stale",
+							Labels: masterLabels(test.staleEpoch),
+						},
+						Status: corev1.PodStatus{
+							PodIP: staleIP,
+							Phase: corev1.PodRunning,
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   "pod-current",
+							Labels: masterLabels(test.currentEpoch),
+						},
+						Status: corev1.PodStatus{
+							PodIP: currentIP,
+							Phase: corev1.PodRunning,
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "pod-replica"},
+						Status: corev1.PodStatus{
+							PodIP: replicaIP,
+							Phase: corev1.PodRunning,
+						},
+					},
+				},
+			}
+			epochs := map[string]string{"pod-stale": test.staleEpoch, "pod-current": test.currentEpoch}
+			ips := map[string]string{"pod-stale": staleIP, "pod-current": currentIP}
+
+			ms := &mK8SService.Services{}
+			ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+			mr := &mRedisService.Client{}
+			mr.On("IsMaster", mock.Anything, staleIP, "0", "").Once().Return(true, nil)
+			mr.On("IsMaster", mock.Anything, currentIP, "0", "").Once().Return(true, nil)
+			mr.On("IsMaster", mock.Anything, replicaIP, "0", "").Once().Return(false, nil)
+			for _, name := range test.expDemoted {
+				if epochs[name] != "" {
+					ms.On("RemovePodLabels", namespace, name, []string{"redisfailovers-epoch"}).Once().Return(nil)
+				}
+				ms.On("UpdatePodLabels", namespace, name, map[string]string{"redisfailovers-role": "slave"}).Once().Return(nil)
+				mr.On("MakeSlaveOfWithPort", mock.Anything, ips[name], test.expMasterIP, "0", "").Once().Return(nil)
+			}
+
+			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+			assert.NoError(healer.DemoteStaleMasters(context.TODO(), rf))
+			assert.Equal(test.expEpoch, rf.Status.FailoverEpoch)
+			ms.AssertExpectations(t)
+			mr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSyncACLUsers(t *testing.T) {
+	tests := []struct {
+		name       string
+		authFails  bool
+		current    map[string]string
+		applied    map[string]string
+		expDeleted []string
+		expChanges []string
+	}{
+		{
+			name:       "Creates the declared users and deletes the undeclared ones",
+			current:    map[string]string{"default": "on nopass ~* &* +@all", "pinger": "on #probe -@all +ping", "old": "on #old ~* +@all"},
+			applied:    map[string]string{"default": "on nopass ~* &* +@all", "pinger": "on #probe -@all +ping", "redis-operator": "on #operator ~* &* +@all", "app": "on #app ~app:* +get"},
+			expDeleted: []string{"old"},
+			expChanges: []string{"created user app", "created user redis-operator", "deleted user old"},
+		},
+		{
+			name:       "Nothing changes when the users are in sync",
+			current:    map[string]string{"default": "on nopass ~* &* +@all", "redis-operator": "on #operator ~* &* +@all", "app": "on #app ~app:* +get"},
+			applied:    map[string]string{"default": "on nopass ~* &* +@all", "redis-operator": "on #operator ~* &* +@all", "app": "on #app ~app:* +get"},
+			expChanges: []string{},
+		},
+		{
+			name:       "Reports the users whose rules changed",
+			current:    map[string]string{"default": "on nopass ~* &* +@all", "redis-operator": "on #operator ~* &* +@all", "app": "on #app ~* +@all"},
+			applied:    map[string]string{"default": "on nopass ~* &* +@all", "redis-operator": "on #operator ~* &* +@all", "app": "on #app ~app:* +get"},
+			expChanges: []string{"updated user app"},
+		},
+		{
+			name:       "Authenticates as the default user when the operator user is unknown",
+			authFails:  true,
+			current:    map[string]string{"default": "on nopass ~* &* +@all"},
+			applied:    map[string]string{"default": "on nopass ~* &* +@all", "redis-operator": "on #operator ~* &* +@all", "app": "on #app ~app:* +get"},
+			expChanges: []string{"created user app", "created user redis-operator"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRF()
+			rf.Spec.Auth.Users = []redisfailoverv1.RedisUser{
+				{Name: "app", SecretPath: "app-secret", Commands: []string{"+get"}, Keys: []string{"app:*"}},
+			}
+
+			ms := &mK8SService.Services{}
+			ms.On("GetSecret", namespace, "rfauth-test").Return(&corev1.Secret{
+				Data: map[string][]byte{"operator-password": []byte("operator"), "probe-password": []byte("probe")},
+			}, nil)
+			ms.On("GetSecret", namespace, "app-secret").Return(&corev1.Secret{
+				Data: map[string][]byte{"password": []byte("app")},
+			}, nil)
+
+			password := "operator"
+			mr := &mRedisService.Client{}
+			mr.On("WithOptions", mock.Anything).Return(mr)
+			if test.authFails {
+				mr.On("GetACLUsers", mock.Anything, "0.0.0.0", "0", password).Once().Return(nil, errors.New("WRONGPASS invalid username-password pair"))
+				password = ""
+			}
+			mr.On("GetACLUsers", mock.Anything, "0.0.0.0", "0", password).Once().Return(test.current, nil)
+			mr.On("SetACLUser", mock.Anything, "0.0.0.0", "0", password, "app", mock.MatchedBy(func(rules []string) bool {
+				return len(rules) > 0 && rules[0] == "reset"
+			})).Once().Return(nil)
+			mr.On("SetACLUser", mock.Anything, "0.0.0.0", "0", password, "redis-operator", mock.Anything).Once().Return(nil)
+			for _, user := range test.expDeleted {
+				mr.On("DeleteACLUser", mock.Anything, "0.0.0.0", "0", password, user).Once().Return(nil)
+			}
+			mr.On("GetACLUsers", mock.Anything, "0.0.0.0", "0", password).Once().Return(test.applied, nil)
+
+			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+			changes, err := healer.SyncACLUsers(context.TODO(), "0.0.0.0", rf)
+			assert.NoError(err)
+			assert.Equal(test.expChanges, changes)
+			mr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddRedisPassword(t *testing.T) {
+	tests := []struct {
+		name          string
+		password      string
+		expRules      []string
+		expMasterauth string
+	}{
+		{
+			name:          "Adds the password hash",
+			password:      "new",
+			expRules:      []string{passwordHash("new")},
+			expMasterauth: "masterauth new",
+		},
+		{
+			name:          "Accepts any password when auth is disabled",
+			expRules:      []string{"nopass"},
+			expMasterauth: `masterauth ""`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRF()
+
+			ms := &mK8SService.Services{}
+			mr := &mRedisService.Client{}
+			mr.On("SetACLUser", mock.Anything, "0.0.0.0", "0", "", "default", test.expRules).Once().Return(nil)
+			mr.On("SetCustomRedisConfig", mock.Anything, "0.0.0.0", "0", []string{test.expMasterauth}, "").Once().Return(nil)
+
+			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+			assert.NoError(healer.AddRedisPassword(context.TODO(), "0.0.0.0", test.password, rf))
+			mr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRemoveRedisPassword(t *testing.T) {
+	tests := []struct {
+		name      string
+		password  string
+		rules     string
+		expRemove bool
+	}{
+		{
+			name:      "Removes the password hash",
+			password:  "old",
+			rules:     "on " + passwordHash("new") + " " + passwordHash("old") + " ~* &* +@all",
+			expRemove: true,
+		},
+		{
+			name:     "Ignores the nodes without the password",
+			password: "old",
+			rules:    "on " + passwordHash("new") + " ~* &* +@all",
+		},
+		{
+			name: "Nothing to remove without password",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			rf := generateRF()
+
+			ms := &mK8SService.Services{}
+			mr := &mRedisService.Client{}
+			if test.password != "" {
+				mr.On("GetACLUsers", mock.Anything, "0.0.0.0", "0", "").Once().Return(map[string]string{"default": test.rules}, nil)
+			}
+			if test.expRemove {
+				mr.On("SetACLUser", mock.Anything, "0.0.0.0", "0", "", "default", []string{"!" + passwordHash("old")[1:]}).Once().Return(nil)
+			}
+
+			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+			assert.NoError(healer.RemoveRedisPassword(context.TODO(), "0.0.0.0", test.password, rf))
+			mr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetSentinelAuthPass(t *testing.T) {
+	assert := assert.New(t)
+	rf := generateRF()
+
+	ms := &mK8SService.Services{}
+	mr := &mRedisService.Client{}
+	mr.On("SetCustomSentinelConfig", mock.Anything, "1.1.1.1", []string{"auth-pass new"}).Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	assert.NoError(healer.SetSentinelAuthPass(context.TODO(), "1.1.1.1", "new", rf))
+	mr.AssertExpectations(t)
+}
+
+func TestSetSentinelCustomConfigAnnounceHostnames(t *testing.T) {
+	assert := assert.New(t)
+	rf := generateRF()
+	rf.Spec.AnnounceHostnames = true
+	rf.Spec.Sentinel.CustomConfig = []string{"down-after-milliseconds 2000"}
+
+	ms := &mK8SService.Services{}
+	mr := &mRedisService.Client{}
+	mr.On("SetSentinelGlobalConfig", mock.Anything, "1.1.1.1", []string{"resolve-hostnames yes", "announce-hostnames yes"}).Once().Return(nil)
+	mr.On("SetCustomSentinelConfig", mock.Anything, "1.1.1.1", []string{"down-after-milliseconds 2000"}).Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	assert.NoError(healer.SetSentinelCustomConfig(context.TODO(), "1.1.1.1", rf))
+	mr.AssertExpectations(t)
+}
+
+func TestSetSentinelCustomConfigCommandRenames(t *testing.T) {
+	assert := assert.New(t)
+	rf := generateRF()
+	rf.Spec.Redis.CustomCommandRenames = []redisfailoverv1.RedisCommandRename{{From: "CONFIG", To: "opconfig"}}
+
+	ms := &mK8SService.Services{}
+	mrRenamed := &mRedisService.Client{}
+	// The renames are given to the sentinels already monitoring the master on every loop
+	mrRenamed.On("SetSentinelCommandRenames", mock.Anything, "1.1.1.1").Once().Return(nil)
+	mrRenamed.On("SetCustomSentinelConfig", mock.Anything, "1.1.1.1", []string(nil)).Once().Return(nil)
+	mr := &mRedisService.Client{}
+	mr.On("WithOptions", redis.ConnectionOptions{CommandRenames: map[string]string{"CONFIG": "opconfig"}}).Once().Return(mrRenamed)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	assert.NoError(healer.SetSentinelCustomConfig(context.TODO(), "1.1.1.1", rf))
+	mr.AssertExpectations(t)
+	mrRenamed.AssertExpectations(t)
+}
+
+func TestSetRedisCustomConfigPersistence(t *testing.T) {
+	assert := assert.New(t)
+	rf := generateRF()
+	rf.Spec.Redis.CustomConfig = []string{"maxmemory-policy allkeys-lru"}
+	rf.Spec.Redis.Persistence = &redisfailoverv1.PersistenceSettings{
+		AOF: &redisfailoverv1.AOFSettings{Enabled: true},
+	}
+
+	ms := &mK8SService.Services{}
+	mr := &mRedisService.Client{}
+	// The configuration of the template is applied first, then the persistence one
+	mr.On("SetCustomRedisConfig", mock.Anything, "0.0.0.0", "0", []string{"tcp-keepalive 60", `save ""`, "appendonly yes", "appendfsync everysec", "maxmemory-policy allkeys-lru"}, "").Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	assert.NoError(healer.SetRedisCustomConfig(context.TODO(), "0.0.0.0", rf))
+	mr.AssertExpectations(t)
+}
+
+func TestSetRedisCustomConfigCommandRenames(t *testing.T) {
+	assert := assert.New(t)
+	rf := generateRF()
+	rf.Spec.Redis.CustomConfig = []string{"maxmemory-policy allkeys-lru"}
+	rf.Spec.Redis.CustomCommandRenames = []redisfailoverv1.RedisCommandRename{
+		{From: "config", To: "opconfig"},
+		{From: "FLUSHALL", To: ""},
+	}
+
+	ms := &mK8SService.Services{}
+	mrRenamed := &mRedisService.Client{}
+	mrRenamed.On("SetCustomRedisConfig", mock.Anything, "0.0.0.0", "0", []string{"tcp-keepalive 60", "save 900 1 300 10", "maxmemory-policy allkeys-lru"}, "").Once().Return(nil)
+	mr := &mRedisService.Client{}
+	// The commands are translated by the client the healer connects with
+	mr.On("WithOptions", redis.ConnectionOptions{CommandRenames: map[string]string{"CONFIG": "opconfig", "FLUSHALL": ""}}).Once().Return(mrRenamed)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	assert.NoError(healer.SetRedisCustomConfig(context.TODO(), "0.0.0.0", rf))
+	mr.AssertExpectations(t)
+	mrRenamed.AssertExpectations(t)
+}
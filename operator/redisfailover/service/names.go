@@ -42,6 +42,35 @@ func GetRedisSlaveName(rf *redisfailoverv1.RedisFailover) string {
 	return generateName(redisSlaveName, rf.Name)
 }
 
+// GetTLSCASecretName returns the name of the secret holding the CA generated by the operator
+func GetTLSCASecretName(rf *redisfailoverv1.RedisFailover) string {
+	return generateName(tlsCAName, rf.Name)
+}
+
+// GetTLSSecretName returns the name of the secret holding the certificate of the redis and sentinel
+// nodes, the one generated by the operator when not given
+func GetTLSSecretName(rf *redisfailoverv1.RedisFailover) string {
+	if rf.TLSEnabled() && rf.Spec.TLS.SecretName != "" {
+		return rf.Spec.TLS.SecretName
+	}
+	return generateName(tlsName, rf.Name)
+}
+
+// GetRedisAuthSecretName returns the name of the secret holding the passwords generated by the operator
+func GetRedisAuthSecretName(rf *redisfailoverv1.RedisFailover) string {
+	return generateName(redisAuthName, rf.Name)
+}
+
+// GetRedisRestoreSecretName returns the name of the secret holding the URL of the snapshot restored
+func GetRedisRestoreSecretName(rf *redisfailoverv1.RedisFailover) string {
+	return generateName(redisRestoreName, rf.Name)
+}
+
+// GetRedisDataVolumeClaimName returns the name of the persistent volume claim of the data of a redis pod
+func GetRedisDataVolumeClaimName(rf *redisfailoverv1.RedisFailover, podName string) string {
+	return fmt.Sprintf("%s-%s", getRedisDataVolumeName(rf), podName)
+}
+
 func generateName(typeName, metaName string) string {
 	return fmt.Sprintf("%s%s-%s", baseName, typeName, metaName)
 }
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+)
+
+// redisRestoreScript seeds the data volume with the snapshot, downloaded from RESTORE_URL or
+// copied from RESTORE_FILE. The append only files are removed so redis loads the snapshot.
+const redisRestoreScript = `set -e
+rm -rf /data/appendonlydir /data/appendonly.aof /data/dump.rdb.restore
+if [ -n "${RESTORE_FILE}" ]; then
+  cp "${RESTORE_FILE}" /data/dump.rdb.restore
+else
+  wget -q -O /data/dump.rdb.restore "${RESTORE_URL}"
+fi
+if [ -n "${RESTORE_CHECKSUM}" ]; then
+  echo "${RESTORE_CHECKSUM}  /data/dump.rdb.restore" | sha256sum -c -
+fi
+mv /data/dump.rdb.restore /data/dump.rdb
+`
+
+// EnsureRedisRestoreSecret makes sure the secret holding the presigned URL the snapshot of
+// spec.restore is downloaded from exists. The URL is renewed every hour, so the restore init
+// container can always download the snapshot.
+func (r *RedisFailoverKubeClient) EnsureRedisRestoreSecret(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	if rf.Spec.Restore.PersistentVolumeClaim != nil {
+		return nil
+	}
+	storage, bucket, key, checksum, err := r.getRestoreObject(ctx, rf)
+	if err != nil {
+		return err
+	}
+	s3Client, err := newS3Client(r.K8SService, rf.Namespace, storage)
+	if err != nil {
+		return err
+	}
+	// Signed at the start of the hour, so the URL does not change on every loop and is valid for
+	// another hour at least
+	url, err := s3Client.PresignGetObject(bucket, key, time.Now().Truncate(time.Hour), restoreURLExpiration)
+	if err != nil {
+		return err
+	}
+
+	secret := generateRedisRestoreSecret(rf, labels, ownerRefs, url, checksum)
+	current, err := r.getSecretIfPresent(rf.Namespace, secret.Name)
+	if err != nil {
+		return err
+	}
+	if current != nil && maps.EqualFunc(current.Data, secret.Data, bytes.Equal) {
+		return nil
+	}
+	err = r.K8SService.CreateOrUpdateSecret(rf.Namespace, secret)
+	r.setEnsureOperationMetrics(secret.Namespace, secret.Name, "Secret", rf.Name, err)
+	return err
+}
+
+// getRestoreObject returns the storage, the bucket, the key and the checksum of the snapshot of
+// spec.restore. The snapshot of a backup is in the storage of the backup, or in the one of the
+// backup settings of its Redis failover.
+func (r *RedisFailoverKubeClient) getRestoreObject(ctx context.Context, rf *redisfailoverv1.RedisFailover) (*redisfailoverv1.BackupStorage, string, string, string, error) {
+	restore := rf.Spec.Restore
+	if restore.S3 != nil {
+		storage := &redisfailoverv1.BackupStorage{S3: &restore.S3.S3Storage}
+		return storage, restore.S3.Bucket, restore.S3.ObjectKey(), restore.S3.Checksum, nil
+	}
+
+	backup, err := r.K8SService.GetRedisFailoverBackup(ctx, rf.Namespace, restore.BackupName)
+	if err != nil {
+		return nil, "", "", "", err
+	}
+	if backup.Status.Phase != redisfailoverv1.BackupPhaseCompleted {
+		return nil, "", "", "", fmt.Errorf("backup %q is not completed", backup.Name)
+	}
+	storage := backup.Spec.Storage
+	if storage == nil {
+		source, err := r.K8SService.GetRedisFailover(ctx, rf.Namespace, backup.Spec.RedisFailoverName)
+		if err != nil {
+			return nil, "", "", "", err
+		}
+		if source.Spec.Backup == nil {
+			return nil, "", "", "", fmt.Errorf("no storage in backup %q nor in the backup settings of redisfailover %q", backup.Name, source.Name)
+		}
+		storage = &source.Spec.Backup.Storage
+	}
+	bucket, key, err := parseBackupLocation(backup.Status.Location)
+	if err != nil {
+		return nil, "", "", "", err
+	}
+	return storage, bucket, key, backup.Status.Checksum, nil
+}
+
+func generateRedisRestoreSecret(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, url, checksum string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            GetRedisRestoreSecretName(rf),
+			Namespace:       rf.Namespace,
+			Labels:          labels,
+			OwnerReferences: ownerRefs,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			restoreURLKey:      []byte(url),
+			restoreChecksumKey: []byte(strings.TrimPrefix(checksum, "sha256:")),
+		},
+	}
+}
+
+// generateRedisRestoreContainer returns the init container seeding the data volume with the
+// snapshot of spec.restore
+func generateRedisRestoreContainer(rf *redisfailoverv1.RedisFailover) corev1.Container {
+	container := corev1.Container{
+		Name:            RedisRestoreContainerName,
+		Image:           rf.Spec.Restore.Image,
+		ImagePullPolicy: pullPolicy(rf.Spec.Redis.ImagePullPolicy),
+		SecurityContext: getContainerSecurityContext(rf.Spec.Redis.ContainerSecurityContext),
+		Command:         []string{"/bin/sh", "-c", redisRestoreScript},
+		Resources:       rf.Spec.Redis.Resources,
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      getRedisDataVolumeName(rf),
+				MountPath: "/data",
+			},
+		},
+	}
+
+	if pvc := rf.Spec.Restore.PersistentVolumeClaim; pvc != nil {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      redisRestoreVolumeName,
+			MountPath: redisRestoreMountPath,
+			ReadOnly:  true,
+		})
+		container.Env = []corev1.EnvVar{
+			{
+				Name:  "RESTORE_FILE",
+				Value: path.Join(redisRestoreMountPath, pvc.Path),
+			},
+		}
+		return container
+	}
+
+	optional := true
+	container.Env = []corev1.EnvVar{
+		{
+			Name: "RESTORE_URL",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: GetRedisRestoreSecretName(rf),
+					},
+					Key: restoreURLKey,
+				},
+			},
+		},
+		{
+			Name: "RESTORE_CHECKSUM",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: GetRedisRestoreSecretName(rf),
+					},
+					Key:      restoreChecksumKey,
+					Optional: &optional,
+				},
+			},
+		},
+	}
+	return container
+}
+
+// getRedisRestoreVolume returns the volume of the persistent volume claim holding the snapshot
+// of spec.restore
+func getRedisRestoreVolume(rf *redisfailoverv1.RedisFailover) corev1.Volume {
+	return corev1.Volume{
+		Name: redisRestoreVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: rf.Spec.Restore.PersistentVolumeClaim.ClaimName,
+				ReadOnly:  true,
+			},
+		},
+	}
+}
@@ -0,0 +1,281 @@
+package service_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+	"github.com/saremox/redis-operator/service/s3/s3test"
+)
+
+func generateRestoringRF(restore *redisfailoverv1.RestoreSettings) *redisfailoverv1.RedisFailover {
+	rf := generateRF()
+	rf.Spec.Restore = restore
+	rf.SetCondition(redisfailoverv1.ConditionRestored, metav1.ConditionFalse, redisfailoverv1.ReasonRestoreInProgress, "")
+	return rf
+}
+
+func TestEnsureRedisRestoreSecret(t *testing.T) {
+	rdb := []byte("REDIS0011\xfa\tredis-ver\x057.2.4\xff")
+
+	server := s3test.NewServer("backups")
+	defer server.Close()
+	server.PutObject("backups", "redis/test/test-202401310300.rdb", rdb)
+	storage := generateBackupStorage(server)
+
+	completedBackup := &redisfailoverv1.RedisFailoverBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-202401310300", Namespace: namespace},
+		Spec:       redisfailoverv1.RedisFailoverBackupSpec{RedisFailoverName: "source", Storage: storage},
+		Status: redisfailoverv1.RedisFailoverBackupStatus{
+			Phase:    redisfailoverv1.BackupPhaseCompleted,
+			Location: "s3://backups/redis/test/test-202401310300.rdb",
+			Checksum: "sha256:0123",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		restore     *redisfailoverv1.RestoreSettings
+		backup      *redisfailoverv1.RedisFailoverBackup
+		source      *redisfailoverv1.RedisFailover
+		expChecksum string
+		expError    string
+	}{
+		{
+			name: "Snapshot in s3",
+			restore: &redisfailoverv1.RestoreSettings{
+				S3: &redisfailoverv1.S3RestoreSource{S3Storage: *storage.S3, Key: "test/test-202401310300.rdb", Checksum: "sha256:4567"},
+			},
+			expChecksum: "4567",
+		},
+		{
+			name:        "Snapshot of a backup",
+			restore:     &redisfailoverv1.RestoreSettings{BackupName: "test-202401310300"},
+			backup:      completedBackup,
+			expChecksum: "0123",
+		},
+		{
+			name:    "Snapshot of a backup in the storage of its redisfailover",
+			restore: &redisfailoverv1.RestoreSettings{BackupName: "test-202401310300"},
+			backup: func() *redisfailoverv1.RedisFailoverBackup {
+				backup := completedBackup.DeepCopy()
+				backup.Spec.Storage = nil
+				return backup
+			}(),
+			source: &redisfailoverv1.RedisFailover{
+				ObjectMeta: metav1.ObjectMeta{Name: "source", Namespace: namespace},
+				Spec:       redisfailoverv1.RedisFailoverSpec{Backup: &redisfailoverv1.BackupSettings{Storage: *storage}},
+			},
+			expChecksum: "0123",
+		},
+		{
+			name:    "Backup not completed",
+			restore: &redisfailoverv1.RestoreSettings{BackupName: "test-202401310300"},
+			backup: func() *redisfailoverv1.RedisFailoverBackup {
+				backup := completedBackup.DeepCopy()
+				backup.Status = redisfailoverv1.RedisFailoverBackupStatus{Phase: redisfailoverv1.BackupPhaseFailed}
+				return backup
+			}(),
+			expError: `backup "test-202401310300" is not completed`,
+		},
+		{
+			name: "Snapshot in a persistent volume claim",
+			restore: &redisfailoverv1.RestoreSettings{
+				PersistentVolumeClaim: &redisfailoverv1.PersistentVolumeClaimRestoreSource{ClaimName: "snapshots", Path: "dump.rdb"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRestoringRF(test.restore)
+			var secret *corev1.Secret
+
+			ms := &mK8SService.Services{}
+			if test.backup != nil {
+				ms.On("GetRedisFailoverBackup", mock.Anything, namespace, test.backup.Name).Once().Return(test.backup, nil)
+			}
+			if test.source != nil {
+				ms.On("GetRedisFailover", mock.Anything, namespace, test.source.Name).Once().Return(test.source, nil)
+			}
+			if test.expError == "" && test.restore.PersistentVolumeClaim == nil {
+				ms.On("GetSecret", namespace, "s3-credentials").Once().Return(generateBackupCredentials(s3test.AccessKeyID), nil)
+				ms.On("GetSecret", namespace, rfservice.GetRedisRestoreSecretName(rf)).Once().Return(nil, kubeerrors.NewNotFound(schema.GroupResource{}, rfservice.GetRedisRestoreSecretName(rf)))
+				ms.On("CreateOrUpdateSecret", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+					secret = args.Get(1).(*corev1.Secret)
+				}).Return(nil)
+			}
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+			err := client.EnsureRedisRestoreSecret(context.TODO(), rf, map[string]string{}, []metav1.OwnerReference{})
+
+			if test.expError != "" {
+				assert.EqualError(err, test.expError)
+			} else {
+				assert.NoError(err)
+			}
+			if test.expError == "" && test.restore.PersistentVolumeClaim == nil && assert.NotNil(secret) {
+				assert.Equal("rfrestore-test", secret.Name)
+				assert.Equal(test.expChecksum, string(secret.Data["checksum"]))
+
+				resp, err := http.Get(string(secret.Data["url"]))
+				if assert.NoError(err) {
+					data, err := io.ReadAll(resp.Body)
+					resp.Body.Close()
+					assert.NoError(err)
+					assert.Equal(rdb, data)
+				}
+			}
+			ms.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRedisStatefulSetRestore(t *testing.T) {
+	tests := []struct {
+		name      string
+		restore   *redisfailoverv1.RestoreSettings
+		restoring bool
+		expEnv    []string
+	}{
+		{
+			name:    "Restore completed",
+			restore: &redisfailoverv1.RestoreSettings{BackupName: "test-202401310300", Image: "redis:7.2.12-alpine"},
+		},
+		{
+			name:      "Restoring from s3",
+			restore:   &redisfailoverv1.RestoreSettings{BackupName: "test-202401310300", Image: "redis:7.2.12-alpine"},
+			restoring: true,
+			expEnv:    []string{"RESTORE_URL", "RESTORE_CHECKSUM"},
+		},
+		{
+			name: "Restoring from a persistent volume claim",
+			restore: &redisfailoverv1.RestoreSettings{
+				PersistentVolumeClaim: &redisfailoverv1.PersistentVolumeClaimRestoreSource{ClaimName: "snapshots", Path: "backups/dump.rdb"},
+				Image:                 "redis:7.2.12-alpine",
+			},
+			restoring: true,
+			expEnv:    []string{"RESTORE_FILE"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Restore = test.restore
+			rf.Spec.Redis.InitContainers = []corev1.Container{{Name: "init"}}
+			if test.restoring {
+				rf = generateRestoringRF(test.restore)
+				rf.Spec.Redis.InitContainers = []corev1.Container{{Name: "init"}}
+			}
+
+			var ss *appsv1.StatefulSet
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
+			ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				ss = args.Get(1).(*appsv1.StatefulSet)
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+			assert.NoError(client.EnsureRedisStatefulset(rf, map[string]string{}, []metav1.OwnerReference{}))
+
+			initContainers := ss.Spec.Template.Spec.InitContainers
+			if !test.restoring {
+				assert.Equal(int32(3), *ss.Spec.Replicas)
+				assert.Len(initContainers, 1)
+				return
+			}
+
+			assert.Equal(int32(1), *ss.Spec.Replicas)
+			assert.Equal(int32(3), rf.Spec.Redis.Replicas)
+			if assert.Len(initContainers, 2) {
+				restore := initContainers[0]
+				assert.Equal(rfservice.RedisRestoreContainerName, restore.Name)
+				assert.Equal("redis:7.2.12-alpine", restore.Image)
+				env := []string{}
+				for _, e := range restore.Env {
+					env = append(env, e.Name)
+				}
+				assert.Equal(test.expEnv, env)
+				assert.Equal("/data", restore.VolumeMounts[0].MountPath)
+				assert.Equal("init", initContainers[1].Name)
+			}
+
+			var restoreVolume *corev1.Volume
+			for i := range ss.Spec.Template.Spec.Volumes {
+				if ss.Spec.Template.Spec.Volumes[i].Name == "redis-restore" {
+					restoreVolume = &ss.Spec.Template.Spec.Volumes[i]
+				}
+			}
+			if test.restore.PersistentVolumeClaim == nil {
+				assert.Nil(restoreVolume)
+				return
+			}
+			if assert.NotNil(restoreVolume) {
+				assert.Equal("snapshots", restoreVolume.PersistentVolumeClaim.ClaimName)
+				assert.True(restoreVolume.PersistentVolumeClaim.ReadOnly)
+			}
+			assert.Equal("/restore/backups/dump.rdb", initContainers[0].Env[0].Value)
+		})
+	}
+}
+
+func TestRedisConfigMapRestoreAOF(t *testing.T) {
+	restore := &redisfailoverv1.RestoreSettings{BackupName: "test-202401310300"}
+	tests := []struct {
+		name      string
+		restoring bool
+		expected  string
+	}{
+		{
+			name:      "AOF off while restoring",
+			restoring: true,
+			expected:  "tcp-keepalive 60\nsave \"\"\nappendonly yes\nappendfsync everysec\nappendonly no\n\ninclude ",
+		},
+		{
+			name:     "AOF on once restored",
+			expected: "tcp-keepalive 60\nsave \"\"\nappendonly yes\nappendfsync everysec\n\ninclude ",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Restore = restore
+			if test.restoring {
+				rf = generateRestoringRF(restore)
+			}
+			rf.Spec.Redis.Persistence = &redisfailoverv1.PersistenceSettings{AOF: &redisfailoverv1.AOFSettings{Enabled: true}}
+
+			var redisConfig string
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdateConfigMap", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				redisConfig = args.Get(1).(*corev1.ConfigMap).Data["redis.conf"]
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy, "cluster.local")
+			assert.NoError(client.EnsureRedisConfigMap(rf, nil, []metav1.OwnerReference{}))
+			assert.Contains(redisConfig, test.expected)
+		})
+	}
+}
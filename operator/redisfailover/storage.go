@@ -0,0 +1,220 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+// fileSystemResizeGracePeriod is how long the file system of an expanded volume is given to be
+// resized online, before the pod using it is restarted to resize it offline
+const fileSystemResizeGracePeriod = 2 * time.Minute
+
+// ExpandStorage expands the persistent volume claims of the redis nodes to the storage requested
+// by spec.redis.storage.persistentVolumeClaim, once their storage class is checked to allow it.
+// The file systems that can't be resized online are resized by restarting their pods one at a
+// time, the master last, after it is switched over. The progress of every claim is reported in
+// status.storage and on the StorageExpanded condition.
+func (r *RedisFailoverHandler) ExpandStorage(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	request := rf.StorageRequest()
+	// The claims are only known once the redis statefulset exists
+	if request == nil || rf.Status.Selector == "" {
+		return nil
+	}
+	claims, err := r.listRedisClaims(rf, rf.Status.Selector)
+	if err != nil {
+		return err
+	}
+
+	// The size is not known yet on the first reconcile, it is the one of the existing claims
+	if rf.Status.Storage == nil || rf.Status.Storage.Size == "" {
+		size, err := r.getStorageSize(rf, claims)
+		if err != nil {
+			return err
+		}
+		if size != nil {
+			if err := rf.ValidateStorageSize(*size); err != nil {
+				return err
+			}
+			rf.Status.Storage = &redisfailoverv1.StorageStatus{Size: size.String()}
+		}
+	}
+
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+	// The claims of the removed redis nodes are left as they are
+	current := []redisClaim{}
+	for _, claim := range claims {
+		if claim.ordinal < int(rf.Spec.Redis.Replicas) {
+			current = append(current, claim)
+		}
+	}
+
+	for _, claim := range current {
+		claimRequest := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+		if claimRequest.Cmp(*request) >= 0 {
+			continue
+		}
+		if err := r.checkExpansionAllowed(claim.PersistentVolumeClaim); err != nil {
+			logger.Warningf("Unable to expand the persistent volume claims: %s", err.Error())
+			rf.SetCondition(redisfailoverv1.ConditionStorageExpanded, metav1.ConditionFalse, redisfailoverv1.ReasonExpansionNotAllowed, err.Error())
+			return nil
+		}
+		if claim.Spec.Resources.Requests == nil {
+			claim.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		claim.Spec.Resources.Requests[corev1.ResourceStorage] = *request
+		if err := r.k8sservice.UpdatePersistentVolumeClaim(rf.Namespace, claim.PersistentVolumeClaim); err != nil {
+			return err
+		}
+		logger.Infof("Persistent volume claim %s expanded from %s to %s", claim.Name, claimRequest.String(), request.String())
+	}
+
+	storage := &redisfailoverv1.StorageStatus{}
+	if previous := rf.Status.Storage; previous != nil {
+		// The size is only raised once every claim is expanded
+		storage.Size = previous.Size
+	}
+	pending := []redisClaim{}
+	for _, claim := range current {
+		// The capacity is only known once the claim is bound
+		if claim.Status.Phase != corev1.ClaimBound {
+			storage.Claims = append(storage.Claims, redisfailoverv1.ClaimStatus{Name: claim.Name})
+			continue
+		}
+		state := claimResizeState(claim.PersistentVolumeClaim, request)
+		capacity := claim.Status.Capacity[corev1.ResourceStorage]
+		storage.Claims = append(storage.Claims, redisfailoverv1.ClaimStatus{Name: claim.Name, Capacity: capacity.String(), State: state})
+		if state != redisfailoverv1.ClaimResized {
+			pending = append(pending, claim)
+		}
+	}
+	rf.Status.Storage = storage
+
+	if len(pending) != 0 {
+		rf.SetCondition(redisfailoverv1.ConditionStorageExpanded, metav1.ConditionFalse, redisfailoverv1.ReasonStorageExpanding, fmt.Sprintf("expanding the persistent volume claims to %s, %d of %d resized", request.String(), len(current)-len(pending), len(current)))
+		return r.restartForFileSystemResize(ctx, rf, pending)
+	}
+
+	storage.Size = request.String()
+	if condition := rf.GetCondition(redisfailoverv1.ConditionStorageExpanded); condition != nil && condition.Status != metav1.ConditionTrue {
+		logger.Infof("Persistent volume claims expanded to %s", request.String())
+		rf.SetCondition(redisfailoverv1.ConditionStorageExpanded, metav1.ConditionTrue, redisfailoverv1.ReasonStorageExpanded, fmt.Sprintf("persistent volume claims expanded to %s", request.String()))
+	}
+	return nil
+}
+
+// getStorageSize returns the largest storage requested by the claims, or the one of the volume
+// claim template of the redis statefulset when there is no claim yet. It is nil when neither exists.
+func (r *RedisFailoverHandler) getStorageSize(rf *redisfailoverv1.RedisFailover, claims []redisClaim) (*resource.Quantity, error) {
+	var size *resource.Quantity
+	for _, claim := range claims {
+		request, ok := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+		if ok && (size == nil || request.Cmp(*size) > 0) {
+			size = &request
+		}
+	}
+	if size != nil {
+		return size, nil
+	}
+
+	ss, err := r.k8sservice.GetStatefulSet(rf.Namespace, rfservice.GetRedisName(rf))
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range ss.Spec.VolumeClaimTemplates {
+		if template.Name != rf.Spec.Redis.Storage.PersistentVolumeClaim.Name {
+			continue
+		}
+		if request, ok := template.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+			return &request, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkExpansionAllowed returns an error when the storage class of the claim doesn't allow its
+// volume to be expanded
+func (r *RedisFailoverHandler) checkExpansionAllowed(pvc *corev1.PersistentVolumeClaim) error {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return fmt.Errorf("persistentvolumeclaim %s has no storage class, its volume can't be expanded", pvc.Name)
+	}
+	storageClass, err := r.k8sservice.GetStorageClass(*pvc.Spec.StorageClassName)
+	if err != nil {
+		return fmt.Errorf("unable to get the storage class of persistentvolumeclaim %s: %w", pvc.Name, err)
+	}
+	if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		return fmt.Errorf("storage class %s doesn't allow volume expansion", storageClass.Name)
+	}
+	return nil
+}
+
+// claimResizeState returns the stage the expansion of the claim to the request is at
+func claimResizeState(pvc *corev1.PersistentVolumeClaim, request *resource.Quantity) redisfailoverv1.ClaimResizeState {
+	if fileSystemResizePendingSince(pvc) != nil {
+		return redisfailoverv1.ClaimFileSystemResizePending
+	}
+	capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+	if capacity.Cmp(*request) >= 0 {
+		return redisfailoverv1.ClaimResized
+	}
+	return redisfailoverv1.ClaimResizing
+}
+
+// fileSystemResizePendingSince returns when the file system of the claim started waiting to be
+// resized, or nil when it doesn't
+func fileSystemResizePendingSince(pvc *corev1.PersistentVolumeClaim) *metav1.Time {
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Type == corev1.PersistentVolumeClaimFileSystemResizePending && condition.Status == corev1.ConditionTrue {
+			return &condition.LastTransitionTime
+		}
+	}
+	return nil
+}
+
+// restartForFileSystemResize restarts the pod of one of the claims whose file system wasn't
+// resized online within the grace period. Nothing is done while a redis pod is not running, so
+// the pods are restarted one at a time. The master is restarted last, once switched over.
+func (r *RedisFailoverHandler) restartForFileSystemResize(ctx context.Context, rf *redisfailoverv1.RedisFailover, pending []redisClaim) error {
+	offline := []redisClaim{}
+	for _, claim := range pending {
+		since := fileSystemResizePendingSince(claim.PersistentVolumeClaim)
+		if since != nil && time.Since(since.Time) > fileSystemResizeGracePeriod {
+			offline = append(offline, claim)
+		}
+	}
+	if len(offline) == 0 || !r.rfChecker.IsRedisRunning(rf) {
+		return nil
+	}
+
+	redisName := rfservice.GetRedisName(rf)
+	masterPod, _ := r.rfChecker.GetRedisesMasterPod(ctx, rf)
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+	for _, claim := range offline {
+		podName := fmt.Sprintf("%s-%d", redisName, claim.ordinal)
+		if podName == masterPod {
+			continue
+		}
+		logger.Infof("Restarting pod %s to resize the file system of persistent volume claim %s", podName, claim.Name)
+		return r.k8sservice.DeletePod(rf.Namespace, podName)
+	}
+
+	// Only the master is left, it is restarted once it serves as a replica
+	if err := r.switchoverMaster(ctx, rf, masterPod, func(podName string) bool {
+		return podName != masterPod
+	}); err != nil {
+		return err
+	}
+	logger.Infof("Master switched over from %s to resize the file system of its persistent volume claim", masterPod)
+	return nil
+}
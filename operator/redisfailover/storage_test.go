@@ -0,0 +1,303 @@
+package redisfailover_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+	mRFService "github.com/saremox/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/saremox/redis-operator/operator/redisfailover"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+func TestExpandStorage(t *testing.T) {
+	selector := "app.kubernetes.io/component=redis,app.kubernetes.io/name=test"
+	standard := "standard"
+	allowed := true
+	// claim returns the bound claim of a redis pod, its file system waiting to be resized for the given time
+	claim := func(ordinal, request, capacity string, resizePendingFor time.Duration) corev1.PersistentVolumeClaim {
+		pvc := corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "redis-data-rfr-test-" + ordinal},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &standard,
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(request)},
+				},
+			},
+			Status: corev1.PersistentVolumeClaimStatus{
+				Phase:    corev1.ClaimBound,
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)},
+			},
+		}
+		if resizePendingFor != 0 {
+			pvc.Status.Conditions = []corev1.PersistentVolumeClaimCondition{{
+				Type:               corev1.PersistentVolumeClaimFileSystemResizePending,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-resizePendingFor)),
+			}}
+		}
+		return pvc
+	}
+
+	tests := []struct {
+		name             string
+		claims           []corev1.PersistentVolumeClaim
+		storageClass     *storagev1.StorageClass
+		condition        string
+		offline          bool
+		redisRunning     bool
+		masterPod        string
+		expUpdated       []string
+		expRestarted     string
+		expSwitchover    bool
+		expSize          string
+		expStates        []redisfailoverv1.ClaimResizeState
+		expReason        string
+		expConditionTrue bool
+	}{
+		{
+			name:      "Claims at the requested size",
+			claims:    []corev1.PersistentVolumeClaim{claim("0", "1Gi", "1Gi", 0), claim("1", "1Gi", "1Gi", 0)},
+			expSize:   "1Gi",
+			expStates: []redisfailoverv1.ClaimResizeState{redisfailoverv1.ClaimResized, redisfailoverv1.ClaimResized},
+		},
+		{
+			name:         "Expansion requested",
+			claims:       []corev1.PersistentVolumeClaim{claim("0", "512Mi", "512Mi", 0), claim("1", "512Mi", "512Mi", 0)},
+			storageClass: &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: standard}, AllowVolumeExpansion: &allowed},
+			expUpdated:   []string{"redis-data-rfr-test-0", "redis-data-rfr-test-1"},
+			expSize:      "512Mi",
+			expStates:    []redisfailoverv1.ClaimResizeState{redisfailoverv1.ClaimResizing, redisfailoverv1.ClaimResizing},
+			expReason:    redisfailoverv1.ReasonStorageExpanding,
+		},
+		{
+			name:         "Expansion not allowed by the storage class",
+			claims:       []corev1.PersistentVolumeClaim{claim("0", "512Mi", "512Mi", 0), claim("1", "512Mi", "512Mi", 0)},
+			storageClass: &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: standard}},
+			expSize:      "512Mi",
+			expReason:    redisfailoverv1.ReasonExpansionNotAllowed,
+		},
+		{
+			name:      "File system resized online",
+			claims:    []corev1.PersistentVolumeClaim{claim("0", "1Gi", "1Gi", 0), claim("1", "1Gi", "1Gi", time.Minute)},
+			condition: redisfailoverv1.ReasonStorageExpanding,
+			expSize:   "512Mi",
+			expStates: []redisfailoverv1.ClaimResizeState{redisfailoverv1.ClaimResized, redisfailoverv1.ClaimFileSystemResizePending},
+			expReason: redisfailoverv1.ReasonStorageExpanding,
+		},
+		{
+			name:         "Replica restarted to resize its file system offline",
+			offline:      true,
+			claims:       []corev1.PersistentVolumeClaim{claim("0", "1Gi", "1Gi", time.Hour), claim("1", "1Gi", "1Gi", time.Hour)},
+			condition:    redisfailoverv1.ReasonStorageExpanding,
+			redisRunning: true,
+			masterPod:    "rfr-test-0",
+			expRestarted: "rfr-test-1",
+			expSize:      "512Mi",
+			expStates:    []redisfailoverv1.ClaimResizeState{redisfailoverv1.ClaimFileSystemResizePending, redisfailoverv1.ClaimFileSystemResizePending},
+			expReason:    redisfailoverv1.ReasonStorageExpanding,
+		},
+		{
+			name:      "Pod restarted not running yet",
+			offline:   true,
+			claims:    []corev1.PersistentVolumeClaim{claim("0", "1Gi", "1Gi", time.Hour), claim("1", "1Gi", "1Gi", 0)},
+			condition: redisfailoverv1.ReasonStorageExpanding,
+			expSize:   "512Mi",
+			expStates: []redisfailoverv1.ClaimResizeState{redisfailoverv1.ClaimFileSystemResizePending, redisfailoverv1.ClaimResized},
+			expReason: redisfailoverv1.ReasonStorageExpanding,
+		},
+		{
+			name:          "Master switched over to resize its file system offline",
+			offline:       true,
+			claims:        []corev1.PersistentVolumeClaim{claim("0", "1Gi", "1Gi", time.Hour), claim("1", "1Gi", "1Gi", 0)},
+			condition:     redisfailoverv1.ReasonStorageExpanding,
+			redisRunning:  true,
+			masterPod:     "rfr-test-0",
+			expSwitchover: true,
+			expSize:       "512Mi",
+			expStates:     []redisfailoverv1.ClaimResizeState{redisfailoverv1.ClaimFileSystemResizePending, redisfailoverv1.ClaimResized},
+			expReason:     redisfailoverv1.ReasonStorageExpanding,
+		},
+		{
+			name:             "Expansion completed",
+			claims:           []corev1.PersistentVolumeClaim{claim("0", "1Gi", "1Gi", 0), claim("1", "1Gi", "1Gi", 0)},
+			condition:        redisfailoverv1.ReasonStorageExpanding,
+			expSize:          "1Gi",
+			expStates:        []redisfailoverv1.ClaimResizeState{redisfailoverv1.ClaimResized, redisfailoverv1.ClaimResized},
+			expReason:        redisfailoverv1.ReasonStorageExpanded,
+			expConditionTrue: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			disabled := false
+			rf := generateRF(false, false)
+			rf.Spec.Sentinel.Enabled = &disabled
+			rf.Spec.Redis.Replicas = 2
+			rf.Spec.Redis.Storage.PersistentVolumeClaim = &redisfailoverv1.EmbeddedPersistentVolumeClaim{
+				EmbeddedObjectMetadata: redisfailoverv1.EmbeddedObjectMetadata{Name: "redis-data"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+					},
+				},
+			}
+			rf.Status.Selector = selector
+			rf.Status.Storage = &redisfailoverv1.StorageStatus{Size: "512Mi"}
+			if test.condition != "" {
+				rf.SetCondition(redisfailoverv1.ConditionStorageExpanded, metav1.ConditionFalse, test.condition, "")
+			}
+
+			mk := &mK8SService.Services{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+			mk.On("ListPersistentVolumeClaims", namespace, metav1.ListOptions{LabelSelector: selector}).Once().Return(&corev1.PersistentVolumeClaimList{Items: test.claims}, nil)
+			if test.storageClass != nil {
+				mk.On("GetStorageClass", standard).Return(test.storageClass, nil)
+			}
+			var updated []string
+			if len(test.expUpdated) != 0 {
+				mk.On("UpdatePersistentVolumeClaim", namespace, mock.Anything).Times(len(test.expUpdated)).Run(func(args mock.Arguments) {
+					pvc := args.Get(1).(*corev1.PersistentVolumeClaim)
+					assert.Equal("1Gi", pvc.Spec.Resources.Requests.Storage().String())
+					updated = append(updated, pvc.Name)
+				}).Return(nil)
+			}
+			if test.offline {
+				mrfc.On("IsRedisRunning", rf).Once().Return(test.redisRunning)
+			}
+			if test.masterPod != "" {
+				mrfc.On("GetRedisesMasterPod", mock.Anything, rf).Once().Return(test.masterPod, nil)
+			}
+			if test.expRestarted != "" {
+				mk.On("DeletePod", namespace, test.expRestarted).Once().Return(nil)
+			}
+			if test.expSwitchover {
+				mrfc.On("GetReplicaReplicationOffsets", mock.Anything, rf).Once().Return([]rfservice.ReplicaInfo{{PodName: "rfr-test-1", IP: "0.0.0.1", IsReady: true}}, nil)
+				mrfc.On("GetMasterIP", mock.Anything, rf).Once().Return("0.0.0.0", nil)
+				mrfh.On("Switchover", mock.Anything, "0.0.0.0", "0.0.0.1", rf).Once().Return(nil)
+				mrfc.On("GetMasterIP", mock.Anything, rf).Once().Return("0.0.0.1", nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, mrfc, mrfh, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			assert.NoError(handler.ExpandStorage(context.TODO(), rf))
+
+			assert.Equal(test.expUpdated, updated)
+			if assert.NotNil(rf.Status.Storage) {
+				assert.Equal(test.expSize, rf.Status.Storage.Size)
+				if test.expStates != nil {
+					states := []redisfailoverv1.ClaimResizeState{}
+					for _, claim := range rf.Status.Storage.Claims {
+						states = append(states, claim.State)
+					}
+					assert.Equal(test.expStates, states)
+				}
+			}
+			condition := rf.GetCondition(redisfailoverv1.ConditionStorageExpanded)
+			if test.expReason == "" {
+				assert.Nil(condition)
+			} else if assert.NotNil(condition) {
+				assert.Equal(test.expReason, condition.Reason)
+				assert.Equal(test.expConditionTrue, condition.Status == metav1.ConditionTrue)
+			}
+			mk.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExpandStorageWithoutStatus(t *testing.T) {
+	selector := "app.kubernetes.io/component=redis,app.kubernetes.io/name=test"
+	claimSpec := func(request string) corev1.PersistentVolumeClaimSpec {
+		return corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(request)},
+			},
+		}
+	}
+	claim := func(request string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "redis-data-rfr-test-0"},
+			Spec:       claimSpec(request),
+			Status: corev1.PersistentVolumeClaimStatus{
+				Phase:    corev1.ClaimBound,
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(request)},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		claims       []corev1.PersistentVolumeClaim
+		templateSize string
+		expErr       string
+		expSize      string
+	}{
+		{
+			name:   "Shrink of the claims refused",
+			claims: []corev1.PersistentVolumeClaim{claim("2Gi")},
+			expErr: "storage persistentVolumeClaim can't be shrunk from 2Gi to 1Gi",
+		},
+		{
+			name:         "Shrink of the volume claim template refused",
+			templateSize: "2Gi",
+			expErr:       "storage persistentVolumeClaim can't be shrunk from 2Gi to 1Gi",
+		},
+		{
+			name:    "Claims at the requested size",
+			claims:  []corev1.PersistentVolumeClaim{claim("1Gi")},
+			expSize: "1Gi",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			rf.Spec.Redis.Replicas = 1
+			rf.Spec.Redis.Storage.PersistentVolumeClaim = &redisfailoverv1.EmbeddedPersistentVolumeClaim{
+				EmbeddedObjectMetadata: redisfailoverv1.EmbeddedObjectMetadata{Name: "redis-data"},
+				Spec:                   claimSpec("1Gi"),
+			}
+			rf.Status.Selector = selector
+
+			mk := &mK8SService.Services{}
+			mk.On("ListPersistentVolumeClaims", namespace, metav1.ListOptions{LabelSelector: selector}).Once().Return(&corev1.PersistentVolumeClaimList{Items: test.claims}, nil)
+			if test.templateSize != "" {
+				ss := &appsv1.StatefulSet{}
+				ss.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{{
+					ObjectMeta: metav1.ObjectMeta{Name: "redis-data"},
+					Spec:       claimSpec(test.templateSize),
+				}}
+				mk.On("GetStatefulSet", namespace, "rfr-test").Once().Return(ss, nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, &mRFService.RedisFailoverCheck{}, &mRFService.RedisFailoverHeal{}, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			err := handler.ExpandStorage(context.TODO(), rf)
+			if test.expErr != "" {
+				assert.EqualError(err, test.expErr)
+				assert.Nil(rf.Status.Storage)
+			} else if assert.NoError(err) && assert.NotNil(rf.Status.Storage) {
+				assert.Equal(test.expSize, rf.Status.Storage.Size)
+			}
+			mk.AssertExpectations(t)
+		})
+	}
+}
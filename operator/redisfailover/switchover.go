@@ -0,0 +1,146 @@
+package redisfailover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+// Switchover performs the manual switchover requested through the switchover annotation.
+// The outcome is reported on the Switchover condition and the annotation is removed, so a
+// failed switchover is not retried until it is requested again.
+func (r *RedisFailoverHandler) Switchover(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	target, requested := rf.SwitchoverRequested()
+	if !requested {
+		return nil
+	}
+
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+
+	if !rf.Bootstrapping() && !r.rfChecker.IsRedisRunning(rf) {
+		logger.Infof("Switchover requested, waiting for all the redis pods to be running")
+		return nil
+	}
+
+	newMaster, err := r.switchover(ctx, rf, target)
+	if err != nil {
+		logger.Errorf("Switchover failed: %s", err.Error())
+		rf.SetCondition(redisfailoverv1.ConditionSwitchover, metav1.ConditionFalse, redisfailoverv1.ReasonSwitchoverFailed, err.Error())
+	} else {
+		rf.SetCondition(redisfailoverv1.ConditionSwitchover, metav1.ConditionTrue, redisfailoverv1.ReasonSwitchoverSucceeded, fmt.Sprintf("master switched over to %s", newMaster))
+	}
+
+	return r.k8sservice.RemoveRedisFailoverAnnotation(context.Background(), rf.Namespace, rf.Name, redisfailoverv1.SwitchoverAnnotation)
+}
+
+// switchover promotes the target replica and returns the name of the new master pod.
+func (r *RedisFailoverHandler) switchover(ctx context.Context, rf *redisfailoverv1.RedisFailover, target string) (string, error) {
+	if rf.Bootstrapping() {
+		return "", errors.New("switchover is not supported while bootstrapping")
+	}
+
+	masterIP, err := r.rfChecker.GetMasterIP(ctx, rf)
+	if err != nil {
+		return "", err
+	}
+	masterPod, err := r.rfChecker.GetRedisesMasterPod(ctx, rf)
+	if err != nil {
+		return "", err
+	}
+	if target == masterPod {
+		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Pod %s is already the master", target)
+		return target, nil
+	}
+
+	replica, err := r.getSwitchoverReplica(ctx, rf, target)
+	if err != nil {
+		return "", err
+	}
+
+	return replica.PodName, r.promote(ctx, rf, masterIP, replica)
+}
+
+// switchoverStaleMaster moves the master role to the most up to date replica before the stale
+// master pod is deleted by a rolling update, and checks the replica is serving as master.
+// When there is no replica to promote the master pod is deleted as is.
+func (r *RedisFailoverHandler) switchoverStaleMaster(ctx context.Context, rf *redisfailoverv1.RedisFailover, masterIP string) error {
+	logger := r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace)
+
+	if masterIP == "" {
+		logger.Warningf("Unknown master IP, deleting the master pod without a switchover")
+		return nil
+	}
+	replica, err := r.rfChecker.GetBestReplicaForPromotion(ctx, rf)
+	if err != nil {
+		logger.Warningf("No replica to switch over to, deleting the master pod: %s", err.Error())
+		return nil
+	}
+	if !replica.IsReady {
+		return fmt.Errorf("replica %s is not in sync with the master", replica.PodName)
+	}
+
+	if err := r.promote(ctx, rf, masterIP, replica); err != nil {
+		return err
+	}
+
+	newMasterIP, err := r.rfChecker.GetMasterIP(ctx, rf)
+	if err != nil {
+		return err
+	}
+	if newMasterIP != replica.IP {
+		return fmt.Errorf("replica %s is not serving as master after the switchover", replica.PodName)
+	}
+	logger.Infof("Master switched over to %s", replica.PodName)
+	return nil
+}
+
+// promote hands the master role over to the replica, through Sentinel when it is enabled.
+func (r *RedisFailoverHandler) promote(ctx context.Context, rf *redisfailoverv1.RedisFailover, masterIP string, replica *rfservice.ReplicaInfo) error {
+	if rf.OperatorManagedFailover() {
+		return r.rfHealer.Switchover(ctx, masterIP, replica.IP, rf)
+	}
+
+	sentinels, err := r.rfChecker.GetSentinelsIPs(rf)
+	if err != nil {
+		return err
+	}
+	if len(sentinels) == 0 {
+		return errors.New("no sentinel available to perform the switchover")
+	}
+	return r.rfHealer.SentinelSwitchover(ctx, sentinels[0], masterIP, replica.IP, rf)
+}
+
+// getSwitchoverReplica returns the replica to promote: the given pod, or the most up to date
+// replica when no pod is given. The replica must be in sync with the master.
+func (r *RedisFailoverHandler) getSwitchoverReplica(ctx context.Context, rf *redisfailoverv1.RedisFailover, target string) (*rfservice.ReplicaInfo, error) {
+	if target == "" {
+		replica, err := r.rfChecker.GetBestReplicaForPromotion(ctx, rf)
+		if err != nil {
+			return nil, err
+		}
+		if !replica.IsReady {
+			return nil, fmt.Errorf("replica %s is not in sync with the master", replica.PodName)
+		}
+		return replica, nil
+	}
+
+	replicas, err := r.rfChecker.GetReplicaReplicationOffsets(ctx, rf)
+	if err != nil {
+		return nil, err
+	}
+	for i := range replicas {
+		if replicas[i].PodName != target {
+			continue
+		}
+		if !replicas[i].IsReady {
+			return nil, fmt.Errorf("replica %s is not in sync with the master", target)
+		}
+		return &replicas[i], nil
+	}
+	return nil, fmt.Errorf("pod %s is not a replica of this redisfailover", target)
+}
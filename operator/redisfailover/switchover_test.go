@@ -0,0 +1,142 @@
+package redisfailover_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+	mRFService "github.com/saremox/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/saremox/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/saremox/redis-operator/operator/redisfailover"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+func TestSwitchover(t *testing.T) {
+	tests := []struct {
+		name            string
+		annotated       bool
+		target          string
+		operatorManaged bool
+		replicaReady    bool
+		healErr         error
+		expHeal         bool
+		expCondition    metav1.ConditionStatus
+	}{
+		{
+			name:      "No switchover requested",
+			annotated: false,
+		},
+		{
+			name:            "Switchover to a given replica with operator managed failover",
+			annotated:       true,
+			target:          "rfr-test-1",
+			operatorManaged: true,
+			replicaReady:    true,
+			expHeal:         true,
+			expCondition:    metav1.ConditionTrue,
+		},
+		{
+			name:         "Switchover to the best replica with sentinel",
+			annotated:    true,
+			replicaReady: true,
+			expHeal:      true,
+			expCondition: metav1.ConditionTrue,
+		},
+		{
+			name:         "Switchover to the current master",
+			annotated:    true,
+			target:       "rfr-test-0",
+			expCondition: metav1.ConditionTrue,
+		},
+		{
+			name:         "Switchover to an unknown pod",
+			annotated:    true,
+			target:       "rfr-test-9",
+			replicaReady: true,
+			expCondition: metav1.ConditionFalse,
+		},
+		{
+			name:         "Switchover to a replica not in sync",
+			annotated:    true,
+			target:       "rfr-test-1",
+			replicaReady: false,
+			expCondition: metav1.ConditionFalse,
+		},
+		{
+			name:            "Switchover fails",
+			annotated:       true,
+			target:          "rfr-test-1",
+			operatorManaged: true,
+			replicaReady:    true,
+			healErr:         errors.New(""),
+			expHeal:         true,
+			expCondition:    metav1.ConditionFalse,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			if test.annotated {
+				rf.Annotations = map[string]string{v1.SwitchoverAnnotation: test.target}
+			}
+			if test.operatorManaged {
+				disabled := false
+				rf.Spec.Sentinel.Enabled = &disabled
+			}
+
+			replica := rfservice.ReplicaInfo{IP: "0.0.0.1", PodName: "rfr-test-1", ReplicationOffset: 100, IsReady: test.replicaReady}
+
+			mk := &mK8SService.Services{}
+			mrfs := &mRFService.RedisFailoverClient{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+
+			if test.annotated {
+				mrfc.On("IsRedisRunning", rf).Once().Return(true)
+				mrfc.On("GetMasterIP", mock.Anything, rf).Once().Return("0.0.0.0", nil)
+				mrfc.On("GetRedisesMasterPod", mock.Anything, rf).Once().Return("rfr-test-0", nil)
+				if test.target == "" {
+					mrfc.On("GetBestReplicaForPromotion", mock.Anything, rf).Once().Return(&replica, nil)
+				} else {
+					mrfc.On("GetReplicaReplicationOffsets", mock.Anything, rf).Maybe().Return([]rfservice.ReplicaInfo{replica}, nil)
+				}
+				if test.expHeal {
+					if test.operatorManaged {
+						mrfh.On("Switchover", mock.Anything, "0.0.0.0", "0.0.0.1", rf).Once().Return(test.healErr)
+					} else {
+						mrfc.On("GetSentinelsIPs", rf).Once().Return([]string{"1.1.1.1"}, nil)
+						mrfh.On("SentinelSwitchover", mock.Anything, "1.1.1.1", "0.0.0.0", "0.0.0.1", rf).Once().Return(test.healErr)
+					}
+				}
+				mk.On("RemoveRedisFailoverAnnotation", context.Background(), rf.Namespace, rf.Name, v1.SwitchoverAnnotation).Once().Return(nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, &mRFService.RedisFailoverBackup{}, mk, metrics.Dummy, log.Dummy)
+			err := handler.Switchover(context.TODO(), rf)
+			assert.NoError(err)
+
+			condition := rf.GetCondition(v1.ConditionSwitchover)
+			if test.annotated {
+				if assert.NotNil(condition) {
+					assert.Equal(test.expCondition, condition.Status)
+				}
+			} else {
+				assert.Nil(condition)
+			}
+
+			mk.AssertExpectations(t)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package redisfailover
+
+import (
+	"slices"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/watch"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	rfservice "github.com/saremox/redis-operator/operator/redisfailover/service"
+)
+
+// secretsWatcher forwards the events of the RedisFailovers watcher, along with an update of the
+// RedisFailovers using a secret each time the secret is modified, so the passwords and
+// certificates are applied without waiting for the resync.
+type secretsWatcher struct {
+	rfWatcher          watch.Interface
+	secretWatcher      watch.Interface
+	listRedisFailovers func(namespace string) ([]redisfailoverv1.RedisFailover, error)
+	resourceVersion    string
+	logger             log.Logger
+	result             chan watch.Event
+	done               chan struct{}
+	stopOnce           sync.Once
+}
+
+// newSecretsWatcher starts forwarding the events from the given watchers. resourceVersion is the
+// one the RedisFailovers are watched from.
+func newSecretsWatcher(rfWatcher, secretWatcher watch.Interface, resourceVersion string, listRedisFailovers func(namespace string) ([]redisfailoverv1.RedisFailover, error), logger log.Logger) watch.Interface {
+	w := &secretsWatcher{
+		rfWatcher:          rfWatcher,
+		secretWatcher:      secretWatcher,
+		listRedisFailovers: listRedisFailovers,
+		resourceVersion:    resourceVersion,
+		logger:             logger,
+		result:             make(chan watch.Event),
+		done:               make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *secretsWatcher) run() {
+	defer close(w.result)
+	defer w.rfWatcher.Stop()
+	defer w.secretWatcher.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.rfWatcher.ResultChan():
+			if !ok {
+				return
+			}
+			if accessor, err := meta.Accessor(event.Object); err == nil && event.Type != watch.Error && accessor.GetResourceVersion() != "" {
+				w.resourceVersion = accessor.GetResourceVersion()
+			}
+			if !w.send(event) {
+				return
+			}
+		case event, ok := <-w.secretWatcher.ResultChan():
+			// Both watches are restarted together when one of them ends
+			if !ok {
+				return
+			}
+			secret, isSecret := event.Object.(*corev1.Secret)
+			if event.Type != watch.Modified || !isSecret {
+				continue
+			}
+			if !w.sendUsing(secret) {
+				return
+			}
+		}
+	}
+}
+
+// sendUsing sends an update of the RedisFailovers using the secret. They are given the resource
+// version of the last event sent, so the RedisFailovers watch is resumed from where it was.
+func (w *secretsWatcher) sendUsing(secret *corev1.Secret) bool {
+	rfs, err := w.listRedisFailovers(secret.Namespace)
+	if err != nil {
+		w.logger.Errorf("Unable to list the redis failovers using secret %s/%s: %s", secret.Namespace, secret.Name, err.Error())
+		return true
+	}
+	for i := range rfs {
+		if !usesSecret(&rfs[i], secret.Name) {
+			continue
+		}
+		rf := rfs[i].DeepCopy()
+		rf.ResourceVersion = w.resourceVersion
+		if !w.send(watch.Event{Type: watch.Modified, Object: rf}) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *secretsWatcher) send(event watch.Event) bool {
+	select {
+	case w.result <- event:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+// Stop stops both watches.
+func (w *secretsWatcher) Stop() {
+	w.stopOnce.Do(func() { close(w.done) })
+}
+
+// ResultChan returns the channel the events are forwarded to.
+func (w *secretsWatcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+// usesSecret returns true when the RedisFailover reads the passwords or certificates of the secret
+func usesSecret(rf *redisfailoverv1.RedisFailover, name string) bool {
+	if rf.Spec.Auth.SecretPath == name {
+		return true
+	}
+	if rf.TLSEnabled() && rfservice.GetTLSSecretName(rf) == name {
+		return true
+	}
+	return slices.ContainsFunc(rf.Spec.Auth.Users, func(user redisfailoverv1.RedisUser) bool {
+		return user.SecretPath == name
+	})
+}
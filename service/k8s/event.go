@@ -0,0 +1,44 @@
+package k8s
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+)
+
+// Event the Event service that knows how to interact with k8s to record them
+type Event interface {
+	CreateEvent(namespace string, event *corev1.Event) error
+}
+
+// EventService is the event service implementation using API calls to kubernetes.
+type EventService struct {
+	kubeClient      kubernetes.Interface
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewEventService returns a new Event KubeService.
+func NewEventService(kubeClient kubernetes.Interface, logger log.Logger, metricsRecorder metrics.Recorder) *EventService {
+	logger = logger.With("service", "k8s.event")
+	return &EventService{
+		kubeClient:      kubeClient,
+		logger:          logger,
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+func (e *EventService) CreateEvent(namespace string, event *corev1.Event) error {
+	_, err := e.kubeClient.CoreV1().Events(namespace).Create(context.TODO(), event, metav1.CreateOptions{})
+	recordMetrics(namespace, "Event", event.InvolvedObject.Name, "CREATE", err, e.metricsRecorder)
+	if err != nil {
+		return err
+	}
+	e.logger.WithField("namespace", namespace).WithField("reason", event.Reason).Debugf("event created for %s", event.InvolvedObject.Name)
+	return nil
+}
@@ -0,0 +1,45 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+	"github.com/saremox/redis-operator/service/k8s"
+)
+
+func TestEventServiceCreate(t *testing.T) {
+	assert := assert.New(t)
+
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test.1",
+			Namespace: "testns",
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "RedisFailover",
+			Name:      "test",
+			Namespace: "testns",
+		},
+		Reason:  "FinalSnapshotTaken",
+		Message: "snapshot uploaded to s3://backups/test/test-final.rdb",
+		Type:    corev1.EventTypeNormal,
+	}
+
+	mcli := kubernetes.NewSimpleClientset()
+	service := k8s.NewEventService(mcli, log.Dummy, metrics.Dummy)
+	assert.NoError(service.CreateEvent("testns", event))
+
+	got, err := mcli.CoreV1().Events("testns").Get(context.TODO(), "test.1", metav1.GetOptions{})
+	assert.NoError(err)
+	assert.Equal(event.Message, got.Message)
+
+	// Creating it again fails
+	assert.Error(service.CreateEvent("testns", event))
+}
@@ -16,10 +16,14 @@ type Services interface {
 	Pod
 	PodDisruptionBudget
 	RedisFailover
+	RedisFailoverBackup
 	Service
 	RBAC
 	Deployment
 	StatefulSet
+	PersistentVolumeClaim
+	StorageClass
+	Event
 }
 
 type services struct {
@@ -28,23 +32,31 @@ type services struct {
 	Pod
 	PodDisruptionBudget
 	RedisFailover
+	RedisFailoverBackup
 	Service
 	RBAC
 	Deployment
 	StatefulSet
+	PersistentVolumeClaim
+	StorageClass
+	Event
 }
 
 // New returns a new Kubernetes service.
 func New(kubecli kubernetes.Interface, crdcli redisfailoverclientset.Interface, apiextcli apiextensionscli.Interface, logger log.Logger, metricsRecorder metrics.Recorder) Services {
 	return &services{
-		ConfigMap:           NewConfigMapService(kubecli, logger, metricsRecorder),
-		Secret:              NewSecretService(kubecli, logger, metricsRecorder),
-		Pod:                 NewPodService(kubecli, logger, metricsRecorder),
-		PodDisruptionBudget: NewPodDisruptionBudgetService(kubecli, logger, metricsRecorder),
-		RedisFailover:       NewRedisFailoverService(crdcli, logger, metricsRecorder),
-		Service:             NewServiceService(kubecli, logger, metricsRecorder),
-		RBAC:                NewRBACService(kubecli, logger, metricsRecorder),
-		Deployment:          NewDeploymentService(kubecli, logger, metricsRecorder),
-		StatefulSet:         NewStatefulSetService(kubecli, logger, metricsRecorder),
+		ConfigMap:             NewConfigMapService(kubecli, logger, metricsRecorder),
+		Secret:                NewSecretService(kubecli, logger, metricsRecorder),
+		Pod:                   NewPodService(kubecli, logger, metricsRecorder),
+		PodDisruptionBudget:   NewPodDisruptionBudgetService(kubecli, logger, metricsRecorder),
+		RedisFailover:         NewRedisFailoverService(crdcli, logger, metricsRecorder),
+		RedisFailoverBackup:   NewRedisFailoverBackupService(crdcli, logger, metricsRecorder),
+		Service:               NewServiceService(kubecli, logger, metricsRecorder),
+		RBAC:                  NewRBACService(kubecli, logger, metricsRecorder),
+		Deployment:            NewDeploymentService(kubecli, logger, metricsRecorder),
+		StatefulSet:           NewStatefulSetService(kubecli, logger, metricsRecorder),
+		PersistentVolumeClaim: NewPersistentVolumeClaimService(kubecli, logger, metricsRecorder),
+		StorageClass:          NewStorageClassService(kubecli, logger, metricsRecorder),
+		Event:                 NewEventService(kubecli, logger, metricsRecorder),
 	}
 }
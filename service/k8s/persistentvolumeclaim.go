@@ -0,0 +1,72 @@
+package k8s
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+)
+
+// PersistentVolumeClaim the PersistentVolumeClaim service that knows how to interact with k8s to manage them
+type PersistentVolumeClaim interface {
+	GetPersistentVolumeClaim(namespace string, name string) (*corev1.PersistentVolumeClaim, error)
+	UpdatePersistentVolumeClaim(namespace string, pvc *corev1.PersistentVolumeClaim) error
+	DeletePersistentVolumeClaim(namespace string, name string) error
+	ListPersistentVolumeClaims(namespace string, opts metav1.ListOptions) (*corev1.PersistentVolumeClaimList, error)
+}
+
+// PersistentVolumeClaimService is the persistent volume claim service implementation using API calls to kubernetes.
+type PersistentVolumeClaimService struct {
+	kubeClient      kubernetes.Interface
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewPersistentVolumeClaimService returns a new PersistentVolumeClaim KubeService.
+func NewPersistentVolumeClaimService(kubeClient kubernetes.Interface, logger log.Logger, metricsRecorder metrics.Recorder) *PersistentVolumeClaimService {
+	logger = logger.With("service", "k8s.persistentVolumeClaim")
+	return &PersistentVolumeClaimService{
+		kubeClient:      kubeClient,
+		logger:          logger,
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+func (p *PersistentVolumeClaimService) GetPersistentVolumeClaim(namespace string, name string) (*corev1.PersistentVolumeClaim, error) {
+	pvc, err := p.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	recordMetrics(namespace, "PersistentVolumeClaim", name, "GET", err, p.metricsRecorder)
+	if err != nil {
+		return nil, err
+	}
+	return pvc, nil
+}
+
+func (p *PersistentVolumeClaimService) UpdatePersistentVolumeClaim(namespace string, pvc *corev1.PersistentVolumeClaim) error {
+	_, err := p.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Update(context.TODO(), pvc, metav1.UpdateOptions{})
+	recordMetrics(namespace, "PersistentVolumeClaim", pvc.Name, "UPDATE", err, p.metricsRecorder)
+	if err != nil {
+		return err
+	}
+	p.logger.WithField("namespace", namespace).WithField("persistentVolumeClaim", pvc.Name).Debugf("persistentVolumeClaim updated")
+	return nil
+}
+
+func (p *PersistentVolumeClaimService) DeletePersistentVolumeClaim(namespace string, name string) error {
+	err := p.kubeClient.CoreV1().PersistentVolumeClaims(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	recordMetrics(namespace, "PersistentVolumeClaim", name, "DELETE", err, p.metricsRecorder)
+	if err != nil {
+		return err
+	}
+	p.logger.WithField("namespace", namespace).WithField("persistentVolumeClaim", name).Debugf("persistentVolumeClaim deleted")
+	return nil
+}
+
+func (p *PersistentVolumeClaimService) ListPersistentVolumeClaims(namespace string, opts metav1.ListOptions) (*corev1.PersistentVolumeClaimList, error) {
+	pvcs, err := p.kubeClient.CoreV1().PersistentVolumeClaims(namespace).List(context.TODO(), opts)
+	recordMetrics(namespace, "PersistentVolumeClaim", metrics.NOT_APPLICABLE, "LIST", err, p.metricsRecorder)
+	return pvcs, err
+}
@@ -0,0 +1,71 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+	"github.com/saremox/redis-operator/service/k8s"
+)
+
+func TestPersistentVolumeClaimServiceGetUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "redis-data-rfr-test-0",
+			Namespace:       "testns",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "RedisFailover", Name: "test"}},
+		},
+	}
+
+	mcli := kubernetes.NewSimpleClientset(pvc)
+	service := k8s.NewPersistentVolumeClaimService(mcli, log.Dummy, metrics.Dummy)
+
+	got, err := service.GetPersistentVolumeClaim("testns", pvc.Name)
+	assert.NoError(err)
+	assert.Len(got.OwnerReferences, 1)
+
+	got.OwnerReferences = nil
+	assert.NoError(service.UpdatePersistentVolumeClaim("testns", got))
+	got, err = mcli.CoreV1().PersistentVolumeClaims("testns").Get(context.TODO(), pvc.Name, metav1.GetOptions{})
+	assert.NoError(err)
+	assert.Empty(got.OwnerReferences)
+
+	_, err = service.GetPersistentVolumeClaim("testns", "missing")
+	assert.True(kubeerrors.IsNotFound(err))
+}
+
+func TestPersistentVolumeClaimServiceListDelete(t *testing.T) {
+	assert := assert.New(t)
+
+	labels := map[string]string{"app.kubernetes.io/component": "redis", "app.kubernetes.io/name": "test"}
+	mcli := kubernetes.NewSimpleClientset(
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "redis-data-rfr-test-0", Namespace: "testns", Labels: labels}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "redis-data-rfr-test-1", Namespace: "testns", Labels: labels}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "testns"}},
+	)
+	service := k8s.NewPersistentVolumeClaimService(mcli, log.Dummy, metrics.Dummy)
+
+	opts := metav1.ListOptions{LabelSelector: "app.kubernetes.io/component=redis,app.kubernetes.io/name=test"}
+	pvcs, err := service.ListPersistentVolumeClaims("testns", opts)
+	assert.NoError(err)
+	assert.Len(pvcs.Items, 2)
+
+	assert.NoError(service.DeletePersistentVolumeClaim("testns", "redis-data-rfr-test-1"))
+	pvcs, err = service.ListPersistentVolumeClaims("testns", opts)
+	assert.NoError(err)
+	if assert.Len(pvcs.Items, 1) {
+		assert.Equal("redis-data-rfr-test-0", pvcs.Items[0].Name)
+	}
+
+	err = service.DeletePersistentVolumeClaim("testns", "redis-data-rfr-test-1")
+	assert.True(kubeerrors.IsNotFound(err))
+}
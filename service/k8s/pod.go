@@ -24,6 +24,7 @@ type Pod interface {
 	DeletePod(namespace string, name string) error
 	ListPods(namespace string) (*corev1.PodList, error)
 	UpdatePodLabels(namespace, podName string, labels map[string]string) error
+	RemovePodLabels(namespace, podName string, keys []string) error
 }
 
 // PodService is the pod service implementation using API calls to kubernetes.
@@ -128,3 +129,21 @@ func (p *PodService) UpdatePodLabels(namespace, podName string, labels map[strin
 	}
 	return err
 }
+
+// RemovePodLabels removes the labels from the pod, the labels it does not have are ignored
+func (p *PodService) RemovePodLabels(namespace, podName string, keys []string) error {
+	p.logger.Infof("Remove pod labels, namespace: %s, pod name: %s, labels: %v", namespace, podName, keys)
+
+	labels := map[string]interface{}{}
+	for _, key := range keys {
+		labels[key] = nil
+	}
+	payloadBytes, _ := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"labels": labels}})
+
+	_, err := p.kubeClient.CoreV1().Pods(namespace).Patch(context.TODO(), podName, types.MergePatchType, payloadBytes, metav1.PatchOptions{})
+	recordMetrics(namespace, "Pod", podName, "PATCH", err, p.metricsRecorder)
+	if err != nil {
+		p.logger.Errorf("Remove pod labels failed, namespace: %s, pod name: %s, error: %v", namespace, podName, err)
+	}
+	return err
+}
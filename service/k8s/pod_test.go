@@ -117,3 +117,23 @@ func TestPodServiceGetCreateOrUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestPodServiceRemovePodLabels(t *testing.T) {
+	assert := assert.New(t)
+	testns := "testns"
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testpod1",
+			Namespace: testns,
+			Labels:    map[string]string{"role": "master", "epoch": "3"},
+		},
+	}
+	mcli := kubernetes.NewSimpleClientset(pod)
+	service := k8s.NewPodService(mcli, log.Dummy, metrics.Dummy)
+
+	assert.NoError(service.RemovePodLabels(testns, pod.Name, []string{"epoch", "unknown"}))
+	updated, err := service.GetPod(testns, pod.Name)
+	assert.NoError(err)
+	assert.Equal(map[string]string{"role": "master"}, updated.Labels)
+}
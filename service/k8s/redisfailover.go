@@ -2,7 +2,9 @@ package k8s
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
+	"slices"
+
 	"k8s.io/apimachinery/pkg/types"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,9 +20,17 @@ import (
 type RedisFailover interface {
 	// ListRedisFailovers lists the redisfailovers on a cluster.
 	ListRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverList, error)
+	// GetRedisFailover gets a redisfailover of a namespace.
+	GetRedisFailover(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailover, error)
 	// WatchRedisFailovers watches the redisfailovers on a cluster.
 	WatchRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error)
 	UpdateRedisFailoverStatus(ctx context.Context, namespace string, redisFailover *redisfailoverv1.RedisFailover, opts metav1.PatchOptions)
+	// RemoveRedisFailoverAnnotation removes an annotation from a redisfailover.
+	RemoveRedisFailoverAnnotation(ctx context.Context, namespace string, name string, key string) error
+	// AddRedisFailoverFinalizer adds a finalizer to a redisfailover.
+	AddRedisFailoverFinalizer(ctx context.Context, redisFailover *redisfailoverv1.RedisFailover, finalizer string) error
+	// RemoveRedisFailoverFinalizer removes a finalizer from a redisfailover.
+	RemoveRedisFailoverFinalizer(ctx context.Context, redisFailover *redisfailoverv1.RedisFailover, finalizer string) error
 }
 
 // RedisFailoverService is the RedisFailover service implementation using API calls to kubernetes.
@@ -47,6 +57,13 @@ func (r *RedisFailoverService) ListRedisFailovers(ctx context.Context, namespace
 	return redisFailoverList, err
 }
 
+// GetRedisFailover satisfies redisfailover.Service interface.
+func (r *RedisFailoverService) GetRedisFailover(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailover, error) {
+	redisFailover, err := r.k8sCli.DatabasesV1().RedisFailovers(namespace).Get(ctx, name, metav1.GetOptions{})
+	recordMetrics(namespace, "RedisFailover", name, "GET", err, r.metricsRecorder)
+	return redisFailover, err
+}
+
 // WatchRedisFailovers satisfies redisfailover.Service interface.
 func (r *RedisFailoverService) WatchRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
 	watcher, err := r.k8sCli.DatabasesV1().RedisFailovers(namespace).Watch(ctx, opts)
@@ -54,11 +71,79 @@ func (r *RedisFailoverService) WatchRedisFailovers(ctx context.Context, namespac
 	return watcher, err
 }
 
+// UpdateRedisFailoverStatus replaces the whole status of the RedisFailover with the given one.
 func (r *RedisFailoverService) UpdateRedisFailoverStatus(ctx context.Context, namespace string, rf *redisfailoverv1.RedisFailover, opts metav1.PatchOptions) {
-	status := fmt.Sprintf(`{"status":  {"state": "%s", "lastChanged": "%s", "message": "%s"}}`, rf.Status.State, rf.Status.LastChanged, rf.Status.Message)
-	_, err := r.k8sCli.DatabasesV1().RedisFailovers(namespace).Patch(ctx, rf.Name, types.MergePatchType, []byte(status), opts)
+	// An "add" operation replaces the status when it already exists, so fields and
+	// conditions that are no longer set are removed as well.
+	patch, err := json.Marshal([]PatchStringValue{{
+		Op:    "add",
+		Path:  "/status",
+		Value: rf.Status,
+	}})
+	if err != nil {
+		r.logger.Errorf("Error while marshalling RedisFailover status %s/%s : %s", rf.Namespace, rf.Name, err.Error())
+		return
+	}
+	_, err = r.k8sCli.DatabasesV1().RedisFailovers(namespace).Patch(ctx, rf.Name, types.JSONPatchType, patch, opts)
 	if err != nil {
 		recordMetrics(namespace, "RedisFailover", metrics.NOT_APPLICABLE, "PATCH", err, r.metricsRecorder)
 		r.logger.Errorf("Error while patching RedisFailover status %s/%s : %s", rf.Namespace, rf.Name, err.Error())
 	}
 }
+
+// RemoveRedisFailoverAnnotation satisfies redisfailover.Service interface.
+func (r *RedisFailoverService) RemoveRedisFailoverAnnotation(ctx context.Context, namespace string, name string, key string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				key: nil,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.k8sCli.DatabasesV1().RedisFailovers(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	recordMetrics(namespace, "RedisFailover", name, "PATCH", err, r.metricsRecorder)
+	return err
+}
+
+// AddRedisFailoverFinalizer satisfies redisfailover.Service interface.
+func (r *RedisFailoverService) AddRedisFailoverFinalizer(ctx context.Context, rf *redisfailoverv1.RedisFailover, finalizer string) error {
+	if slices.Contains(rf.Finalizers, finalizer) {
+		return nil
+	}
+	return r.patchRedisFailoverFinalizers(ctx, rf, append(slices.Clone(rf.Finalizers), finalizer))
+}
+
+// RemoveRedisFailoverFinalizer satisfies redisfailover.Service interface.
+func (r *RedisFailoverService) RemoveRedisFailoverFinalizer(ctx context.Context, rf *redisfailoverv1.RedisFailover, finalizer string) error {
+	if !slices.Contains(rf.Finalizers, finalizer) {
+		return nil
+	}
+	finalizers := slices.DeleteFunc(slices.Clone(rf.Finalizers), func(f string) bool { return f == finalizer })
+	return r.patchRedisFailoverFinalizers(ctx, rf, finalizers)
+}
+
+// patchRedisFailoverFinalizers replaces the finalizers of the redisfailover. The patch is
+// rejected when the redisfailover changed since it was read, so finalizers added meanwhile by
+// others are not lost.
+func (r *RedisFailoverService) patchRedisFailoverFinalizers(ctx context.Context, rf *redisfailoverv1.RedisFailover, finalizers []string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers":      finalizers,
+			"resourceVersion": rf.ResourceVersion,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	patched, err := r.k8sCli.DatabasesV1().RedisFailovers(rf.Namespace).Patch(ctx, rf.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	recordMetrics(rf.Namespace, "RedisFailover", rf.Name, "PATCH", err, r.metricsRecorder)
+	if err != nil {
+		return err
+	}
+	rf.Finalizers = patched.Finalizers
+	rf.ResourceVersion = patched.ResourceVersion
+	return nil
+}
@@ -0,0 +1,82 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	redisfailoverfake "github.com/saremox/redis-operator/client/k8s/clientset/versioned/fake"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+)
+
+func TestRedisFailoverServiceUpdateStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test_namespace",
+		},
+		Status: redisfailoverv1.RedisFailoverStatus{
+			State:   redisfailoverv1.NotHealthyState,
+			Message: "unable to get number of masters",
+		},
+	}
+	rf.SetCondition(redisfailoverv1.ConditionMasterAvailable, metav1.ConditionFalse, redisfailoverv1.ReasonCheckFailed, "unable to get number of masters")
+
+	cli := redisfailoverfake.NewSimpleClientset(rf.DeepCopy())
+	service := NewRedisFailoverService(cli, log.Dummy, metrics.Dummy)
+
+	// Fields that are no longer set must be removed from the stored status.
+	rf.Status.State = redisfailoverv1.HealthyState
+	rf.Status.Message = ""
+	rf.Status.ObservedGeneration = 4
+	rf.SetCondition(redisfailoverv1.ConditionMasterAvailable, metav1.ConditionTrue, redisfailoverv1.ReasonMasterElected, "one master is serving")
+	service.UpdateRedisFailoverStatus(context.TODO(), rf.Namespace, rf, metav1.PatchOptions{})
+
+	got, err := cli.DatabasesV1().RedisFailovers(rf.Namespace).Get(context.TODO(), rf.Name, metav1.GetOptions{})
+	assert.NoError(err)
+	assert.Equal(redisfailoverv1.HealthyState, got.Status.State)
+	assert.Empty(got.Status.Message)
+	assert.Equal(int64(4), got.Status.ObservedGeneration)
+	if assert.Len(got.Status.Conditions, 1) {
+		assert.Equal(metav1.ConditionTrue, got.Status.Conditions[0].Status)
+		assert.Equal(redisfailoverv1.ReasonMasterElected, got.Status.Conditions[0].Reason)
+	}
+}
+
+func TestRedisFailoverServiceFinalizers(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test",
+			Namespace:  "test_namespace",
+			Finalizers: []string{"example.com/other"},
+		},
+	}
+
+	cli := redisfailoverfake.NewSimpleClientset(rf.DeepCopy())
+	service := NewRedisFailoverService(cli, log.Dummy, metrics.Dummy)
+
+	assert.NoError(service.AddRedisFailoverFinalizer(context.TODO(), rf, redisfailoverv1.FinalSnapshotFinalizer))
+	assert.Equal([]string{"example.com/other", redisfailoverv1.FinalSnapshotFinalizer}, rf.Finalizers)
+	got, err := cli.DatabasesV1().RedisFailovers(rf.Namespace).Get(context.TODO(), rf.Name, metav1.GetOptions{})
+	assert.NoError(err)
+	assert.Equal([]string{"example.com/other", redisfailoverv1.FinalSnapshotFinalizer}, got.Finalizers)
+
+	// Adding it again is a no-op
+	actions := len(cli.Actions())
+	assert.NoError(service.AddRedisFailoverFinalizer(context.TODO(), rf, redisfailoverv1.FinalSnapshotFinalizer))
+	assert.Len(cli.Actions(), actions)
+
+	assert.NoError(service.RemoveRedisFailoverFinalizer(context.TODO(), rf, redisfailoverv1.FinalSnapshotFinalizer))
+	assert.Equal([]string{"example.com/other"}, rf.Finalizers)
+	got, err = cli.DatabasesV1().RedisFailovers(rf.Namespace).Get(context.TODO(), rf.Name, metav1.GetOptions{})
+	assert.NoError(err)
+	assert.Equal([]string{"example.com/other"}, got.Finalizers)
+}
@@ -0,0 +1,92 @@
+package k8s
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	redisfailoverclientset "github.com/saremox/redis-operator/client/k8s/clientset/versioned"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+)
+
+// RedisFailoverBackup the RF backup service that knows how to interact with k8s to get them
+type RedisFailoverBackup interface {
+	// GetRedisFailoverBackup gets a redisfailoverbackup.
+	GetRedisFailoverBackup(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailoverBackup, error)
+	// ListRedisFailoverBackups lists the redisfailoverbackups on a cluster.
+	ListRedisFailoverBackups(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverBackupList, error)
+	// WatchRedisFailoverBackups watches the redisfailoverbackups on a cluster.
+	WatchRedisFailoverBackups(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error)
+	// CreateRedisFailoverBackup creates a redisfailoverbackup.
+	CreateRedisFailoverBackup(ctx context.Context, namespace string, backup *redisfailoverv1.RedisFailoverBackup) error
+	// UpdateRedisFailoverBackup updates a redisfailoverbackup, along with its status.
+	UpdateRedisFailoverBackup(ctx context.Context, namespace string, backup *redisfailoverv1.RedisFailoverBackup) (*redisfailoverv1.RedisFailoverBackup, error)
+	// DeleteRedisFailoverBackup deletes a redisfailoverbackup.
+	DeleteRedisFailoverBackup(ctx context.Context, namespace string, name string) error
+}
+
+// RedisFailoverBackupService is the RedisFailoverBackup service implementation using API calls to kubernetes.
+type RedisFailoverBackupService struct {
+	k8sCli          redisfailoverclientset.Interface
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewRedisFailoverBackupService returns a new RedisFailoverBackup KubeService.
+func NewRedisFailoverBackupService(k8scli redisfailoverclientset.Interface, logger log.Logger, metricsRecorder metrics.Recorder) *RedisFailoverBackupService {
+	logger = logger.With("service", "k8s.redisfailoverbackup")
+	return &RedisFailoverBackupService{
+		k8sCli:          k8scli,
+		logger:          logger,
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+// GetRedisFailoverBackup satisfies redisfailoverbackup.Service interface.
+func (r *RedisFailoverBackupService) GetRedisFailoverBackup(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailoverBackup, error) {
+	backup, err := r.k8sCli.DatabasesV1().RedisFailoverBackups(namespace).Get(ctx, name, metav1.GetOptions{})
+	recordMetrics(namespace, "RedisFailoverBackup", name, "GET", err, r.metricsRecorder)
+	return backup, err
+}
+
+// ListRedisFailoverBackups satisfies redisfailoverbackup.Service interface.
+func (r *RedisFailoverBackupService) ListRedisFailoverBackups(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverBackupList, error) {
+	backupList, err := r.k8sCli.DatabasesV1().RedisFailoverBackups(namespace).List(ctx, opts)
+	recordMetrics(namespace, "RedisFailoverBackup", metrics.NOT_APPLICABLE, "LIST", err, r.metricsRecorder)
+	return backupList, err
+}
+
+// WatchRedisFailoverBackups satisfies redisfailoverbackup.Service interface.
+func (r *RedisFailoverBackupService) WatchRedisFailoverBackups(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	watcher, err := r.k8sCli.DatabasesV1().RedisFailoverBackups(namespace).Watch(ctx, opts)
+	recordMetrics(namespace, "RedisFailoverBackup", metrics.NOT_APPLICABLE, "WATCH", err, r.metricsRecorder)
+	return watcher, err
+}
+
+// CreateRedisFailoverBackup satisfies redisfailoverbackup.Service interface.
+func (r *RedisFailoverBackupService) CreateRedisFailoverBackup(ctx context.Context, namespace string, backup *redisfailoverv1.RedisFailoverBackup) error {
+	_, err := r.k8sCli.DatabasesV1().RedisFailoverBackups(namespace).Create(ctx, backup, metav1.CreateOptions{})
+	recordMetrics(namespace, "RedisFailoverBackup", backup.Name, "CREATE", err, r.metricsRecorder)
+	if err != nil {
+		return err
+	}
+	r.logger.WithField("namespace", namespace).WithField("redisfailoverbackup", backup.Name).Debugf("redisfailoverbackup created")
+	return nil
+}
+
+// UpdateRedisFailoverBackup satisfies redisfailoverbackup.Service interface.
+func (r *RedisFailoverBackupService) UpdateRedisFailoverBackup(ctx context.Context, namespace string, backup *redisfailoverv1.RedisFailoverBackup) (*redisfailoverv1.RedisFailoverBackup, error) {
+	updated, err := r.k8sCli.DatabasesV1().RedisFailoverBackups(namespace).Update(ctx, backup, metav1.UpdateOptions{})
+	recordMetrics(namespace, "RedisFailoverBackup", backup.Name, "UPDATE", err, r.metricsRecorder)
+	return updated, err
+}
+
+// DeleteRedisFailoverBackup satisfies redisfailoverbackup.Service interface.
+func (r *RedisFailoverBackupService) DeleteRedisFailoverBackup(ctx context.Context, namespace string, name string) error {
+	err := r.k8sCli.DatabasesV1().RedisFailoverBackups(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	recordMetrics(namespace, "RedisFailoverBackup", name, "DELETE", err, r.metricsRecorder)
+	return err
+}
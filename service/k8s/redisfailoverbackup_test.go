@@ -0,0 +1,54 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	redisfailoverfake "github.com/saremox/redis-operator/client/k8s/clientset/versioned/fake"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+)
+
+func TestRedisFailoverBackupService(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.TODO()
+
+	backup := &redisfailoverv1.RedisFailoverBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-202401310300",
+			Namespace: "test_namespace",
+		},
+		Spec: redisfailoverv1.RedisFailoverBackupSpec{
+			RedisFailoverName: "test",
+		},
+	}
+
+	cli := redisfailoverfake.NewSimpleClientset()
+	service := NewRedisFailoverBackupService(cli, log.Dummy, metrics.Dummy)
+
+	assert.NoError(service.CreateRedisFailoverBackup(ctx, backup.Namespace, backup.DeepCopy()))
+	assert.True(errors.IsAlreadyExists(service.CreateRedisFailoverBackup(ctx, backup.Namespace, backup.DeepCopy())))
+
+	got, err := service.GetRedisFailoverBackup(ctx, backup.Namespace, backup.Name)
+	assert.NoError(err)
+	got.Status.Phase = redisfailoverv1.BackupPhaseCompleted
+	got.Status.Size = 1024
+	updated, err := service.UpdateRedisFailoverBackup(ctx, backup.Namespace, got)
+	assert.NoError(err)
+	assert.Equal(redisfailoverv1.BackupPhaseCompleted, updated.Status.Phase)
+
+	backups, err := service.ListRedisFailoverBackups(ctx, backup.Namespace, metav1.ListOptions{})
+	assert.NoError(err)
+	if assert.Len(backups.Items, 1) {
+		assert.Equal(int64(1024), backups.Items[0].Status.Size)
+	}
+
+	assert.NoError(service.DeleteRedisFailoverBackup(ctx, backup.Namespace, backup.Name))
+	_, err = service.GetRedisFailoverBackup(ctx, backup.Namespace, backup.Name)
+	assert.True(errors.IsNotFound(err))
+}
@@ -6,13 +6,26 @@ import (
 	"github.com/saremox/redis-operator/log"
 	"github.com/saremox/redis-operator/metrics"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 )
 
-// Secret interacts with k8s to get secrets
+// Keys of the certificate, private key and CA certificate in a TLS secret
+const (
+	TLSCertKey       = corev1.TLSCertKey
+	TLSPrivateKeyKey = corev1.TLSPrivateKeyKey
+	TLSCAKey         = "ca.crt"
+)
+
+// Secret interacts with k8s to get, create and update secrets
 type Secret interface {
 	GetSecret(namespace, name string) (*corev1.Secret, error)
+	CreateSecret(namespace string, secret *corev1.Secret) error
+	UpdateSecret(namespace string, secret *corev1.Secret) error
+	CreateOrUpdateSecret(namespace string, secret *corev1.Secret) error
+	WatchSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error)
 }
 
 // SecretService is the secret service implementation using API calls to kubernetes.
@@ -42,3 +55,45 @@ func (s *SecretService) GetSecret(namespace, name string) (*corev1.Secret, error
 
 	return secret, err
 }
+
+func (s *SecretService) CreateSecret(namespace string, secret *corev1.Secret) error {
+	_, err := s.kubeClient.CoreV1().Secrets(namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+	recordMetrics(namespace, "Secret", secret.GetName(), "CREATE", err, s.metricsRecorder)
+	if err != nil {
+		return err
+	}
+	s.logger.WithField("namespace", namespace).WithField("secret", secret.Name).Debugf("secret created")
+	return nil
+}
+
+func (s *SecretService) UpdateSecret(namespace string, secret *corev1.Secret) error {
+	_, err := s.kubeClient.CoreV1().Secrets(namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+	recordMetrics(namespace, "Secret", secret.GetName(), "UPDATE", err, s.metricsRecorder)
+	if err != nil {
+		return err
+	}
+	s.logger.WithField("namespace", namespace).WithField("secret", secret.Name).Debugf("secret updated")
+	return nil
+}
+
+func (s *SecretService) CreateOrUpdateSecret(namespace string, secret *corev1.Secret) error {
+	storedSecret, err := s.GetSecret(namespace, secret.Name)
+	if err != nil {
+		// If no resource we need to create.
+		if errors.IsNotFound(err) {
+			return s.CreateSecret(namespace, secret)
+		}
+		return err
+	}
+
+	// Already exists, need to Update.
+	// Set the correct resource version to ensure we are on the latest version.
+	secret.ResourceVersion = storedSecret.ResourceVersion
+	return s.UpdateSecret(namespace, secret)
+}
+
+func (s *SecretService) WatchSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	watcher, err := s.kubeClient.CoreV1().Secrets(namespace).Watch(ctx, opts)
+	recordMetrics(namespace, "Secret", metrics.NOT_APPLICABLE, "WATCH", err, s.metricsRecorder)
+	return watcher, err
+}
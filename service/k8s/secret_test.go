@@ -12,6 +12,7 @@ import (
 	errors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	kubernetes "k8s.io/client-go/kubernetes/fake"
 	kubetesting "k8s.io/client-go/testing"
 )
@@ -58,3 +59,55 @@ func TestSecretServiceGet(t *testing.T) {
 		assertTest.True(errors.IsNotFound(err))
 	})
 }
+
+func TestSecretServiceCreateOrUpdate(t *testing.T) {
+	secretsGroup := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "secrets"}
+	testSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "test_secret",
+			ResourceVersion: "10",
+		},
+	}
+	testns := "testns"
+
+	tests := []struct {
+		name            string
+		getSecretResult *corev1.Secret
+		errorOnGet      error
+		expActions      []kubetesting.Action
+	}{
+		{
+			name:       "A new secret should create a new secret.",
+			errorOnGet: errors.NewNotFound(schema.GroupResource{}, ""),
+			expActions: []kubetesting.Action{
+				kubetesting.NewGetAction(secretsGroup, testns, testSecret.Name),
+				kubetesting.NewCreateAction(secretsGroup, testns, testSecret),
+			},
+		},
+		{
+			name:            "An existent secret should update the secret.",
+			getSecretResult: testSecret,
+			expActions: []kubetesting.Action{
+				kubetesting.NewGetAction(secretsGroup, testns, testSecret.Name),
+				kubetesting.NewUpdateAction(secretsGroup, testns, testSecret),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assertTest := assert.New(t)
+
+			mcli := &kubernetes.Clientset{}
+			mcli.AddReactor("get", "secrets", func(action kubetesting.Action) (bool, runtime.Object, error) {
+				return true, test.getSecretResult, test.errorOnGet
+			})
+
+			service := NewSecretService(mcli, log.Dummy, metrics.Dummy)
+			err := service.CreateOrUpdateSecret(testns, testSecret)
+
+			assertTest.NoError(err)
+			assertTest.Equal(test.expActions, mcli.Actions())
+		})
+	}
+}
@@ -109,7 +109,8 @@ func mergeImmutableServiceFields(stored, desired *corev1.Service) {
 	if len(desired.Spec.ClusterIPs) == 0 {
 		desired.Spec.ClusterIPs = stored.Spec.ClusterIPs
 	}
-	if len(desired.Spec.IPFamilies) == 0 {
+	// The primary IP family can't be changed once the service is created
+	if len(desired.Spec.IPFamilies) == 0 || (len(stored.Spec.IPFamilies) != 0 && desired.Spec.IPFamilies[0] != stored.Spec.IPFamilies[0]) {
 		desired.Spec.IPFamilies = stored.Spec.IPFamilies
 	}
 	if desired.Spec.IPFamilyPolicy == nil {
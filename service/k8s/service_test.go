@@ -234,3 +234,48 @@ func TestCreateOrUpdateServicePreservesHealthCheckNodePort(t *testing.T) {
 	assert.Equal(t, int32(32100), updatedService.Spec.HealthCheckNodePort, "healthCheckNodePort must be preserved")
 	assert.Equal(t, "10.0.0.2", updatedService.Spec.ClusterIP, "clusterIP must be preserved")
 }
+
+func TestCreateOrUpdateServicePreservesPrimaryIPFamily(t *testing.T) {
+	testns := "testns"
+
+	storedService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "testsvc-family",
+			ResourceVersion: "3",
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP:  "10.0.0.3",
+			ClusterIPs: []string{"10.0.0.3"},
+			IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+		},
+	}
+
+	preferDualStack := corev1.IPFamilyPolicyPreferDualStack
+	desiredService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "testsvc-family",
+		},
+		Spec: corev1.ServiceSpec{
+			IPFamilies:     []corev1.IPFamily{corev1.IPv6Protocol},
+			IPFamilyPolicy: &preferDualStack,
+		},
+	}
+
+	var updatedService *corev1.Service
+	mcli := &kubernetes.Clientset{}
+	mcli.AddReactor("get", "services", func(action kubetesting.Action) (bool, runtime.Object, error) {
+		return true, storedService, nil
+	})
+	mcli.AddReactor("update", "services", func(action kubetesting.Action) (bool, runtime.Object, error) {
+		ua := action.(kubetesting.UpdateAction)
+		updatedService = ua.GetObject().(*corev1.Service)
+		return true, updatedService, nil
+	})
+
+	svc := k8s.NewServiceService(mcli, log.Dummy, metrics.Dummy)
+	err := svc.CreateOrUpdateService(testns, desiredService)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []corev1.IPFamily{corev1.IPv4Protocol}, updatedService.Spec.IPFamilies, "the primary ipFamily can't be changed")
+	assert.Equal(t, &preferDualStack, updatedService.Spec.IPFamilyPolicy, "ipFamilyPolicy must come from desired service")
+}
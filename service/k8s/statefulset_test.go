@@ -120,9 +120,9 @@ func TestStatefulSetServiceGetCreateOrUpdate(t *testing.T) {
 			}
 		})
 	}
-	// test resize pvc
+	// test the volume claim templates are kept
 	{
-		t.Run("test_Resize_Pvc", func(t *testing.T) {
+		t.Run("test_Keep_VolumeClaimTemplates", func(t *testing.T) {
 			assertTest := assert.New(t)
 			beforeSts := &appsv1.StatefulSet{
 				ObjectMeta: metav1.ObjectMeta{
@@ -162,62 +162,24 @@ func TestStatefulSetServiceGetCreateOrUpdate(t *testing.T) {
 					},
 				},
 			}
-			pvcList := &v1.PersistentVolumeClaimList{
-				Items: []v1.PersistentVolumeClaim{
-					{
-						ObjectMeta: metav1.ObjectMeta{
-							Labels: map[string]string{
-								"app.kubernetes.io/component": "redis",
-								"app.kubernetes.io/name":      "teststatefulSet1",
-								"app.kubernetes.io/part-of":   "redis-failover",
-							},
-						},
-						Spec: v1.PersistentVolumeClaimSpec{
-							VolumeName: "vol-1",
-							Resources: v1.VolumeResourceRequirements{
-								Requests: v1.ResourceList{
-									v1.ResourceStorage: resource.MustParse("0.5Gi"),
-								},
-							},
-						},
-					},
-					// resized already
-					{
-						Spec: v1.PersistentVolumeClaimSpec{
-							VolumeName: "vol-2",
-							Resources: v1.VolumeResourceRequirements{
-								Requests: v1.ResourceList{
-									v1.ResourceStorage: resource.MustParse("1Gi"),
-								},
-							},
-						},
-					},
-				},
-			}
 			// Mock.
+			var updatedSts *appsv1.StatefulSet
 			mcli := &kubernetes.Clientset{}
 			mcli.AddReactor("get", "statefulsets", func(action kubetesting.Action) (bool, runtime.Object, error) {
 				return true, beforeSts, nil
 			})
-			mcli.AddReactor("list", "persistentvolumeclaims", func(action kubetesting.Action) (handled bool, ret runtime.Object, err error) {
-				return true, pvcList, nil
+			mcli.AddReactor("update", "statefulsets", func(action kubetesting.Action) (bool, runtime.Object, error) {
+				updatedSts = action.(kubetesting.UpdateActionImpl).Object.(*appsv1.StatefulSet)
+				return true, updatedSts, nil
 			})
-			mcli.AddReactor("update", "persistentvolumeclaims", func(action kubetesting.Action) (handled bool, ret runtime.Object, err error) {
-				// update pvc[0]
-				pvcList.Items[0] = *action.(kubetesting.UpdateActionImpl).Object.(*v1.PersistentVolumeClaim)
-				return true, action.(kubetesting.UpdateActionImpl).Object, nil
+			mcli.AddReactor("*", "persistentvolumeclaims", func(action kubetesting.Action) (handled bool, ret runtime.Object, err error) {
+				panic("shouldn't touch the persistent volume claims")
 			})
 			service := k8s.NewStatefulSetService(mcli, log.Dummy, metrics.Dummy)
 			err := service.CreateOrUpdateStatefulSet(testns, afterSts)
 			assertTest.NoError(err)
-			assertTest.Equal(pvcList.Items[0].Spec.Resources, pvcList.Items[1].Spec.Resources)
-			// should not call update
-			mcli.AddReactor("update", "persistentvolumeclaims", func(action kubetesting.Action) (handled bool, ret runtime.Object, err error) {
-				panic("shouldn't call update")
-			})
-			service = k8s.NewStatefulSetService(mcli, log.Dummy, metrics.Dummy)
-			err = service.CreateOrUpdateStatefulSet(testns, afterSts)
-			assertTest.NoError(err)
+			// The volume claim templates can't be updated
+			assertTest.Equal(beforeSts.Spec.VolumeClaimTemplates, updatedSts.Spec.VolumeClaimTemplates)
 		})
 	}
 }
@@ -1,6 +1,8 @@
 package k8s
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 
 	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
@@ -29,6 +31,58 @@ func GetRedisPassword(s Services, rf *redisfailoverv1.RedisFailover) (string, er
 	return "", fmt.Errorf("secret \"%s\" does not have a password field", rf.Spec.Auth.SecretPath)
 }
 
+// GetRedisTLSConfig builds the TLS configuration used to connect to the redis and sentinel
+// nodes from the given secret of the RedisFailover, or returns nil if TLS is not enabled.
+// The nodes are reached by IP, so the server certificate is verified against the CA without
+// checking its host name.
+func GetRedisTLSConfig(s Services, rf *redisfailoverv1.RedisFailover, secretName string) (*tls.Config, error) {
+	if !rf.TLSEnabled() {
+		return nil, nil
+	}
+
+	secret, err := s.GetSecret(rf.Namespace, secretName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range []string{TLSCertKey, TLSPrivateKeyKey, TLSCAKey} {
+		if _, ok := secret.Data[key]; !ok {
+			return nil, fmt.Errorf("secret \"%s\" does not have a %s field", secretName, key)
+		}
+	}
+
+	cert, err := tls.X509KeyPair(secret.Data[TLSCertKey], secret.Data[TLSPrivateKeyKey])
+	if err != nil {
+		return nil, err
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(secret.Data[TLSCAKey]) {
+		return nil, fmt.Errorf("secret \"%s\" does not have a valid CA certificate", secretName)
+	}
+
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      roots,
+		// The host name verification is skipped, the chain is verified in VerifyConnection
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return fmt.Errorf("no certificate presented by %s", cs.ServerName)
+			}
+			intermediates := x509.NewCertPool()
+			for _, c := range cs.PeerCertificates[1:] {
+				intermediates.AddCert(c)
+			}
+			_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+				Roots:         roots,
+				Intermediates: intermediates,
+			})
+			return err
+		},
+	}, nil
+}
+
 func recordMetrics(namespace string, kind string, object string, operation string, err error, metricsRecorder metrics.Recorder) {
 	if nil == err {
 		metricsRecorder.RecordK8sOperation(namespace, kind, object, operation, metrics.SUCCESS, metrics.NOT_APPLICABLE)
@@ -0,0 +1,125 @@
+package k8s
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+
+	redisfailoverv1 "github.com/saremox/redis-operator/api/redisfailover/v1"
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+)
+
+// newTestCertificate returns a certificate signed by the given parent, self-signed when parent is nil.
+func newTestCertificate(t *testing.T, cn string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, []byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return cert, key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func TestGetRedisTLSConfig(t *testing.T) {
+	ca, caKey, caPEM, _ := newTestCertificate(t, "ca", nil, nil)
+	server, _, serverPEM, serverKeyPEM := newTestCertificate(t, "rfr-test", ca, caKey)
+	otherCA, otherCAKey, _, _ := newTestCertificate(t, "other-ca", nil, nil)
+	untrusted, _, _, _ := newTestCertificate(t, "rfr-test", otherCA, otherCAKey)
+
+	tests := []struct {
+		name   string
+		tls    *redisfailoverv1.TLSSettings
+		data   map[string][]byte
+		expNil bool
+		expErr bool
+	}{
+		{
+			name:   "TLS disabled",
+			expNil: true,
+		},
+		{
+			name: "TLS enabled",
+			tls:  &redisfailoverv1.TLSSettings{SecretName: "redis-tls"},
+			data: map[string][]byte{
+				TLSCertKey:       serverPEM,
+				TLSPrivateKeyKey: serverKeyPEM,
+				TLSCAKey:         caPEM,
+			},
+		},
+		{
+			name: "Missing CA certificate",
+			tls:  &redisfailoverv1.TLSSettings{SecretName: "redis-tls"},
+			data: map[string][]byte{
+				TLSCertKey:       serverPEM,
+				TLSPrivateKeyKey: serverKeyPEM,
+			},
+			expErr: true,
+		},
+		{
+			name:   "Missing secret",
+			tls:    &redisfailoverv1.TLSSettings{SecretName: "missing"},
+			expErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := &redisfailoverv1.RedisFailover{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+				Spec:       redisfailoverv1.RedisFailoverSpec{TLS: test.tls},
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "redis-tls", Namespace: "testns"},
+				Data:       test.data,
+			}
+			services := New(kubernetes.NewSimpleClientset(secret), nil, nil, log.Dummy, metrics.Dummy)
+
+			tlsConfig, err := GetRedisTLSConfig(services, rf, "redis-tls")
+			if test.expErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			if test.expNil {
+				assert.Nil(tlsConfig)
+				return
+			}
+
+			assert.Len(tlsConfig.Certificates, 1)
+			// The server is reached by IP, so only the chain is verified
+			assert.NoError(tlsConfig.VerifyConnection(tls.ConnectionState{ServerName: "10.0.0.1", PeerCertificates: []*x509.Certificate{server}}))
+			assert.Error(tlsConfig.VerifyConnection(tls.ConnectionState{ServerName: "10.0.0.1", PeerCertificates: []*x509.Certificate{untrusted}}))
+		})
+	}
+}
@@ -1,13 +1,17 @@
 package redis
 
 import (
+	"bufio"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	rediscli "github.com/go-redis/redis/v8"
 	"github.com/saremox/redis-operator/log"
@@ -26,49 +30,134 @@ type ReplicationInfo struct {
 	SyncInProgress   bool   // true if slave is syncing
 }
 
+// PersistenceInfo contains the RDB persistence information of a Redis instance
+type PersistenceInfo struct {
+	BgsaveInProgress bool   // true while a BGSAVE is running
+	LastBgsaveStatus string // "ok" or "err"
+	LastSaveTime     int64  // unix time of the last successful save
+}
+
 // Client defines the functions neccesary to connect to redis and sentinel to get or set what we nned
 type Client interface {
-	GetNumberSentinelsInMemory(ip string) (int32, error)
-	GetNumberSentinelSlavesInMemory(ip string) (int32, error)
-	ResetSentinel(ip string) error
-	GetSlaveOf(ip, port, password string) (string, error)
-	IsMaster(ip, port, password string) (bool, error)
-	MonitorRedis(ip, monitor, quorum, password string) error
-	MonitorRedisWithPort(ip, monitor, port, quorum, password string) error
-	MakeMaster(ip, port, password string) error
-	MakeSlaveOf(ip, masterIP, password string) error
-	MakeSlaveOfWithPort(ip, masterIP, masterPort, password string) error
-	GetSentinelMonitor(ip string) (string, string, error)
-	SetCustomSentinelConfig(ip string, configs []string) error
-	SetCustomRedisConfig(ip string, port string, configs []string, password string) error
-	SlaveIsReady(ip, port, password string) (bool, error)
-	SentinelCheckQuorum(ip string) error
-	GetReplicationInfo(ip, port, password string) (*ReplicationInfo, error)
+	GetNumberSentinelsInMemory(ctx context.Context, ip string) (int32, error)
+	GetNumberSentinelSlavesInMemory(ctx context.Context, ip string) (int32, error)
+	ResetSentinel(ctx context.Context, ip string) error
+	GetSlaveOf(ctx context.Context, ip, port, password string) (string, error)
+	IsMaster(ctx context.Context, ip, port, password string) (bool, error)
+	MonitorRedis(ctx context.Context, ip, monitor, quorum, password string) error
+	MonitorRedisWithPort(ctx context.Context, ip, monitor, port, quorum, password string) error
+	MakeMaster(ctx context.Context, ip, port, password string) error
+	MakeSlaveOf(ctx context.Context, ip, masterIP, password string) error
+	MakeSlaveOfWithPort(ctx context.Context, ip, masterIP, masterPort, password string) error
+	GetSentinelMonitor(ctx context.Context, ip string) (string, string, error)
+	SetCustomSentinelConfig(ctx context.Context, ip string, configs []string) error
+	SetSentinelGlobalConfig(ctx context.Context, ip string, configs []string) error
+	SetSentinelCommandRenames(ctx context.Context, ip string) error
+	SetCustomRedisConfig(ctx context.Context, ip string, port string, configs []string, password string) error
+	GetRedisConfig(ctx context.Context, ip, port, password string, parameters []string) (map[string]string, error)
+	SlaveIsReady(ctx context.Context, ip, port, password string) (bool, error)
+	SentinelCheckQuorum(ctx context.Context, ip string) error
+	GetReplicationInfo(ctx context.Context, ip, port, password string) (*ReplicationInfo, error)
+	GetRedisVersion(ctx context.Context, ip, port, password string) (string, error)
+	PauseWrites(ctx context.Context, ip, port, password string, timeout time.Duration) error
+	UnpauseClients(ctx context.Context, ip, port, password string) error
+	SentinelFailover(ctx context.Context, ip string) error
+	GetSentinelReplicaPriorities(ctx context.Context, ip string) (map[string]string, error)
+	GetACLUsers(ctx context.Context, ip, port, password string) (map[string]string, error)
+	SetACLUser(ctx context.Context, ip, port, password, user string, rules []string) error
+	DeleteACLUser(ctx context.Context, ip, port, password, user string) error
+	BackgroundSave(ctx context.Context, ip, port, password string) error
+	GetPersistenceInfo(ctx context.Context, ip, port, password string) (*PersistenceInfo, error)
+	SyncRDB(ctx context.Context, ip, port, password string) (io.ReadCloser, int64, error)
+	WithOptions(options ConnectionOptions) Client
+}
+
+// ConnectionOptions holds the settings used to connect to the redis and sentinel nodes
+// of a RedisFailover
+type ConnectionOptions struct {
+	// TLSConfig enables TLS when set
+	TLSConfig *tls.Config
+	// Username is the ACL user the redis nodes are authenticated with, the default user when empty
+	Username string
+	// MasterName is the name the sentinels monitor the master under, mymaster when empty
+	MasterName string
+	// SentinelPort is the port the sentinels listen on, 26379 when empty
+	SentinelPort string
+	// CommandRenames holds the name every command renamed on the redis nodes is sent under,
+	// keyed by its upper case name. An empty name means the command is disabled.
+	CommandRenames map[string]string
 }
 
 type client struct {
 	metricsRecorder metrics.Recorder
+	options         ConnectionOptions
+	pools           *pools
 }
 
-// New returns a redis client
-func New(metricsRecorder metrics.Recorder) Client {
+// New returns a redis client. The connections to every redis and sentinel endpoint are pooled,
+// and shared by the clients returned by WithOptions.
+func New(metricsRecorder metrics.Recorder, config Config) Client {
 	return &client{
 		metricsRecorder: metricsRecorder,
+		pools:           newPools(config.withDefaults(), metricsRecorder),
+	}
+}
+
+// WithOptions returns a client connecting with the given options
+func (c *client) WithOptions(options ConnectionOptions) Client {
+	return &client{
+		metricsRecorder: c.metricsRecorder,
+		options:         options,
+		pools:           c.pools,
+	}
+}
+
+// redisClient returns the pooled client of the redis node, and the function to call once done
+// with it
+func (c *client) redisClient(ip, port, password string) (*rediscli.Client, func()) {
+	return c.pools.get(metrics.KIND_REDIS, net.JoinHostPort(ip, port), c.options.Username, password, c.options.TLSConfig, c.options.CommandRenames)
+}
+
+// sentinelClient returns the pooled client of the sentinel, and the function to call once done
+// with it
+func (c *client) sentinelClient(ip string) (*rediscli.Client, func()) {
+	port := c.options.SentinelPort
+	if port == "" {
+		port = defaultSentinelPort
+	}
+	return c.pools.get(metrics.KIND_SENTINEL, net.JoinHostPort(ip, port), "", "", c.options.TLSConfig, nil)
+}
+
+// masterName returns the name the sentinels monitor the master under
+func (c *client) masterName() string {
+	if c.options.MasterName == "" {
+		return defaultMasterName
+	}
+	return c.options.MasterName
+}
+
+// addrHost returns the host of the address, without the brackets of an IPv6 one
+func addrHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
 	}
+	return host
 }
 
 const (
 	sentinelsNumberREString = "sentinels=([0-9]+)"
 	slaveNumberREString     = "slaves=([0-9]+)"
 	sentinelStatusREString  = "status=([a-z]+)"
-	redisMasterHostREString = "master_host:([0-9.]+)"
+	redisMasterHostREString = "master_host:([^\\r\\n]+)"
+	redisVersionREString    = "redis_version:([^\\r\\n]+)"
 	redisRoleMaster         = "role:master"
 	redisSyncing            = "master_sync_in_progress:1"
 	redisMasterSillPending  = "master_host:127.0.0.1"
 	redisLinkUp             = "master_link_status:up"
 	redisPort               = "6379"
-	sentinelPort            = "26379"
-	masterName              = "mymaster"
+	defaultSentinelPort     = "26379"
+	defaultMasterName       = "mymaster"
 )
 
 var (
@@ -76,23 +165,14 @@ var (
 	sentinelStatusRE  = regexp.MustCompile(sentinelStatusREString)
 	slaveNumberRE     = regexp.MustCompile(slaveNumberREString)
 	redisMasterHostRE = regexp.MustCompile(redisMasterHostREString)
+	redisVersionRE    = regexp.MustCompile(redisVersionREString)
 )
 
 // GetNumberSentinelsInMemory return the number of sentinels that the requested sentinel has
-func (c *client) GetNumberSentinelsInMemory(ip string) (int32, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func(rClient *rediscli.Client) {
-		err := rClient.Close()
-		if err != nil {
-			log.Error(err.Error())
-		}
-	}(rClient)
-	info, err := rClient.Info(context.TODO(), "sentinel").Result()
+func (c *client) GetNumberSentinelsInMemory(ctx context.Context, ip string) (int32, error) {
+	rClient, release := c.sentinelClient(ip)
+	defer release()
+	info, err := rClient.Info(ctx, "sentinel").Result()
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_NUM_SENTINELS_IN_MEM, metrics.FAIL, getRedisError(err))
 		return 0, err
@@ -120,20 +200,10 @@ func (c *client) GetNumberSentinelsInMemory(ip string) (int32, error) {
 }
 
 // GetNumberSentinelSlavesInMemory return the number of sentinels that the requested sentinel has
-func (c *client) GetNumberSentinelSlavesInMemory(ip string) (int32, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func(rClient *rediscli.Client) {
-		err := rClient.Close()
-		if err != nil {
-			log.Error(err.Error())
-		}
-	}(rClient)
-	info, err := rClient.Info(context.TODO(), "sentinel").Result()
+func (c *client) GetNumberSentinelSlavesInMemory(ctx context.Context, ip string) (int32, error) {
+	rClient, release := c.sentinelClient(ip)
+	defer release()
+	info, err := rClient.Info(ctx, "sentinel").Result()
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_NUM_REDIS_SLAVES_IN_MEM, metrics.FAIL, getRedisError(err))
 		return 0, err
@@ -169,21 +239,11 @@ func isSentinelReady(info string) error {
 }
 
 // ResetSentinel sends a sentinel reset * for the given sentinel
-func (c *client) ResetSentinel(ip string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func(rClient *rediscli.Client) {
-		err := rClient.Close()
-		if err != nil {
-			log.Error(err.Error())
-		}
-	}(rClient)
-	cmd := rediscli.NewIntCmd(context.TODO(), "SENTINEL", "reset", "*")
-	err := rClient.Process(context.TODO(), cmd)
+func (c *client) ResetSentinel(ctx context.Context, ip string) error {
+	rClient, release := c.sentinelClient(ip)
+	defer release()
+	cmd := rediscli.NewIntCmd(ctx, "SENTINEL", "reset", "*")
+	err := rClient.Process(ctx, cmd)
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.RESET_SENTINEL, metrics.FAIL, getRedisError(err))
 		return err
@@ -198,21 +258,11 @@ func (c *client) ResetSentinel(ip string) error {
 }
 
 // GetSlaveOf returns the master of the given redis, or nil if it's master
-func (c *client) GetSlaveOf(ip, port, password string) (string, error) {
+func (c *client) GetSlaveOf(ctx context.Context, ip, port, password string) (string, error) {
 
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func(rClient *rediscli.Client) {
-		err := rClient.Close()
-		if err != nil {
-			log.Error(err.Error())
-		}
-	}(rClient)
-	info, err := rClient.Info(context.TODO(), "replication").Result()
+	rClient, release := c.redisClient(ip, port, password)
+	defer release()
+	info, err := rClient.Info(ctx, "replication").Result()
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_SLAVE_OF, metrics.FAIL, getRedisError(err))
 		log.Errorf("error while getting masterIP : Failed to get info replication while querying redis instance %v", ip)
@@ -227,20 +277,10 @@ func (c *client) GetSlaveOf(ip, port, password string) (string, error) {
 	return match[1], nil
 }
 
-func (c *client) IsMaster(ip, port, password string) (bool, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func(rClient *rediscli.Client) {
-		err := rClient.Close()
-		if err != nil {
-			log.Error(err.Error())
-		}
-	}(rClient)
-	info, err := rClient.Info(context.TODO(), "replication").Result()
+func (c *client) IsMaster(ctx context.Context, ip, port, password string) (bool, error) {
+	rClient, release := c.redisClient(ip, port, password)
+	defer release()
+	info, err := rClient.Info(ctx, "replication").Result()
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.IS_MASTER, metrics.FAIL, getRedisError(err))
 		return false, err
@@ -249,28 +289,26 @@ func (c *client) IsMaster(ip, port, password string) (bool, error) {
 	return strings.Contains(info, redisRoleMaster), nil
 }
 
-func (c *client) MonitorRedis(ip, monitor, quorum, password string) error {
-	return c.MonitorRedisWithPort(ip, monitor, redisPort, quorum, password)
+func (c *client) MonitorRedis(ctx context.Context, ip, monitor, quorum, password string) error {
+	return c.MonitorRedisWithPort(ctx, ip, monitor, redisPort, quorum, password)
 }
 
-func (c *client) MonitorRedisWithPort(ip, monitor, port, quorum, password string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
+func (c *client) MonitorRedisWithPort(ctx context.Context, ip, monitor, port, quorum, password string) error {
+	rClient, release := c.sentinelClient(ip)
+	defer release()
+	// The sentinels monitor a single master, the ones under a previous master name are removed too
+	names := []string{c.masterName()}
+	masters := rediscli.NewSliceCmd(ctx, "SENTINEL", "MASTERS")
+	if err := rClient.Process(ctx, masters); err == nil {
+		names = sentinelMasterNames(masters.Val())
+	}
+	for _, name := range names {
+		cmd := rediscli.NewBoolCmd(ctx, "SENTINEL", "REMOVE", name)
+		_ = rClient.Process(ctx, cmd)
 	}
-	rClient := rediscli.NewClient(options)
-	defer func(rClient *rediscli.Client) {
-		err := rClient.Close()
-		if err != nil {
-			log.Error(err.Error())
-		}
-	}(rClient)
-	cmd := rediscli.NewBoolCmd(context.TODO(), "SENTINEL", "REMOVE", masterName)
-	_ = rClient.Process(context.TODO(), cmd)
 	// We'll continue even if it fails, the priority is to have the redises monitored
-	cmd = rediscli.NewBoolCmd(context.TODO(), "SENTINEL", "MONITOR", masterName, monitor, port, quorum)
-	err := rClient.Process(context.TODO(), cmd)
+	cmd := rediscli.NewBoolCmd(ctx, "SENTINEL", "MONITOR", c.masterName(), monitor, port, quorum)
+	err := rClient.Process(ctx, cmd)
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MONITOR_REDIS_WITH_PORT, metrics.FAIL, getRedisError(err))
 		return err
@@ -282,8 +320,8 @@ func (c *client) MonitorRedisWithPort(ip, monitor, port, quorum, password string
 	}
 
 	if password != "" {
-		cmd = rediscli.NewBoolCmd(context.TODO(), "SENTINEL", "SET", masterName, "auth-pass", password)
-		err := rClient.Process(context.TODO(), cmd)
+		cmd = rediscli.NewBoolCmd(ctx, "SENTINEL", "SET", c.masterName(), "auth-pass", password)
+		err := rClient.Process(ctx, cmd)
 		if err != nil {
 			c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MONITOR_REDIS_WITH_PORT, metrics.FAIL, getRedisError(err))
 			return err
@@ -294,71 +332,45 @@ func (c *client) MonitorRedisWithPort(ip, monitor, port, quorum, password string
 			return err
 		}
 	}
+	if err := c.setSentinelCommandRenames(ctx, rClient); err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MONITOR_REDIS_WITH_PORT, metrics.FAIL, getRedisError(err))
+		return err
+	}
 	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MONITOR_REDIS_WITH_PORT, metrics.SUCCESS, metrics.NOT_APPLICABLE)
 	return nil
 }
 
-func (c *client) MakeMaster(ip string, port string, password string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func(rClient *rediscli.Client) {
-		err := rClient.Close()
-		if err != nil {
-			log.Error(err.Error())
-		}
-	}(rClient)
-	if res := rClient.SlaveOf(context.TODO(), "NO", "ONE"); res.Err() != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MAKE_MASTER, metrics.FAIL, getRedisError(res.Err()))
-		return res.Err()
+func (c *client) MakeMaster(ctx context.Context, ip string, port string, password string) error {
+	rClient, release := c.redisClient(ip, port, password)
+	defer release()
+	if err := replicaOf(ctx, rClient, "NO", "ONE"); err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MAKE_MASTER, metrics.FAIL, getRedisError(err))
+		return err
 	}
 	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MAKE_MASTER, metrics.SUCCESS, metrics.NOT_APPLICABLE)
 	return nil
 }
 
-func (c *client) MakeSlaveOf(ip, masterIP, password string) error {
-	return c.MakeSlaveOfWithPort(ip, masterIP, redisPort, password)
+func (c *client) MakeSlaveOf(ctx context.Context, ip, masterIP, password string) error {
+	return c.MakeSlaveOfWithPort(ctx, ip, masterIP, redisPort, password)
 }
 
-func (c *client) MakeSlaveOfWithPort(ip, masterIP, masterPort, password string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, masterPort), // this is IP and Port for the RedisFailover redis
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func(rClient *rediscli.Client) {
-		err := rClient.Close()
-		if err != nil {
-			log.Error(err.Error())
-		}
-	}(rClient)
-	if res := rClient.SlaveOf(context.TODO(), masterIP, masterPort); res.Err() != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MAKE_SLAVE_OF, metrics.FAIL, getRedisError(res.Err()))
-		return res.Err()
+func (c *client) MakeSlaveOfWithPort(ctx context.Context, ip, masterIP, masterPort, password string) error {
+	rClient, release := c.redisClient(ip, masterPort, password)
+	defer release()
+	if err := replicaOf(ctx, rClient, masterIP, masterPort); err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MAKE_SLAVE_OF, metrics.FAIL, getRedisError(err))
+		return err
 	}
 	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MAKE_SLAVE_OF, metrics.SUCCESS, metrics.NOT_APPLICABLE)
 	return nil
 }
 
-func (c *client) GetSentinelMonitor(ip string) (string, string, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func(rClient *rediscli.Client) {
-		err := rClient.Close()
-		if err != nil {
-			log.Error(err.Error())
-		}
-	}(rClient)
-	cmd := rediscli.NewSliceCmd(context.TODO(), "SENTINEL", "master", masterName)
-	err := rClient.Process(context.TODO(), cmd)
+func (c *client) GetSentinelMonitor(ctx context.Context, ip string) (string, string, error) {
+	rClient, release := c.sentinelClient(ip)
+	defer release()
+	cmd := rediscli.NewSliceCmd(ctx, "SENTINEL", "master", c.masterName())
+	err := rClient.Process(ctx, cmd)
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_MONITOR, metrics.FAIL, getRedisError(err))
 		return "", "", err
@@ -368,53 +380,130 @@ func (c *client) GetSentinelMonitor(ip string) (string, string, error) {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_MONITOR, metrics.FAIL, getRedisError(err))
 		return "", "", err
 	}
-	masterIP := res[3].(string)
-	masterPort := res[5].(string)
+	masterIP, masterPort, err := parseSentinelMaster(res)
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_MONITOR, metrics.FAIL, getRedisError(err))
+		return "", "", err
+	}
 	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_MONITOR, metrics.SUCCESS, metrics.NOT_APPLICABLE)
 	return masterIP, masterPort, nil
 }
 
-func (c *client) SetCustomSentinelConfig(ip string, configs []string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
+// sentinelFields returns the field and value pairs of a SENTINEL MASTER or REPLICAS entry by
+// field, as their position changes between the redis versions
+func sentinelFields(res []interface{}) map[string]string {
+	fields := map[string]string{}
+	for i := 0; i+1 < len(res); i += 2 {
+		field, ok := res[i].(string)
+		if !ok {
+			continue
+		}
+		if value, ok := res[i+1].(string); ok {
+			fields[field] = value
+		}
 	}
-	rClient := rediscli.NewClient(options)
-	defer func(rClient *rediscli.Client) {
-		err := rClient.Close()
-		if err != nil {
-			log.Error(err.Error())
+	return fields
+}
+
+// parseSentinelMaster returns the ip and port of the master from the reply of SENTINEL MASTER
+func parseSentinelMaster(res []interface{}) (string, string, error) {
+	fields := sentinelFields(res)
+	masterIP, ok := fields["ip"]
+	if !ok {
+		return "", "", errors.New("sentinel master reply has no ip")
+	}
+	masterPort, ok := fields["port"]
+	if !ok {
+		return "", "", errors.New("sentinel master reply has no port")
+	}
+	return masterIP, masterPort, nil
+}
+
+// sentinelMasterNames returns the names of the masters listed by SENTINEL MASTERS
+func sentinelMasterNames(masters []interface{}) []string {
+	names := []string{}
+	for _, master := range masters {
+		fields, ok := master.([]interface{})
+		if !ok {
+			continue
+		}
+		for i := 0; i+1 < len(fields); i += 2 {
+			if field, ok := fields[i].(string); ok && field == "name" {
+				if name, ok := fields[i+1].(string); ok {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// SetSentinelCommandRenames tells the sentinel the names the commands are renamed to on the
+// redis nodes, as it sends CONFIG, SLAVEOF and INFO to them too and needs the renames to fail over
+func (c *client) SetSentinelCommandRenames(ctx context.Context, ip string) error {
+	rClient, release := c.sentinelClient(ip)
+	defer release()
+	if err := c.setSentinelCommandRenames(ctx, rClient); err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.APPLY_SENTINEL_CONFIG, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.APPLY_SENTINEL_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+func (c *client) setSentinelCommandRenames(ctx context.Context, rClient *rediscli.Client) error {
+	for _, rename := range sentinelCommandRenames(c.options.CommandRenames) {
+		cmd := rediscli.NewStatusCmd(ctx, "SENTINEL", "SET", c.masterName(), "rename-command", rename[0], rename[1])
+		if err := rClient.Process(ctx, cmd); err != nil {
+			return err
 		}
-	}(rClient)
+	}
+	return nil
+}
+
+func (c *client) SetCustomSentinelConfig(ctx context.Context, ip string, configs []string) error {
+	rClient, release := c.sentinelClient(ip)
+	defer release()
 
 	for _, config := range configs {
 		param, value, err := c.getConfigParameters(config)
 		if err != nil {
 			return err
 		}
-		if err := c.applySentinelConfig(param, value, rClient); err != nil {
+		if err := c.applySentinelConfig(ctx, param, value, rClient); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (c *client) SentinelCheckQuorum(ip string) error {
+// SetSentinelGlobalConfig sets the configuration of the sentinel itself, as opposed to the one of
+// the monitored master, with SENTINEL CONFIG SET available since redis 6.2
+func (c *client) SetSentinelGlobalConfig(ctx context.Context, ip string, configs []string) error {
+	rClient, release := c.sentinelClient(ip)
+	defer release()
 
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewSentinelClient(options)
-	defer func(rClient *rediscli.SentinelClient) {
-		err := rClient.Close()
+	for _, config := range configs {
+		param, value, err := c.getConfigParameters(config)
 		if err != nil {
-			log.Error(err.Error())
+			return err
+		}
+		cmd := rediscli.NewStatusCmd(ctx, "SENTINEL", "CONFIG", "SET", param, value)
+		if err := rClient.Process(ctx, cmd); err != nil {
+			c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.APPLY_SENTINEL_CONFIG, metrics.FAIL, getRedisError(err))
+			return err
 		}
-	}(rClient)
-	cmd := rClient.CkQuorum(context.TODO(), masterName)
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.APPLY_SENTINEL_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+func (c *client) SentinelCheckQuorum(ctx context.Context, ip string) error {
+
+	rClient, release := c.sentinelClient(ip)
+	defer release()
+	cmd := rediscli.NewStringCmd(ctx, "SENTINEL", "ckquorum", c.masterName())
+	_ = rClient.Process(ctx, cmd)
 	res, err := cmd.Result()
 
 	if err != nil {
@@ -446,19 +535,9 @@ func (c *client) SentinelCheckQuorum(ip string) error {
 	}
 
 }
-func (c *client) SetCustomRedisConfig(ip string, port string, configs []string, password string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func(rClient *rediscli.Client) {
-		err := rClient.Close()
-		if err != nil {
-			log.Error(err.Error())
-		}
-	}(rClient)
+func (c *client) SetCustomRedisConfig(ctx context.Context, ip string, port string, configs []string, password string) error {
+	rClient, release := c.redisClient(ip, port, password)
+	defer release()
 
 	for _, config := range configs {
 		param, value, err := c.getConfigParameters(config)
@@ -470,31 +549,56 @@ func (c *client) SetCustomRedisConfig(ip string, port string, configs []string,
 		if strings.TrimSpace(param) == "" {
 			continue
 		}
-		if err := c.applyRedisConfig(param, value, rClient); err != nil {
+		if err := c.applyRedisConfig(ctx, param, value, rClient); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (c *client) applyRedisConfig(parameter string, value string, rClient *rediscli.Client) error {
-	result := rClient.ConfigSet(context.TODO(), parameter, value)
+func (c *client) applyRedisConfig(ctx context.Context, parameter string, value string, rClient *rediscli.Client) error {
+	result := rClient.ConfigSet(ctx, parameter, value)
 	if nil != result.Err() {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, strings.Split(rClient.Options().Addr, ":")[0], metrics.APPLY_REDIS_CONFIG, metrics.FAIL, getRedisError(result.Err()))
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, addrHost(rClient.Options().Addr), metrics.APPLY_REDIS_CONFIG, metrics.FAIL, getRedisError(result.Err()))
 		return result.Err()
 	}
-	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, strings.Split(rClient.Options().Addr, ":")[0], metrics.APPLY_REDIS_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, addrHost(rClient.Options().Addr), metrics.APPLY_REDIS_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
 	return result.Err()
 }
 
-func (c *client) applySentinelConfig(parameter string, value string, rClient *rediscli.Client) error {
-	cmd := rediscli.NewStatusCmd(context.TODO(), "SENTINEL", "set", masterName, parameter, value)
-	err := rClient.Process(context.TODO(), cmd)
+// GetRedisConfig returns the values of the given configuration parameters by parameter, as
+// reported by CONFIG GET. The parameters unknown to the redis node are left out.
+func (c *client) GetRedisConfig(ctx context.Context, ip, port, password string, parameters []string) (map[string]string, error) {
+	rClient, release := c.redisClient(ip, port, password)
+	defer release()
+
+	config := map[string]string{}
+	for _, parameter := range parameters {
+		// Only one parameter per call, as redis 6 doesn't accept more
+		result, err := rClient.ConfigGet(ctx, parameter).Result()
+		if err != nil {
+			c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REDIS_CONFIG, metrics.FAIL, getRedisError(err))
+			return nil, err
+		}
+		if len(result) < 2 {
+			continue
+		}
+		if value, ok := result[1].(string); ok {
+			config[parameter] = value
+		}
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REDIS_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return config, nil
+}
+
+func (c *client) applySentinelConfig(ctx context.Context, parameter string, value string, rClient *rediscli.Client) error {
+	cmd := rediscli.NewStatusCmd(ctx, "SENTINEL", "set", c.masterName(), parameter, value)
+	err := rClient.Process(ctx, cmd)
 	if err != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, strings.Split(rClient.Options().Addr, ":")[0], metrics.APPLY_SENTINEL_CONFIG, metrics.FAIL, getRedisError(err))
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, addrHost(rClient.Options().Addr), metrics.APPLY_SENTINEL_CONFIG, metrics.FAIL, getRedisError(err))
 		return err
 	}
-	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, strings.Split(rClient.Options().Addr, ":")[0], metrics.APPLY_SENTINEL_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, addrHost(rClient.Options().Addr), metrics.APPLY_SENTINEL_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
 	return cmd.Err()
 }
 
@@ -509,49 +613,29 @@ func (c *client) getConfigParameters(config string) (parameter string, value str
 	return s[0], strings.Join(s[1:], " "), nil
 }
 
-func (c *client) SlaveIsReady(ip, port, password string) (bool, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func(rClient *rediscli.Client) {
-		err := rClient.Close()
-		if err != nil {
-			log.Error(err.Error())
-		}
-	}(rClient)
-	info, err := rClient.Info(context.TODO(), "replication").Result()
+func (c *client) SlaveIsReady(ctx context.Context, ip, port, password string) (bool, error) {
+	rClient, release := c.redisClient(ip, port, password)
+	defer release()
+	info, err := rClient.Info(ctx, "replication").Result()
 	if err != nil {
-		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, strings.Split(rClient.Options().Addr, ":")[0], metrics.SLAVE_IS_READY, metrics.FAIL, getRedisError(err))
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, addrHost(rClient.Options().Addr), metrics.SLAVE_IS_READY, metrics.FAIL, getRedisError(err))
 		return false, err
 	}
 
 	ok := !strings.Contains(info, redisSyncing) &&
 		!strings.Contains(info, redisMasterSillPending) &&
 		strings.Contains(info, redisLinkUp)
-	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, strings.Split(rClient.Options().Addr, ":")[0], metrics.SLAVE_IS_READY, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, addrHost(rClient.Options().Addr), metrics.SLAVE_IS_READY, metrics.SUCCESS, metrics.NOT_APPLICABLE)
 	return ok, nil
 }
 
 // GetReplicationInfo returns detailed replication information for a Redis instance.
 // This is used for operator-managed failover to select the best replica for promotion.
-func (c *client) GetReplicationInfo(ip, port, password string) (*ReplicationInfo, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer func(rClient *rediscli.Client) {
-		err := rClient.Close()
-		if err != nil {
-			log.Error(err.Error())
-		}
-	}(rClient)
+func (c *client) GetReplicationInfo(ctx context.Context, ip, port, password string) (*ReplicationInfo, error) {
+	rClient, release := c.redisClient(ip, port, password)
+	defer release()
 
-	info, err := rClient.Info(context.TODO(), "replication").Result()
+	info, err := rClient.Info(ctx, "replication").Result()
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REPLICATION_INFO, metrics.FAIL, getRedisError(err))
 		return nil, err
@@ -604,6 +688,355 @@ func (c *client) GetReplicationInfo(ip, port, password string) (*ReplicationInfo
 	return replInfo, nil
 }
 
+// GetRedisVersion returns the version reported by the Redis server on INFO server.
+func (c *client) GetRedisVersion(ctx context.Context, ip, port, password string) (string, error) {
+	rClient, release := c.redisClient(ip, port, password)
+	defer release()
+
+	info, err := rClient.Info(ctx, "server").Result()
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REDIS_VERSION, metrics.FAIL, getRedisError(err))
+		return "", err
+	}
+	match := redisVersionRE.FindStringSubmatch(info)
+	if len(match) == 0 {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REDIS_VERSION, metrics.FAIL, metrics.REGEX_NOT_FOUND)
+		return "", errors.New("redis version not found in info server")
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REDIS_VERSION, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return match[1], nil
+}
+
+// PauseWrites suspends the write commands of every client for the given time (CLIENT PAUSE WRITE).
+// Reads keep being served, so replicas can catch up with the master before a switchover.
+func (c *client) PauseWrites(ctx context.Context, ip, port, password string, timeout time.Duration) error {
+	rClient, release := c.redisClient(ip, port, password)
+	defer release()
+	cmd := rediscli.NewStatusCmd(ctx, "CLIENT", "PAUSE", strconv.FormatInt(timeout.Milliseconds(), 10), "WRITE")
+	if err := rClient.Process(ctx, cmd); err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.PAUSE_WRITES, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.PAUSE_WRITES, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+// UnpauseClients resumes the clients paused by PauseWrites (CLIENT UNPAUSE).
+func (c *client) UnpauseClients(ctx context.Context, ip, port, password string) error {
+	rClient, release := c.redisClient(ip, port, password)
+	defer release()
+	cmd := rediscli.NewStatusCmd(ctx, "CLIENT", "UNPAUSE")
+	if err := rClient.Process(ctx, cmd); err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.UNPAUSE_CLIENTS, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.UNPAUSE_CLIENTS, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+// SentinelFailover asks the given sentinel to start a failover of the monitored master
+func (c *client) SentinelFailover(ctx context.Context, ip string) error {
+	rClient, release := c.sentinelClient(ip)
+	defer release()
+	cmd := rediscli.NewStatusCmd(ctx, "SENTINEL", "failover", c.masterName())
+	if err := rClient.Process(ctx, cmd); err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.SENTINEL_FAILOVER, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.SENTINEL_FAILOVER, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+// GetSentinelReplicaPriorities returns the replica-priority the sentinel knows every replica of
+// the monitored master with, by replica address. The priorities are refreshed from INFO by the
+// sentinel every 10 seconds.
+func (c *client) GetSentinelReplicaPriorities(ctx context.Context, ip string) (map[string]string, error) {
+	rClient, release := c.sentinelClient(ip)
+	defer release()
+	cmd := rediscli.NewSliceCmd(ctx, "SENTINEL", "REPLICAS", c.masterName())
+	if err := rClient.Process(ctx, cmd); err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_REPLICAS, metrics.FAIL, getRedisError(err))
+		return nil, err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_REPLICAS, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return parseSentinelReplicaPriorities(cmd.Val()), nil
+}
+
+// parseSentinelReplicaPriorities returns the slave-priority of every replica listed by
+// SENTINEL REPLICAS, by replica address
+func parseSentinelReplicaPriorities(replicas []interface{}) map[string]string {
+	priorities := map[string]string{}
+	for _, replica := range replicas {
+		res, ok := replica.([]interface{})
+		if !ok {
+			continue
+		}
+		fields := sentinelFields(res)
+		if fields["ip"] != "" {
+			priorities[fields["ip"]] = fields["slave-priority"]
+		}
+	}
+	return priorities
+}
+
+// GetACLUsers returns the rules of the ACL users of the redis node by user name, as listed by ACL LIST
+func (c *client) GetACLUsers(ctx context.Context, ip, port, password string) (map[string]string, error) {
+	rClient, release := c.redisClient(ip, port, password)
+	defer release()
+	cmd := rediscli.NewStringSliceCmd(ctx, "ACL", "LIST")
+	if err := rClient.Process(ctx, cmd); err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_ACL_USERS, metrics.FAIL, getRedisError(err))
+		return nil, err
+	}
+	users := map[string]string{}
+	for _, line := range cmd.Val() {
+		// Each line is formatted as "user <name> <rules>"
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) < 2 || fields[0] != "user" {
+			continue
+		}
+		rules := ""
+		if len(fields) == 3 {
+			rules = fields[2]
+		}
+		users[fields[1]] = rules
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_ACL_USERS, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return users, nil
+}
+
+// SetACLUser creates or modifies the ACL user with the given rules (ACL SETUSER)
+func (c *client) SetACLUser(ctx context.Context, ip, port, password, user string, rules []string) error {
+	rClient, release := c.redisClient(ip, port, password)
+	defer release()
+	args := []interface{}{"ACL", "SETUSER", user}
+	for _, rule := range rules {
+		args = append(args, rule)
+	}
+	cmd := rediscli.NewStatusCmd(ctx, args...)
+	if err := rClient.Process(ctx, cmd); err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.SET_ACL_USER, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.SET_ACL_USER, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+// DeleteACLUser deletes the ACL user and disconnects its clients (ACL DELUSER)
+func (c *client) DeleteACLUser(ctx context.Context, ip, port, password, user string) error {
+	rClient, release := c.redisClient(ip, port, password)
+	defer release()
+	cmd := rediscli.NewIntCmd(ctx, "ACL", "DELUSER", user)
+	if err := rClient.Process(ctx, cmd); err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.DELETE_ACL_USER, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.DELETE_ACL_USER, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+// BackgroundSave starts a snapshot of the dataset to the RDB file (BGSAVE). A snapshot already
+// in progress is not an error.
+func (c *client) BackgroundSave(ctx context.Context, ip, port, password string) error {
+	rClient, release := c.redisClient(ip, port, password)
+	defer release()
+	if err := rClient.BgSave(ctx).Err(); err != nil && !strings.Contains(err.Error(), "already in progress") {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.BACKGROUND_SAVE, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.BACKGROUND_SAVE, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+// GetPersistenceInfo returns the RDB persistence information of a Redis instance (INFO persistence)
+func (c *client) GetPersistenceInfo(ctx context.Context, ip, port, password string) (*PersistenceInfo, error) {
+	rClient, release := c.redisClient(ip, port, password)
+	defer release()
+
+	info, err := rClient.Info(ctx, "persistence").Result()
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_PERSISTENCE_INFO, metrics.FAIL, getRedisError(err))
+		return nil, err
+	}
+
+	persistenceInfo := &PersistenceInfo{}
+	for _, line := range strings.Split(info, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "rdb_bgsave_in_progress":
+			persistenceInfo.BgsaveInProgress = value == "1"
+		case "rdb_last_bgsave_status":
+			persistenceInfo.LastBgsaveStatus = value
+		case "rdb_last_save_time":
+			if t, err := strconv.ParseInt(value, 10, 64); err == nil {
+				persistenceInfo.LastSaveTime = t
+			}
+		}
+	}
+
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_PERSISTENCE_INFO, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return persistenceInfo, nil
+}
+
+// syncReadTimeout is how long SyncRDB waits for data. The node sends a newline every second
+// while it prepares the snapshot, so it only expires when the node is gone.
+const syncReadTimeout = time.Minute
+
+// SyncRDB requests a full resynchronization of the redis node, as a replica would (SYNC), and
+// returns the RDB snapshot the node sends along with its size. REPLCONF rdb-only tells the nodes
+// supporting it not to stream the replication backlog after the snapshot.
+func (c *client) SyncRDB(ctx context.Context, ip, port, password string) (io.ReadCloser, int64, error) {
+	rdb, size, err := c.syncRDB(ctx, ip, port, password)
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.SYNC_RDB, metrics.FAIL, getRedisError(err))
+		return nil, 0, err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.SYNC_RDB, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return rdb, size, nil
+}
+
+func (c *client) syncRDB(ctx context.Context, ip, port, password string) (io.ReadCloser, int64, error) {
+	replconfCommand, err := c.commandName("REPLCONF")
+	if err != nil {
+		return nil, 0, err
+	}
+	syncCommand, err := c.commandName("SYNC")
+	if err != nil {
+		return nil, 0, err
+	}
+	dialer := &net.Dialer{Timeout: c.pools.config.DialTimeout}
+	addr := net.JoinHostPort(ip, port)
+	var conn net.Conn
+	if c.options.TLSConfig != nil {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: c.options.TLSConfig}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	stream := &rdbStream{conn: conn}
+	stream.reader = bufio.NewReader(deadlineReader{conn})
+
+	if password != "" {
+		args := []string{"AUTH", password}
+		if c.options.Username != "" {
+			args = []string{"AUTH", c.options.Username, password}
+		}
+		if err := stream.command(args...); err != nil {
+			conn.Close()
+			return nil, 0, err
+		}
+	}
+	// Nodes older than 7.0 do not know rdb-only, the backlog following the snapshot is then
+	// ignored as the stream is closed once the snapshot is read
+	if err := stream.command(replconfCommand, "rdb-only", "1"); err != nil && !strings.Contains(err.Error(), "ERR") {
+		conn.Close()
+		return nil, 0, err
+	}
+	if err := writeCommand(conn, syncCommand); err != nil {
+		conn.Close()
+		return nil, 0, err
+	}
+
+	for {
+		line, err := stream.reader.ReadString('\n')
+		if err != nil {
+			conn.Close()
+			return nil, 0, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "":
+			// keepalive sent while the snapshot is prepared
+			continue
+		case strings.HasPrefix(line, "-"):
+			conn.Close()
+			return nil, 0, errors.New(line[1:])
+		case strings.HasPrefix(line, "$EOF:"):
+			conn.Close()
+			return nil, 0, errors.New("diskless snapshots are not supported")
+		case strings.HasPrefix(line, "$"):
+			size, err := strconv.ParseInt(line[1:], 10, 64)
+			if err != nil {
+				conn.Close()
+				return nil, 0, fmt.Errorf("invalid snapshot size %q", line)
+			}
+			stream.limited = &io.LimitedReader{R: stream.reader, N: size}
+			return stream, size, nil
+		default:
+			conn.Close()
+			return nil, 0, fmt.Errorf("unexpected reply to SYNC: %q", line)
+		}
+	}
+}
+
+// rdbStream is the snapshot sent in reply to SYNC
+type rdbStream struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	limited *io.LimitedReader
+}
+
+func (s *rdbStream) Read(p []byte) (int, error) {
+	n, err := s.limited.Read(p)
+	if err == io.EOF && s.limited.N > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (s *rdbStream) Close() error {
+	return s.conn.Close()
+}
+
+// command sends a command and reads its status reply
+func (s *rdbStream) command(args ...string) error {
+	if err := writeCommand(s.conn, args...); err != nil {
+		return err
+	}
+	line, err := s.reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if strings.HasPrefix(line, "-") {
+		return errors.New(line[1:])
+	}
+	return nil
+}
+
+func writeCommand(w io.Writer, args ...string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// deadlineReader extends the read deadline of the connection before every read
+type deadlineReader struct {
+	conn net.Conn
+}
+
+func (r deadlineReader) Read(p []byte) (int, error) {
+	if err := r.conn.SetReadDeadline(time.Now().Add(syncReadTimeout)); err != nil {
+		return 0, err
+	}
+	return r.conn.Read(p)
+}
+
+// IsAuthError returns true when the error is due to the redis node rejecting the credentials
+func IsAuthError(err error) bool {
+	return err != nil && (strings.Contains(err.Error(), "WRONGPASS") || strings.Contains(err.Error(), "NOAUTH"))
+}
+
 func getRedisError(err error) string {
 	if strings.Contains(err.Error(), "NOAUTH") {
 		return metrics.NOAUTH
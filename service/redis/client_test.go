@@ -0,0 +1,94 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saremox/redis-operator/metrics"
+)
+
+func TestRedisMasterHostRE(t *testing.T) {
+	tests := []struct {
+		name         string
+		info         string
+		expectedHost string
+	}{
+		{
+			name:         "IPv4 master",
+			info:         "# Replication\r\nrole:slave\r\nmaster_host:10.0.0.1\r\nmaster_port:6379\r\n",
+			expectedHost: "10.0.0.1",
+		},
+		{
+			name:         "IPv6 master",
+			info:         "# Replication\r\nrole:slave\r\nmaster_host:fd00::1\r\nmaster_port:6379\r\n",
+			expectedHost: "fd00::1",
+		},
+		{
+			name: "master",
+			info: "# Replication\r\nrole:master\r\nconnected_slaves:0\r\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			match := redisMasterHostRE.FindStringSubmatch(test.info)
+			if test.expectedHost == "" {
+				assert.Empty(t, match)
+			} else {
+				assert.Equal(t, test.expectedHost, match[1])
+			}
+		})
+	}
+}
+
+func TestParseSentinelMaster(t *testing.T) {
+	assert := assert.New(t)
+
+	masterIP, masterPort, err := parseSentinelMaster([]interface{}{"name", "mymaster", "ip", "fd00::1", "port", "6379", "runid", ""})
+	assert.NoError(err)
+	assert.Equal("fd00::1", masterIP)
+	assert.Equal("6379", masterPort)
+
+	_, _, err = parseSentinelMaster([]interface{}{"name", "mymaster"})
+	assert.EqualError(err, "sentinel master reply has no ip")
+}
+
+func TestAddrHost(t *testing.T) {
+	assert.Equal(t, "10.0.0.1", addrHost("10.0.0.1:6379"))
+	assert.Equal(t, "fd00::1", addrHost("[fd00::1]:6379"))
+}
+
+func TestSentinelConnectionOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	c := New(metrics.Dummy, Config{}).(*client)
+	sentinelClient, release := c.sentinelClient("0.0.0.0")
+	release()
+	assert.Equal("0.0.0.0:26379", sentinelClient.Options().Addr)
+	assert.Equal("mymaster", c.masterName())
+
+	c = c.WithOptions(ConnectionOptions{MasterName: "cache", SentinelPort: "26380"}).(*client)
+	sentinelClient, release = c.sentinelClient("0.0.0.0")
+	release()
+	assert.Equal("0.0.0.0:26380", sentinelClient.Options().Addr)
+	assert.Equal("cache", c.masterName())
+}
+
+func TestSentinelMasterNames(t *testing.T) {
+	masters := []interface{}{
+		[]interface{}{"name", "mymaster", "ip", "10.0.0.1", "port", "6379"},
+		[]interface{}{"name", "cache", "ip", "10.0.0.1", "port", "6379"},
+	}
+	assert.Equal(t, []string{"mymaster", "cache"}, sentinelMasterNames(masters))
+	assert.Empty(t, sentinelMasterNames(nil))
+}
+
+func TestParseSentinelReplicaPriorities(t *testing.T) {
+	replicas := []interface{}{
+		[]interface{}{"name", "10.0.0.2:6379", "ip", "10.0.0.2", "port", "6379", "slave-priority", "0"},
+		[]interface{}{"name", "10.0.0.3:6379", "ip", "10.0.0.3", "port", "6379", "slave-priority", "100"},
+	}
+	assert.Equal(t, map[string]string{"10.0.0.2": "0", "10.0.0.3": "100"}, parseSentinelReplicaPriorities(replicas))
+	assert.Empty(t, parseSentinelReplicaPriorities(nil))
+}
@@ -0,0 +1,211 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	rediscli "github.com/go-redis/redis/v8"
+
+	"github.com/saremox/redis-operator/log"
+	"github.com/saremox/redis-operator/metrics"
+)
+
+// Config holds the settings of the connections to the redis and sentinel nodes. The zero
+// fields take the value of DefaultConfig.
+type Config struct {
+	// DialTimeout is how long establishing a connection may take
+	DialTimeout time.Duration
+	// ReadTimeout is how long the reply to a command may take
+	ReadTimeout time.Duration
+	// WriteTimeout is how long sending a command may take
+	WriteTimeout time.Duration
+	// PoolSize is the maximum number of connections open to every endpoint
+	PoolSize int
+	// PoolIdleTimeout is how long the pool of an endpoint is kept unused before its connections
+	// are closed
+	PoolIdleTimeout time.Duration
+}
+
+// DefaultConfig is the configuration of the connections used when none is given
+var DefaultConfig = Config{
+	DialTimeout:     5 * time.Second,
+	ReadTimeout:     3 * time.Second,
+	WriteTimeout:    3 * time.Second,
+	PoolSize:        2,
+	PoolIdleTimeout: 5 * time.Minute,
+}
+
+func (c Config) withDefaults() Config {
+	if c.DialTimeout <= 0 {
+		c.DialTimeout = DefaultConfig.DialTimeout
+	}
+	if c.ReadTimeout <= 0 {
+		c.ReadTimeout = DefaultConfig.ReadTimeout
+	}
+	if c.WriteTimeout <= 0 {
+		c.WriteTimeout = DefaultConfig.WriteTimeout
+	}
+	if c.PoolSize <= 0 {
+		c.PoolSize = DefaultConfig.PoolSize
+	}
+	if c.PoolIdleTimeout <= 0 {
+		c.PoolIdleTimeout = DefaultConfig.PoolIdleTimeout
+	}
+	return c
+}
+
+// poolKey identifies the pool of an endpoint. The credentials are part of it, so the
+// connections authenticated with a rotated password or certificate are not reused.
+type poolKey struct {
+	addr     string
+	username string
+	password string
+	tls      string
+	renames  string
+}
+
+// tlsKey identifies the TLS configuration by its client certificates, as it is built again from
+// its secret on every reconcile
+func tlsKey(config *tls.Config) string {
+	if config == nil {
+		return ""
+	}
+	if len(config.Certificates) == 0 {
+		return fmt.Sprintf("%p", config)
+	}
+	hash := sha256.New()
+	for _, certificate := range config.Certificates {
+		for _, der := range certificate.Certificate {
+			hash.Write(der)
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type pool struct {
+	client   *rediscli.Client
+	users    int
+	lastUsed time.Time
+}
+
+// pools caches the connection pool of every endpoint. A pool is closed once it has not been
+// used for the idle timeout.
+type pools struct {
+	config          Config
+	metricsRecorder metrics.Recorder
+	now             func() time.Time
+
+	mutex sync.Mutex
+	pools map[poolKey]*pool
+}
+
+func newPools(config Config, metricsRecorder metrics.Recorder) *pools {
+	return &pools{
+		config:          config,
+		metricsRecorder: metricsRecorder,
+		now:             time.Now,
+		pools:           map[poolKey]*pool{},
+	}
+}
+
+// get returns the client of the endpoint pool, creating it when needed, and the function
+// releasing it. A pool is never closed while one of its clients is in use. The commands sent
+// by the client are translated through the renames.
+func (p *pools) get(kind string, addr, username, password string, tlsConfig *tls.Config, renames map[string]string) (*rediscli.Client, func()) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.evictIdle()
+	key := poolKey{addr: addr, username: username, password: password, tls: tlsKey(tlsConfig), renames: renamesKey(renames)}
+	endpoint, ok := p.pools[key]
+	if !ok {
+		client := rediscli.NewClient(&rediscli.Options{
+			Addr:         addr,
+			Username:     username,
+			Password:     password,
+			DB:           0,
+			TLSConfig:    tlsConfig,
+			DialTimeout:  p.config.DialTimeout,
+			ReadTimeout:  p.config.ReadTimeout,
+			WriteTimeout: p.config.WriteTimeout,
+			PoolSize:     p.config.PoolSize,
+			IdleTimeout:  p.config.PoolIdleTimeout,
+		})
+		client.AddHook(latencyHook{kind: kind, metricsRecorder: p.metricsRecorder})
+		if len(renames) > 0 {
+			client.AddHook(renameHook{renames: renames})
+		}
+		endpoint = &pool{client: client}
+		p.pools[key] = endpoint
+	}
+	endpoint.users++
+	p.recordStats()
+
+	var once sync.Once
+	return endpoint.client, func() {
+		once.Do(func() {
+			p.mutex.Lock()
+			defer p.mutex.Unlock()
+			endpoint.users--
+			endpoint.lastUsed = p.now()
+		})
+	}
+}
+
+// evictIdle closes the pools unused for the idle timeout. It is called with the mutex held.
+func (p *pools) evictIdle() {
+	now := p.now()
+	for key, endpoint := range p.pools {
+		if endpoint.users > 0 || now.Sub(endpoint.lastUsed) < p.config.PoolIdleTimeout {
+			continue
+		}
+		if err := endpoint.client.Close(); err != nil {
+			log.Error(err.Error())
+		}
+		delete(p.pools, key)
+	}
+}
+
+// recordStats records the number of pools and of their connections. It is called with the
+// mutex held.
+func (p *pools) recordStats() {
+	connections, idleConnections := 0, 0
+	for _, endpoint := range p.pools {
+		stats := endpoint.client.PoolStats()
+		connections += int(stats.TotalConns)
+		idleConnections += int(stats.IdleConns)
+	}
+	p.metricsRecorder.SetRedisConnectionPools(len(p.pools), connections, idleConnections)
+}
+
+type latencyStartKey struct{}
+
+// latencyHook records the time every command sent to the endpoint takes
+type latencyHook struct {
+	kind            string
+	metricsRecorder metrics.Recorder
+}
+
+func (h latencyHook) BeforeProcess(ctx context.Context, _ rediscli.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, latencyStartKey{}, time.Now()), nil
+}
+
+func (h latencyHook) AfterProcess(ctx context.Context, cmd rediscli.Cmder) error {
+	if start, ok := ctx.Value(latencyStartKey{}).(time.Time); ok {
+		h.metricsRecorder.RecordRedisCommandDuration(h.kind, cmd.Name(), time.Since(start))
+	}
+	return nil
+}
+
+func (h latencyHook) BeforeProcessPipeline(ctx context.Context, _ []rediscli.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h latencyHook) AfterProcessPipeline(_ context.Context, _ []rediscli.Cmder) error {
+	return nil
+}
@@ -0,0 +1,97 @@
+package redis
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saremox/redis-operator/metrics"
+)
+
+func TestConfigWithDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(DefaultConfig, Config{}.withDefaults())
+
+	config := Config{ReadTimeout: time.Second, PoolSize: 5}.withDefaults()
+	assert.Equal(time.Second, config.ReadTimeout)
+	assert.Equal(5, config.PoolSize)
+	assert.Equal(DefaultConfig.DialTimeout, config.DialTimeout)
+	assert.Equal(DefaultConfig.WriteTimeout, config.WriteTimeout)
+	assert.Equal(DefaultConfig.PoolIdleTimeout, config.PoolIdleTimeout)
+}
+
+func TestPoolsGet(t *testing.T) {
+	assert := assert.New(t)
+
+	c := New(metrics.Dummy, Config{}).(*client)
+	redisClient, release := c.redisClient("0.0.0.0", "6379", "pass")
+	release()
+	release()
+	sameClient, release := c.redisClient("0.0.0.0", "6379", "pass")
+	release()
+	assert.Same(redisClient, sameClient)
+
+	// A rotated password gets a pool of its own
+	rotatedClient, release := c.redisClient("0.0.0.0", "6379", "newpass")
+	release()
+	assert.NotSame(redisClient, rotatedClient)
+
+	// The clients with other options share the pools
+	userClient := c.WithOptions(ConnectionOptions{Username: "operator"}).(*client)
+	assert.Same(c.pools, userClient.pools)
+	otherUserClient, release := userClient.redisClient("0.0.0.0", "6379", "pass")
+	release()
+	assert.NotSame(redisClient, otherUserClient)
+
+	// The TLS configuration built again from the same secret shares the pool
+	tlsConfig := func(certificate string) *tls.Config {
+		return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{[]byte(certificate)}}}}
+	}
+	tlsClient, release := c.WithOptions(ConnectionOptions{TLSConfig: tlsConfig("cert")}).(*client).redisClient("0.0.0.0", "6379", "pass")
+	release()
+	sameTLSClient, release := c.WithOptions(ConnectionOptions{TLSConfig: tlsConfig("cert")}).(*client).redisClient("0.0.0.0", "6379", "pass")
+	release()
+	renewedTLSClient, release := c.WithOptions(ConnectionOptions{TLSConfig: tlsConfig("renewed")}).(*client).redisClient("0.0.0.0", "6379", "pass")
+	release()
+	assert.Same(tlsClient, sameTLSClient)
+	assert.NotSame(tlsClient, renewedTLSClient)
+	assert.NotSame(redisClient, tlsClient)
+
+	sentinelClient, release := c.sentinelClient("0.0.0.0")
+	release()
+	assert.Equal("0.0.0.0:26379", sentinelClient.Options().Addr)
+	assert.Len(c.pools.pools, 6)
+}
+
+func TestPoolsEvictIdle(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	c := New(metrics.Dummy, Config{PoolIdleTimeout: time.Minute}).(*client)
+	c.pools.now = func() time.Time { return now }
+
+	idleClient, release := c.redisClient("0.0.0.0", "6379", "")
+	release()
+	usedClient, releaseUsed := c.redisClient("0.0.0.1", "6379", "")
+
+	// Pools are kept until the idle timeout
+	now = now.Add(30 * time.Second)
+	_, release = c.redisClient("0.0.0.2", "6379", "")
+	release()
+	assert.Len(c.pools.pools, 3)
+
+	// Idle pools are closed, the ones in use are kept
+	now = now.Add(time.Minute)
+	_, release = c.redisClient("0.0.0.2", "6379", "")
+	release()
+	assert.Len(c.pools.pools, 2)
+	assert.Error(idleClient.Close(), "the idle pool should be closed already")
+
+	sameClient, release := c.redisClient("0.0.0.1", "6379", "")
+	release()
+	releaseUsed()
+	assert.Same(usedClient, sameClient)
+}
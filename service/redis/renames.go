@@ -0,0 +1,132 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	rediscli "github.com/go-redis/redis/v8"
+)
+
+// renamesKey identifies the rename table of a pool, its connections sending the renamed commands
+func renamesKey(renames map[string]string) string {
+	if len(renames) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(renames))
+	for name := range renames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var key strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&key, "%s=%s\n", name, renames[name])
+	}
+	return key.String()
+}
+
+// sentinelCommandRenames returns the renames the sentinels are configured with, sorted by
+// command. The disabled commands can't be renamed on the sentinels and are left out.
+func sentinelCommandRenames(renames map[string]string) [][2]string {
+	sentinelRenames := [][2]string{}
+	for name, renamed := range renames {
+		if renamed != "" {
+			sentinelRenames = append(sentinelRenames, [2]string{name, renamed})
+		}
+	}
+	sort.Slice(sentinelRenames, func(i, j int) bool {
+		return sentinelRenames[i][0] < sentinelRenames[j][0]
+	})
+	return sentinelRenames
+}
+
+// commandName returns the name the command is known by on the redis nodes
+func (c *client) commandName(name string) (string, error) {
+	renamed, ok := c.options.CommandRenames[strings.ToUpper(name)]
+	switch {
+	case !ok:
+		return name, nil
+	case renamed == "":
+		return "", disabledCommandError(name)
+	default:
+		return renamed, nil
+	}
+}
+
+func disabledCommandError(name string) error {
+	return fmt.Errorf("ERR unknown command '%s', disabled by customCommandRenames", name)
+}
+
+// isUnknownCommand tells whether the error is the reply to a command the node does not know
+func isUnknownCommand(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "ERR unknown command")
+}
+
+// replicaOf makes the node replicate the given master, or a master when it is NO ONE. REPLICAOF is
+// sent, SLAVEOF being used on the nodes older than 5.0 or where REPLICAOF is disabled.
+func replicaOf(ctx context.Context, rClient *rediscli.Client, host, port string) error {
+	cmd := rediscli.NewStatusCmd(ctx, "REPLICAOF", host, port)
+	if err := rClient.Process(ctx, cmd); isUnknownCommand(err) {
+		return rClient.SlaveOf(ctx, host, port).Err()
+	}
+	return cmd.Err()
+}
+
+type renamedCommandKey struct{}
+
+// renameHook sends the commands under the name they were renamed to on the redis nodes. The
+// original name is restored once processed, so the metrics never record the renamed ones.
+type renameHook struct {
+	renames map[string]string
+}
+
+func (h renameHook) BeforeProcess(ctx context.Context, cmd rediscli.Cmder) (context.Context, error) {
+	name, err := h.rename(cmd)
+	if name == "" {
+		return ctx, err
+	}
+	return context.WithValue(ctx, renamedCommandKey{}, name), err
+}
+
+func (h renameHook) AfterProcess(ctx context.Context, cmd rediscli.Cmder) error {
+	if name, ok := ctx.Value(renamedCommandKey{}).(string); ok {
+		cmd.Args()[0] = name
+	}
+	return nil
+}
+
+func (h renameHook) BeforeProcessPipeline(ctx context.Context, cmds []rediscli.Cmder) (context.Context, error) {
+	for _, cmd := range cmds {
+		if _, err := h.rename(cmd); err != nil {
+			return ctx, err
+		}
+	}
+	return ctx, nil
+}
+
+func (h renameHook) AfterProcessPipeline(_ context.Context, _ []rediscli.Cmder) error {
+	return nil
+}
+
+// rename replaces the name of the command by the one it was renamed to, and returns the original
+// name when it was replaced
+func (h renameHook) rename(cmd rediscli.Cmder) (string, error) {
+	args := cmd.Args()
+	if len(args) == 0 {
+		return "", nil
+	}
+	name, ok := args[0].(string)
+	if !ok {
+		return "", nil
+	}
+	renamed, ok := h.renames[strings.ToUpper(name)]
+	switch {
+	case !ok:
+		return "", nil
+	case renamed == "":
+		return "", disabledCommandError(name)
+	}
+	args[0] = renamed
+	return name, nil
+}
@@ -0,0 +1,229 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	rediscli "github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saremox/redis-operator/metrics"
+)
+
+var errCaptured = errors.New("captured")
+
+// captureHook records the commands as sent to the node, and replies to them without sending them
+type captureHook struct {
+	sent    *[]string
+	replies map[string]error
+}
+
+func (h captureHook) BeforeProcess(ctx context.Context, cmd rediscli.Cmder) (context.Context, error) {
+	name := cmd.Args()[0].(string)
+	*h.sent = append(*h.sent, name)
+	if err, ok := h.replies[name]; ok {
+		return ctx, err
+	}
+	return ctx, errCaptured
+}
+
+func (h captureHook) AfterProcess(_ context.Context, _ rediscli.Cmder) error {
+	return nil
+}
+
+func (h captureHook) BeforeProcessPipeline(ctx context.Context, _ []rediscli.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h captureHook) AfterProcessPipeline(_ context.Context, _ []rediscli.Cmder) error {
+	return nil
+}
+
+func TestRenameHook(t *testing.T) {
+	assert := assert.New(t)
+
+	c := New(metrics.Dummy, Config{}).WithOptions(ConnectionOptions{CommandRenames: map[string]string{"CONFIG": "opconfig", "FLUSHALL": ""}}).(*client)
+	rClient, release := c.redisClient("0.0.0.0", "6379", "")
+	defer release()
+	sent := []string{}
+	rClient.AddHook(captureHook{sent: &sent})
+
+	configSet := rClient.ConfigSet(context.TODO(), "maxmemory", "1gb")
+	assert.Equal(errCaptured, configSet.Err())
+	assert.Equal(errCaptured, rClient.Ping(context.TODO()).Err())
+	assert.Equal([]string{"opconfig", "ping"}, sent)
+	// The metrics record the commands under their original name
+	assert.Equal("config", configSet.Name())
+
+	// The disabled commands are not sent
+	sent = []string{}
+	err := rClient.FlushAll(context.TODO()).Err()
+	assert.True(isUnknownCommand(err))
+	assert.Empty(sent)
+
+	// The sentinel commands are not translated
+	sentinelClient, release := c.sentinelClient("0.0.0.0")
+	defer release()
+	sentinelClient.AddHook(captureHook{sent: &sent})
+	_ = sentinelClient.ConfigSet(context.TODO(), "loglevel", "verbose")
+	assert.Equal([]string{"config"}, sent)
+}
+
+// okServer is a node replying OK to every command, and recording them
+func okServer(t *testing.T) (string, func() [][]string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	var mutex sync.Mutex
+	received := [][]string{}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					var n int
+					if _, err := fmt.Fscanf(reader, "*%d\r\n", &n); err != nil {
+						return
+					}
+					args := make([]string, n)
+					for i := range args {
+						var size int
+						if _, err := fmt.Fscanf(reader, "$%d\r\n", &size); err != nil {
+							return
+						}
+						arg := make([]byte, size+2)
+						if _, err := io.ReadFull(reader, arg); err != nil {
+							return
+						}
+						args[i] = string(arg[:size])
+					}
+					mutex.Lock()
+					received = append(received, args)
+					mutex.Unlock()
+					if _, err := conn.Write([]byte("+OK\r\n")); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	return port, func() [][]string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return received
+	}
+}
+
+func TestSetSentinelCommandRenames(t *testing.T) {
+	assert := assert.New(t)
+
+	port, received := okServer(t)
+	c := New(metrics.Dummy, Config{}).WithOptions(ConnectionOptions{
+		MasterName:     "cache",
+		SentinelPort:   port,
+		CommandRenames: map[string]string{"SLAVEOF": "opslaveof", "FLUSHALL": "", "CONFIG": "opconfig"},
+	})
+
+	assert.NoError(c.SetSentinelCommandRenames(context.TODO(), "127.0.0.1"))
+	// The disabled commands can't be renamed on the sentinels
+	assert.Equal([][]string{
+		{"SENTINEL", "SET", "cache", "rename-command", "CONFIG", "opconfig"},
+		{"SENTINEL", "SET", "cache", "rename-command", "SLAVEOF", "opslaveof"},
+	}, received())
+}
+
+func TestReplicaOf(t *testing.T) {
+	unknownReplicaOf := errors.New("ERR unknown command 'REPLICAOF', with args beginning with: 'NO' 'ONE'")
+	tests := []struct {
+		name         string
+		renames      map[string]string
+		replies      map[string]error
+		expectedSent []string
+	}{
+		{
+			name:         "REPLICAOF is used when available",
+			expectedSent: []string{"REPLICAOF"},
+		},
+		{
+			name:         "SLAVEOF is used on the nodes older than 5.0",
+			replies:      map[string]error{"REPLICAOF": unknownReplicaOf},
+			expectedSent: []string{"REPLICAOF", "slaveof"},
+		},
+		{
+			name:         "SLAVEOF is renamed",
+			renames:      map[string]string{"SLAVEOF": "opslaveof"},
+			replies:      map[string]error{"REPLICAOF": unknownReplicaOf},
+			expectedSent: []string{"REPLICAOF", "opslaveof"},
+		},
+		{
+			name:         "REPLICAOF is renamed",
+			renames:      map[string]string{"REPLICAOF": "opreplicaof"},
+			expectedSent: []string{"opreplicaof"},
+		},
+		{
+			name:         "REPLICAOF is disabled",
+			renames:      map[string]string{"REPLICAOF": ""},
+			expectedSent: []string{"slaveof"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := New(metrics.Dummy, Config{}).WithOptions(ConnectionOptions{CommandRenames: test.renames}).(*client)
+			rClient, release := c.redisClient("0.0.0.0", "6379", "")
+			defer release()
+			sent := []string{}
+			rClient.AddHook(captureHook{sent: &sent, replies: test.replies})
+
+			err := replicaOf(context.TODO(), rClient, "NO", "ONE")
+			assert.Equal(t, errCaptured, err)
+			assert.Equal(t, test.expectedSent, sent)
+		})
+	}
+}
+
+func TestCommandName(t *testing.T) {
+	assert := assert.New(t)
+
+	c := New(metrics.Dummy, Config{}).WithOptions(ConnectionOptions{CommandRenames: map[string]string{"SYNC": "opsync", "REPLCONF": ""}}).(*client)
+	name, err := c.commandName("sync")
+	assert.NoError(err)
+	assert.Equal("opsync", name)
+	name, err = c.commandName("INFO")
+	assert.NoError(err)
+	assert.Equal("INFO", name)
+	_, err = c.commandName("REPLCONF")
+	assert.True(isUnknownCommand(err))
+}
+
+func TestRenamesKey(t *testing.T) {
+	assert.Empty(t, renamesKey(nil))
+	assert.Equal(t, renamesKey(map[string]string{"CONFIG": "opconfig", "SLAVEOF": ""}), renamesKey(map[string]string{"SLAVEOF": "", "CONFIG": "opconfig"}))
+	assert.NotEqual(t, renamesKey(map[string]string{"CONFIG": "opconfig"}), renamesKey(map[string]string{"CONFIG": "newconfig"}))
+}
+
+func TestSentinelCommandRenames(t *testing.T) {
+	renames := sentinelCommandRenames(map[string]string{"SLAVEOF": "opslaveof", "FLUSHALL": "", "CONFIG": "opconfig"})
+	assert.Equal(t, [][2]string{{"CONFIG", "opconfig"}, {"SLAVEOF", "opslaveof"}}, renames)
+	assert.Empty(t, sentinelCommandRenames(nil))
+}
+
+func TestIsUnknownCommand(t *testing.T) {
+	assert.True(t, isUnknownCommand(errors.New("ERR unknown command 'REPLICAOF'")))
+	assert.False(t, isUnknownCommand(errors.New("ERR syntax error")))
+	assert.False(t, isUnknownCommand(nil))
+}
@@ -0,0 +1,371 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client interacts with a bucket of an S3 compatible object storage
+type Client interface {
+	PutObject(ctx context.Context, bucket, key string, body io.Reader, size int64) error
+	GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, int64, error)
+	ListObjects(ctx context.Context, bucket, prefix string) ([]Object, error)
+	DeleteObject(ctx context.Context, bucket, key string) error
+	PresignGetObject(bucket, key string, t time.Time, expires time.Duration) (string, error)
+}
+
+// Config holds the settings used to connect to the object storage
+type Config struct {
+	// Endpoint is the URL of the object storage, the buckets are addressed with the path style
+	Endpoint        string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// HTTPClient defaults to http.DefaultClient
+	HTTPClient *http.Client
+	// PartSize is the size of the parts the objects larger than it are uploaded in, 64 MiB by
+	// default. It is raised when the object would need more than 10000 parts.
+	PartSize int64
+}
+
+// Object is an object listed in a bucket
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Error is an error returned by the object storage
+type Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("s3: %s", http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("s3: %s: %s", e.Code, e.Message)
+}
+
+// IsNotFound returns true when the bucket or the object does not exist
+func IsNotFound(err error) bool {
+	e, ok := err.(*Error)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// maxPresignExpires is the longest validity of a presigned URL accepted by the object storages
+const maxPresignExpires = 7 * 24 * time.Hour
+
+const (
+	// defaultPartSize keeps the multipart uploads well above the 5 MiB minimum part size of S3,
+	// a single PUT is limited to 5 GiB
+	defaultPartSize = 64 * 1024 * 1024
+	// maxParts is the largest number of parts of a multipart upload
+	maxParts = 10000
+)
+
+type client struct {
+	endpoint   *url.URL
+	signer     Signer
+	httpClient *http.Client
+	partSize   int64
+}
+
+// New returns an object storage client
+func New(config Config) (Client, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(config.Endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid endpoint %q, expected an http or https URL", config.Endpoint)
+	}
+	region := config.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	partSize := config.PartSize
+	if partSize <= 0 {
+		partSize = defaultPartSize
+	}
+	return &client{
+		endpoint: endpoint,
+		signer: Signer{
+			AccessKeyID:     config.AccessKeyID,
+			SecretAccessKey: config.SecretAccessKey,
+			Region:          region,
+		},
+		httpClient: httpClient,
+		partSize:   partSize,
+	}, nil
+}
+
+// PutObject uploads the object. The body is streamed, so its size must be known. The objects
+// larger than the part size are uploaded in parts, as a single PUT is limited to 5 GiB.
+func (c *client) PutObject(ctx context.Context, bucket, key string, body io.Reader, size int64) error {
+	if size > c.partSize {
+		return c.putObjectParts(ctx, bucket, key, body, size)
+	}
+	req, err := c.newRequest(ctx, http.MethodPut, bucket, key, nil, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	resp, err := c.do(req, UnsignedPayload)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+type initiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+// putObjectParts uploads the object with a multipart upload, the upload is aborted when a part
+// fails so the storage does not keep its parts
+func (c *client) putObjectParts(ctx context.Context, bucket, key string, body io.Reader, size int64) error {
+	partSize := c.partSize
+	if (size+partSize-1)/partSize > maxParts {
+		partSize = (size + maxParts - 1) / maxParts
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, bucket, key, url.Values{"uploads": {""}}, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req, EmptyPayload)
+	if err != nil {
+		return err
+	}
+	initiated := initiateMultipartUploadResult{}
+	err = xml.NewDecoder(resp.Body).Decode(&initiated)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	if initiated.UploadID == "" {
+		return fmt.Errorf("s3: no upload id returned for %s", key)
+	}
+
+	parts := []completedPart{}
+	for number, offset := 1, int64(0); offset < size; number, offset = number+1, offset+partSize {
+		etag, err := c.putPart(ctx, bucket, key, initiated.UploadID, number, io.LimitReader(body, partSize), min(partSize, size-offset))
+		if err != nil {
+			c.abortUpload(bucket, key, initiated.UploadID)
+			return fmt.Errorf("uploading part %d: %w", number, err)
+		}
+		parts = append(parts, completedPart{PartNumber: number, ETag: etag})
+	}
+
+	if err := c.completeUpload(ctx, bucket, key, initiated.UploadID, parts); err != nil {
+		c.abortUpload(bucket, key, initiated.UploadID)
+		return err
+	}
+	return nil
+}
+
+func (c *client) putPart(ctx context.Context, bucket, key, uploadID string, number int, body io.Reader, size int64) (string, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}
+	req, err := c.newRequest(ctx, http.MethodPut, bucket, key, query, body)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = size
+	resp, err := c.do(req, UnsignedPayload)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	return resp.Header.Get("ETag"), nil
+}
+
+// completeUpload assembles the parts, the storage may reply 200 with an error document when
+// the assembly fails
+func (c *client) completeUpload(ctx context.Context, bucket, key, uploadID string, parts []completedPart) error {
+	data, err := xml.Marshal(completeMultipartUpload{Parts: parts})
+	if err != nil {
+		return err
+	}
+	req, err := c.newRequest(ctx, http.MethodPost, bucket, key, url.Values{"uploadId": {uploadID}}, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req, UnsignedPayload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	reply, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return err
+	}
+	s3Err := &Error{}
+	if xml.Unmarshal(reply, s3Err) == nil && s3Err.Code != "" {
+		s3Err.StatusCode = resp.StatusCode
+		return s3Err
+	}
+	return nil
+}
+
+// abortUpload deletes the parts already uploaded. It does not use the request context, which may
+// be the cause of the failure, and its error is ignored as the bucket lifecycle rules remain
+// the fallback for the incomplete uploads.
+func (c *client) abortUpload(bucket, key, uploadID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	req, err := c.newRequest(ctx, http.MethodDelete, bucket, key, url.Values{"uploadId": {uploadID}}, nil)
+	if err != nil {
+		return
+	}
+	if resp, err := c.do(req, EmptyPayload); err == nil {
+		resp.Body.Close()
+	}
+}
+
+// GetObject downloads the object and returns its size
+func (c *client) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, int64, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, bucket, key, nil, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := c.do(req, EmptyPayload)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.Body, resp.ContentLength, nil
+}
+
+type listBucketResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+}
+
+// ListObjects returns the objects of the bucket whose key starts with the prefix, sorted by key
+func (c *client) ListObjects(ctx context.Context, bucket, prefix string) ([]Object, error) {
+	objects := []Object{}
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		req, err := c.newRequest(ctx, http.MethodGet, bucket, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.do(req, EmptyPayload)
+		if err != nil {
+			return nil, err
+		}
+		result := listBucketResult{}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, content := range result.Contents {
+			objects = append(objects, Object{Key: content.Key, Size: content.Size, LastModified: content.LastModified})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// DeleteObject deletes the object, deleting a missing object is not an error
+func (c *client) DeleteObject(ctx context.Context, bucket, key string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, bucket, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req, EmptyPayload)
+	if IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// PresignGetObject returns the URL the object can be downloaded from without the credentials,
+// signed at the given time and valid for the given duration, at most 7 days
+func (c *client) PresignGetObject(bucket, key string, t time.Time, expires time.Duration) (string, error) {
+	if expires <= 0 || expires > maxPresignExpires {
+		return "", fmt.Errorf("invalid expiration %s, expected at most %s", expires, maxPresignExpires)
+	}
+	req, err := c.newRequest(context.Background(), http.MethodGet, bucket, key, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	c.signer.Presign(req, t, expires)
+	return req.URL.String(), nil
+}
+
+func (c *client) newRequest(ctx context.Context, method, bucket, key string, query url.Values, body io.Reader) (*http.Request, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("bucket name is required")
+	}
+	u := *c.endpoint
+	u.Path = u.Path + "/" + bucket
+	if key != "" {
+		u.Path = u.Path + "/" + key
+	}
+	u.RawPath = encodePath(u.Path)
+	u.RawQuery = encodeQuery(query)
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+// do signs and sends the request, the replies other than 2xx are returned as an *Error
+func (c *client) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	c.signer.Sign(req, payloadHash, time.Now())
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	s3Err := &Error{}
+	if body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024)); err == nil {
+		// the HEAD replies and some gateways have no error document
+		_ = xml.Unmarshal(body, s3Err)
+	}
+	s3Err.StatusCode = resp.StatusCode
+	return nil, s3Err
+}
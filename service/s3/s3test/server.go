@@ -0,0 +1,372 @@
+// Package s3test provides an in-memory S3 compatible object storage, standing in for a MinIO
+// server in the tests.
+package s3test
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/saremox/redis-operator/service/s3"
+)
+
+// Credentials and region accepted by the server
+const (
+	AccessKeyID     = "minioadmin"
+	SecretAccessKey = "minioadmin-secret"
+	Region          = "us-east-1"
+)
+
+// maxKeys is the number of objects listed per page, kept low so the pagination is exercised
+const maxKeys = 2
+
+type object struct {
+	data         []byte
+	lastModified time.Time
+}
+
+// upload is a multipart upload in progress
+type upload struct {
+	bucket string
+	key    string
+	parts  map[int][]byte
+}
+
+// Server is an in-memory object storage serving the path style S3 API: PUT, GET and DELETE of
+// objects, the multipart uploads and ListObjectsV2. The requests must be signed with the AWS
+// signature version 4, in their headers or, for the presigned ones, in their query.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	buckets  map[string]map[string]object
+	uploads  map[string]*upload
+	uploadID int
+}
+
+// NewServer starts a server with the given empty buckets. It must be closed once done.
+func NewServer(buckets ...string) *Server {
+	s := &Server{buckets: map[string]map[string]object{}, uploads: map[string]*upload{}}
+	for _, bucket := range buckets {
+		s.buckets[bucket] = map[string]object{}
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Config returns the client settings to connect to the server
+func (s *Server) Config() s3.Config {
+	return s3.Config{
+		Endpoint:        s.URL,
+		Region:          Region,
+		AccessKeyID:     AccessKeyID,
+		SecretAccessKey: SecretAccessKey,
+	}
+}
+
+// Object returns the content of an object
+func (s *Server) Object(bucket, key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.buckets[bucket][key]
+	return o.data, ok
+}
+
+// PutObject stores an object, the bucket must exist
+func (s *Server) PutObject(bucket, key string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buckets[bucket][key] = object{data: data, lastModified: time.Now().UTC()}
+}
+
+// Uploads returns the number of the multipart uploads neither completed nor aborted
+func (s *Server) Uploads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.uploads)
+}
+
+// Keys returns the sorted keys of the objects of a bucket
+func (s *Server) Keys(bucket string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := []string{}
+	for key := range s.buckets[bucket] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if code, message := s.authenticate(r); code != "" {
+		writeError(w, http.StatusForbidden, code, message)
+		return
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	objects, ok := s.buckets[bucket]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+		return
+	}
+
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		s.listObjects(w, r, objects)
+	case key != "" && (query.Has("uploads") || query.Has("uploadId")):
+		s.serveUpload(w, r, bucket, key, objects)
+	case r.Method == http.MethodPut && key != "":
+		data, ok := readBody(w, r)
+		if !ok {
+			return
+		}
+		objects[key] = object{data: data, lastModified: time.Now().UTC()}
+		w.Header().Set("ETag", etag(data))
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodGet && key != "":
+		o, ok := objects[key]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(o.data)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(o.data)
+	case r.Method == http.MethodDelete && key != "":
+		delete(objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotImplemented, "NotImplemented", "A header you provided implies functionality that is not implemented")
+	}
+}
+
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+type completeMultipartUpload struct {
+	Parts []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+type completeMultipartUploadResult struct {
+	XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+	Bucket  string   `xml:"Bucket"`
+	Key     string   `xml:"Key"`
+	ETag    string   `xml:"ETag"`
+}
+
+// serveUpload initiates, uploads the parts of, completes and aborts the multipart uploads
+func (s *Server) serveUpload(w http.ResponseWriter, r *http.Request, bucket, key string, objects map[string]object) {
+	query := r.URL.Query()
+	if r.Method == http.MethodPost && query.Has("uploads") {
+		s.uploadID++
+		id := strconv.Itoa(s.uploadID)
+		s.uploads[id] = &upload{bucket: bucket, key: key, parts: map[int][]byte{}}
+		w.Header().Set("Content-Type", "application/xml")
+		_ = xml.NewEncoder(w).Encode(initiateMultipartUploadResult{Bucket: bucket, Key: key, UploadID: id})
+		return
+	}
+
+	id := query.Get("uploadId")
+	u, ok := s.uploads[id]
+	if !ok || u.bucket != bucket || u.key != key {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", "The specified multipart upload does not exist.")
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		number, err := strconv.Atoi(query.Get("partNumber"))
+		if err != nil || number < 1 || number > 10000 {
+			writeError(w, http.StatusBadRequest, "InvalidArgument", "Part number must be an integer between 1 and 10000, inclusive")
+			return
+		}
+		data, ok := readBody(w, r)
+		if !ok {
+			return
+		}
+		u.parts[number] = data
+		w.Header().Set("ETag", etag(data))
+		w.WriteHeader(http.StatusOK)
+	case http.MethodPost:
+		complete := completeMultipartUpload{}
+		if err := xml.NewDecoder(r.Body).Decode(&complete); err != nil || len(complete.Parts) == 0 {
+			writeError(w, http.StatusBadRequest, "MalformedXML", "The XML you provided was not well-formed or did not validate against our published schema.")
+			return
+		}
+		data := []byte{}
+		for i, part := range complete.Parts {
+			content, ok := u.parts[part.PartNumber]
+			if !ok || etag(content) != part.ETag {
+				writeError(w, http.StatusBadRequest, "InvalidPart", "One or more of the specified parts could not be found.")
+				return
+			}
+			if i > 0 && part.PartNumber <= complete.Parts[i-1].PartNumber {
+				writeError(w, http.StatusBadRequest, "InvalidPartOrder", "The list of parts was not in ascending order.")
+				return
+			}
+			data = append(data, content...)
+		}
+		delete(s.uploads, id)
+		objects[key] = object{data: data, lastModified: time.Now().UTC()}
+		w.Header().Set("Content-Type", "application/xml")
+		_ = xml.NewEncoder(w).Encode(completeMultipartUploadResult{Bucket: bucket, Key: key, ETag: etag(data)})
+	case http.MethodDelete:
+		delete(s.uploads, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotImplemented, "NotImplemented", "A header you provided implies functionality that is not implemented")
+	}
+}
+
+// readBody reads the body of an upload, it writes the error when the body does not have the
+// announced length
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil || int64(len(data)) != r.ContentLength {
+		writeError(w, http.StatusBadRequest, "IncompleteBody", "You did not provide the number of bytes specified by the Content-Length HTTP header")
+		return nil, false
+	}
+	return data, true
+}
+
+func etag(data []byte) string {
+	sum := md5.Sum(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+type listContent struct {
+	Key          string `xml:"Key"`
+	Size         int    `xml:"Size"`
+	LastModified string `xml:"LastModified"`
+}
+
+type listBucketResult struct {
+	XMLName               xml.Name      `xml:"ListBucketResult"`
+	Name                  string        `xml:"Name"`
+	Prefix                string        `xml:"Prefix"`
+	KeyCount              int           `xml:"KeyCount"`
+	IsTruncated           bool          `xml:"IsTruncated"`
+	NextContinuationToken string        `xml:"NextContinuationToken,omitempty"`
+	Contents              []listContent `xml:"Contents"`
+}
+
+func (s *Server) listObjects(w http.ResponseWriter, r *http.Request, objects map[string]object) {
+	query := r.URL.Query()
+	if query.Get("list-type") != "2" {
+		writeError(w, http.StatusNotImplemented, "NotImplemented", "Only ListObjectsV2 is implemented")
+		return
+	}
+	prefix := query.Get("prefix")
+	// the continuation token is the last key of the previous page
+	after := query.Get("continuation-token")
+
+	keys := []string{}
+	for key := range objects {
+		if strings.HasPrefix(key, prefix) && key > after {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	result := listBucketResult{Prefix: prefix}
+	if len(keys) > maxKeys {
+		keys = keys[:maxKeys]
+		result.IsTruncated = true
+		result.NextContinuationToken = keys[len(keys)-1]
+	}
+	for _, key := range keys {
+		result.Contents = append(result.Contents, listContent{
+			Key:          key,
+			Size:         len(objects[key].data),
+			LastModified: objects[key].lastModified.Format(time.RFC3339),
+		})
+	}
+	result.KeyCount = len(result.Contents)
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+// authenticate checks the signature of the request, it returns the error code when it is invalid
+func (s *Server) authenticate(r *http.Request) (string, string) {
+	if r.URL.Query().Has("X-Amz-Signature") {
+		return s.authenticatePresigned(r)
+	}
+	auth := strings.TrimPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ")
+	params := map[string]string{}
+	for _, param := range strings.Split(auth, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		params[name] = value
+	}
+	credential := strings.SplitN(params["Credential"], "/", 2)
+	if credential[0] != AccessKeyID {
+		return "InvalidAccessKeyId", "The Access Key Id you provided does not exist in our records."
+	}
+
+	t, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return "AccessDenied", "X-Amz-Date is missing or invalid"
+	}
+	signer := s3.Signer{AccessKeyID: AccessKeyID, SecretAccessKey: SecretAccessKey, Region: Region}
+	signature := signer.Signature(r, strings.Split(params["SignedHeaders"], ";"), r.Header.Get("X-Amz-Content-Sha256"), t)
+	if signature != params["Signature"] {
+		return "SignatureDoesNotMatch", "The request signature we calculated does not match the signature you provided."
+	}
+	return "", ""
+}
+
+// authenticatePresigned checks the signature of the query of a presigned request and its expiration
+func (s *Server) authenticatePresigned(r *http.Request) (string, string) {
+	query := r.URL.Query()
+	credential := strings.SplitN(query.Get("X-Amz-Credential"), "/", 2)
+	if credential[0] != AccessKeyID {
+		return "InvalidAccessKeyId", "The Access Key Id you provided does not exist in our records."
+	}
+
+	t, err := time.Parse("20060102T150405Z", query.Get("X-Amz-Date"))
+	if err != nil {
+		return "AccessDenied", "X-Amz-Date is missing or invalid"
+	}
+	expires, err := strconv.Atoi(query.Get("X-Amz-Expires"))
+	if err != nil {
+		return "AccessDenied", "X-Amz-Expires is missing or invalid"
+	}
+	if time.Now().After(t.Add(time.Duration(expires) * time.Second)) {
+		return "AccessDenied", "Request has expired"
+	}
+
+	signature := query.Get("X-Amz-Signature")
+	query.Del("X-Amz-Signature")
+	unsigned := r.Clone(r.Context())
+	unsigned.URL.RawQuery = query.Encode()
+	signer := s3.Signer{AccessKeyID: AccessKeyID, SecretAccessKey: SecretAccessKey, Region: Region}
+	if signer.Signature(unsigned, strings.Split(query.Get("X-Amz-SignedHeaders"), ";"), s3.UnsignedPayload, t) != signature {
+		return "SignatureDoesNotMatch", "The request signature we calculated does not match the signature you provided."
+	}
+	return "", ""
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<Error><Code>%s</Code><Message>%s</Message></Error>", code, message)
+}
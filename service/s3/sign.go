@@ -0,0 +1,156 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Payload hashes of the requests whose body is not signed and of the requests without a body
+const (
+	UnsignedPayload = "UNSIGNED-PAYLOAD"
+	EmptyPayload    = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+const (
+	signAlgorithm  = "AWS4-HMAC-SHA256"
+	signService    = "s3"
+	amzDateFormat  = "20060102T150405Z"
+	amzDateHeader  = "X-Amz-Date"
+	amzContentHash = "X-Amz-Content-Sha256"
+	amzSignature   = "X-Amz-Signature"
+)
+
+// Signer signs the requests with the AWS signature version 4
+type Signer struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	Region          string
+}
+
+// Sign sets the date and the authorization headers of the request. Every header already set on
+// the request is signed, along with the host.
+func (s Signer) Sign(req *http.Request, payloadHash string, t time.Time) {
+	t = t.UTC()
+	req.Header.Set(amzDateHeader, t.Format(amzDateFormat))
+	req.Header.Set(amzContentHash, payloadHash)
+
+	signedHeaders := []string{"host"}
+	for name := range req.Header {
+		if name = strings.ToLower(name); name != "authorization" {
+			signedHeaders = append(signedHeaders, name)
+		}
+	}
+	sort.Strings(signedHeaders)
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		signAlgorithm, s.AccessKeyID, s.scope(t), strings.Join(signedHeaders, ";"), s.Signature(req, signedHeaders, payloadHash, t)))
+}
+
+// Presign adds the signature of the request to its query, so it can be sent by a client without
+// the credentials until it expires. Only the host is signed and the payload is not.
+func (s Signer) Presign(req *http.Request, t time.Time, expires time.Duration) {
+	t = t.UTC()
+	query := req.URL.Query()
+	query.Set("X-Amz-Algorithm", signAlgorithm)
+	query.Set("X-Amz-Credential", s.AccessKeyID+"/"+s.scope(t))
+	query.Set(amzDateHeader, t.Format(amzDateFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	req.URL.RawQuery = encodeQuery(query)
+
+	signature := s.Signature(req, []string{"host"}, UnsignedPayload, t)
+	req.URL.RawQuery += "&" + amzSignature + "=" + signature
+}
+
+// Signature returns the signature of the request over the given lower case headers
+func (s Signer) Signature(req *http.Request, signedHeaders []string, payloadHash string, t time.Time) string {
+	t = t.UTC()
+	canonicalHeaders := strings.Builder{}
+	for _, name := range signedHeaders {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.Host
+			if value == "" {
+				value = req.URL.Host
+			}
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		encodePath(req.URL.Path),
+		encodeQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		signAlgorithm,
+		t.Format(amzDateFormat),
+		s.scope(t),
+		hexSHA256(canonicalRequest),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), t.Format("20060102"))
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, signService)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func (s Signer) scope(t time.Time) string {
+	return fmt.Sprintf("%s/%s/%s/aws4_request", t.Format("20060102"), s.Region, signService)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// encodePath escapes every byte of the path but the unreserved characters and the slashes
+func encodePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return strings.ReplaceAll(uriEncode(path), "%2F", "/")
+}
+
+// encodeQuery returns the query sorted by name, with the names and values escaped
+func encodeQuery(query url.Values) string {
+	params := []string{}
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, uriEncode(name)+"="+uriEncode(value))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+func uriEncode(s string) string {
+	b := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
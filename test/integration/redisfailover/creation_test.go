@@ -160,7 +160,7 @@ func TestRedisFailover(t *testing.T) {
 	require.NoError(err)
 
 	// Create the redis clients
-	redisClient := redis.New(metrics.Dummy)
+	redisClient := redis.New(metrics.Dummy, redis.DefaultConfig)
 
 	clients := clients{
 		k8sClient:   k8sClient,
@@ -318,7 +318,7 @@ func (c *clients) testRedisMaster(t *testing.T) {
 
 	for _, pod := range redisPodList.Items {
 		ip := pod.Status.PodIP
-		if ok, _ := c.redisClient.IsMaster(ip, "6379", testPass); ok {
+		if ok, _ := c.redisClient.IsMaster(context.Background(), ip, "6379", testPass); ok {
 			masters = append(masters, ip)
 		}
 	}
@@ -341,7 +341,7 @@ func (c *clients) testSentinelMonitoring(t *testing.T) {
 
 	for _, pod := range sentinelPodList.Items {
 		ip := pod.Status.PodIP
-		master, _, _ := c.redisClient.GetSentinelMonitor(ip)
+		master, _, _ := c.redisClient.GetSentinelMonitor(context.Background(), ip)
 		masters = append(masters, master)
 	}
 
@@ -349,7 +349,7 @@ func (c *clients) testSentinelMonitoring(t *testing.T) {
 		assert.Equal(masters[0], masterIP, "all master ip monitoring should equal")
 	}
 
-	isMaster, err := c.redisClient.IsMaster(masters[0], "6379", testPass)
+	isMaster, err := c.redisClient.IsMaster(context.Background(), masters[0], "6379", testPass)
 	assert.NoError(err)
 	assert.True(isMaster, "Sentinel should monitor the Redis master")
 }
@@ -359,8 +359,12 @@ func (c *clients) testAuth(t *testing.T) {
 
 	redisCfg, err := c.k8sClient.CoreV1().ConfigMaps(namespace).Get(context.Background(), fmt.Sprintf("rfr-%s", name), metav1.GetOptions{})
 	assert.NoError(err)
-	assert.Contains(redisCfg.Data["redis.conf"], "requirepass "+testPass)
-	assert.Contains(redisCfg.Data["redis.conf"], "masterauth "+testPass)
+	assert.NotContains(redisCfg.Data["redis.conf"], testPass)
+
+	authSecret, err := c.k8sClient.CoreV1().Secrets(namespace).Get(context.Background(), fmt.Sprintf("rfauth-%s", name), metav1.GetOptions{})
+	assert.NoError(err)
+	assert.Contains(string(authSecret.Data["auth.conf"]), "requirepass "+testPass)
+	assert.Contains(string(authSecret.Data["auth.conf"]), "masterauth "+testPass)
 
 	redisSS, err := c.k8sClient.AppsV1().StatefulSets(namespace).Get(context.Background(), fmt.Sprintf("rfr-%s", name), metav1.GetOptions{})
 	assert.NoError(err)